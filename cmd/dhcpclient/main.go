@@ -0,0 +1,319 @@
+// dhcpclient checks whether DHCP works on a network interface.
+//
+// It runs a DHCPv4 or DHCPv6 exchange, a DHCPv6 Information-Request, a BSDP
+// exchange or a netboot flow on the interface, and prints the messages
+// exchanged with their Summary, or as JSON with -json. It exits with status 1
+// if the exchange fails, e.g. if no server answers, and 2 on usage errors.
+//
+// Extra options are added to the messages sent with -opt code=type:value,
+// where type is one of hex, str, ip, ips, u8, u16 and u32, e.g.
+//
+//	dhcpclient -i eth0
+//	dhcpclient -i eth0 -mode v6 -json
+//	dhcpclient -i eth0 -opt 60=str:PXEClient -opt 93=u16:7
+//	dhcpclient -i eth0 -mode netboot6 -retries 3
+//
+// It needs the privileges to open raw sockets and bind to the DHCP client
+// ports, usually root.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/bsdp"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/insomniacslk/dhcp/netboot"
+)
+
+// modes are the flows dhcpclient runs, see -mode.
+var modes = map[string]func(*config) *result{
+	"v4":       runV4,
+	"v6":       runV6,
+	"info":     runInfo,
+	"bsdp":     runBSDP,
+	"netboot4": runNetboot4,
+	"netboot6": runNetboot6,
+}
+
+const modeUsage = `flow to run:
+  v4        DHCPv4 DISCOVER, OFFER, REQUEST, ACK
+  v6        DHCPv6 SOLICIT, ADVERTISE, REQUEST, REPLY
+  info      DHCPv6 INFORMATION-REQUEST, REPLY
+  bsdp      BSDP INFORM[LIST], ACK[LIST], INFORM[SELECT], ACK[SELECT]
+  netboot4  DHCPv4 netboot, printing the network configuration and boot file
+  netboot6  DHCPv6 netboot, printing the network configuration and boot file`
+
+// config is the configuration given on the command line.
+type config struct {
+	iface   string
+	server  string
+	timeout time.Duration
+	retries int
+	options optionList
+}
+
+func main() {
+	var (
+		cfg     config
+		mode    = flag.String("mode", "v4", modeUsage)
+		useJSON = flag.Bool("json", false, "print the result as JSON")
+	)
+	flag.StringVar(&cfg.iface, "i", "", "network interface to run on (required)")
+	flag.StringVar(&cfg.server, "server", "", "server address to send to instead of the broadcast or multicast address, for v4, v6, info and bsdp")
+	flag.DurationVar(&cfg.timeout, "timeout", 3*time.Second, "time to wait for each reply")
+	flag.IntVar(&cfg.retries, "retries", 3, "number of retransmissions, for bsdp, netboot4 and netboot6")
+	flag.Var(&cfg.options, "opt", "option to add to the messages sent, as code=type:value with type one of "+optionTypes+"; can be repeated")
+	flag.Parse()
+
+	run, ok := modes[*mode]
+	if !ok || cfg.iface == "" || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	res := run(&cfg)
+	res.Mode = *mode
+	res.Iface = cfg.iface
+	if *useJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(res)
+	} else {
+		printSummary(os.Stdout, res)
+	}
+	if !res.OK {
+		if !*useJSON {
+			fmt.Fprintf(os.Stderr, "%s on %s failed: %s\n", *mode, cfg.iface, res.Error)
+		}
+		os.Exit(1)
+	}
+}
+
+// printSummary prints the summaries of the messages of r, and the
+// network configuration it got, if any.
+func printSummary(w io.Writer, r *result) {
+	for _, s := range r.summaries {
+		fmt.Fprintln(w, s)
+	}
+	if r.NetConf != nil {
+		fmt.Fprintln(w, "Network configuration")
+		for _, a := range r.NetConf.Addresses {
+			fmt.Fprintf(w, "  address: %s (preferred %ds, valid %ds)\n", &a.IPNet, a.PreferredLifetime, a.ValidLifetime)
+		}
+		fmt.Fprintf(w, "  routers: %v\n", r.NetConf.Routers)
+		fmt.Fprintf(w, "  DNS servers: %v\n", r.NetConf.DNSServers)
+		fmt.Fprintf(w, "  DNS search list: %v\n", r.NetConf.DNSSearchList)
+		fmt.Fprintf(w, "  boot file: %s\n", r.BootFile)
+	}
+}
+
+// fail records err as the failure of r, and returns r.
+func (r *result) fail(err error) *result {
+	r.OK = false
+	r.Error = err.Error()
+	return r
+}
+
+func (r *result) addV4(messages []*dhcpv4.DHCPv4) {
+	for _, m := range messages {
+		r.Messages = append(r.Messages, v4JSON(m, nil))
+		r.summaries = append(r.summaries, m.Summary())
+	}
+}
+
+func (r *result) addV6(messages ...dhcpv6.DHCPv6) {
+	for _, m := range messages {
+		if m == nil {
+			continue
+		}
+		r.Messages = append(r.Messages, v6JSON(m))
+		r.summaries = append(r.summaries, m.Summary())
+	}
+}
+
+func resolveServer(network, addr string, port int) (*net.UDPAddr, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, fmt.Sprint(port))
+	}
+	return net.ResolveUDPAddr(network, addr)
+}
+
+func newV4Client(cfg *config) (*dhcpv4.Client, error) {
+	c := dhcpv4.NewClient()
+	c.ReadTimeout, c.WriteTimeout = cfg.timeout, cfg.timeout
+	if cfg.server != "" {
+		raddr, err := resolveServer("udp4", cfg.server, dhcpv4.ServerPort)
+		if err != nil {
+			return nil, err
+		}
+		c.RemoteAddr = raddr
+	}
+	return c, nil
+}
+
+func newV6Client(cfg *config) (*dhcpv6.Client, error) {
+	c := dhcpv6.NewClient()
+	c.ReadTimeout, c.WriteTimeout = cfg.timeout, cfg.timeout
+	if cfg.server != "" {
+		raddr, err := resolveServer("udp6", cfg.server, dhcpv6.DefaultServerPort)
+		if err != nil {
+			return nil, err
+		}
+		c.RemoteAddr = raddr
+	}
+	return c, nil
+}
+
+func runV4(cfg *config) *result {
+	r := &result{Messages: []jsonMessage{}}
+	mods, err := cfg.options.v4Modifiers()
+	if err != nil {
+		return r.fail(err)
+	}
+	c, err := newV4Client(cfg)
+	if err != nil {
+		return r.fail(err)
+	}
+	conversation, err := c.Exchange(cfg.iface, mods...)
+	r.addV4(conversation)
+	if err != nil {
+		return r.fail(err)
+	}
+	r.OK = true
+	return r
+}
+
+// replyError returns an error if reply carries a status code other than
+// Success, at the top level or in an IA_NA.
+func replyError(reply dhcpv6.DHCPv6) error {
+	statuses := []dhcpv6.Option{reply.GetOneOption(dhcpv6.OptionStatusCode)}
+	for _, opt := range reply.GetOption(dhcpv6.OptionIANA) {
+		if iaNa, ok := opt.(*dhcpv6.OptIANA); ok {
+			statuses = append(statuses, iaNa.GetOneOption(dhcpv6.OptionStatusCode))
+		}
+	}
+	for _, opt := range statuses {
+		if sc, ok := opt.(*dhcpv6.OptStatusCode); ok && sc.StatusCode != iana.StatusSuccess {
+			return fmt.Errorf("%s: %s", sc.StatusCode, sc.StatusMessage)
+		}
+	}
+	return nil
+}
+
+func runV6(cfg *config) *result {
+	r := &result{Messages: []jsonMessage{}}
+	mods, err := cfg.options.v6Modifiers()
+	if err != nil {
+		return r.fail(err)
+	}
+	c, err := newV6Client(cfg)
+	if err != nil {
+		return r.fail(err)
+	}
+	conversation, err := c.Exchange(cfg.iface, mods...)
+	r.addV6(conversation...)
+	if err != nil {
+		return r.fail(err)
+	}
+	if err := replyError(conversation[len(conversation)-1]); err != nil {
+		return r.fail(err)
+	}
+	r.OK = true
+	return r
+}
+
+func runInfo(cfg *config) *result {
+	r := &result{Messages: []jsonMessage{}}
+	mods, err := cfg.options.v6Modifiers()
+	if err != nil {
+		return r.fail(err)
+	}
+	c, err := newV6Client(cfg)
+	if err != nil {
+		return r.fail(err)
+	}
+	request, reply, err := c.InformationRequest(cfg.iface, mods...)
+	r.addV6(request, reply)
+	if err != nil {
+		return r.fail(err)
+	}
+	if err := replyError(reply); err != nil {
+		return r.fail(err)
+	}
+	r.OK = true
+	return r
+}
+
+func runBSDP(cfg *config) *result {
+	r := &result{Messages: []jsonMessage{}}
+	if len(cfg.options) > 0 {
+		return r.fail(errors.New("-opt is not supported with bsdp"))
+	}
+	v4, err := newV4Client(cfg)
+	if err != nil {
+		return r.fail(err)
+	}
+	c := bsdp.NewClient()
+	c.Client = *v4
+	c.Retries = cfg.retries
+	conversation, err := c.Exchange(cfg.iface)
+	for _, p := range conversation {
+		r.Messages = append(r.Messages, v4JSON(&p.DHCPv4, &bsdp.VendorOptions{}))
+		r.summaries = append(r.summaries, p.Summary())
+	}
+	if err != nil {
+		return r.fail(err)
+	}
+	r.OK = true
+	return r
+}
+
+func runNetboot4(cfg *config) *result {
+	r := &result{Messages: []jsonMessage{}}
+	if cfg.server != "" {
+		return r.fail(errors.New("-server is not supported with netboot4"))
+	}
+	mods, err := cfg.options.v4Modifiers()
+	if err != nil {
+		return r.fail(err)
+	}
+	conversation, err := netboot.RequestNetbootv4(cfg.iface, cfg.timeout, cfg.retries, mods...)
+	r.addV4(conversation)
+	if err != nil {
+		return r.fail(err)
+	}
+	if r.NetConf, r.BootFile, err = netboot.ConversationToNetconfv4(conversation); err != nil {
+		return r.fail(err)
+	}
+	r.OK = true
+	return r
+}
+
+func runNetboot6(cfg *config) *result {
+	r := &result{Messages: []jsonMessage{}}
+	if cfg.server != "" {
+		return r.fail(errors.New("-server is not supported with netboot6"))
+	}
+	mods, err := cfg.options.v6Modifiers()
+	if err != nil {
+		return r.fail(err)
+	}
+	conversation, err := netboot.RequestNetbootv6(cfg.iface, cfg.timeout, cfg.retries, mods...)
+	r.addV6(conversation...)
+	if err != nil {
+		return r.fail(err)
+	}
+	if r.NetConf, r.BootFile, err = netboot.ConversationToNetconf(conversation); err != nil {
+		return r.fail(err)
+	}
+	r.OK = true
+	return r
+}
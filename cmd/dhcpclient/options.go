@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+)
+
+// option is an option given on the command line, as code=type:value.
+type option struct {
+	code uint16
+	data []byte
+}
+
+// optionList is a flag.Value collecting the options given with -opt.
+type optionList []option
+
+func (l *optionList) String() string {
+	var s []string
+	for _, o := range *l {
+		s = append(s, fmt.Sprintf("%d=hex:%x", o.code, o.data))
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *optionList) Set(value string) error {
+	o, err := parseOption(value)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, o)
+	return nil
+}
+
+// optionTypes are the value types of parseOption.
+const optionTypes = "hex, str, ip, ips, u8, u16, u32"
+
+// parseOption parses an option given as code=type:value, e.g. 60=str:PXE,
+// 43=hex:0104c0000201 or 6=ips:192.0.2.1,192.0.2.2. A bare code=value is
+// hex.
+func parseOption(s string) (option, error) {
+	codeStr, value, ok := cut(s, "=")
+	if !ok {
+		return option{}, fmt.Errorf("option %q: want code=type:value", s)
+	}
+	code, err := strconv.ParseUint(codeStr, 10, 16)
+	if err != nil {
+		return option{}, fmt.Errorf("option %q: invalid code: %v", s, err)
+	}
+	kind, value, ok := cut(value, ":")
+	if !ok {
+		kind, value = "hex", kind
+	}
+	var data []byte
+	switch kind {
+	case "hex":
+		data, err = hex.DecodeString(strings.Replace(value, ":", "", -1))
+	case "str":
+		data = []byte(value)
+	case "ip", "ips":
+		for _, v := range strings.Split(value, ",") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				return option{}, fmt.Errorf("option %q: invalid IP address %q", s, v)
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			data = append(data, ip...)
+		}
+		if kind == "ip" && strings.Contains(value, ",") {
+			err = fmt.Errorf("several addresses, use ips")
+		}
+	case "u8", "u16", "u32":
+		bits, _ := strconv.Atoi(kind[1:])
+		var n uint64
+		n, err = strconv.ParseUint(value, 0, bits)
+		data = make([]byte, 8)
+		binary.BigEndian.PutUint64(data, n)
+		data = data[8-bits/8:]
+	default:
+		return option{}, fmt.Errorf("option %q: unknown type %q, want one of %s", s, kind, optionTypes)
+	}
+	if err != nil {
+		return option{}, fmt.Errorf("option %q: %v", s, err)
+	}
+	return option{code: uint16(code), data: data}, nil
+}
+
+// cut slices s around the first sep.
+func cut(s, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// v4Modifiers returns modifiers adding the options to DHCPv4 messages.
+func (l optionList) v4Modifiers() ([]dhcpv4.Modifier, error) {
+	var mods []dhcpv4.Modifier
+	for _, o := range l {
+		if o.code == 0 || o.code >= 255 {
+			return nil, fmt.Errorf("invalid DHCPv4 option code %d", o.code)
+		}
+		mods = append(mods, dhcpv4.WithGeneric(dhcpv4.GenericOptionCode(o.code), o.data))
+	}
+	return mods, nil
+}
+
+// v6Modifiers returns modifiers adding the options to DHCPv6 messages.
+func (l optionList) v6Modifiers() ([]dhcpv6.Modifier, error) {
+	var mods []dhcpv6.Modifier
+	for _, o := range l {
+		if o.code == 0 {
+			return nil, fmt.Errorf("invalid DHCPv6 option code %d", o.code)
+		}
+		opt := &dhcpv6.OptionGeneric{OptionCode: dhcpv6.OptionCode(o.code), OptionData: o.data}
+		mods = append(mods, func(d dhcpv6.DHCPv6) dhcpv6.DHCPv6 {
+			d.UpdateOption(opt)
+			return d
+		})
+	}
+	return mods, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOption(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want option
+	}{
+		{"43=hex:0104c0000201", option{43, []byte{1, 4, 192, 0, 2, 1}}},
+		{"43=0104", option{43, []byte{1, 4}}},
+		{"43=hex:01:04", option{43, []byte{1, 4}}},
+		{"60=str:PXEClient", option{60, []byte("PXEClient")}},
+		{"60=str:a=b:c", option{60, []byte("a=b:c")}},
+		{"54=ip:192.0.2.1", option{54, []byte{192, 0, 2, 1}}},
+		{"6=ips:192.0.2.1,192.0.2.2", option{6, []byte{192, 0, 2, 1, 192, 0, 2, 2}}},
+		{"23=ip:2001:db8::1", option{23, net.ParseIP("2001:db8::1")}},
+		{"19=u8:1", option{19, []byte{1}}},
+		{"93=u16:7", option{93, []byte{0, 7}}},
+		{"51=u32:0x100", option{51, []byte{0, 0, 1, 0}}},
+		{"1000=str:x", option{1000, []byte("x")}},
+	} {
+		got, err := parseOption(tt.in)
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{
+		"43",
+		"x=hex:01",
+		"70000=hex:01",
+		"43=hex:0",
+		"54=ip:192.0.2",
+		"54=ip:192.0.2.1,192.0.2.2",
+		"19=u8:256",
+		"19=u8:-1",
+		"60=float:1.5",
+	} {
+		_, err := parseOption(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestOptionList(t *testing.T) {
+	var l optionList
+	require.NoError(t, l.Set("60=str:PXE"))
+	require.NoError(t, l.Set("93=u16:7"))
+	require.Error(t, l.Set("93"))
+	require.Len(t, l, 2)
+	require.Equal(t, "60=hex:505845,93=hex:0007", l.String())
+
+	v4mods, err := l.v4Modifiers()
+	require.NoError(t, err)
+	m, err := dhcpv4.New(v4mods...)
+	require.NoError(t, err)
+	require.Equal(t, []byte("PXE"), m.Options.Get(dhcpv4.OptionClassIdentifier))
+	require.Equal(t, []byte{0, 7}, m.Options.Get(dhcpv4.OptionClientSystemArchitectureType))
+
+	v6mods, err := l.v6Modifiers()
+	require.NoError(t, err)
+	d, err := dhcpv6.NewMessage(v6mods...)
+	require.NoError(t, err)
+	require.Equal(t, &dhcpv6.OptionGeneric{OptionCode: 93, OptionData: []byte{0, 7}}, d.GetOneOption(93))
+
+	_, err = optionList{{code: 255}}.v4Modifiers()
+	require.Error(t, err)
+	_, err = optionList{{code: 0}}.v6Modifiers()
+	require.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/netboot"
+)
+
+// jsonOption is the JSON form of an option: its code and name, its value
+// as printed by Summary, and its raw data.
+type jsonOption struct {
+	Code  uint16 `json:"code"`
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	Data  string `json:"data"`
+}
+
+// jsonMessage is the JSON form of a DHCPv4 or DHCPv6 message.
+type jsonMessage struct {
+	Type          string `json:"type"`
+	TransactionID string `json:"xid,omitempty"`
+	// DHCPv4 header
+	OpCode         string `json:"opcode,omitempty"`
+	Flags          string `json:"flags,omitempty"`
+	ClientIPAddr   net.IP `json:"ciaddr,omitempty"`
+	YourIPAddr     net.IP `json:"yiaddr,omitempty"`
+	ServerIPAddr   net.IP `json:"siaddr,omitempty"`
+	GatewayIPAddr  net.IP `json:"giaddr,omitempty"`
+	ClientHWAddr   string `json:"chaddr,omitempty"`
+	ServerHostName string `json:"sname,omitempty"`
+	BootFileName   string `json:"file,omitempty"`
+	// DHCPv6 relay header
+	HopCount *uint8       `json:"hop_count,omitempty"`
+	LinkAddr net.IP       `json:"link_addr,omitempty"`
+	PeerAddr net.IP       `json:"peer_addr,omitempty"`
+	Options  []jsonOption `json:"options"`
+}
+
+// result is the outcome of a run, and its JSON output.
+type result struct {
+	Mode     string           `json:"mode"`
+	Iface    string           `json:"interface"`
+	OK       bool             `json:"ok"`
+	Error    string           `json:"error,omitempty"`
+	Messages []jsonMessage    `json:"messages"`
+	NetConf  *netboot.NetConf `json:"netconf,omitempty"`
+	BootFile string           `json:"boot_file,omitempty"`
+
+	// summaries are the summaries of the messages, for the text output.
+	summaries []string
+}
+
+// v4JSON returns the JSON form of m, decoding the vendor-specific option
+// with vendorDecoder if not nil.
+func v4JSON(m *dhcpv4.DHCPv4, vendorDecoder dhcpv4.OptionDecoder) jsonMessage {
+	j := jsonMessage{
+		Type:           m.MessageType().String(),
+		TransactionID:  m.TransactionID.String(),
+		OpCode:         m.OpCode.String(),
+		Flags:          m.FlagsToString(),
+		ClientIPAddr:   m.ClientIPAddr,
+		YourIPAddr:     m.YourIPAddr,
+		ServerIPAddr:   m.ServerIPAddr,
+		GatewayIPAddr:  m.GatewayIPAddr,
+		ClientHWAddr:   m.ClientHWAddr.String(),
+		ServerHostName: m.ServerHostName,
+		BootFileName:   m.BootFileName,
+		Options:        []jsonOption{},
+	}
+	codes := make([]int, 0, len(m.Options))
+	for code := range m.Options {
+		codes = append(codes, int(code))
+	}
+	sort.Ints(codes)
+	for _, c := range codes {
+		code := uint8(c)
+		data := m.Options[code]
+		// The summary of a single option is "    name: value\n".
+		summary := dhcpv4.Options{code: data}.Summary(vendorDecoder)
+		name, value, _ := cut(strings.TrimSpace(summary), ": ")
+		j.Options = append(j.Options, jsonOption{
+			Code:  uint16(code),
+			Name:  name,
+			Value: value,
+			Data:  hex.EncodeToString(data),
+		})
+	}
+	return j
+}
+
+// v6JSON returns the JSON form of m.
+func v6JSON(m dhcpv6.DHCPv6) jsonMessage {
+	j := jsonMessage{
+		Type:    m.Type().String(),
+		Options: []jsonOption{},
+	}
+	switch msg := m.(type) {
+	case *dhcpv6.DHCPv6Message:
+		j.TransactionID = fmt.Sprintf("0x%06x", msg.TransactionID())
+	case *dhcpv6.DHCPv6Relay:
+		hopCount := msg.HopCount()
+		j.HopCount = &hopCount
+		j.LinkAddr = msg.LinkAddr()
+		j.PeerAddr = msg.PeerAddr()
+	}
+	for _, o := range m.Options() {
+		name, ok := dhcpv6.OptionCodeToString[o.Code()]
+		if !ok {
+			name = fmt.Sprintf("unknown (%d)", o.Code())
+		}
+		// ToBytes includes the code and length.
+		data := o.ToBytes()
+		if len(data) >= 4 {
+			data = data[4:]
+		}
+		j.Options = append(j.Options, jsonOption{
+			Code:  uint16(o.Code()),
+			Name:  name,
+			Value: o.String(),
+			Data:  hex.EncodeToString(data),
+		})
+	}
+	return j
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/bsdp"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/insomniacslk/dhcp/netboot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV4JSON(t *testing.T) {
+	m, err := dhcpv4.NewDiscovery(net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		dhcpv4.WithTransactionID(dhcpv4.TransactionID{1, 2, 3, 4}))
+	require.NoError(t, err)
+	j := v4JSON(m, nil)
+	require.Equal(t, "DISCOVER", j.Type)
+	require.Equal(t, "0x01020304", j.TransactionID)
+	require.Equal(t, "BootRequest", j.OpCode)
+	require.Equal(t, "00:01:02:03:04:05", j.ClientHWAddr)
+	require.Equal(t, jsonOption{Code: 53, Name: "DHCP Message Type", Value: "DISCOVER", Data: "01"}, j.Options[0])
+	// sorted by code
+	for i := 1; i < len(j.Options); i++ {
+		require.True(t, j.Options[i-1].Code < j.Options[i].Code)
+	}
+
+	// BSDP vendor-specific options are decoded
+	list, err := bsdp.NewInformList(net.HardwareAddr{0, 1, 2, 3, 4, 5}, net.IPv4(192, 0, 2, 10), 0)
+	require.NoError(t, err)
+	j = v4JSON(&list.DHCPv4, &bsdp.VendorOptions{})
+	for _, o := range j.Options {
+		if o.Code == uint16(dhcpv4.OptionVendorSpecificInformation.Code()) {
+			require.Contains(t, o.Value, "BSDP Message Type")
+		}
+	}
+}
+
+func TestV6JSON(t *testing.T) {
+	duid := dhcpv6.Duid{Type: dhcpv6.DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5}}
+	m, err := dhcpv6.NewSolicitWithCID(duid)
+	require.NoError(t, err)
+	m.(*dhcpv6.DHCPv6Message).SetTransactionID(0xabcdef)
+	j := v6JSON(m)
+	require.Equal(t, "SOLICIT", j.Type)
+	require.Equal(t, "0xabcdef", j.TransactionID)
+	require.Equal(t, "OPTION_CLIENTID", j.Options[0].Name)
+	require.Equal(t, "00030001000102030405", j.Options[0].Data)
+
+	relay, err := dhcpv6.EncapsulateRelay(m, dhcpv6.MessageTypeRelayForward, net.IPv6loopback, net.IPv6loopback)
+	require.NoError(t, err)
+	j = v6JSON(relay)
+	require.Equal(t, "RELAY-FORW", j.Type)
+	require.Equal(t, uint8(0), *j.HopCount)
+	require.Equal(t, net.IPv6loopback, j.PeerAddr)
+}
+
+func TestReplyError(t *testing.T) {
+	reply := &dhcpv6.DHCPv6Message{}
+	reply.SetMessage(dhcpv6.MessageTypeReply)
+	require.NoError(t, replyError(reply))
+
+	iaNa := &dhcpv6.OptIANA{}
+	iaNa.AddOption(&dhcpv6.OptStatusCode{StatusCode: iana.StatusNoAddrsAvail, StatusMessage: []byte("pool exhausted")})
+	reply.AddOption(iaNa)
+	require.EqualError(t, replyError(reply), "NoAddrsAvail: pool exhausted")
+}
+
+func TestPrintSummary(t *testing.T) {
+	var buf bytes.Buffer
+	r := &result{
+		summaries: []string{"first", "second"},
+		NetConf: &netboot.NetConf{
+			Addresses: []netboot.AddrConf{{IPNet: net.IPNet{IP: net.IPv4(192, 0, 2, 10), Mask: net.CIDRMask(24, 32)}}},
+		},
+		BootFile: "http://192.0.2.1/boot.efi",
+	}
+	printSummary(&buf, r)
+	out := buf.String()
+	require.Contains(t, out, "first\nsecond\n")
+	require.Contains(t, out, "address: 192.0.2.10/24")
+	require.Contains(t, out, "boot file: http://192.0.2.1/boot.efi")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcppcap"
+	"golang.org/x/sys/unix"
+)
+
+// liveSource reads the Ethernet frames of an interface from an AF_PACKET
+// socket.
+type liveSource struct {
+	fd  int
+	buf []byte
+	// loopback is set on loopback interfaces, where every packet is
+	// captured both as outgoing and incoming.
+	loopback bool
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+// openLive starts capturing the frames of ifname, in both directions.
+func openLive(ifname string) (source, error) {
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, err
+	}
+	proto := htons(unix.ETH_P_ALL)
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, int(proto))
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Bind(fd, &unix.SockaddrLinklayer{Protocol: proto, Ifindex: iface.Index}); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return &liveSource{
+		fd:       fd,
+		buf:      make([]byte, 1<<16),
+		loopback: iface.Flags&net.FlagLoopback != 0,
+	}, nil
+}
+
+func (l *liveSource) NextFrame() (*dhcppcap.Frame, error) {
+	for {
+		n, from, err := unix.Recvfrom(l.fd, l.buf, 0)
+		if err != nil {
+			return nil, err
+		}
+		if sa, ok := from.(*unix.SockaddrLinklayer); ok && l.loopback && sa.Pkttype == unix.PACKET_OUTGOING {
+			continue
+		}
+		data := make([]byte, n)
+		copy(data, l.buf[:n])
+		return &dhcppcap.Frame{Time: time.Now(), LinkType: dhcppcap.LinkTypeEthernet, Data: data}, nil
+	}
+}
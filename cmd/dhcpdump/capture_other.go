@@ -0,0 +1,9 @@
+// +build !linux
+
+package main
+
+import "errors"
+
+func openLive(ifname string) (source, error) {
+	return nil, errors.New("live capture is only supported on Linux, read a pcap file with -r")
+}
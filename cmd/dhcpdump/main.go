@@ -0,0 +1,205 @@
+// dhcpdump prints the DHCPv4 and DHCPv6 messages captured on an interface or
+// read from a pcap or pcapng file.
+//
+// Each message is decoded with FromBytes, with the relay chain of relayed
+// DHCPv6 messages, and the BSDP vendor-specific options of Apple clients.
+// Messages are correlated into exchanges by transaction ID, and each one is
+// printed with the time since the previous message of its exchange, e.g.
+//
+//	2018-02-01 10:00:00.000000 0.0.0.0:68 > 255.255.255.255:67 DHCPv4 DISCOVER xid 0x01020304 hwaddr 00:01:02:03:04:05 exchange 1 +0s
+//	2018-02-01 10:00:00.002134 192.0.2.1:67 > 255.255.255.255:68 DHCPv4 OFFER xid 0x01020304 hwaddr 00:01:02:03:04:05 exchange 1 +2.134ms
+//
+// followed by the summary of the message unless -q is given. For example:
+//
+//	dhcpdump -i eth0 -q
+//	dhcpdump -r capture.pcap -mac 00:01:02:03:04:05 -type ACK
+//
+// Live capture uses an AF_PACKET socket, and needs root or CAP_NET_RAW.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcppcap"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/bsdp"
+)
+
+// source is a source of frames: a capture, or a pcap or pcapng file.
+type source interface {
+	NextFrame() (*dhcppcap.Frame, error)
+}
+
+// Vendor decoders, see -vendor.
+const (
+	vendorAuto = "auto"
+	vendorBSDP = "bsdp"
+	vendorNone = "none"
+)
+
+// printer prints messages.
+type printer struct {
+	w      io.Writer
+	vendor string
+	quiet  bool
+}
+
+func (p *printer) vendorDecoder(m *dhcpv4.DHCPv4) dhcpv4.OptionDecoder {
+	switch p.vendor {
+	case vendorBSDP:
+		return &bsdp.VendorOptions{}
+	case vendorAuto:
+		if strings.HasPrefix(m.ClassIdentifier(), bsdp.AppleVendorID) {
+			return &bsdp.VendorOptions{}
+		}
+	}
+	return nil
+}
+
+func (p *printer) print(m *message, ex *exchange, sincePrevious time.Duration) {
+	proto := "DHCPv4"
+	if m.isV6() {
+		proto = "DHCPv6"
+	}
+	xid := fmt.Sprintf("0x%08x", m.xid())
+	if m.isV6() {
+		xid = fmt.Sprintf("0x%06x", m.xid())
+	}
+	line := fmt.Sprintf("%s %s > %s %s %s xid %s", m.time.Format("2006-01-02 15:04:05.000000"), m.src, m.dst, proto, m.typ(), xid)
+	if hw := m.hwAddr(); hw != nil {
+		line += fmt.Sprintf(" hwaddr %s", hw)
+	}
+	fmt.Fprintf(p.w, "%s exchange %d +%v\n", line, ex.id, sincePrevious)
+	if chain := m.relayChain(); chain != "" {
+		fmt.Fprintf(p.w, "  relayed: %s\n", chain)
+	}
+	if p.quiet {
+		return
+	}
+	if m.isV6() {
+		fmt.Fprintln(p.w, m.inner.Summary())
+	} else {
+		fmt.Fprintln(p.w, m.v4.SummaryWithVendor(p.vendorDecoder(m.v4)))
+	}
+}
+
+// dump prints the messages of src which match f, up to count if not zero.
+func dump(src source, f *filter, t *tracker, p *printer, count int) error {
+	printed := 0
+	for count == 0 || printed < count {
+		frame, err := src.NextFrame()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		packet, err := frame.Decode()
+		if err == dhcppcap.ErrNotDHCP {
+			continue
+		}
+		if err != nil {
+			log.Printf("%s: skipping frame: %v", frame.Time.Format(time.RFC3339Nano), err)
+			continue
+		}
+		m, err := decode(packet)
+		if err != nil {
+			log.Printf("%s: %s > %s: cannot decode DHCP message: %v", frame.Time.Format(time.RFC3339Nano), packet.Src, packet.Dst, err)
+			continue
+		}
+		ex, sincePrevious := t.track(m)
+		if !f.match(m) {
+			continue
+		}
+		p.print(m, ex, sincePrevious)
+		printed++
+	}
+	return nil
+}
+
+// parseXID parses a transaction ID in hexadecimal, with or without 0x.
+func parseXID(s string) (uint32, error) {
+	xid, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(s), "0x"), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transaction ID %q", s)
+	}
+	return uint32(xid), nil
+}
+
+func main() {
+	var (
+		iface   = flag.String("i", "", "network interface to capture on")
+		file    = flag.String("r", "", "pcap or pcapng file to read instead of capturing, - for stdin")
+		mac     = flag.String("mac", "", "only print the messages of the client with this hardware address")
+		xid     = flag.String("xid", "", "only print the messages with this transaction ID, in hexadecimal")
+		msgType = flag.String("type", "", "only print the messages of this type, e.g. DISCOVER, ACK or SOLICIT")
+		vendor  = flag.String("vendor", vendorAuto, "decoder of the DHCPv4 vendor-specific option: auto (BSDP for Apple clients), bsdp or none")
+		quiet   = flag.Bool("q", false, "print one line per message, without its summary")
+		count   = flag.Int("c", 0, "exit after printing this many messages, 0 for no limit")
+		window  = flag.Duration("window", time.Minute, "time after which a transaction ID starts a new exchange")
+	)
+	flag.Parse()
+	if (*iface == "") == (*file == "") || flag.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "dhcpdump: exactly one of -i and -r is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*iface, *file, *mac, *xid, *msgType, *vendor, *quiet, *count, *window); err != nil {
+		fmt.Fprintf(os.Stderr, "dhcpdump: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(iface, file, mac, xid, msgType, vendor string, quiet bool, count int, window time.Duration) error {
+	f := &filter{msgType: msgType}
+	if mac != "" {
+		hw, err := net.ParseMAC(mac)
+		if err != nil {
+			return err
+		}
+		f.hwAddr = hw
+	}
+	if xid != "" {
+		x, err := parseXID(xid)
+		if err != nil {
+			return err
+		}
+		f.xid = &x
+	}
+	switch vendor {
+	case vendorAuto, vendorBSDP, vendorNone:
+	default:
+		return fmt.Errorf("unknown vendor decoder %q", vendor)
+	}
+
+	var (
+		src source
+		err error
+	)
+	switch {
+	case file == "-":
+		src, err = dhcppcap.NewReader(os.Stdin)
+	case file != "":
+		var fd *os.File
+		if fd, err = os.Open(file); err != nil {
+			return err
+		}
+		defer fd.Close()
+		src, err = dhcppcap.NewReader(fd)
+	default:
+		src, err = openLive(iface)
+	}
+	if err != nil {
+		return err
+	}
+	p := &printer{w: os.Stdout, vendor: vendor, quiet: quiet}
+	return dump(src, f, newTracker(window), p, count)
+}
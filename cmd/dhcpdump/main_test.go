@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcppcap"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/bsdp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	discover, err := dhcpv4.NewDiscovery(testHWAddr, dhcpv4.WithTransactionID(dhcpv4.TransactionID{1, 2, 3, 4}))
+	require.NoError(t, err)
+	offer, err := dhcpv4.NewReplyFromRequest(discover, dhcpv4.WithMessageType(dhcpv4.MessageTypeOffer))
+	require.NoError(t, err)
+	inform, err := bsdp.NewInformList(testHWAddr, net.IPv4(192, 0, 2, 10), 0)
+	require.NoError(t, err)
+	server := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1).To4(), Port: dhcpv4.ServerPort}
+	dns := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 53).To4(), Port: 53}
+
+	start := time.Unix(1500000000, 0)
+	var buf bytes.Buffer
+	w, err := dhcppcap.NewWriter(&buf)
+	require.NoError(t, err)
+	for _, p := range []*dhcppcap.Packet{
+		{Time: start, Src: v4Client, Dst: v4Server, DHCPv4: discover},
+		{Time: start.Add(time.Millisecond), Src: dns, Dst: dns, DHCPv4: discover},
+		{Time: start.Add(3 * time.Millisecond), Src: server, Dst: v4Client, DHCPv4: offer},
+		{Time: start.Add(4 * time.Millisecond), Src: v6Client, Dst: v6Server, DHCPv6: v6Solicit(t, 0x123456)},
+		{Time: start.Add(5 * time.Millisecond), Src: v4Client, Dst: server, DHCPv4: &inform.DHCPv4},
+	} {
+		require.NoError(t, w.WritePacket(p))
+	}
+	file := buf.Bytes()
+
+	dumpFile := func(f *filter, quiet bool, count int) []string {
+		r, err := dhcppcap.NewReader(bytes.NewReader(file))
+		require.NoError(t, err)
+		var out bytes.Buffer
+		p := &printer{w: &out, vendor: vendorAuto, quiet: quiet}
+		require.NoError(t, dump(r, f, newTracker(time.Minute), p, count))
+		return strings.Split(strings.TrimSpace(out.String()), "\n")
+	}
+
+	lines := dumpFile(&filter{}, true, 0)
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "0.0.0.0:68 > 255.255.255.255:67 DHCPv4 DISCOVER xid 0x01020304 hwaddr 00:01:02:03:04:05 exchange 1 +0s")
+	require.Contains(t, lines[1], "DHCPv4 OFFER xid 0x01020304 hwaddr 00:01:02:03:04:05 exchange 1 +3ms")
+	require.Contains(t, lines[2], "DHCPv6 SOLICIT xid 0x123456 hwaddr 00:01:02:03:04:05 exchange 2 +0s")
+	require.Contains(t, lines[3], "DHCPv4 INFORM")
+
+	// the timing is relative to the filtered out messages too
+	lines = dumpFile(&filter{msgType: "offer"}, true, 0)
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], "OFFER xid 0x01020304 hwaddr 00:01:02:03:04:05 exchange 1 +3ms")
+
+	require.Len(t, dumpFile(&filter{}, true, 2), 2)
+
+	// BSDP vendor-specific options are decoded
+	lines = dumpFile(&filter{msgType: "INFORM"}, false, 0)
+	require.Contains(t, strings.Join(lines, "\n"), "BSDP Message Type: LIST")
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcppcap"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+)
+
+// message is a decoded DHCPv4 or DHCPv6 message.
+type message struct {
+	time     time.Time
+	src, dst *net.UDPAddr
+	v4       *dhcpv4.DHCPv4
+	// v6 is the DHCPv6 message as captured, and inner the message in its
+	// relay chain, if any.
+	v6, inner dhcpv6.DHCPv6
+	relays    []*dhcpv6.DHCPv6Relay
+}
+
+// decode returns the message of p, decapsulating relayed DHCPv6 messages.
+func decode(p *dhcppcap.Packet) (*message, error) {
+	m := &message{time: p.Time, src: p.Src, dst: p.Dst, v4: p.DHCPv4}
+	if p.DHCPv6 == nil {
+		return m, nil
+	}
+	m.v6, m.inner = p.DHCPv6, p.DHCPv6
+	var err error
+	for m.inner.IsRelay() {
+		m.relays = append(m.relays, m.inner.(*dhcpv6.DHCPv6Relay))
+		if m.inner, err = dhcpv6.DecapsulateRelay(m.inner); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *message) isV6() bool {
+	return m.v6 != nil
+}
+
+// xid returns the transaction ID of m.
+func (m *message) xid() uint32 {
+	if m.isV6() {
+		if msg, ok := m.inner.(*dhcpv6.DHCPv6Message); ok {
+			return msg.TransactionID()
+		}
+		return 0
+	}
+	return binary.BigEndian.Uint32(m.v4.TransactionID[:])
+}
+
+// typ returns the message type of m, e.g. DISCOVER or SOLICIT.
+func (m *message) typ() string {
+	if m.isV6() {
+		return m.inner.Type().String()
+	}
+	return m.v4.MessageType().String()
+}
+
+// hwAddr returns the hardware address of the client of m: its CHADDR, or the
+// link-layer address of the DUID of its client ID.
+func (m *message) hwAddr() net.HardwareAddr {
+	if !m.isV6() {
+		return m.v4.ClientHWAddr
+	}
+	if cid, ok := m.inner.GetOneOption(dhcpv6.OptionClientID).(*dhcpv6.OptClientId); ok {
+		return cid.Cid.LinkLayerAddr
+	}
+	return nil
+}
+
+// relayChain describes how m was relayed, or returns an empty string.
+func (m *message) relayChain() string {
+	if !m.isV6() {
+		if gw := m.v4.GatewayIPAddr; gw != nil && !gw.IsUnspecified() {
+			return fmt.Sprintf("relay agent %s, hops %d", gw, m.v4.HopCount)
+		}
+		return ""
+	}
+	var chain []string
+	for _, r := range m.relays {
+		chain = append(chain, fmt.Sprintf("%s(hops=%d link=%s peer=%s)", r.Type(), r.HopCount(), r.LinkAddr(), r.PeerAddr()))
+	}
+	if len(chain) == 0 {
+		return ""
+	}
+	return strings.Join(append(chain, m.inner.Type().String()), " > ")
+}
+
+// filter selects the messages to print.
+type filter struct {
+	hwAddr  net.HardwareAddr
+	xid     *uint32
+	msgType string
+}
+
+func (f *filter) match(m *message) bool {
+	if f.hwAddr != nil && m.hwAddr().String() != f.hwAddr.String() {
+		return false
+	}
+	if f.xid != nil && m.xid() != *f.xid {
+		return false
+	}
+	if f.msgType != "" && !strings.EqualFold(f.msgType, m.typ()) {
+		return false
+	}
+	return true
+}
+
+// exchange is a series of messages with the same transaction ID.
+type exchange struct {
+	id          int
+	first, last time.Time
+}
+
+type exchangeKey struct {
+	v6  bool
+	xid uint32
+}
+
+// tracker correlates messages into exchanges by transaction ID. A message
+// starts a new exchange if the last one with its transaction ID is older than
+// window.
+type tracker struct {
+	window    time.Duration
+	exchanges map[exchangeKey]*exchange
+	next      int
+	tracked   int
+}
+
+func newTracker(window time.Duration) *tracker {
+	return &tracker{window: window, exchanges: make(map[exchangeKey]*exchange)}
+}
+
+// track adds m to its exchange, and returns the exchange and the time since
+// the previous message of the exchange, zero for the first one.
+func (t *tracker) track(m *message) (*exchange, time.Duration) {
+	t.tracked++
+	if t.tracked%1000 == 0 {
+		t.expire(m.time)
+	}
+	key := exchangeKey{v6: m.isV6(), xid: m.xid()}
+	ex, ok := t.exchanges[key]
+	if !ok || m.time.Sub(ex.last) > t.window {
+		t.next++
+		ex = &exchange{id: t.next, first: m.time, last: m.time}
+		t.exchanges[key] = ex
+	}
+	sincePrevious := m.time.Sub(ex.last)
+	ex.last = m.time
+	return ex, sincePrevious
+}
+
+// expire forgets the exchanges that ended more than window before now.
+func (t *tracker) expire(now time.Time) {
+	for key, ex := range t.exchanges {
+		if now.Sub(ex.last) > t.window {
+			delete(t.exchanges, key)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcppcap"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testHWAddr = net.HardwareAddr{0, 1, 2, 3, 4, 5}
+	v4Client   = &net.UDPAddr{IP: net.IPv4zero.To4(), Port: dhcpv4.ClientPort}
+	v4Server   = &net.UDPAddr{IP: net.IPv4bcast.To4(), Port: dhcpv4.ServerPort}
+	v6Client   = &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: dhcpv6.DefaultClientPort}
+	v6Server   = &net.UDPAddr{IP: net.ParseIP("ff02::1:2"), Port: dhcpv6.DefaultServerPort}
+)
+
+func v4Message(t *testing.T, modifiers ...dhcpv4.Modifier) *message {
+	discover, err := dhcpv4.NewDiscovery(testHWAddr, modifiers...)
+	require.NoError(t, err)
+	m, err := decode(&dhcppcap.Packet{Time: time.Now(), Src: v4Client, Dst: v4Server, DHCPv4: discover})
+	require.NoError(t, err)
+	return m
+}
+
+func v6Solicit(t *testing.T, xid uint32) dhcpv6.DHCPv6 {
+	duid := dhcpv6.Duid{Type: dhcpv6.DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: testHWAddr}
+	solicit, err := dhcpv6.NewSolicitWithCID(duid)
+	require.NoError(t, err)
+	solicit.(*dhcpv6.DHCPv6Message).SetTransactionID(xid)
+	return solicit
+}
+
+func TestDecodeV4(t *testing.T) {
+	m := v4Message(t, dhcpv4.WithTransactionID(dhcpv4.TransactionID{1, 2, 3, 4}))
+	require.False(t, m.isV6())
+	require.Equal(t, uint32(0x01020304), m.xid())
+	require.Equal(t, "DISCOVER", m.typ())
+	require.Equal(t, testHWAddr, m.hwAddr())
+	require.Equal(t, "", m.relayChain())
+
+	m = v4Message(t, dhcpv4.WithRelay(net.IPv4(192, 0, 2, 1).To4()))
+	require.Equal(t, "relay agent 192.0.2.1, hops 1", m.relayChain())
+}
+
+func TestDecodeV6Relayed(t *testing.T) {
+	solicit := v6Solicit(t, 0xabcdef)
+	relayed, err := dhcpv6.EncapsulateRelay(solicit, dhcpv6.MessageTypeRelayForward, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+	relayed, err = dhcpv6.EncapsulateRelay(relayed, dhcpv6.MessageTypeRelayForward, net.ParseIP("2001:db8:1::1"), net.ParseIP("2001:db8::1"))
+	require.NoError(t, err)
+
+	m, err := decode(&dhcppcap.Packet{Time: time.Now(), Src: v6Client, Dst: v6Server, DHCPv6: relayed})
+	require.NoError(t, err)
+	require.True(t, m.isV6())
+	require.Len(t, m.relays, 2)
+	require.Equal(t, uint32(0xabcdef), m.xid())
+	require.Equal(t, "SOLICIT", m.typ())
+	require.Equal(t, testHWAddr, m.hwAddr())
+	require.Equal(t,
+		"RELAY-FORW(hops=1 link=2001:db8:1::1 peer=2001:db8::1) > RELAY-FORW(hops=0 link=2001:db8::1 peer=fe80::1) > SOLICIT",
+		m.relayChain())
+}
+
+func TestFilter(t *testing.T) {
+	m := v4Message(t, dhcpv4.WithTransactionID(dhcpv4.TransactionID{1, 2, 3, 4}))
+	require.True(t, (&filter{}).match(m))
+	require.True(t, (&filter{hwAddr: testHWAddr, msgType: "discover"}).match(m))
+	require.False(t, (&filter{hwAddr: net.HardwareAddr{0, 1, 2, 3, 4, 6}}).match(m))
+	require.False(t, (&filter{msgType: "OFFER"}).match(m))
+	xid := uint32(0x01020304)
+	require.True(t, (&filter{xid: &xid}).match(m))
+	xid++
+	require.False(t, (&filter{xid: &xid}).match(m))
+}
+
+func TestTracker(t *testing.T) {
+	tr := newTracker(time.Second)
+	at := func(m *message, d time.Duration) *message {
+		c := *m
+		c.time = time.Unix(0, 0).Add(d)
+		return &c
+	}
+	a := v4Message(t, dhcpv4.WithTransactionID(dhcpv4.TransactionID{1, 2, 3, 4}))
+	b := v4Message(t, dhcpv4.WithTransactionID(dhcpv4.TransactionID{5, 6, 7, 8}))
+
+	ex, since := tr.track(at(a, 0))
+	require.Equal(t, 1, ex.id)
+	require.Equal(t, time.Duration(0), since)
+	ex, _ = tr.track(at(b, 10*time.Millisecond))
+	require.Equal(t, 2, ex.id)
+	ex, since = tr.track(at(a, 30*time.Millisecond))
+	require.Equal(t, 1, ex.id)
+	require.Equal(t, 30*time.Millisecond, since)
+
+	// after the window, the transaction ID starts a new exchange
+	ex, since = tr.track(at(a, 2*time.Second))
+	require.Equal(t, 3, ex.id)
+	require.Equal(t, time.Duration(0), since)
+
+	tr.expire(time.Unix(0, 0).Add(2 * time.Second))
+	require.Len(t, tr.exchanges, 1)
+}
+
+func TestParseXID(t *testing.T) {
+	xid, err := parseXID("0x01020304")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x01020304), xid)
+	xid, err = parseXID("ABCDEF")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0xabcdef), xid)
+	_, err = parseXID("0x1234567890")
+	require.Error(t, err)
+	_, err = parseXID("xyz")
+	require.Error(t, err)
+}
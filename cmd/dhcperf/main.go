@@ -0,0 +1,283 @@
+// dhcperf is a load generator for DHCPv4 and DHCPv6 servers.
+//
+// It simulates many clients, each with its own synthetic MAC address or
+// DUID, which run full DORA (DHCPv4) or SARR (DHCPv6) exchanges at a target
+// rate. At the end it reports the latency percentiles of the exchanges and
+// of each of their round trips, and how many of them were dropped, refused
+// with a NAK or NoAddrsAvail, or failed.
+//
+// With -serve, dhcperf runs its own server, built on dhcpv4.Server or
+// dhcpv6.Server, on a loopback address and benchmarks it, e.g.
+//
+//	dhcperf -serve -n 10000 -rate 500
+//	dhcperf -6 -serve -pool 100 -clients 200
+//
+// Against a real server, run it as root, e.g.
+//
+//	dhcperf -iface eth1 -clients 5000 -n 20000 -rate 200
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	v4async "github.com/insomniacslk/dhcp/dhcpv4/async"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	v6async "github.com/insomniacslk/dhcp/dhcpv6/async"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+var (
+	flagV6          = flag.Bool("6", false, "run SARR exchanges with DHCPv6 instead of DORA exchanges with DHCPv4")
+	flagServer      = flag.String("server", "", "server address; with -serve, the address to serve on (default: broadcast or multicast, or loopback with -serve)")
+	flagLocal       = flag.String("local", "", "local address of the clients (default: client port of all addresses, or loopback with -serve)")
+	flagIface       = flag.String("iface", "", "network interface to run the clients on")
+	flagClients     = flag.Int("clients", 1000, "number of distinct clients")
+	flagN           = flag.Int("n", 10000, "number of exchanges")
+	flagRate        = flag.Float64("rate", 100, "exchanges started per second, 0 for as many as -max-inflight allows")
+	flagTimeout     = flag.Duration("timeout", time.Second, "time to wait for each reply")
+	flagMaxInflight = flag.Int("max-inflight", 256, "maximum number of exchanges in flight")
+	flagReadBuffer  = flag.Int("read-buffer", 1<<22, "size of the socket receive buffer, 0 for the system default")
+	flagServe       = flag.Bool("serve", false, "run a server in process and benchmark it")
+	flagPool        = flag.Int("pool", 0, "with -serve, number of addresses to lease, 0 for all of 198.18.0.0/15 or 2001:db8::/64")
+)
+
+// exchangeFunc runs an exchange for client i.
+type exchangeFunc func(ctx context.Context, i int) result
+
+// failed returns r ended by err, a drop if no reply arrived.
+func failed(r result, err error) result {
+	switch err {
+	case v4async.ErrNoResponse, v6async.ErrNoResponse:
+		r.outcome = outcomeDropped
+	default:
+		r.outcome = outcomeError
+		r.err = err
+	}
+	return r
+}
+
+// run starts n exchanges at rate per second, with at most inflight of them
+// at a time, and records their results in s.
+func run(ctx context.Context, exchange exchangeFunc, s *stats, n, clients int, rate float64, inflight int) {
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(time.Second) / rate)
+	}
+	sem := make(chan struct{}, inflight)
+	var wg sync.WaitGroup
+	start := s.begin()
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		if d := time.Until(start.Add(time.Duration(i) * interval)); d > 0 {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				continue
+			}
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r := exchange(ctx, i%clients)
+			// Exchanges cut short by an interrupt are not reported.
+			if ctx.Err() == nil {
+				s.record(r)
+			}
+		}(i)
+	}
+	wg.Wait()
+	s.finish()
+}
+
+// waitListening waits until the server at addr() listens, and returns its
+// address.
+func waitListening(addr func() net.Addr) (*net.UDPAddr, error) {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if a := addr(); a != nil {
+			return a.(*net.UDPAddr), nil
+		}
+	}
+	return nil, fmt.Errorf("server did not start")
+}
+
+func resolve(network, addr, def string) (*net.UDPAddr, error) {
+	if addr == "" {
+		addr = def
+	}
+	return net.ResolveUDPAddr(network, addr)
+}
+
+func setupV4(s *stats) (exchangeFunc, func(), error) {
+	if *flagServe {
+		laddr, err := resolve("udp4", *flagServer, "127.0.0.1:0")
+		if err != nil {
+			return nil, nil, err
+		}
+		_, prefix, _ := net.ParseCIDR("198.18.0.0/15")
+		server := dhcpv4.NewServer(*laddr, v4Handler(newPool(prefix, *flagPool), laddr.IP, prefix.Mask))
+		go func() {
+			if err := server.ActivateAndServe(); err != nil {
+				fatal(err)
+			}
+		}()
+		raddr, err := waitListening(server.LocalAddr)
+		if err != nil {
+			return nil, nil, err
+		}
+		*flagServer = raddr.String()
+		if *flagLocal == "" {
+			*flagLocal = "127.0.0.1:0"
+		}
+	}
+
+	c := v4async.NewClient()
+	c.ReadTimeout = *flagTimeout
+	c.ReadBufferSize = *flagReadBuffer
+	raddr, err := resolve("udp4", *flagServer, fmt.Sprintf("%s:%d", net.IPv4bcast, dhcpv4.ServerPort))
+	if err != nil {
+		return nil, nil, err
+	}
+	c.RemoteAddr = raddr
+	if *flagLocal != "" {
+		if c.LocalAddr, err = net.ResolveUDPAddr("udp4", *flagLocal); err != nil {
+			return nil, nil, err
+		}
+	}
+	if *flagIface != "" {
+		err = c.OpenForInterface(*flagIface, *flagMaxInflight)
+	} else {
+		if c.LocalAddr == nil {
+			c.LocalAddr = &net.UDPAddr{IP: net.IPv4zero, Port: dhcpv4.ClientPort}
+		}
+		err = c.Open(*flagMaxInflight)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	go func() {
+		for range c.Errors() {
+			s.socketError()
+		}
+	}()
+	exchange := func(ctx context.Context, i int) result {
+		return dora(ctx, c, i)
+	}
+	return exchange, c.Close, nil
+}
+
+func setupV6(s *stats) (exchangeFunc, func(), error) {
+	if *flagServe {
+		laddr, err := resolve("udp6", *flagServer, "[::1]:0")
+		if err != nil {
+			return nil, nil, err
+		}
+		_, prefix, _ := net.ParseCIDR("2001:db8::/64")
+		serverID := dhcpv6.Duid{
+			Type:          dhcpv6.DUID_LL,
+			HwType:        iana.HWTypeEthernet,
+			LinkLayerAddr: net.HardwareAddr{0x02, 0xff, 0, 0, 0, 1},
+		}
+		server := dhcpv6.NewServer(*laddr, v6Handler(newPool(prefix, *flagPool), serverID))
+		go func() {
+			if err := server.ActivateAndServe(); err != nil {
+				fatal(err)
+			}
+		}()
+		raddr, err := waitListening(server.LocalAddr)
+		if err != nil {
+			return nil, nil, err
+		}
+		*flagServer = raddr.String()
+		if *flagLocal == "" {
+			*flagLocal = "[::1]:0"
+		}
+	}
+
+	c := v6async.NewClient()
+	c.ReadTimeout = *flagTimeout
+	c.ReadBufferSize = *flagReadBuffer
+	if *flagServer != "" {
+		raddr, err := net.ResolveUDPAddr("udp6", *flagServer)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.RemoteAddr = raddr
+	}
+	var err error
+	if *flagIface != "" && *flagLocal == "" {
+		err = c.OpenForInterface(*flagIface, *flagMaxInflight)
+	} else {
+		if c.LocalAddr, err = resolve("udp6", *flagLocal, fmt.Sprintf("[::]:%d", dhcpv6.DefaultClientPort)); err != nil {
+			return nil, nil, err
+		}
+		err = c.Open(*flagMaxInflight)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	go func() {
+		for range c.Errors() {
+			s.socketError()
+		}
+	}()
+	exchange := func(ctx context.Context, i int) result {
+		return sarr(ctx, c, i)
+	}
+	return exchange, c.Close, nil
+}
+
+// fatal reports err and exits. It does not use log, which is silenced with
+// -serve.
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+func main() {
+	flag.Parse()
+	if *flagClients <= 0 || *flagMaxInflight <= 0 {
+		fatal(fmt.Errorf("-clients and -max-inflight must be positive"))
+	}
+	if *flagServe {
+		// dhcpv4.Server and dhcpv6.Server log every request.
+		log.SetOutput(ioutil.Discard)
+	}
+
+	s := newStats()
+	setup, phases, refused := setupV4, []string{"offer", "ack"}, "NAK"
+	if *flagV6 {
+		setup, phases, refused = setupV6, []string{"advertise", "reply"}, "NoAddrsAvail"
+	}
+	exchange, closeClient, err := setup(s)
+	if err != nil {
+		fatal(err)
+	}
+	defer closeClient()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	run(ctx, exchange, s, *flagN, *flagClients, *flagRate, *flagMaxInflight)
+	s.report(os.Stdout, phases, refused)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math/big"
+	"net"
+	"sync"
+)
+
+// pool hands out the addresses of a prefix to clients, for the built-in
+// server.
+type pool struct {
+	mu     sync.Mutex
+	base   *big.Int
+	size   int
+	bits   int
+	leases map[string]net.IP
+}
+
+// newPool returns a pool of the addresses of prefix, at most size of them if
+// size is not zero. The first address of the prefix is skipped.
+func newPool(prefix *net.IPNet, size int) *pool {
+	ones, bits := prefix.Mask.Size()
+	avail := 1<<uint(bits-ones) - 2
+	if bits-ones > 30 {
+		avail = 1<<30 - 2
+	}
+	if size <= 0 || size > avail {
+		size = avail
+	}
+	ip := prefix.IP.To16()
+	if bits == 32 {
+		ip = prefix.IP.To4()
+	}
+	return &pool{
+		base:   new(big.Int).SetBytes(ip),
+		size:   size,
+		bits:   bits,
+		leases: make(map[string]net.IP),
+	}
+}
+
+// lease returns the address leased to client, or nil if the pool is
+// exhausted.
+func (p *pool) lease(client string) net.IP {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ip, ok := p.leases[client]; ok {
+		return ip
+	}
+	if len(p.leases) >= p.size {
+		return nil
+	}
+	n := new(big.Int).Add(p.base, big.NewInt(int64(len(p.leases)+1)))
+	ip := make(net.IP, p.bits/8)
+	b := n.Bytes()
+	copy(ip[len(ip)-len(b):], b)
+	p.leases[client] = ip
+	return ip
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	_, prefix, err := net.ParseCIDR("192.0.2.0/30")
+	require.NoError(t, err)
+	p := newPool(prefix, 0)
+	a := p.lease("a")
+	require.Equal(t, net.IPv4(192, 0, 2, 1).To4(), a)
+	// leases are stable
+	require.Equal(t, a, p.lease("a"))
+	require.Equal(t, net.IPv4(192, 0, 2, 2).To4(), p.lease("b"))
+	// exhausted
+	require.Nil(t, p.lease("c"))
+
+	_, prefix, err = net.ParseCIDR("2001:db8::/64")
+	require.NoError(t, err)
+	p = newPool(prefix, 1)
+	require.Equal(t, net.ParseIP("2001:db8::1"), p.lease("a"))
+	require.Nil(t, p.lease("b"))
+}
+
+func TestSyntheticIdentities(t *testing.T) {
+	require.Equal(t, "02:00:00:01:02:03", hwAddrFor(0x010203).String())
+	require.NotEqual(t, hwAddrFor(1), hwAddrFor(2))
+	duid := duidFor(1)
+	require.Equal(t, hwAddrFor(1), duid.LinkLayerAddr)
+}
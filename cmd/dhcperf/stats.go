@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// outcome is how an exchange ended.
+type outcome int
+
+const (
+	// outcomeOK is a completed exchange, e.g. DORA up to the ACK.
+	outcomeOK outcome = iota
+	// outcomeDropped is an exchange where a message got no reply.
+	outcomeDropped
+	// outcomeRefused is an exchange the server refused, with a NAK or a
+	// NoAddrsAvail status.
+	outcomeRefused
+	// outcomeError is an exchange that failed otherwise, e.g. with an
+	// unexpected reply.
+	outcomeError
+)
+
+// result is the outcome of an exchange and how long each of its
+// request/reply round trips took.
+type result struct {
+	outcome outcome
+	// rtts has the round trip times of the replies received, e.g. of the
+	// OFFER and of the ACK.
+	rtts []time.Duration
+	err  error
+}
+
+// stats collects the results of exchanges.
+type stats struct {
+	mu     sync.Mutex
+	counts map[outcome]int
+	total  []time.Duration
+	rtts   [][]time.Duration
+	errors map[string]int
+	// socketErrors counts the errors of the client sockets, e.g. of
+	// writes.
+	socketErrors int
+	started      time.Time
+	finished     time.Time
+}
+
+func newStats() *stats {
+	return &stats{
+		counts:  make(map[outcome]int),
+		errors:  make(map[string]int),
+		started: time.Now(),
+	}
+}
+
+// begin resets the start time of the run to now, and returns it.
+func (s *stats) begin() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = time.Now()
+	return s.started
+}
+
+func (s *stats) record(r result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[r.outcome]++
+	if r.err != nil {
+		s.errors[r.err.Error()]++
+	}
+	for i, rtt := range r.rtts {
+		for len(s.rtts) <= i {
+			s.rtts = append(s.rtts, nil)
+		}
+		s.rtts[i] = append(s.rtts[i], rtt)
+	}
+	if r.outcome == outcomeOK {
+		var total time.Duration
+		for _, rtt := range r.rtts {
+			total += rtt
+		}
+		s.total = append(s.total, total)
+	}
+}
+
+func (s *stats) socketError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.socketErrors++
+}
+
+func (s *stats) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finished = time.Now()
+}
+
+// percentile returns the p-th percentile of the sorted durations d, using
+// the nearest-rank method.
+func percentile(d []time.Duration, p float64) time.Duration {
+	if len(d) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(d))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(d) {
+		rank = len(d)
+	}
+	return d[rank-1]
+}
+
+func writeLatencies(w io.Writer, name string, d []time.Duration) {
+	if len(d) == 0 {
+		return
+	}
+	sorted := append([]time.Duration(nil), d...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	fmt.Fprintf(w, "  %-14s n=%-7d min=%-10v p50=%-10v p90=%-10v p99=%-10v max=%v\n",
+		name, len(sorted), sorted[0], percentile(sorted, 50), percentile(sorted, 90),
+		percentile(sorted, 99), sorted[len(sorted)-1])
+}
+
+// report writes a summary of the results to w. phases names the round trips
+// of an exchange, e.g. "offer" and "ack", and refused names the refusals,
+// e.g. "NAK".
+func (s *stats) report(w io.Writer, phases []string, refused string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	finished := s.finished
+	if finished.IsZero() {
+		finished = time.Now()
+	}
+	elapsed := finished.Sub(s.started)
+	var n int
+	for _, c := range s.counts {
+		n += c
+	}
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(s.counts[outcomeOK]) / elapsed.Seconds()
+	}
+	pct := func(c int) float64 {
+		if n == 0 {
+			return 0
+		}
+		return 100 * float64(c) / float64(n)
+	}
+	fmt.Fprintf(w, "exchanges: %d in %v, %.1f completed/s\n", n, elapsed.Round(time.Millisecond), rate)
+	count := func(label string, c int) {
+		fmt.Fprintf(w, "  %-14s %d (%.2f%%)\n", label+":", c, pct(c))
+	}
+	count("completed", s.counts[outcomeOK])
+	count("dropped", s.counts[outcomeDropped])
+	count(refused, s.counts[outcomeRefused])
+	count("errors", s.counts[outcomeError])
+	if s.socketErrors > 0 {
+		fmt.Fprintf(w, "  %-14s %d\n", "socket errors:", s.socketErrors)
+	}
+	errs := make([]string, 0, len(s.errors))
+	for e := range s.errors {
+		errs = append(errs, e)
+	}
+	sort.Strings(errs)
+	for _, e := range errs {
+		fmt.Fprintf(w, "    %d x %s\n", s.errors[e], e)
+	}
+	fmt.Fprintln(w, "latency:")
+	for i, d := range s.rtts {
+		name := fmt.Sprintf("rtt%d", i+1)
+		if i < len(phases) {
+			name = phases[i]
+		}
+		writeLatencies(w, name, d)
+	}
+	writeLatencies(w, "exchange", s.total)
+}
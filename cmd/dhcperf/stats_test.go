@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	require.Equal(t, time.Duration(0), percentile(nil, 50))
+
+	d := make([]time.Duration, 100)
+	for i := range d {
+		d[i] = time.Duration(i+1) * time.Millisecond
+	}
+	require.Equal(t, time.Millisecond, percentile(d, 0))
+	require.Equal(t, 50*time.Millisecond, percentile(d, 50))
+	require.Equal(t, 99*time.Millisecond, percentile(d, 99))
+	require.Equal(t, 100*time.Millisecond, percentile(d, 100))
+
+	d = []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	require.Equal(t, 2*time.Second, percentile(d, 50))
+	require.Equal(t, 3*time.Second, percentile(d, 90))
+}
+
+func TestStats(t *testing.T) {
+	s := newStats()
+	s.record(result{outcome: outcomeOK, rtts: []time.Duration{time.Millisecond, 2 * time.Millisecond}})
+	s.record(result{outcome: outcomeOK, rtts: []time.Duration{3 * time.Millisecond, 4 * time.Millisecond}})
+	s.record(result{outcome: outcomeDropped, rtts: []time.Duration{5 * time.Millisecond}})
+	s.record(result{outcome: outcomeRefused, rtts: []time.Duration{6 * time.Millisecond}})
+	s.record(result{outcome: outcomeError, err: errors.New("unexpected reply")})
+	s.socketError()
+	s.finish()
+
+	require.Equal(t, 2, s.counts[outcomeOK])
+	require.Equal(t, 1, s.counts[outcomeDropped])
+	require.Equal(t, 1, s.counts[outcomeRefused])
+	require.Equal(t, 1, s.counts[outcomeError])
+	require.Equal(t, map[string]int{"unexpected reply": 1}, s.errors)
+	require.Equal(t, [][]time.Duration{
+		{time.Millisecond, 3 * time.Millisecond, 5 * time.Millisecond, 6 * time.Millisecond},
+		{2 * time.Millisecond, 4 * time.Millisecond},
+	}, s.rtts)
+	// only completed exchanges count for the total latency
+	require.Equal(t, []time.Duration{3 * time.Millisecond, 7 * time.Millisecond}, s.total)
+
+	var buf bytes.Buffer
+	s.report(&buf, []string{"offer", "ack"}, "NAK")
+	out := buf.String()
+	require.Contains(t, out, "exchanges: 5 ")
+	require.Contains(t, out, "completed:     2 (40.00%)")
+	require.Contains(t, out, "NAK:           1 (20.00%)")
+	require.Contains(t, out, "socket errors: 1")
+	require.Contains(t, out, "1 x unexpected reply")
+	require.Contains(t, out, "offer          n=4")
+	require.Contains(t, out, "exchange       n=2")
+}
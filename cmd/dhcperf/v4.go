@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/async"
+)
+
+// hwAddrFor returns the synthetic, locally administered MAC address of
+// client i.
+func hwAddrFor(i int) net.HardwareAddr {
+	return net.HardwareAddr{0x02, 0x00, byte(i >> 24), byte(i >> 16), byte(i >> 8), byte(i)}
+}
+
+// dora runs a DISCOVER/OFFER/REQUEST/ACK exchange for client i.
+func dora(ctx context.Context, c *async.Client, i int) result {
+	var r result
+	discover, err := dhcpv4.NewDiscovery(hwAddrFor(i))
+	if err != nil {
+		return failed(r, err)
+	}
+	start := time.Now()
+	offer, err := c.SendContext(ctx, discover)
+	if err != nil {
+		return failed(r, err)
+	}
+	r.rtts = append(r.rtts, time.Since(start))
+	switch offer.MessageType() {
+	case dhcpv4.MessageTypeOffer:
+	case dhcpv4.MessageTypeNak:
+		r.outcome = outcomeRefused
+		return r
+	default:
+		return failed(r, fmt.Errorf("unexpected reply to DISCOVER: %v", offer.MessageType()))
+	}
+
+	request, err := dhcpv4.NewRequestFromOffer(offer)
+	if err != nil {
+		return failed(r, err)
+	}
+	start = time.Now()
+	ack, err := c.SendContext(ctx, request)
+	if err != nil {
+		return failed(r, err)
+	}
+	r.rtts = append(r.rtts, time.Since(start))
+	switch ack.MessageType() {
+	case dhcpv4.MessageTypeAck:
+		r.outcome = outcomeOK
+	case dhcpv4.MessageTypeNak:
+		r.outcome = outcomeRefused
+	default:
+		return failed(r, fmt.Errorf("unexpected reply to REQUEST: %v", ack.MessageType()))
+	}
+	return r
+}
+
+// v4Handler returns a dhcpv4.Server handler which leases the addresses of p,
+// and NAKs REQUESTs for other addresses. It does not answer DISCOVERs once p
+// is exhausted.
+func v4Handler(p *pool, serverIP net.IP, mask net.IPMask) dhcpv4.Handler {
+	return func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+		ip := p.lease(m.ClientHWAddr.String())
+		var reply *dhcpv4.DHCPv4
+		var err error
+		switch m.MessageType() {
+		case dhcpv4.MessageTypeDiscover:
+			if ip == nil {
+				return
+			}
+			reply, err = dhcpv4.NewReplyFromRequest(m,
+				dhcpv4.WithMessageType(dhcpv4.MessageTypeOffer),
+				dhcpv4.WithYourIP(ip),
+				dhcpv4.WithServerIP(serverIP),
+				dhcpv4.WithNetmask(mask),
+				dhcpv4.WithLeaseTime(3600),
+				dhcpv4.WithOption(dhcpv4.OptServerIdentifier(serverIP)),
+			)
+		case dhcpv4.MessageTypeRequest:
+			mt := dhcpv4.MessageTypeAck
+			if ip == nil || !ip.Equal(m.RequestedIPAddress()) {
+				mt = dhcpv4.MessageTypeNak
+				ip = net.IPv4zero
+			}
+			reply, err = dhcpv4.NewReplyFromRequest(m,
+				dhcpv4.WithMessageType(mt),
+				dhcpv4.WithYourIP(ip),
+				dhcpv4.WithServerIP(serverIP),
+				dhcpv4.WithOption(dhcpv4.OptServerIdentifier(serverIP)),
+			)
+		default:
+			return
+		}
+		if err != nil {
+			return
+		}
+		conn.WriteTo(reply.ToBytes(), peer)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/async"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// duidFor returns the synthetic DUID-LL of client i.
+func duidFor(i int) dhcpv6.Duid {
+	return dhcpv6.Duid{
+		Type:          dhcpv6.DUID_LL,
+		HwType:        iana.HWTypeEthernet,
+		LinkLayerAddr: hwAddrFor(i),
+	}
+}
+
+// noAddrsAvail tells whether the status of m, or of its IA_NA, is
+// NoAddrsAvail.
+func noAddrsAvail(m dhcpv6.DHCPv6) bool {
+	if sc, ok := m.GetOneOption(dhcpv6.OptionStatusCode).(*dhcpv6.OptStatusCode); ok && sc.StatusCode == iana.StatusNoAddrsAvail {
+		return true
+	}
+	iaNa, ok := m.GetOneOption(dhcpv6.OptionIANA).(*dhcpv6.OptIANA)
+	if !ok {
+		return false
+	}
+	sc, ok := iaNa.GetOneOption(dhcpv6.OptionStatusCode).(*dhcpv6.OptStatusCode)
+	return ok && sc.StatusCode == iana.StatusNoAddrsAvail
+}
+
+// sarr runs a SOLICIT/ADVERTISE/REQUEST/REPLY exchange for client i.
+func sarr(ctx context.Context, c *async.Client, i int) result {
+	var r result
+	solicit, err := dhcpv6.NewSolicitWithCID(duidFor(i))
+	if err != nil {
+		return failed(r, err)
+	}
+	start := time.Now()
+	advertise, err := c.SendContext(ctx, solicit)
+	if err != nil {
+		return failed(r, err)
+	}
+	r.rtts = append(r.rtts, time.Since(start))
+	if advertise.Type() != dhcpv6.MessageTypeAdvertise {
+		return failed(r, fmt.Errorf("unexpected reply to SOLICIT: %v", advertise.Type()))
+	}
+	if noAddrsAvail(advertise) {
+		r.outcome = outcomeRefused
+		return r
+	}
+
+	request, err := dhcpv6.NewRequestFromAdvertise(advertise)
+	if err != nil {
+		return failed(r, err)
+	}
+	// The REQUEST starts a new transaction.
+	xid, err := dhcpv6.GenerateTransactionID()
+	if err != nil {
+		return failed(r, err)
+	}
+	request.(*dhcpv6.DHCPv6Message).SetTransactionID(*xid)
+	start = time.Now()
+	reply, err := c.SendContext(ctx, request)
+	if err != nil {
+		return failed(r, err)
+	}
+	r.rtts = append(r.rtts, time.Since(start))
+	if reply.Type() != dhcpv6.MessageTypeReply {
+		return failed(r, fmt.Errorf("unexpected reply to REQUEST: %v", reply.Type()))
+	}
+	if noAddrsAvail(reply) {
+		r.outcome = outcomeRefused
+		return r
+	}
+	r.outcome = outcomeOK
+	return r
+}
+
+// v6Handler returns a dhcpv6.Server handler which leases the addresses of p,
+// with a NoAddrsAvail status once p is exhausted.
+func v6Handler(p *pool, serverID dhcpv6.Duid) dhcpv6.Handler {
+	return func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+		cid, ok := m.GetOneOption(dhcpv6.OptionClientID).(*dhcpv6.OptClientId)
+		if !ok {
+			return
+		}
+		ip := p.lease(string(cid.Cid.ToBytes()))
+		var mod dhcpv6.Modifier
+		if ip == nil {
+			mod = func(d dhcpv6.DHCPv6) dhcpv6.DHCPv6 {
+				d.AddOption(&dhcpv6.OptStatusCode{
+					StatusCode:    iana.StatusNoAddrsAvail,
+					StatusMessage: []byte("no addresses available"),
+				})
+				return d
+			}
+		} else {
+			mod = dhcpv6.WithIANA(dhcpv6.OptIAAddress{
+				IPv6Addr:          ip,
+				PreferredLifetime: 3600,
+				ValidLifetime:     7200,
+			})
+		}
+		var reply dhcpv6.DHCPv6
+		var err error
+		switch m.Type() {
+		case dhcpv6.MessageTypeSolicit:
+			reply, err = dhcpv6.NewAdvertiseFromSolicit(m, dhcpv6.WithServerID(serverID), mod)
+		case dhcpv6.MessageTypeRequest:
+			reply, err = dhcpv6.NewReplyFromDHCPv6Message(m, dhcpv6.WithServerID(serverID), mod)
+		default:
+			return
+		}
+		if err != nil {
+			return
+		}
+		conn.WriteTo(reply.ToBytes(), peer)
+	}
+}
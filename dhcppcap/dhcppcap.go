@@ -0,0 +1,133 @@
+// Package dhcppcap reads DHCPv4 and DHCPv6 messages from pcap and pcapng
+// captures, and writes them to pcap files.
+//
+// Frames are decoded from Ethernet (with VLAN tags), Linux cooked and raw IP
+// link types, over IPv4 or IPv6 and UDP. The UDP ports tell DHCPv4 (67, 68)
+// from DHCPv6 (546, 547) messages. Fragmented packets are not reassembled.
+package dhcppcap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+)
+
+// Packet is a DHCP message and its UDP framing.
+type Packet struct {
+	Time     time.Time
+	Src, Dst *net.UDPAddr
+	// DHCPv4 is set for messages to or from the DHCPv4 ports, and DHCPv6
+	// for messages to or from the DHCPv6 ports.
+	DHCPv4 *dhcpv4.DHCPv4
+	DHCPv6 dhcpv6.DHCPv6
+}
+
+// Decode decodes the DHCP message in f. It returns ErrNotDHCP if f does not
+// carry a UDP datagram to or from a DHCP port.
+func (f *Frame) Decode() (*Packet, error) {
+	d, err := decodeFrame(f.LinkType, f.Data)
+	if err != nil {
+		return nil, err
+	}
+	p := &Packet{Time: f.Time, Src: d.src, Dst: d.dst}
+	if d.isV6() {
+		p.DHCPv6, err = dhcpv6.FromBytes(d.payload)
+	} else {
+		p.DHCPv4, err = dhcpv4.FromBytes(d.payload)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s > %s: %v", d.src, d.dst, err)
+	}
+	return p, nil
+}
+
+// frameReader reads the frames of a capture file.
+type frameReader interface {
+	next() (*Frame, error)
+}
+
+// Reader reads the DHCP messages of a pcap or pcapng capture.
+type Reader struct {
+	frames frameReader
+}
+
+// NewReader returns a Reader for the pcap or pcapng capture in r.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("reading capture header: %v", err)
+	}
+	var frames frameReader
+	if isPcapng(magic) {
+		frames, err = newPcapngReader(br)
+	} else {
+		frames, err = newPcapReader(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{frames: frames}, nil
+}
+
+// NextFrame returns the next frame, or io.EOF at the end of the capture.
+func (r *Reader) NextFrame() (*Frame, error) {
+	return r.frames.next()
+}
+
+// Next returns the next DHCP message, skipping the frames of other traffic,
+// or io.EOF at the end of the capture. If a frame cannot be decoded, Next
+// returns an error and can be called again to read on.
+func (r *Reader) Next() (*Packet, error) {
+	for {
+		f, err := r.frames.next()
+		if err != nil {
+			return nil, err
+		}
+		p, err := f.Decode()
+		if err == ErrNotDHCP {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("frame at %s: %v", f.Time.Format(time.RFC3339Nano), err)
+		}
+		return p, nil
+	}
+}
+
+// ReadAll returns all the DHCP messages of the pcap or pcapng capture in r.
+// It fails on the first frame that cannot be decoded.
+func ReadAll(r io.Reader) ([]*Packet, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	var packets []*Packet
+	for {
+		p, err := reader.Next()
+		if err == io.EOF {
+			return packets, nil
+		}
+		if err != nil {
+			return packets, err
+		}
+		packets = append(packets, p)
+	}
+}
+
+// ReadFile returns all the DHCP messages of a pcap or pcapng file, see
+// ReadAll.
+func ReadFile(path string) ([]*Packet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadAll(f)
+}
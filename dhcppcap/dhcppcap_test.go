@@ -0,0 +1,86 @@
+package dhcppcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderNext(t *testing.T) {
+	discover := v4Conversation(t)[0]
+	solicit := v6Conversation(t)[0]
+	start := time.Unix(1500000000, 0)
+	dns := ipv4UDP(t, v4Client, v4Server, nil)
+	dns[20], dns[21], dns[22], dns[23] = 0, 53, 0, 53
+	file := pcapFile(binary.LittleEndian, false, LinkTypeEthernet,
+		[]time.Time{start, start, start, start.Add(time.Second)},
+		ethernet(etherTypeIPv4, ipv4UDP(t, v4Client, v4Server, discover.ToBytes())),
+		ethernet(etherTypeIPv4, dns),
+		ethernet(etherTypeIPv4, ipv4UDP(t, v4Client, v4Server, []byte{1, 2, 3})),
+		ethernet(etherTypeIPv6, ipv6UDP(v6Client, v6Server, solicit.ToBytes()), 10),
+	)
+
+	r, err := NewReader(bytes.NewReader(file))
+	require.NoError(t, err)
+	p, err := r.Next()
+	require.NoError(t, err)
+	require.True(t, start.Equal(p.Time))
+	require.Equal(t, v4Client, p.Src)
+	require.Equal(t, v4Server, p.Dst)
+	require.Equal(t, discover.ToBytes(), p.DHCPv4.ToBytes())
+	require.Nil(t, p.DHCPv6)
+
+	// the DNS frame is skipped, and the reader goes on after an invalid
+	// DHCP message
+	_, err = r.Next()
+	require.Error(t, err)
+	p, err = r.Next()
+	require.NoError(t, err)
+	require.True(t, start.Add(time.Second).Equal(p.Time))
+	require.Equal(t, solicit.ToBytes(), p.DHCPv6.ToBytes())
+	require.Nil(t, p.DHCPv4)
+
+	_, err = r.Next()
+	require.Equal(t, io.EOF, err)
+
+	_, err = ReadAll(bytes.NewReader(file))
+	require.Error(t, err)
+}
+
+func TestReadFilePcapng(t *testing.T) {
+	solicit := v6Conversation(t)[0]
+	order := binary.BigEndian
+	var file []byte
+	file = append(file, pcapngSHB(order)...)
+	file = append(file, pcapngIDB(order, LinkTypeRaw, 0)...)
+	file = append(file, pcapngEPB(order, 0, 1500000000000000, ipv6UDP(v6Client, v6Server, solicit.ToBytes()))...)
+
+	dir, err := ioutil.TempDir("", "dhcppcap")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "capture.pcapng")
+	require.NoError(t, ioutil.WriteFile(path, file, 0644))
+
+	packets, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, packets, 1)
+	require.True(t, time.Unix(1500000000, 0).Equal(packets[0].Time))
+	require.Equal(t, solicit.ToBytes(), packets[0].DHCPv6.ToBytes())
+
+	_, err = ReadFile(filepath.Join(dir, "missing.pcap"))
+	require.Error(t, err)
+}
+
+func TestNewReaderInvalid(t *testing.T) {
+	_, err := NewReader(bytes.NewReader(nil))
+	require.Error(t, err)
+	_, err = NewReader(bytes.NewReader(make([]byte, 64)))
+	require.Error(t, err)
+}
@@ -0,0 +1,225 @@
+package dhcppcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// LinkType is the link-layer header type of frames, as in pcap and pcapng
+// files.
+type LinkType uint32
+
+// Link types of the frames that can be decoded.
+const (
+	LinkTypeEthernet LinkType = 1
+	LinkTypeRaw      LinkType = 101
+	LinkTypeLinuxSLL LinkType = 113
+)
+
+// Frame is a captured frame.
+type Frame struct {
+	Time     time.Time
+	LinkType LinkType
+	Data     []byte
+}
+
+// EtherTypes of the frames.
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88a8
+)
+
+// Header lengths.
+const (
+	ethHeaderLen  = 14
+	sllHeaderLen  = 16
+	vlanHeaderLen = 4
+	minIPv4HdrLen = 20
+	ipv6HeaderLen = 40
+	udpHeaderLen  = 8
+)
+
+// IP protocols and IPv6 extension headers.
+const (
+	ipv6HopByHop  = 0
+	ipProtocolUDP = 17
+	ipv6Routing   = 43
+	ipv6Fragment  = 44
+	ipv6DestOpts  = 60
+)
+
+// ipv4FlagsMF and ipv4OffsetMask are the bits of fragments in the IPv4 flags
+// and fragment offset field.
+const (
+	ipv4FlagsMF    = 0x2000
+	ipv4OffsetMask = 0x1fff
+)
+
+// DHCP ports.
+const (
+	dhcpv4Server = 67
+	dhcpv4Client = 68
+	dhcpv6Client = 546
+	dhcpv6Server = 547
+)
+
+// ErrNotDHCP is returned when decoding frames which do not carry a DHCP
+// message, e.g. ARP or DNS traffic.
+var ErrNotDHCP = errors.New("not a DHCP packet")
+
+// datagram is a UDP datagram to or from a DHCP port.
+type datagram struct {
+	src, dst *net.UDPAddr
+	payload  []byte
+}
+
+// isV6 tells whether d is sent to or from a DHCPv6 port.
+func (d *datagram) isV6() bool {
+	return d.src.Port == dhcpv6Client || d.src.Port == dhcpv6Server ||
+		d.dst.Port == dhcpv6Client || d.dst.Port == dhcpv6Server
+}
+
+func isDHCPPort(port int) bool {
+	switch port {
+	case dhcpv4Server, dhcpv4Client, dhcpv6Client, dhcpv6Server:
+		return true
+	}
+	return false
+}
+
+// decodeFrame returns the UDP datagram to or from a DHCP port in frame, of
+// the given link type. Ethernet frames may have VLAN tags.
+func decodeFrame(linkType LinkType, frame []byte) (*datagram, error) {
+	var etherType uint16
+	switch linkType {
+	case LinkTypeEthernet:
+		if len(frame) < ethHeaderLen {
+			return nil, fmt.Errorf("short Ethernet frame: %d bytes", len(frame))
+		}
+		etherType = binary.BigEndian.Uint16(frame[12:14])
+		frame = frame[ethHeaderLen:]
+		for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+			if len(frame) < vlanHeaderLen {
+				return nil, errors.New("short VLAN header")
+			}
+			etherType = binary.BigEndian.Uint16(frame[2:4])
+			frame = frame[vlanHeaderLen:]
+		}
+	case LinkTypeLinuxSLL:
+		if len(frame) < sllHeaderLen {
+			return nil, fmt.Errorf("short Linux cooked frame: %d bytes", len(frame))
+		}
+		etherType = binary.BigEndian.Uint16(frame[14:16])
+		frame = frame[sllHeaderLen:]
+	case LinkTypeRaw:
+		if len(frame) == 0 {
+			return nil, errors.New("empty frame")
+		}
+		switch frame[0] >> 4 {
+		case 4:
+			etherType = etherTypeIPv4
+		case 6:
+			etherType = etherTypeIPv6
+		}
+	default:
+		return nil, fmt.Errorf("unsupported link type %d", linkType)
+	}
+
+	var (
+		src, dst net.IP
+		udp      []byte
+		err      error
+	)
+	switch etherType {
+	case etherTypeIPv4:
+		src, dst, udp, err = decodeIPv4(frame)
+	case etherTypeIPv6:
+		src, dst, udp, err = decodeIPv6(frame)
+	default:
+		return nil, ErrNotDHCP
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeUDP(src, dst, udp)
+}
+
+// decodeIPv4 returns the addresses and the UDP payload of an IPv4 packet.
+func decodeIPv4(b []byte) (net.IP, net.IP, []byte, error) {
+	if len(b) < minIPv4HdrLen {
+		return nil, nil, nil, fmt.Errorf("short IPv4 header: %d bytes", len(b))
+	}
+	hdrLen := int(b[0]&0x0f) * 4
+	totalLen := int(binary.BigEndian.Uint16(b[2:4]))
+	if hdrLen < minIPv4HdrLen || totalLen < hdrLen || totalLen > len(b) {
+		return nil, nil, nil, errors.New("invalid IPv4 header")
+	}
+	if b[9] != ipProtocolUDP {
+		return nil, nil, nil, ErrNotDHCP
+	}
+	if binary.BigEndian.Uint16(b[6:8])&(ipv4FlagsMF|ipv4OffsetMask) != 0 {
+		return nil, nil, nil, errors.New("fragmented IPv4 packet")
+	}
+	return net.IP(b[12:16]), net.IP(b[16:20]), b[hdrLen:totalLen], nil
+}
+
+// decodeIPv6 returns the addresses and the UDP payload of an IPv6 packet,
+// skipping the extension headers.
+func decodeIPv6(b []byte) (net.IP, net.IP, []byte, error) {
+	if len(b) < ipv6HeaderLen {
+		return nil, nil, nil, fmt.Errorf("short IPv6 header: %d bytes", len(b))
+	}
+	payloadLen := int(binary.BigEndian.Uint16(b[4:6]))
+	if ipv6HeaderLen+payloadLen > len(b) {
+		return nil, nil, nil, errors.New("invalid IPv6 payload length")
+	}
+	next := b[6]
+	src, dst := net.IP(b[8:24]), net.IP(b[24:40])
+	payload := b[ipv6HeaderLen : ipv6HeaderLen+payloadLen]
+	for {
+		switch next {
+		case ipProtocolUDP:
+			return src, dst, payload, nil
+		case ipv6HopByHop, ipv6Routing, ipv6DestOpts:
+			if len(payload) < 8 {
+				return nil, nil, nil, errors.New("short IPv6 extension header")
+			}
+			n := (int(payload[1]) + 1) * 8
+			if n > len(payload) {
+				return nil, nil, nil, errors.New("invalid IPv6 extension header")
+			}
+			next = payload[0]
+			payload = payload[n:]
+		case ipv6Fragment:
+			return nil, nil, nil, errors.New("fragmented IPv6 packet")
+		default:
+			return nil, nil, nil, ErrNotDHCP
+		}
+	}
+}
+
+// decodeUDP returns the datagram in b if it is to or from a DHCP port.
+func decodeUDP(src, dst net.IP, b []byte) (*datagram, error) {
+	if len(b) < udpHeaderLen {
+		return nil, fmt.Errorf("short UDP header: %d bytes", len(b))
+	}
+	srcPort := int(binary.BigEndian.Uint16(b[0:2]))
+	dstPort := int(binary.BigEndian.Uint16(b[2:4]))
+	if !isDHCPPort(srcPort) && !isDHCPPort(dstPort) {
+		return nil, ErrNotDHCP
+	}
+	length := int(binary.BigEndian.Uint16(b[4:6]))
+	if length < udpHeaderLen || length > len(b) {
+		return nil, errors.New("invalid UDP length")
+	}
+	return &datagram{
+		src:     &net.UDPAddr{IP: src, Port: srcPort},
+		dst:     &net.UDPAddr{IP: dst, Port: dstPort},
+		payload: b[udpHeaderLen:length],
+	}, nil
+}
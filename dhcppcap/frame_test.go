@@ -0,0 +1,145 @@
+package dhcppcap
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/require"
+)
+
+// ethernet returns an Ethernet frame carrying payload, with the given VLAN
+// tags.
+func ethernet(etherType uint16, payload []byte, vlans ...uint16) []byte {
+	frame := make([]byte, 12, ethHeaderLen+len(payload))
+	for _, vlan := range vlans {
+		frame = append(frame, 0x81, 0x00, byte(vlan>>8), byte(vlan))
+	}
+	frame = append(frame, byte(etherType>>8), byte(etherType))
+	return append(frame, payload...)
+}
+
+func ipv4UDP(t *testing.T, src, dst *net.UDPAddr, payload []byte) []byte {
+	packet, err := dhcpv4.MakeRawUDPPacket(payload, *dst, *src)
+	require.NoError(t, err)
+	return packet
+}
+
+// ipv6UDP returns an IPv6 packet carrying a UDP datagram, after the given
+// extension headers.
+func ipv6UDP(src, dst *net.UDPAddr, payload []byte, extensions ...byte) []byte {
+	udp := make([]byte, udpHeaderLen, udpHeaderLen+len(payload))
+	binary.BigEndian.PutUint16(udp[0:2], uint16(src.Port))
+	binary.BigEndian.PutUint16(udp[2:4], uint16(dst.Port))
+	binary.BigEndian.PutUint16(udp[4:6], uint16(udpHeaderLen+len(payload)))
+	udp = append(udp, payload...)
+
+	next := byte(ipProtocolUDP)
+	for i := len(extensions) - 1; i >= 0; i-- {
+		ext := make([]byte, 8)
+		ext[0] = next
+		udp = append(ext, udp...)
+		next = extensions[i]
+	}
+	hdr := make([]byte, ipv6HeaderLen)
+	hdr[0] = 6 << 4
+	binary.BigEndian.PutUint16(hdr[4:6], uint16(len(udp)))
+	hdr[6] = next
+	hdr[7] = 64
+	copy(hdr[8:24], src.IP.To16())
+	copy(hdr[24:40], dst.IP.To16())
+	return append(hdr, udp...)
+}
+
+var (
+	v4Client = &net.UDPAddr{IP: net.IPv4zero.To4(), Port: dhcpv4Client}
+	v4Server = &net.UDPAddr{IP: net.IPv4bcast.To4(), Port: dhcpv4Server}
+	v6Client = &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: dhcpv6Client}
+	v6Server = &net.UDPAddr{IP: net.ParseIP("ff02::1:2"), Port: dhcpv6Server}
+)
+
+func TestDecodeFrameIPv4(t *testing.T) {
+	payload := []byte("payload")
+	packet := ipv4UDP(t, v4Client, v4Server, payload)
+	for _, frame := range [][]byte{
+		ethernet(etherTypeIPv4, packet),
+		ethernet(etherTypeIPv4, packet, 10),
+		ethernet(etherTypeIPv4, packet, 10, 20),
+	} {
+		d, err := decodeFrame(LinkTypeEthernet, frame)
+		require.NoError(t, err)
+		require.Equal(t, v4Client, d.src)
+		require.Equal(t, v4Server, d.dst)
+		require.Equal(t, payload, d.payload)
+		require.False(t, d.isV6())
+	}
+
+	d, err := decodeFrame(LinkTypeRaw, packet)
+	require.NoError(t, err)
+	require.Equal(t, payload, d.payload)
+
+	sll := append(make([]byte, 14), 0x08, 0x00)
+	d, err = decodeFrame(LinkTypeLinuxSLL, append(sll, packet...))
+	require.NoError(t, err)
+	require.Equal(t, payload, d.payload)
+
+	// trailing padding of short Ethernet frames
+	d, err = decodeFrame(LinkTypeEthernet, append(ethernet(etherTypeIPv4, packet), 0, 0, 0))
+	require.NoError(t, err)
+	require.Equal(t, payload, d.payload)
+}
+
+func TestDecodeFrameIPv6(t *testing.T) {
+	payload := []byte("payload")
+	for _, packet := range [][]byte{
+		ipv6UDP(v6Client, v6Server, payload),
+		ipv6UDP(v6Client, v6Server, payload, ipv6HopByHop, ipv6DestOpts),
+	} {
+		d, err := decodeFrame(LinkTypeEthernet, ethernet(etherTypeIPv6, packet))
+		require.NoError(t, err)
+		require.Equal(t, v6Client.IP, d.src.IP)
+		require.Equal(t, v6Server, d.dst)
+		require.Equal(t, payload, d.payload)
+		require.True(t, d.isV6())
+	}
+}
+
+func TestDecodeFrameNotDHCP(t *testing.T) {
+	dns := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1).To4(), Port: 53}
+	_, err := decodeFrame(LinkTypeEthernet, ethernet(etherTypeIPv4, ipv4UDP(t, dns, dns, nil)))
+	require.Equal(t, ErrNotDHCP, err)
+	// ARP
+	_, err = decodeFrame(LinkTypeEthernet, ethernet(0x0806, make([]byte, 28)))
+	require.Equal(t, ErrNotDHCP, err)
+	// TCP
+	packet := ipv4UDP(t, v4Client, v4Server, nil)
+	packet[9] = 6
+	_, err = decodeFrame(LinkTypeEthernet, ethernet(etherTypeIPv4, packet))
+	require.Equal(t, ErrNotDHCP, err)
+}
+
+func TestDecodeFrameInvalid(t *testing.T) {
+	packet := ipv4UDP(t, v4Client, v4Server, []byte("payload"))
+	for _, frame := range [][]byte{
+		nil,
+		make([]byte, 10),
+		ethernet(etherTypeIPv4, packet[:10]),
+		ethernet(etherTypeIPv4, packet[:len(packet)-1]),
+		ethernet(etherTypeVLAN, nil),
+		ethernet(etherTypeIPv6, ipv6UDP(v6Client, v6Server, nil)[:30]),
+		ethernet(etherTypeIPv6, ipv6UDP(v6Client, v6Server, nil, ipv6Fragment)),
+	} {
+		_, err := decodeFrame(LinkTypeEthernet, frame)
+		require.Error(t, err)
+		require.NotEqual(t, ErrNotDHCP, err)
+	}
+
+	fragment := append([]byte(nil), packet...)
+	fragment[6] = 0x20 // more fragments
+	_, err := decodeFrame(LinkTypeRaw, fragment)
+	require.Error(t, err)
+
+	_, err = decodeFrame(1000, packet)
+	require.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package dhcppcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+)
+
+// Magic numbers of pcap files, with microsecond or nanosecond timestamps.
+const (
+	pcapMagicMicro = 0xa1b2c3d4
+	pcapMagicNano  = 0xa1b23c4d
+	pcapHeaderLen  = 24
+	pcapRecordLen  = 16
+	pcapSnapLen    = 65535
+	// maxFrameLen bounds the size of a frame, to reject corrupt files.
+	maxFrameLen = 1 << 18
+)
+
+// pcapReader reads the frames of a pcap file.
+type pcapReader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nano     bool
+	linkType LinkType
+}
+
+func newPcapReader(r io.Reader) (*pcapReader, error) {
+	var hdr [pcapHeaderLen]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, fmt.Errorf("reading pcap header: %v", err)
+	}
+	p := &pcapReader{r: r}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(hdr[0:4]) {
+		case pcapMagicMicro:
+			p.order = order
+		case pcapMagicNano:
+			p.order, p.nano = order, true
+		}
+	}
+	if p.order == nil {
+		return nil, fmt.Errorf("not a pcap or pcapng file: magic 0x%x", hdr[0:4])
+	}
+	p.linkType = LinkType(p.order.Uint32(hdr[20:24]))
+	return p, nil
+}
+
+func (p *pcapReader) next() (*Frame, error) {
+	var hdr [pcapRecordLen]byte
+	if _, err := io.ReadFull(p.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errors.New("truncated pcap record header")
+		}
+		return nil, err
+	}
+	sec := int64(p.order.Uint32(hdr[0:4]))
+	frac := int64(p.order.Uint32(hdr[4:8]))
+	if !p.nano {
+		frac *= int64(time.Microsecond)
+	}
+	n := p.order.Uint32(hdr[8:12])
+	if n > maxFrameLen {
+		return nil, fmt.Errorf("invalid pcap record length %d", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(p.r, data); err != nil {
+		return nil, fmt.Errorf("truncated pcap record: %v", err)
+	}
+	return &Frame{Time: time.Unix(sec, frac), LinkType: p.linkType, Data: data}, nil
+}
+
+// Writer writes DHCP messages to a pcap file, as raw IP packets.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter writes the header of a pcap file to w, and returns a Writer for
+// its packets.
+func NewWriter(w io.Writer) (*Writer, error) {
+	var hdr [pcapHeaderLen]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], pcapMagicMicro)
+	binary.LittleEndian.PutUint16(hdr[4:6], 2)
+	binary.LittleEndian.PutUint16(hdr[6:8], 4)
+	binary.LittleEndian.PutUint32(hdr[16:20], pcapSnapLen)
+	binary.LittleEndian.PutUint32(hdr[20:24], uint32(LinkTypeRaw))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w}, nil
+}
+
+// WritePacket writes the DHCPv4 or DHCPv6 message of p, from p.Src to p.Dst.
+func (w *Writer) WritePacket(p *Packet) error {
+	if p.Src == nil || p.Dst == nil {
+		return errors.New("missing source or destination address")
+	}
+	var (
+		packet []byte
+		err    error
+	)
+	switch {
+	case p.DHCPv4 != nil:
+		packet, err = dhcpv4.MakeRawUDPPacket(p.DHCPv4.ToBytes(), *p.Dst, *p.Src)
+	case p.DHCPv6 != nil:
+		packet, err = makeRawUDPv6Packet(p.DHCPv6.ToBytes(), *p.Dst, *p.Src)
+	default:
+		err = errors.New("no DHCP message")
+	}
+	if err != nil {
+		return err
+	}
+	return w.writeRecord(p.Time, packet)
+}
+
+func (w *Writer) writeRecord(t time.Time, packet []byte) error {
+	var hdr [pcapRecordLen]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], uint32(t.Unix()))
+	binary.LittleEndian.PutUint32(hdr[4:8], uint32(t.Nanosecond()/int(time.Microsecond)))
+	binary.LittleEndian.PutUint32(hdr[8:12], uint32(len(packet)))
+	binary.LittleEndian.PutUint32(hdr[12:16], uint32(len(packet)))
+	if _, err := w.w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(packet)
+	return err
+}
+
+// Addresses of the messages written by WriteDHCPv6Conversation.
+var (
+	ConversationV6ClientAddr = &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: dhcpv6Client}
+	ConversationV6ServerAddr = &net.UDPAddr{IP: net.ParseIP("fe80::2"), Port: dhcpv6Server}
+)
+
+// WriteDHCPv4Conversation writes the messages of a conversation, e.g. the
+// result of dhcpv4.Client.Exchange, starting at start and step apart.
+// BOOTREQUESTs are broadcast from the client IP address, or 0.0.0.0, and
+// BOOTREPLYs are broadcast from the server identifier.
+func (w *Writer) WriteDHCPv4Conversation(start time.Time, step time.Duration, conversation []*dhcpv4.DHCPv4) error {
+	for i, m := range conversation {
+		p := &Packet{Time: start.Add(time.Duration(i) * step), DHCPv4: m}
+		if m.OpCode == dhcpv4.OpcodeBootReply {
+			serverIP := m.ServerIdentifier()
+			if serverIP == nil {
+				serverIP = m.ServerIPAddr
+			}
+			p.Src = &net.UDPAddr{IP: ipv4OrZero(serverIP), Port: dhcpv4.ServerPort}
+			p.Dst = &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpv4.ClientPort}
+		} else {
+			p.Src = &net.UDPAddr{IP: ipv4OrZero(m.ClientIPAddr), Port: dhcpv4.ClientPort}
+			p.Dst = &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpv4.ServerPort}
+		}
+		if err := w.WritePacket(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ipv4OrZero(ip net.IP) net.IP {
+	if ip.To4() == nil {
+		return net.IPv4zero
+	}
+	return ip
+}
+
+// WriteDHCPv6Conversation writes the messages of a conversation, e.g. the
+// result of dhcpv6.Client.Exchange, starting at start and step apart. Client
+// messages are sent from ConversationV6ClientAddr to
+// All_DHCP_Relay_Agents_and_Servers, and server messages from
+// ConversationV6ServerAddr to ConversationV6ClientAddr.
+func (w *Writer) WriteDHCPv6Conversation(start time.Time, step time.Duration, conversation []dhcpv6.DHCPv6) error {
+	allServers := &net.UDPAddr{IP: dhcpv6.AllDHCPRelayAgentsAndServers, Port: dhcpv6Server}
+	for i, m := range conversation {
+		p := &Packet{Time: start.Add(time.Duration(i) * step), DHCPv6: m}
+		switch m.Type() {
+		case dhcpv6.MessageTypeAdvertise, dhcpv6.MessageTypeReply,
+			dhcpv6.MessageTypeReconfigure, dhcpv6.MessageTypeRelayReply,
+			dhcpv6.MessageTypeLeaseQueryReply:
+			p.Src, p.Dst = ConversationV6ServerAddr, ConversationV6ClientAddr
+		default:
+			p.Src, p.Dst = ConversationV6ClientAddr, allServers
+		}
+		if err := w.WritePacket(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeRawUDPv6Packet is like dhcpv4.MakeRawUDPPacket for IPv6: it converts
+// a payload into a raw IPv6 packet carrying a UDP datagram.
+func makeRawUDPv6Packet(payload []byte, serverAddr, clientAddr net.UDPAddr) ([]byte, error) {
+	if serverAddr.IP.To16() == nil || clientAddr.IP.To16() == nil {
+		return nil, errors.New("invalid IPv6 address")
+	}
+	udpLen := udpHeaderLen + len(payload)
+	packet := make([]byte, ipv6HeaderLen+udpHeaderLen, ipv6HeaderLen+udpLen)
+	packet[0] = 6 << 4
+	binary.BigEndian.PutUint16(packet[4:6], uint16(udpLen))
+	packet[6] = ipProtocolUDP
+	packet[7] = 64 // hop limit
+	copy(packet[8:24], clientAddr.IP.To16())
+	copy(packet[24:40], serverAddr.IP.To16())
+
+	udp := packet[ipv6HeaderLen:]
+	binary.BigEndian.PutUint16(udp[0:2], uint16(clientAddr.Port))
+	binary.BigEndian.PutUint16(udp[2:4], uint16(serverAddr.Port))
+	binary.BigEndian.PutUint16(udp[4:6], uint16(udpLen))
+	packet = append(packet, payload...)
+	binary.BigEndian.PutUint16(udp[6:8], udpv6Checksum(packet))
+	return packet, nil
+}
+
+// udpv6Checksum returns the checksum of the UDP datagram of an IPv6 packet
+// without extension headers, which is mandatory over IPv6.
+func udpv6Checksum(packet []byte) uint16 {
+	udp := packet[ipv6HeaderLen:]
+	var sum uint32
+	add := func(b []byte) {
+		for i := 0; i+1 < len(b); i += 2 {
+			sum += uint32(binary.BigEndian.Uint16(b[i:]))
+		}
+		if len(b)%2 == 1 {
+			sum += uint32(b[len(b)-1]) << 8
+		}
+	}
+	// pseudo-header
+	add(packet[8:40])
+	sum += uint32(len(udp))
+	sum += ipProtocolUDP
+	add(udp)
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	csum := ^uint16(sum)
+	if csum == 0 {
+		csum = 0xffff
+	}
+	return csum
+}
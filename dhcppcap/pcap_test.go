@@ -0,0 +1,183 @@
+package dhcppcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+// pcapFile returns a pcap file with the given frames.
+func pcapFile(order binary.ByteOrder, nano bool, linkType LinkType, times []time.Time, frames ...[]byte) []byte {
+	var buf bytes.Buffer
+	magic := uint32(pcapMagicMicro)
+	if nano {
+		magic = pcapMagicNano
+	}
+	binary.Write(&buf, order, []uint32{magic, 2 | 4<<16, 0, 0, 65535, uint32(linkType)})
+	for i, frame := range frames {
+		frac := uint32(times[i].Nanosecond())
+		if !nano {
+			frac /= 1000
+		}
+		binary.Write(&buf, order, []uint32{uint32(times[i].Unix()), frac, uint32(len(frame)), uint32(len(frame))})
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+func TestPcapReader(t *testing.T) {
+	times := []time.Time{time.Unix(1500000000, 123456000), time.Unix(1500000001, 0)}
+	frames := [][]byte{{1, 2, 3}, {4, 5}}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for _, nano := range []bool{false, true} {
+			r, err := newPcapReader(bytes.NewReader(pcapFile(order, nano, LinkTypeEthernet, times, frames...)))
+			require.NoError(t, err)
+			for i := range frames {
+				f, err := r.next()
+				require.NoError(t, err)
+				require.True(t, times[i].Equal(f.Time))
+				require.Equal(t, LinkTypeEthernet, f.LinkType)
+				require.Equal(t, frames[i], f.Data)
+			}
+			_, err = r.next()
+			require.Equal(t, io.EOF, err)
+		}
+	}
+}
+
+func TestPcapReaderInvalid(t *testing.T) {
+	_, err := newPcapReader(bytes.NewReader([]byte{0xa1, 0xb2}))
+	require.Error(t, err)
+	_, err = newPcapReader(bytes.NewReader(make([]byte, pcapHeaderLen)))
+	require.Error(t, err)
+
+	file := pcapFile(binary.LittleEndian, false, LinkTypeEthernet, []time.Time{time.Now()}, []byte{1, 2, 3})
+	r, err := newPcapReader(bytes.NewReader(file[:len(file)-1]))
+	require.NoError(t, err)
+	_, err = r.next()
+	require.Error(t, err)
+	require.NotEqual(t, io.EOF, err)
+}
+
+var testHWAddr = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+func v4Conversation(t *testing.T) []*dhcpv4.DHCPv4 {
+	serverIP := net.IPv4(192, 0, 2, 1).To4()
+	discover, err := dhcpv4.NewDiscovery(testHWAddr)
+	require.NoError(t, err)
+	offer, err := dhcpv4.NewReplyFromRequest(discover,
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeOffer),
+		dhcpv4.WithYourIP(net.IPv4(192, 0, 2, 10).To4()),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(serverIP)),
+	)
+	require.NoError(t, err)
+	request, err := dhcpv4.NewRequestFromOffer(offer)
+	require.NoError(t, err)
+	ack, err := dhcpv4.NewReplyFromRequest(request,
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeAck),
+		dhcpv4.WithYourIP(net.IPv4(192, 0, 2, 10).To4()),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(serverIP)),
+	)
+	require.NoError(t, err)
+	return []*dhcpv4.DHCPv4{discover, offer, request, ack}
+}
+
+func v6Conversation(t *testing.T) []dhcpv6.DHCPv6 {
+	duid := dhcpv6.Duid{Type: dhcpv6.DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: testHWAddr}
+	serverID := dhcpv6.Duid{Type: dhcpv6.DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0x02, 0, 0, 0, 0, 0xff}}
+	solicit, err := dhcpv6.NewSolicitWithCID(duid)
+	require.NoError(t, err)
+	iaAddr := dhcpv6.WithIANA(dhcpv6.OptIAAddress{
+		IPv6Addr:          net.ParseIP("2001:db8::10"),
+		PreferredLifetime: 3600,
+		ValidLifetime:     7200,
+	})
+	advertise, err := dhcpv6.NewAdvertiseFromSolicit(solicit, dhcpv6.WithServerID(serverID), iaAddr)
+	require.NoError(t, err)
+	request, err := dhcpv6.NewRequestFromAdvertise(advertise)
+	require.NoError(t, err)
+	reply, err := dhcpv6.NewReplyFromDHCPv6Message(request, dhcpv6.WithServerID(serverID), iaAddr)
+	require.NoError(t, err)
+	return []dhcpv6.DHCPv6{solicit, advertise, request, reply}
+}
+
+func TestWriterDHCPv4Conversation(t *testing.T) {
+	conversation := v4Conversation(t)
+	start := time.Unix(1500000000, 0)
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteDHCPv4Conversation(start, time.Millisecond, conversation))
+
+	packets, err := ReadAll(&buf)
+	require.NoError(t, err)
+	require.Len(t, packets, len(conversation))
+	for i, p := range packets {
+		require.True(t, start.Add(time.Duration(i)*time.Millisecond).Equal(p.Time))
+		require.Nil(t, p.DHCPv6)
+		require.Equal(t, conversation[i].ToBytes(), p.DHCPv4.ToBytes())
+		require.True(t, p.Dst.IP.Equal(net.IPv4bcast))
+	}
+	require.True(t, packets[0].Src.IP.Equal(net.IPv4zero))
+	require.Equal(t, dhcpv4.ClientPort, packets[0].Src.Port)
+	require.Equal(t, dhcpv4.ServerPort, packets[0].Dst.Port)
+	require.True(t, packets[1].Src.IP.Equal(net.IPv4(192, 0, 2, 1)))
+	require.Equal(t, dhcpv4.ServerPort, packets[1].Src.Port)
+	require.Equal(t, dhcpv4.ClientPort, packets[1].Dst.Port)
+}
+
+func TestWriterDHCPv6Conversation(t *testing.T) {
+	conversation := v6Conversation(t)
+	start := time.Unix(1500000000, 0)
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteDHCPv6Conversation(start, time.Second, conversation))
+
+	packets, err := ReadAll(&buf)
+	require.NoError(t, err)
+	require.Len(t, packets, len(conversation))
+	for i, p := range packets {
+		require.True(t, start.Add(time.Duration(i)*time.Second).Equal(p.Time))
+		require.Nil(t, p.DHCPv4)
+		require.Equal(t, conversation[i].ToBytes(), p.DHCPv6.ToBytes())
+	}
+	require.Equal(t, ConversationV6ClientAddr, packets[0].Src)
+	require.True(t, packets[0].Dst.IP.Equal(dhcpv6.AllDHCPRelayAgentsAndServers))
+	require.Equal(t, ConversationV6ServerAddr, packets[1].Src)
+	require.Equal(t, ConversationV6ClientAddr, packets[1].Dst)
+}
+
+func TestWriterInvalid(t *testing.T) {
+	w, err := NewWriter(ioutil.Discard)
+	require.NoError(t, err)
+	require.Error(t, w.WritePacket(&Packet{Src: v4Client, Dst: v4Server}))
+	require.Error(t, w.WritePacket(&Packet{DHCPv4: v4Conversation(t)[0]}))
+}
+
+func TestUDPv6Checksum(t *testing.T) {
+	packet, err := makeRawUDPv6Packet([]byte("odd"), *v6Server, *v6Client)
+	require.NoError(t, err)
+	// The ones' complement sum of a datagram and its pseudo-header, including
+	// the checksum, is 0xffff.
+	var sum uint32
+	words := append(append([]byte(nil), packet[8:40]...), packet[ipv6HeaderLen:]...)
+	words = append(words, 0)
+	for i := 0; i+1 < len(words); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(words[i:]))
+	}
+	sum += uint32(len(packet)-ipv6HeaderLen) + ipProtocolUDP
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	require.Equal(t, uint32(0xffff), sum)
+}
@@ -0,0 +1,210 @@
+package dhcppcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// pcapng block types, see
+// https://www.ietf.org/archive/id/draft-tuexen-opsawg-pcapng-05.html
+const (
+	blockTypeSHB = 0x0a0d0d0a
+	blockTypeIDB = 0x00000001
+	blockTypeSPB = 0x00000003
+	blockTypeEPB = 0x00000006
+)
+
+const (
+	pcapngByteOrderMagic = 0x1a2b3c4d
+	pcapngBlockHeaderLen = 8
+	pcapngBlockMinLen    = 12
+	pcapngOptEndOfOpt    = 0
+	pcapngOptTSResol     = 9
+)
+
+// isPcapng returns true if magic starts a pcapng section header block.
+func isPcapng(magic []byte) bool {
+	return len(magic) >= 4 && binary.BigEndian.Uint32(magic) == blockTypeSHB
+}
+
+// pcapngInterface is an interface of a pcapng section.
+type pcapngInterface struct {
+	linkType LinkType
+	// tsUnit is the duration of a timestamp unit.
+	tsUnit float64
+}
+
+// pcapngReader reads the frames of a pcapng file.
+type pcapngReader struct {
+	r          io.Reader
+	order      binary.ByteOrder
+	interfaces []pcapngInterface
+}
+
+func newPcapngReader(r io.Reader) (*pcapngReader, error) {
+	p := &pcapngReader{r: r}
+	blockType, _, err := p.readBlock()
+	if err != nil {
+		return nil, fmt.Errorf("reading pcapng header: %v", err)
+	}
+	if blockType != blockTypeSHB {
+		return nil, fmt.Errorf("pcapng file starts with block type 0x%x", blockType)
+	}
+	return p, nil
+}
+
+// readBlock reads the next block and returns its type and body. A section
+// header block sets the byte order and resets the interfaces.
+func (p *pcapngReader) readBlock() (uint32, []byte, error) {
+	var hdr [pcapngBlockHeaderLen]byte
+	if _, err := io.ReadFull(p.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errors.New("truncated pcapng block header")
+		}
+		return 0, nil, err
+	}
+	// The block type of a section header block is a palindrome, so it can be
+	// read before the byte order is known.
+	blockType := binary.BigEndian.Uint32(hdr[0:4])
+	if blockType == blockTypeSHB {
+		var magic [4]byte
+		if _, err := io.ReadFull(p.r, magic[:]); err != nil {
+			return 0, nil, fmt.Errorf("truncated pcapng section header: %v", err)
+		}
+		switch {
+		case binary.LittleEndian.Uint32(magic[:]) == pcapngByteOrderMagic:
+			p.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(magic[:]) == pcapngByteOrderMagic:
+			p.order = binary.BigEndian
+		default:
+			return 0, nil, fmt.Errorf("invalid pcapng byte-order magic 0x%x", magic)
+		}
+		p.interfaces = nil
+		body, err := p.readBody(p.order.Uint32(hdr[4:8]), 4)
+		if err != nil {
+			return 0, nil, err
+		}
+		return blockType, append(magic[:], body...), nil
+	}
+	if p.order == nil {
+		return 0, nil, errors.New("pcapng block before the section header")
+	}
+	blockType = p.order.Uint32(hdr[0:4])
+	body, err := p.readBody(p.order.Uint32(hdr[4:8]), 0)
+	return blockType, body, err
+}
+
+// readBody reads the rest of a block of length blockLen, of which read bytes
+// of the body were already read, and returns the body without the trailing
+// length.
+func (p *pcapngReader) readBody(blockLen uint32, read int) ([]byte, error) {
+	if blockLen < pcapngBlockMinLen+uint32(read) || blockLen%4 != 0 || blockLen > maxFrameLen {
+		return nil, fmt.Errorf("invalid pcapng block length %d", blockLen)
+	}
+	rest := make([]byte, int(blockLen)-pcapngBlockHeaderLen-read)
+	if _, err := io.ReadFull(p.r, rest); err != nil {
+		return nil, fmt.Errorf("truncated pcapng block: %v", err)
+	}
+	if trailer := p.order.Uint32(rest[len(rest)-4:]); trailer != blockLen {
+		return nil, fmt.Errorf("pcapng block length mismatch: %d and %d", blockLen, trailer)
+	}
+	return rest[:len(rest)-4], nil
+}
+
+func (p *pcapngReader) next() (*Frame, error) {
+	for {
+		blockType, body, err := p.readBlock()
+		if err != nil {
+			return nil, err
+		}
+		switch blockType {
+		case blockTypeIDB:
+			iface, err := p.decodeInterface(body)
+			if err != nil {
+				return nil, err
+			}
+			p.interfaces = append(p.interfaces, iface)
+		case blockTypeEPB:
+			return p.decodeEnhancedPacket(body)
+		case blockTypeSPB:
+			return p.decodeSimplePacket(body)
+		}
+		// Section headers and other blocks carry no frames.
+	}
+}
+
+func (p *pcapngReader) decodeInterface(body []byte) (pcapngInterface, error) {
+	if len(body) < 8 {
+		return pcapngInterface{}, errors.New("short pcapng interface description block")
+	}
+	iface := pcapngInterface{
+		linkType: LinkType(p.order.Uint16(body[0:2])),
+		tsUnit:   1e-6,
+	}
+	opts := body[8:]
+	for len(opts) >= 4 {
+		code := p.order.Uint16(opts[0:2])
+		length := int(p.order.Uint16(opts[2:4]))
+		if code == pcapngOptEndOfOpt || len(opts) < 4+length {
+			break
+		}
+		if code == pcapngOptTSResol && length >= 1 {
+			v := opts[4]
+			if v&0x80 == 0 {
+				iface.tsUnit = math.Pow(10, -float64(v))
+			} else {
+				iface.tsUnit = math.Pow(2, -float64(v&0x7f))
+			}
+		}
+		opts = opts[4+(length+3)&^3:]
+	}
+	return iface, nil
+}
+
+func (p *pcapngReader) decodeEnhancedPacket(body []byte) (*Frame, error) {
+	if len(body) < 20 {
+		return nil, errors.New("short pcapng enhanced packet block")
+	}
+	id := p.order.Uint32(body[0:4])
+	if int(id) >= len(p.interfaces) {
+		return nil, fmt.Errorf("pcapng packet on unknown interface %d", id)
+	}
+	iface := p.interfaces[id]
+	ts := uint64(p.order.Uint32(body[4:8]))<<32 | uint64(p.order.Uint32(body[8:12]))
+	n := p.order.Uint32(body[12:16])
+	if uint64(n) > uint64(len(body)-20) {
+		return nil, fmt.Errorf("invalid pcapng captured length %d", n)
+	}
+	data := body[20 : 20+n]
+	return &Frame{Time: timestamp(ts, iface.tsUnit), LinkType: iface.linkType, Data: data}, nil
+}
+
+func (p *pcapngReader) decodeSimplePacket(body []byte) (*Frame, error) {
+	if len(p.interfaces) == 0 {
+		return nil, errors.New("pcapng packet without interface")
+	}
+	if len(body) < 4 {
+		return nil, errors.New("short pcapng simple packet block")
+	}
+	data := body[4:]
+	if n := p.order.Uint32(body[0:4]); n < uint32(len(data)) {
+		data = data[:n]
+	}
+	return &Frame{LinkType: p.interfaces[0].linkType, Data: data}, nil
+}
+
+// timestamp converts a number of units of unit seconds since the epoch.
+func timestamp(ts uint64, unit float64) time.Time {
+	if unit == 1e-6 {
+		return time.Unix(int64(ts/1e6), int64(ts%1e6)*int64(time.Microsecond))
+	}
+	if unit == 1e-9 {
+		return time.Unix(int64(ts/1e9), int64(ts%1e9))
+	}
+	sec, frac := math.Modf(float64(ts) * unit)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
@@ -0,0 +1,133 @@
+package dhcppcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pcapngBlock returns a pcapng block with the given body, padded to 32 bits.
+func pcapngBlock(order binary.ByteOrder, blockType uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	b := make([]byte, 12+len(body))
+	order.PutUint32(b[0:4], blockType)
+	order.PutUint32(b[4:8], uint32(len(b)))
+	copy(b[8:], body)
+	order.PutUint32(b[len(b)-4:], uint32(len(b)))
+	return b
+}
+
+func pcapngSHB(order binary.ByteOrder) []byte {
+	body := make([]byte, 16)
+	order.PutUint32(body[0:4], pcapngByteOrderMagic)
+	order.PutUint16(body[4:6], 1)
+	binary.BigEndian.PutUint64(body[8:16], 0xffffffffffffffff)
+	return pcapngBlock(order, blockTypeSHB, body)
+}
+
+// pcapngIDB returns an interface description block, with an if_tsresol
+// option if tsresol is not zero.
+func pcapngIDB(order binary.ByteOrder, linkType LinkType, tsresol byte) []byte {
+	body := make([]byte, 8)
+	order.PutUint16(body[0:2], uint16(linkType))
+	order.PutUint32(body[4:8], 65535)
+	if tsresol != 0 {
+		opt := make([]byte, 8)
+		order.PutUint16(opt[0:2], pcapngOptTSResol)
+		order.PutUint16(opt[2:4], 1)
+		opt[4] = tsresol
+		body = append(body, opt...)
+		body = append(body, 0, 0, 0, 0) // opt_endofopt
+	}
+	return pcapngBlock(order, blockTypeIDB, body)
+}
+
+func pcapngEPB(order binary.ByteOrder, iface uint32, ts uint64, data []byte) []byte {
+	body := make([]byte, 20)
+	order.PutUint32(body[0:4], iface)
+	order.PutUint32(body[4:8], uint32(ts>>32))
+	order.PutUint32(body[8:12], uint32(ts))
+	order.PutUint32(body[12:16], uint32(len(data)))
+	order.PutUint32(body[16:20], uint32(len(data)))
+	return pcapngBlock(order, blockTypeEPB, append(body, data...))
+}
+
+func pcapngSPB(order binary.ByteOrder, data []byte) []byte {
+	body := make([]byte, 4)
+	order.PutUint32(body, uint32(len(data)))
+	return pcapngBlock(order, blockTypeSPB, append(body, data...))
+}
+
+func TestPcapngReader(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		var file []byte
+		file = append(file, pcapngSHB(order)...)
+		file = append(file, pcapngIDB(order, LinkTypeEthernet, 0)...)
+		file = append(file, pcapngIDB(order, LinkTypeRaw, 9)...)
+		file = append(file, pcapngBlock(order, 5, make([]byte, 10))...) // interface statistics
+		file = append(file, pcapngEPB(order, 0, 1500000000123456, []byte{1, 2, 3})...)
+		file = append(file, pcapngEPB(order, 1, 1500000000123456789, []byte{4, 5})...)
+		file = append(file, pcapngSPB(order, []byte{6})...)
+		// A new section resets the interfaces.
+		file = append(file, pcapngSHB(order)...)
+		file = append(file, pcapngIDB(order, LinkTypeLinuxSLL, 0x80|10)...)
+		file = append(file, pcapngEPB(order, 0, 1024*1500000000+512, []byte{7})...)
+
+		p, err := newPcapngReader(bytes.NewReader(file))
+		require.NoError(t, err)
+		for _, want := range []Frame{
+			{Time: time.Unix(1500000000, 123456000), LinkType: LinkTypeEthernet, Data: []byte{1, 2, 3}},
+			{Time: time.Unix(1500000000, 123456789), LinkType: LinkTypeRaw, Data: []byte{4, 5}},
+			{LinkType: LinkTypeEthernet, Data: []byte{6}},
+			{Time: time.Unix(1500000000, 500000000), LinkType: LinkTypeLinuxSLL, Data: []byte{7}},
+		} {
+			f, err := p.next()
+			require.NoError(t, err)
+			require.True(t, want.Time.Equal(f.Time), "%s != %s", want.Time, f.Time)
+			require.Equal(t, want.LinkType, f.LinkType)
+			require.Equal(t, want.Data, f.Data)
+		}
+		_, err = p.next()
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestPcapngReaderInvalid(t *testing.T) {
+	order := binary.LittleEndian
+	shb := pcapngSHB(order)
+
+	// bad byte-order magic
+	bad := append([]byte(nil), shb...)
+	bad[8] = 0
+	_, err := newPcapngReader(bytes.NewReader(bad))
+	require.Error(t, err)
+
+	// packet on an unknown interface
+	p, err := newPcapngReader(bytes.NewReader(append(shb, pcapngEPB(order, 0, 0, []byte{1})...)))
+	require.NoError(t, err)
+	_, err = p.next()
+	require.Error(t, err)
+
+	// mismatched trailing length
+	block := pcapngIDB(order, LinkTypeEthernet, 0)
+	block[len(block)-1] = 1
+	p, err = newPcapngReader(bytes.NewReader(append(shb, block...)))
+	require.NoError(t, err)
+	_, err = p.next()
+	require.Error(t, err)
+	require.NotEqual(t, io.EOF, err)
+
+	// truncated block
+	block = pcapngIDB(order, LinkTypeEthernet, 0)
+	p, err = newPcapngReader(bytes.NewReader(append(shb, block[:len(block)-2]...)))
+	require.NoError(t, err)
+	_, err = p.next()
+	require.Error(t, err)
+	require.NotEqual(t, io.EOF, err)
+}
@@ -2,13 +2,16 @@ package async
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/fanliao/go-promise"
 	"github.com/insomniacslk/dhcp/dhcpv4"
+	"golang.org/x/sys/unix"
 )
 
 // Default ports
@@ -17,10 +20,21 @@ const (
 	DefaultClientPort = 68
 )
 
+// ErrNoResponse is returned by SendContext when no response arrives within
+// ReadTimeout.
+var ErrNoResponse = errors.New("no response received")
+
 // Client implements an asynchronous DHCPv4 client
-// It doesn't use the broadcast socket! Which means it should be used only when
-// the network is already established.
+// Unless it is bound to an interface, it doesn't use the broadcast socket!
+// Which means it should be used only when the network is already
+// established, see OpenForInterface otherwise.
 // https://github.com/insomniacslk/dhcp/issues/143
+//
+// A single receiver loop reads all the incoming packets and dispatches them
+// to the pending transactions by transaction ID, so any number of exchanges
+// can be in flight at the same time. A transaction stays pending until its
+// context is done, or for ReadTimeout after its packet is sent, and gets all
+// the replies received in the meantime.
 type Client struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
@@ -28,14 +42,90 @@ type Client struct {
 	RemoteAddr   net.Addr
 	IgnoreErrors bool
 
-	connection   *net.UDPConn
-	cancel       context.CancelFunc
-	stopping     *sync.WaitGroup
-	receiveQueue chan *dhcpv4.DHCPv4
-	sendQueue    chan *dhcpv4.DHCPv4
-	packetsLock  sync.Mutex
-	packets      map[dhcpv4.TransactionID]*promise.Promise
-	errors       chan error
+	// ReadBufferSize, if not zero, sets the size of the socket receive
+	// buffer. Clients with many exchanges in flight need a large one to
+	// absorb bursts of replies.
+	ReadBufferSize int
+
+	// Interface, if not empty, binds the client to the given network
+	// interface (SO_BINDTODEVICE), see OpenForInterface.
+	Interface string
+
+	// Unsolicited, if set, is called from the receiver loop with the
+	// packets that match no pending transaction, e.g. replies that arrive
+	// after their transaction expired.
+	Unsolicited func(*dhcpv4.DHCPv4)
+
+	connection  *net.UDPConn
+	cancel      context.CancelFunc
+	done        <-chan struct{}
+	stopping    *sync.WaitGroup
+	sendQueue   chan *dhcpv4.DHCPv4
+	packetsLock sync.Mutex
+	packets     map[dhcpv4.TransactionID]*transaction
+	errors      chan error
+}
+
+// Reply is a response received by SendAll, or the error that ended the
+// exchange.
+type Reply struct {
+	Packet *dhcpv4.DHCPv4
+	Err    error
+}
+
+// replyQueueSize is the number of replies a transaction holds for a slow
+// reader before dropping new ones.
+const replyQueueSize = 16
+
+// transaction is a pending exchange. Its replies are queued until it ends.
+type transaction struct {
+	replies chan Reply
+	done    chan struct{}
+	mu      sync.Mutex
+	ended   bool
+	timer   *time.Timer
+}
+
+func newTransaction() *transaction {
+	return &transaction{
+		replies: make(chan Reply, replyQueueSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// deliver queues r. It never blocks the receiver loop: r is dropped if the
+// queue is full.
+func (t *transaction) deliver(r Reply) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ended {
+		return
+	}
+	select {
+	case t.replies <- r:
+	default:
+	}
+}
+
+// end closes the reply queue, after queueing err if not nil.
+func (t *transaction) end(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ended {
+		return
+	}
+	if err != nil {
+		select {
+		case t.replies <- Reply{Err: err}:
+		default:
+		}
+	}
+	t.ended = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	close(t.replies)
+	close(t.done)
 }
 
 // NewClient creates an asynchronous client
@@ -46,6 +136,52 @@ func NewClient() *Client {
 	}
 }
 
+// OpenForInterface starts the client bound to interface ifname, on the DHCP
+// client port of all addresses unless LocalAddr is set, e.g. to run several
+// DORA exchanges on unconfigured VLAN interfaces at the same time.
+//
+// The client broadcasts to RemoteAddr, by default the limited broadcast
+// address, so it works before the interface has an address. As it cannot
+// receive unicast replies to an address it does not have yet, the broadcast
+// flag is set on the messages it sends.
+func (c *Client) OpenForInterface(ifname string, bufferSize int) error {
+	if c.LocalAddr == nil {
+		c.LocalAddr = &net.UDPAddr{IP: net.IPv4zero, Port: DefaultClientPort}
+	}
+	c.Interface = ifname
+	return c.Open(bufferSize)
+}
+
+// listen returns a socket for addr that can send broadcasts, bound to
+// c.Interface if set.
+func (c *Client) listen(addr *net.UDPAddr) (*net.UDPConn, error) {
+	lc := net.ListenConfig{
+		Control: func(network, address string, raw syscall.RawConn) error {
+			var err error
+			if cerr := raw.Control(func(fd uintptr) {
+				err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_BROADCAST, 1)
+				if err != nil || c.Interface == "" {
+					return
+				}
+				// Clients on different interfaces share the client port.
+				err = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1)
+				if err != nil {
+					return
+				}
+				err = dhcpv4.BindToInterface(int(fd), c.Interface)
+			}); cerr != nil {
+				return cerr
+			}
+			return err
+		},
+	}
+	conn, err := lc.ListenPacket(context.Background(), "udp4", addr.String())
+	if err != nil {
+		return nil, err
+	}
+	return conn.(*net.UDPConn), nil
+}
+
 // Open starts the client. The requests made with Send function call are first
 // put to the buffered channel and dispatched in FIFO order. BufferSize
 // indicates the number of packets that can be waiting to be send before
@@ -62,19 +198,26 @@ func (c *Client) Open(bufferSize int) error {
 	}
 
 	// prepare the socket to listen on for replies
-	c.connection, err = net.ListenUDP("udp4", addr)
+	c.connection, err = c.listen(addr)
 	if err != nil {
 		return err
 	}
+	if c.ReadBufferSize > 0 {
+		if err := c.connection.SetReadBuffer(c.ReadBufferSize); err != nil {
+			c.connection.Close()
+			return err
+		}
+	}
 	c.stopping = new(sync.WaitGroup)
 	c.sendQueue = make(chan *dhcpv4.DHCPv4, bufferSize)
-	c.receiveQueue = make(chan *dhcpv4.DHCPv4, bufferSize)
-	c.packets = make(map[dhcpv4.TransactionID]*promise.Promise)
+	c.packets = make(map[dhcpv4.TransactionID]*transaction)
 	c.packetsLock = sync.Mutex{}
 	c.errors = make(chan error)
 
 	var ctx context.Context
 	ctx, c.cancel = context.WithCancel(context.Background())
+	c.done = ctx.Done()
+	c.stopping.Add(2)
 	go c.receiverLoop(ctx)
 	go c.senderLoop(ctx)
 
@@ -83,16 +226,20 @@ func (c *Client) Open(bufferSize int) error {
 
 // Close stops the client
 func (c *Client) Close() {
-	// Wait for sender and receiver loops
-	c.stopping.Add(2)
+	// Closing the connection unblocks the receiver loop.
 	c.cancel()
+	c.connection.Close()
 	c.stopping.Wait()
 
-	close(c.sendQueue)
-	close(c.receiveQueue)
 	close(c.errors)
 
-	c.connection.Close()
+	c.packetsLock.Lock()
+	packets := c.packets
+	c.packets = make(map[dhcpv4.TransactionID]*transaction)
+	c.packetsLock.Unlock()
+	for _, t := range packets {
+		t.end(nil)
+	}
 }
 
 // Errors returns a channel where runtime errors are posted
@@ -102,19 +249,35 @@ func (c *Client) Errors() <-chan error {
 
 func (c *Client) addError(err error) {
 	if !c.IgnoreErrors {
-		c.errors <- err
+		select {
+		case c.errors <- err:
+		case <-c.done:
+		}
 	}
 }
 
 func (c *Client) receiverLoop(ctx context.Context) {
 	defer func() { c.stopping.Done() }()
+	oobdata := []byte{}
+	buffer := make([]byte, dhcpv4.MaxUDPReceivedPacketSize)
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		case packet := <-c.receiveQueue:
-			c.receive(packet)
+		n, _, _, _, err := c.connection.ReadMsgUDP(buffer, oobdata)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			c.addError(fmt.Errorf("Error receiving the message: %s", err))
+			continue
 		}
+		// The parsed packet may keep references to its data.
+		received, err := dhcpv4.FromBytes(append([]byte(nil), buffer[:n]...))
+		if err != nil {
+			// skip non-DHCP packets
+			continue
+		}
+		c.dispatch(received)
 	}
 }
 
@@ -131,54 +294,104 @@ func (c *Client) senderLoop(ctx context.Context) {
 }
 
 func (c *Client) send(packet *dhcpv4.DHCPv4) {
-	c.packetsLock.Lock()
-	p := c.packets[packet.TransactionID]
-	c.packetsLock.Unlock()
-
 	raddr, err := c.remoteAddr()
 	if err != nil {
-		p.Reject(err)
+		c.failTransaction(packet.TransactionID, err)
 		return
 	}
 
 	c.connection.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
 	_, err = c.connection.WriteTo(packet.ToBytes(), raddr)
 	if err != nil {
-		p.Reject(err)
+		c.failTransaction(packet.TransactionID, err)
 		return
 	}
 
-	c.receiveQueue <- packet
+	c.startTransaction(packet.TransactionID)
 }
 
-func (c *Client) receive(_ *dhcpv4.DHCPv4) {
-	var (
-		oobdata  = []byte{}
-		received *dhcpv4.DHCPv4
-	)
+// dispatch hands received over to its pending transaction, if any.
+func (c *Client) dispatch(received *dhcpv4.DHCPv4) {
+	c.packetsLock.Lock()
+	t, ok := c.packets[received.TransactionID]
+	c.packetsLock.Unlock()
+	if ok {
+		t.deliver(Reply{Packet: received})
+	} else if c.Unsolicited != nil {
+		c.Unsolicited(received)
+	}
+}
 
-	c.connection.SetReadDeadline(time.Now().Add(c.ReadTimeout))
-	for {
-		buffer := make([]byte, dhcpv4.MaxUDPReceivedPacketSize)
-		n, _, _, _, err := c.connection.ReadMsgUDP(buffer, oobdata)
-		if err != nil {
-			if err, ok := err.(net.Error); !ok || !err.Timeout() {
-				c.addError(fmt.Errorf("Error receiving the message: %s", err))
-			}
-			return
-		}
-		received, err = dhcpv4.FromBytes(buffer[:n])
-		if err == nil {
-			break
-		}
+func (c *Client) addTransaction(id dhcpv4.TransactionID, t *transaction) {
+	c.packetsLock.Lock()
+	old, ok := c.packets[id]
+	c.packets[id] = t
+	c.packetsLock.Unlock()
+	if ok {
+		old.end(nil)
 	}
+}
 
+// startTransaction expires the transaction id ReadTimeout from now.
+func (c *Client) startTransaction(id dhcpv4.TransactionID) {
 	c.packetsLock.Lock()
-	if p, ok := c.packets[received.TransactionID]; ok {
-		delete(c.packets, received.TransactionID)
-		p.Resolve(received)
+	t, ok := c.packets[id]
+	c.packetsLock.Unlock()
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.ended {
+		t.timer = time.AfterFunc(c.ReadTimeout, func() {
+			c.endTransaction(id, t, nil)
+		})
+	}
+}
+
+// endTransaction ends t, and removes it if it is still the transaction
+// pending for id.
+func (c *Client) endTransaction(id dhcpv4.TransactionID, t *transaction, err error) {
+	c.packetsLock.Lock()
+	if c.packets[id] == t {
+		delete(c.packets, id)
 	}
 	c.packetsLock.Unlock()
+	t.end(err)
+}
+
+func (c *Client) failTransaction(id dhcpv4.TransactionID, err error) {
+	c.packetsLock.Lock()
+	t, ok := c.packets[id]
+	c.packetsLock.Unlock()
+	if ok {
+		c.endTransaction(id, t, err)
+	}
+}
+
+// start queues message for sending in a new transaction, which ends when ctx
+// is done.
+func (c *Client) start(ctx context.Context, message *dhcpv4.DHCPv4) *transaction {
+	if c.Interface != "" {
+		message.SetBroadcast()
+	}
+	id := message.TransactionID
+	t := newTransaction()
+	c.addTransaction(id, t)
+	select {
+	case c.sendQueue <- message:
+	case <-ctx.Done():
+		c.endTransaction(id, t, nil)
+		return t
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.endTransaction(id, t, nil)
+		case <-t.done:
+		}
+	}()
+	return t
 }
 
 func (c *Client) remoteAddr() (*net.UDPAddr, error) {
@@ -192,13 +405,53 @@ func (c *Client) remoteAddr() (*net.UDPAddr, error) {
 	return nil, fmt.Errorf("Invalid remote address: %v not a net.UDPAddr", c.RemoteAddr)
 }
 
+// SendAll sends message asynchronously, and returns a channel with all the
+// responses to it. The channel is closed when ctx is done or ReadTimeout
+// after message is sent, whichever comes first. If message cannot be sent,
+// the channel gets a Reply with the error. Replies that the caller does not
+// read in time are dropped.
+func (c *Client) SendAll(ctx context.Context, message *dhcpv4.DHCPv4) <-chan Reply {
+	return c.start(ctx, message).replies
+}
+
+// SendContext sends message and waits for the first response to it. It
+// returns ctx.Err() if ctx is done first, and ErrNoResponse if no response
+// arrives within ReadTimeout.
+func (c *Client) SendContext(ctx context.Context, message *dhcpv4.DHCPv4) (*dhcpv4.DHCPv4, error) {
+	sendCtx, cancel := context.WithCancel(ctx)
+	// Later responses are not needed.
+	defer cancel()
+	r, ok := <-c.SendAll(sendCtx, message)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoResponse
+	}
+	return r.Packet, r.Err
+}
+
 // Send inserts a message to the queue to be sent asynchronously.
-// Returns a future which resolves to response and error.
+// Returns a future which resolves to the first response and error. If no
+// response arrives within ReadTimeout, the future is never resolved.
+//
+// Send is an adapter to SendContext for callers of the future-based API.
 func (c *Client) Send(message *dhcpv4.DHCPv4) *promise.Future {
 	p := promise.NewPromise()
-	c.packetsLock.Lock()
-	c.packets[message.TransactionID] = p
-	c.packetsLock.Unlock()
-	c.sendQueue <- message
+	ctx, cancel := context.WithCancel(context.Background())
+	// The message is queued now, so that Send keeps the order of messages.
+	replies := c.start(ctx, message).replies
+	go func() {
+		defer cancel()
+		r, ok := <-replies
+		if !ok {
+			return
+		}
+		if r.Err != nil {
+			_ = p.Reject(r.Err)
+		} else {
+			_ = p.Resolve(r.Packet)
+		}
+	}()
 	return p.Future
 }
@@ -0,0 +1,236 @@
+package dhcpv4
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// Authenticator signs outgoing DHCPv4 messages and verifies incoming ones.
+//
+// A Client with an Authenticator signs its requests and ignores replies that
+// fail verification. Server handlers are wrapped with
+// WithAuthentication.
+type Authenticator interface {
+	Sign(d *DHCPv4) error
+	Verify(d *DHCPv4) error
+}
+
+// delayedAuthInfoLen is the length of the authentication information of the
+// delayed authentication protocol: a 32-bit secret ID and an HMAC-MD5.
+const delayedAuthInfoLen = 4 + md5.Size
+
+// DelayedAuth implements the delayed authentication protocol described by
+// RFC 3118, Section 5, with the HMAC-MD5 algorithm.
+//
+// Outgoing messages are signed with a replay detection counter derived from
+// the current time, so that it keeps increasing across restarts. Incoming
+// messages are rejected unless their counter is greater than the last one
+// accepted from the same peer: the server identifier of replies, and the
+// client identifier or hardware address of requests.
+type DelayedAuth struct {
+	// SecretID identifies Key between the client and the server.
+	SecretID uint32
+	Key      []byte
+
+	mu       sync.Mutex
+	lastSent uint64
+	lastSeen map[string]uint64
+}
+
+// NewDelayedAuth returns a DelayedAuth using the given shared secret.
+func NewDelayedAuth(secretID uint32, key []byte) *DelayedAuth {
+	return &DelayedAuth{
+		SecretID: secretID,
+		Key:      key,
+	}
+}
+
+// Sign adds a delayed authentication option to d. No other change may be
+// made to d afterwards, except to the hops and giaddr fields, which relay
+// agents are allowed to change.
+//
+// A DISCOVER only requests authentication: per RFC 3118, Section 5.1, its
+// option carries no authentication information.
+func (a *DelayedAuth) Sign(d *DHCPv4) error {
+	a.mu.Lock()
+	replay := uint64(time.Now().UnixNano())
+	if replay <= a.lastSent {
+		replay = a.lastSent + 1
+	}
+	a.lastSent = replay
+	a.mu.Unlock()
+
+	auth := Authentication{
+		Protocol:        AuthProtocolDelayed,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             RDMMonotonicCounter,
+		ReplayDetection: replay,
+	}
+	if d.MessageType() == MessageTypeDiscover {
+		d.UpdateOption(OptAuthentication(auth))
+		return nil
+	}
+	auth.AuthInfo = make([]byte, delayedAuthInfoLen)
+	binary.BigEndian.PutUint32(auth.AuthInfo, a.SecretID)
+	d.UpdateOption(OptAuthentication(auth))
+	mac, err := delayedAuthMAC(d.ToBytes(), a.Key)
+	if err != nil {
+		return err
+	}
+	copy(auth.AuthInfo[4:], mac)
+	d.UpdateOption(OptAuthentication(auth))
+	return nil
+}
+
+// Verify checks the delayed authentication option of d: the secret ID, the
+// HMAC-MD5 of the message, and that the replay detection counter is greater
+// than the last one accepted from the same peer. If d was decoded by
+// FromBytes, the MAC is computed over the packet as received.
+//
+// A DISCOVER carrying an authentication option without information, which
+// requests authentication, is accepted.
+func (a *DelayedAuth) Verify(d *DHCPv4) error {
+	auth := d.Authentication()
+	if auth == nil {
+		return errors.New("no authentication option")
+	}
+	if auth.Protocol != AuthProtocolDelayed || auth.Algorithm != AuthAlgorithmHMACMD5 || auth.RDM != RDMMonotonicCounter {
+		return fmt.Errorf("unsupported authentication: %s", auth)
+	}
+	if len(auth.AuthInfo) == 0 && d.MessageType() == MessageTypeDiscover {
+		return nil
+	}
+	if len(auth.AuthInfo) != delayedAuthInfoLen {
+		return fmt.Errorf("invalid delayed authentication information length %d", len(auth.AuthInfo))
+	}
+	if id := binary.BigEndian.Uint32(auth.AuthInfo); id != a.SecretID {
+		return fmt.Errorf("unknown secret ID %d", id)
+	}
+
+	packet := d.raw
+	if packet == nil {
+		packet = d.ToBytes()
+	}
+	mac, err := delayedAuthMAC(packet, a.Key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(auth.AuthInfo[4:], mac) {
+		return errors.New("invalid authentication MAC")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.lastSeen == nil {
+		a.lastSeen = make(map[string]uint64)
+	}
+	peer := replayPeer(d)
+	if last, ok := a.lastSeen[peer]; ok && auth.ReplayDetection <= last {
+		return fmt.Errorf("replayed message: replay detection %d is not greater than %d", auth.ReplayDetection, last)
+	}
+	a.lastSeen[peer] = auth.ReplayDetection
+	return nil
+}
+
+// replayPeer returns the key of the replay detection counter of the sender of
+// d: the server identifier of a reply, or the client identity of a request.
+func replayPeer(d *DHCPv4) string {
+	if d.OpCode == OpcodeBootReply {
+		serverID := d.ServerIdentifier()
+		if serverID == nil {
+			serverID = d.ServerIPAddr
+		}
+		return "server " + serverID.String()
+	}
+	return "client " + string(d.ClientIdentity().ToBytes())
+}
+
+// delayedAuthMAC computes the HMAC-MD5 of packet with hops, giaddr and the
+// MAC of its authentication option set to zero, as described by RFC 3118,
+// Section 5.4.
+func delayedAuthMAC(packet []byte, key []byte) ([]byte, error) {
+	offset, err := authOptionOffset(packet)
+	if err != nil {
+		return nil, err
+	}
+	m := append([]byte(nil), packet...)
+	// hops
+	m[3] = 0
+	// giaddr
+	copy(m[24:28], net.IPv4zero.To4())
+	// MAC, after the protocol, algorithm, RDM, replay detection and secret
+	// ID fields
+	macOffset := offset + 3 + 8 + 4
+	copy(m[macOffset:macOffset+md5.Size], make([]byte, md5.Size))
+	mac := hmac.New(md5.New, key)
+	mac.Write(m)
+	return mac.Sum(nil), nil
+}
+
+// authOptionOffset returns the offset of the value of the delayed
+// authentication option in packet.
+func authOptionOffset(packet []byte) (int, error) {
+	i := minPacketLen + len(magicCookie)
+	for i < len(packet) {
+		code := packet[i]
+		if code == OptionEnd.Code() {
+			break
+		}
+		if code == OptionPad.Code() {
+			i++
+			continue
+		}
+		if i+2 > len(packet) {
+			break
+		}
+		length := int(packet[i+1])
+		if code == OptionAuthentication.Code() {
+			if length != 3+8+delayedAuthInfoLen || i+2+length > len(packet) {
+				return 0, fmt.Errorf("invalid delayed authentication option length %d", length)
+			}
+			return i + 2, nil
+		}
+		i += 2 + length
+	}
+	return 0, errors.New("no authentication option")
+}
+
+// WithAuthentication wraps a server handler so that it only sees requests
+// that pass verification by a, and so that the replies it writes to its
+// connection are signed by a.
+func WithAuthentication(a Authenticator, handler Handler) Handler {
+	return func(conn net.PacketConn, peer net.Addr, m *DHCPv4) {
+		if err := a.Verify(m); err != nil {
+			log.Printf("Dropping unauthenticated request from %v: %v", peer, err)
+			return
+		}
+		handler(&authConn{PacketConn: conn, auth: a}, peer, m)
+	}
+}
+
+// authConn signs the DHCPv4 messages written to it.
+type authConn struct {
+	net.PacketConn
+	auth Authenticator
+}
+
+func (c *authConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	m, err := FromBytes(b)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.auth.Sign(m); err != nil {
+		return 0, err
+	}
+	if _, err := c.PacketConn.WriteTo(m.ToBytes(), addr); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,174 @@
+package dhcpv4
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthTestMessage(t *testing.T) *DHCPv4 {
+	m, err := New(
+		WithHwAddr(net.HardwareAddr{1, 2, 3, 4, 5, 6}),
+		WithMessageType(MessageTypeRequest),
+	)
+	require.NoError(t, err)
+	return m
+}
+
+func TestDelayedAuth(t *testing.T) {
+	key := []byte("secret")
+	client := NewDelayedAuth(7, key)
+	server := NewDelayedAuth(7, key)
+
+	m := newAuthTestMessage(t)
+	require.NoError(t, client.Sign(m))
+	auth := m.Authentication()
+	require.NotNil(t, auth)
+	require.Equal(t, AuthProtocolDelayed, auth.Protocol)
+	require.Equal(t, AuthAlgorithmHMACMD5, auth.Algorithm)
+	require.Len(t, auth.AuthInfo, delayedAuthInfoLen)
+
+	// Survives serialization, and relays may change hops and giaddr.
+	m, err := FromBytes(m.ToBytes())
+	require.NoError(t, err)
+	m.HopCount = 1
+	m.GatewayIPAddr = net.IP{10, 0, 0, 1}
+	require.NoError(t, server.Verify(m))
+
+	// Replayed.
+	require.Error(t, server.Verify(m))
+
+	// The next message is accepted.
+	m = newAuthTestMessage(t)
+	require.NoError(t, client.Sign(m))
+	require.NoError(t, server.Verify(m))
+}
+
+func TestDelayedAuthReject(t *testing.T) {
+	client := NewDelayedAuth(7, []byte("secret"))
+
+	// Missing option.
+	require.Error(t, client.Verify(newAuthTestMessage(t)))
+
+	// Wrong key.
+	m := newAuthTestMessage(t)
+	require.NoError(t, client.Sign(m))
+	require.Error(t, NewDelayedAuth(7, []byte("other")).Verify(m))
+
+	// Wrong secret ID.
+	require.Error(t, NewDelayedAuth(8, []byte("secret")).Verify(m))
+
+	// Tampered message.
+	m.UpdateOption(OptMessageType(MessageTypeInform))
+	require.Error(t, NewDelayedAuth(7, []byte("secret")).Verify(m))
+
+	// Other protocol.
+	m = newAuthTestMessage(t)
+	m.UpdateOption(OptAuthentication(Authentication{Protocol: AuthProtocolConfigurationToken}))
+	require.Error(t, client.Verify(m))
+}
+
+func TestDelayedAuthSignIncreasing(t *testing.T) {
+	a := NewDelayedAuth(1, []byte("secret"))
+	var last uint64
+	for i := 0; i < 10; i++ {
+		m := newAuthTestMessage(t)
+		require.NoError(t, a.Sign(m))
+		replay := m.Authentication().ReplayDetection
+		require.Greater(t, replay, last)
+		last = replay
+	}
+}
+
+func TestDelayedAuthDiscover(t *testing.T) {
+	a := NewDelayedAuth(7, []byte("secret"))
+	m, err := NewDiscovery(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	require.NoError(t, a.Sign(m))
+	auth := m.Authentication()
+	require.NotNil(t, auth)
+	require.Equal(t, AuthProtocolDelayed, auth.Protocol)
+	require.Empty(t, auth.AuthInfo)
+	require.NoError(t, a.Verify(m))
+
+	// Only a DISCOVER may omit the authentication information.
+	m.UpdateOption(OptMessageType(MessageTypeRequest))
+	require.Error(t, a.Verify(m))
+}
+
+// TestDelayedAuthWireBytes checks that the MAC is verified over the packet
+// as received, whose options are not in the order nor padded the way
+// ToBytes would write them.
+func TestDelayedAuthWireBytes(t *testing.T) {
+	key := []byte("secret")
+	m := newAuthTestMessage(t)
+	m.Options = Options{}
+	header := m.ToBytes()[:minPacketLen+len(magicCookie)]
+
+	auth := Authentication{
+		Protocol:        AuthProtocolDelayed,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             RDMMonotonicCounter,
+		ReplayDetection: 1,
+		AuthInfo:        make([]byte, delayedAuthInfoLen),
+	}
+	binary.BigEndian.PutUint32(auth.AuthInfo, 7)
+	authValue := auth.ToBytes()
+	packet := append([]byte(nil), header...)
+	packet = append(packet, byte(OptionAuthentication), byte(len(authValue)))
+	packet = append(packet, authValue...)
+	packet = append(packet, byte(OptionPad), byte(OptionPad))
+	packet = append(packet, byte(OptionDHCPMessageType), 1, byte(MessageTypeRequest))
+	packet = append(packet, byte(OptionEnd), 0, 0, 0)
+
+	h := hmac.New(md5.New, key)
+	h.Write(packet)
+	copy(packet[len(header)+2+3+8+4:], h.Sum(nil))
+
+	// relays may change hops and giaddr
+	packet[3] = 2
+	copy(packet[24:28], []byte{10, 0, 0, 1})
+
+	received, err := FromBytes(packet)
+	require.NoError(t, err)
+	require.NotEqual(t, packet, received.ToBytes())
+	require.NoError(t, NewDelayedAuth(7, key).Verify(received))
+}
+
+func TestDelayedAuthReplayPerServer(t *testing.T) {
+	key := []byte("secret")
+	client := NewDelayedAuth(7, key)
+	request := newAuthTestMessage(t)
+
+	reply := func(serverID net.IP, replay uint64) *DHCPv4 {
+		m, err := NewReplyFromRequest(request,
+			WithMessageType(MessageTypeOffer),
+			WithOption(OptServerIdentifier(serverID)),
+		)
+		require.NoError(t, err)
+		auth := Authentication{
+			Protocol:        AuthProtocolDelayed,
+			Algorithm:       AuthAlgorithmHMACMD5,
+			RDM:             RDMMonotonicCounter,
+			ReplayDetection: replay,
+			AuthInfo:        make([]byte, delayedAuthInfoLen),
+		}
+		binary.BigEndian.PutUint32(auth.AuthInfo, 7)
+		m.UpdateOption(OptAuthentication(auth))
+		mac, err := delayedAuthMAC(m.ToBytes(), key)
+		require.NoError(t, err)
+		copy(auth.AuthInfo[4:], mac)
+		m.UpdateOption(OptAuthentication(auth))
+		return m
+	}
+
+	require.NoError(t, client.Verify(reply(net.IP{192, 0, 2, 1}, 100)))
+	// a server with a lower counter is not a replay of another one
+	require.NoError(t, client.Verify(reply(net.IP{192, 0, 2, 2}, 10)))
+	require.Error(t, client.Verify(reply(net.IP{192, 0, 2, 2}, 10)))
+	require.NoError(t, client.Verify(reply(net.IP{192, 0, 2, 1}, 101)))
+}
@@ -16,7 +16,12 @@ const (
 	BootImageTypeMacOSX              BootImageType = 1
 	BootImageTypeMacOSXServer        BootImageType = 2
 	BootImageTypeHardwareDiagnostics BootImageType = 3
-	// 4 - 127 are reserved for future use.
+	// 4 - 127 are reserved for future use, and used by non-Apple NetBoot
+	// images.
+
+	// MaxBootImageType is the largest image type that fits in a boot image
+	// ID, whose top bit is the install flag.
+	MaxBootImageType BootImageType = 0x7f
 )
 
 // bootImageTypeToString maps the different BootImageTypes to human-readable
@@ -28,6 +33,20 @@ var bootImageTypeToString = map[BootImageType]string{
 	BootImageTypeHardwareDiagnostics: "Hardware Diagnostic",
 }
 
+// String returns a human-readable representation of the image type.
+func (t BootImageType) String() string {
+	if s, ok := bootImageTypeToString[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(t))
+}
+
+// IsMacOS returns whether t is one of the image types defined by Apple.
+func (t BootImageType) IsMacOS() bool {
+	_, ok := bootImageTypeToString[t]
+	return ok
+}
+
 // BootImageID describes a boot image ID - whether it's an install image and
 // what kind of boot image (e.g. OS 9, macOS, hardware diagnostics)
 type BootImageID struct {
@@ -52,7 +71,7 @@ func (b BootImageID) Marshal(buf *uio.Lexer) {
 	if b.IsInstall {
 		byte0 |= 0x80
 	}
-	byte0 |= byte(b.ImageType)
+	byte0 |= byte(b.ImageType & MaxBootImageType)
 	buf.Write8(byte0)
 	buf.Write8(byte(0))
 	buf.Write16(b.Index)
@@ -73,6 +92,12 @@ func (b BootImageID) String() string {
 	return s + " " + t + " image"
 }
 
+// Attributes returns the attributes of the image, as matched by a
+// BootImageAttributesFilterList.
+func (b BootImageID) Attributes() BootImageAttributes {
+	return BootImageAttributes{IsInstall: b.IsInstall, ImageType: b.ImageType}
+}
+
 // Unmarshal reads b's binary representation from buf.
 func (b *BootImageID) Unmarshal(buf *uio.Lexer) error {
 	byte0 := buf.Read8()
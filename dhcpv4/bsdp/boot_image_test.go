@@ -53,6 +53,24 @@ func TestBootImageIDString(t *testing.T) {
 	require.Equal(t, "[1001] uninstallable macOS image", b.String())
 }
 
+func TestBootImageIDNonMacOS(t *testing.T) {
+	b := BootImageID{IsInstall: true, ImageType: 42, Index: 7}
+	require.Equal(t, "[7] installable unknown image", b.String())
+	require.Equal(t, []byte{0xaa, 0x00, 0x00, 0x07}, b.ToBytes())
+	require.Equal(t, BootImageAttributes{IsInstall: true, ImageType: 42}, b.Attributes())
+
+	// the kind cannot clobber the install flag
+	b = BootImageID{ImageType: 0xaa, Index: 7}
+	require.Equal(t, []byte{0x2a, 0x00, 0x00, 0x07}, b.ToBytes())
+}
+
+func TestBootImageTypeString(t *testing.T) {
+	require.Equal(t, "macOS Server", BootImageTypeMacOSXServer.String())
+	require.True(t, BootImageTypeMacOSXServer.IsMacOS())
+	require.Equal(t, "unknown (42)", BootImageType(42).String())
+	require.False(t, BootImageType(42).IsMacOS())
+}
+
 /*
  * BootImage
  */
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/insomniacslk/dhcp/dhcpv4"
 )
@@ -26,6 +27,9 @@ type ReplyConfig struct {
 	ServerPriority               uint16
 	Images                       []BootImage
 	DefaultImage, SelectedImage  *BootImage
+	// ShadowMountPath and ShadowFilePath locate the shadow file of the
+	// client in an ACK[SELECT], if set.
+	ShadowMountPath, ShadowFilePath string
 }
 
 // ParseBootImageListFromAck parses the list of boot images presented in the
@@ -38,6 +42,17 @@ func ParseBootImageListFromAck(ack *dhcpv4.DHCPv4) ([]BootImage, error) {
 	return vendorOpts.BootImageList(), nil
 }
 
+// ParseVendorClassIdentifier splits the vendor class identifier of a BSDP
+// client, e.g. "AAPLBSDPC/i386/MacMini6,1", into the client architecture and
+// model.
+func ParseVendorClassIdentifier(vci string) (arch, model string, err error) {
+	parts := strings.SplitN(vci, "/", 3)
+	if len(parts) != 3 || parts[0] != AppleVendorID {
+		return "", "", fmt.Errorf("%q is not a BSDP vendor class identifier", vci)
+	}
+	return parts[1], parts[2], nil
+}
+
 func needsReplyPort(replyPort uint16) bool {
 	return replyPort != 0 && replyPort != dhcpv4.ClientPort
 }
@@ -79,7 +94,7 @@ func (p Packet) Summary() string {
 
 // NewInformListForInterface creates a new INFORM packet for interface ifname
 // with configuration options specified by config.
-func NewInformListForInterface(ifname string, replyPort uint16) (*Packet, error) {
+func NewInformListForInterface(ifname string, replyPort uint16, modifiers ...dhcpv4.Modifier) (*Packet, error) {
 	iface, err := net.InterfaceByName(ifname)
 	if err != nil {
 		return nil, err
@@ -96,7 +111,7 @@ func NewInformListForInterface(ifname string, replyPort uint16) (*Packet, error)
 	if localIPs == nil || len(localIPs) == 0 {
 		return nil, fmt.Errorf("could not get local IPv4 addr for %s", iface.Name)
 	}
-	return NewInformList(iface.HardwareAddr, localIPs[0], replyPort)
+	return NewInformList(iface.HardwareAddr, localIPs[0], replyPort, modifiers...)
 }
 
 // NewInformList creates a new INFORM packet for interface with hardware address
@@ -116,6 +131,7 @@ func NewInformList(hwaddr net.HardwareAddr, localIP net.IP, replyPort uint16, mo
 	vendorOpts := []dhcpv4.Option{
 		OptMessageType(MessageTypeList),
 		OptVersion(Version1_1),
+		OptMaxMessageSize(MaxDHCPMessageSize),
 	}
 	if needsReplyPort(replyPort) {
 		vendorOpts = append(vendorOpts, OptReplyPort(replyPort))
@@ -136,6 +152,22 @@ func NewInformList(hwaddr net.HardwareAddr, localIP net.IP, replyPort uint16, mo
 	return PacketFor(d), nil
 }
 
+// WithVendorOptions adds the given BSDP options to the vendor-specific
+// information of a packet, e.g. a boot image attributes filter list to an
+// INFORM[LIST].
+func WithVendorOptions(opts ...dhcpv4.Option) dhcpv4.Modifier {
+	return func(d *dhcpv4.DHCPv4) {
+		vendorOpts := GetVendorOptions(d.Options)
+		if vendorOpts == nil {
+			vendorOpts = &VendorOptions{make(dhcpv4.Options)}
+		}
+		for _, opt := range opts {
+			vendorOpts.Update(opt)
+		}
+		d.UpdateOption(dhcpv4.Option{Code: dhcpv4.OptionVendorSpecificInformation, Value: vendorOpts})
+	}
+}
+
 // InformSelectForAck constructs an INFORM[SELECT] packet given an ACK to the
 // previously-sent INFORM[LIST].
 func InformSelectForAck(ack *Packet, replyPort uint16, selectedImage BootImage) (*Packet, error) {
@@ -246,9 +278,16 @@ func NewReplyForInformSelect(inform *Packet, config ReplyConfig) (*Packet, error
 	reply.UpdateOption(dhcpv4.OptClassIdentifier(AppleVendorID))
 
 	// BSDP opts.
-	reply.UpdateOption(OptVendorOptions(
+	vendorOpts := []dhcpv4.Option{
 		OptMessageType(MessageTypeSelect),
 		OptSelectedBootImageID(config.SelectedImage.ID),
-	))
+	}
+	if config.ShadowMountPath != "" {
+		vendorOpts = append(vendorOpts, OptShadowMountPath(config.ShadowMountPath))
+	}
+	if config.ShadowFilePath != "" {
+		vendorOpts = append(vendorOpts, OptShadowFilePath(config.ShadowFilePath))
+	}
+	reply.UpdateOption(OptVendorOptions(vendorOpts...))
 	return PacketFor(reply), nil
 }
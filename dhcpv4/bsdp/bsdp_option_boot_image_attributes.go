@@ -0,0 +1,119 @@
+package bsdp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// BootImageAttributes are the attributes of a boot image: the first two bytes
+// of its BootImageID.
+type BootImageAttributes struct {
+	IsInstall bool
+	ImageType BootImageType
+}
+
+// Marshal writes the binary representation to buf.
+func (a BootImageAttributes) Marshal(buf *uio.Lexer) {
+	var byte0 byte
+	if a.IsInstall {
+		byte0 |= 0x80
+	}
+	byte0 |= byte(a.ImageType & MaxBootImageType)
+	buf.Write8(byte0)
+	buf.Write8(byte(0))
+}
+
+// Unmarshal reads a's binary representation from buf.
+func (a *BootImageAttributes) Unmarshal(buf *uio.Lexer) error {
+	byte0 := buf.Read8()
+	_ = buf.Read8()
+	a.IsInstall = byte0&0x80 != 0
+	a.ImageType = BootImageType(byte0 & 0x7f)
+	return buf.Error()
+}
+
+// String converts the attributes to a human-readable representation.
+func (a BootImageAttributes) String() string {
+	if a.IsInstall {
+		return "installable " + a.ImageType.String()
+	}
+	return "uninstallable " + a.ImageType.String()
+}
+
+// BootImageAttributesFilterList is the list of image attributes a client is
+// interested in. Servers only list the images that match one of them.
+//
+// Implements the BSDP option boot image attributes filter list, sent by
+// NetBoot 2.0 clients in an INFORM[LIST].
+type BootImageAttributesFilterList []BootImageAttributes
+
+// FromBytes deserializes data into l.
+func (l *BootImageAttributesFilterList) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	if len(data)%2 != 0 {
+		return fmt.Errorf("BSDP boot image attributes filter list has odd length %d", len(data))
+	}
+	for buf.Has(2) {
+		var a BootImageAttributes
+		if err := a.Unmarshal(buf); err != nil {
+			return err
+		}
+		*l = append(*l, a)
+	}
+	return buf.FinError()
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (l BootImageAttributesFilterList) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, a := range l {
+		a.Marshal(buf)
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (l BootImageAttributesFilterList) String() string {
+	s := make([]string, 0, len(l))
+	for _, a := range l {
+		s = append(s, a.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// Matches returns whether the image with the given ID passes the filter. An
+// empty filter matches all images.
+func (l BootImageAttributesFilterList) Matches(id BootImageID) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, a := range l {
+		if a == id.Attributes() {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the images that pass the filter.
+func (l BootImageAttributesFilterList) Filter(images []BootImage) []BootImage {
+	var filtered []BootImage
+	for _, img := range images {
+		if l.Matches(img.ID) {
+			filtered = append(filtered, img)
+		}
+	}
+	return filtered
+}
+
+// OptBootImageAttributesFilterList returns a new BSDP boot image attributes
+// filter list option.
+func OptBootImageAttributesFilterList(a ...BootImageAttributes) dhcpv4.Option {
+	return dhcpv4.Option{
+		Code:  OptionBootImageAttributesFilterList,
+		Value: BootImageAttributesFilterList(a),
+	}
+}
@@ -0,0 +1,60 @@
+package bsdp
+
+import (
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootImageAttributesFilterListToBytes(t *testing.T) {
+	o := OptBootImageAttributesFilterList(
+		BootImageAttributes{IsInstall: true, ImageType: BootImageTypeMacOSX},
+		BootImageAttributes{ImageType: BootImageTypeHardwareDiagnostics},
+	)
+	require.Equal(t, OptionBootImageAttributesFilterList, o.Code, "Code")
+	require.Equal(t, []byte{0x81, 0x00, 0x03, 0x00}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "BSDP Boot Image Attributes Filter List: installable macOS, uninstallable Hardware Diagnostic", o.String())
+}
+
+func TestBootImageAttributesFilterListFromBytes(t *testing.T) {
+	var l BootImageAttributesFilterList
+	require.NoError(t, l.FromBytes([]byte{0x81, 0x00, 0x2a, 0x00}))
+	require.Equal(t, BootImageAttributesFilterList{
+		{IsInstall: true, ImageType: BootImageTypeMacOSX},
+		{ImageType: 42},
+	}, l)
+
+	l = nil
+	require.Error(t, l.FromBytes([]byte{0x81, 0x00, 0x2a}), "odd length")
+}
+
+func TestGetBootImageAttributesFilterList(t *testing.T) {
+	filter := []BootImageAttributes{{IsInstall: true, ImageType: BootImageTypeMacOSX}}
+	o := VendorOptions{dhcpv4.OptionsFromList(OptBootImageAttributesFilterList(filter...))}
+	require.Equal(t, BootImageAttributesFilterList(filter), o.BootImageAttributesFilterList())
+
+	o = VendorOptions{dhcpv4.Options{OptionBootImageAttributesFilterList.Code(): []byte{1}}}
+	require.Nil(t, o.BootImageAttributesFilterList())
+
+	o = VendorOptions{dhcpv4.Options{}}
+	require.Nil(t, o.BootImageAttributesFilterList())
+}
+
+func TestBootImageAttributesFilterListMatches(t *testing.T) {
+	install := BootImage{ID: BootImageID{IsInstall: true, ImageType: BootImageTypeMacOSX, Index: 1}, Name: "install"}
+	boot := BootImage{ID: BootImageID{ImageType: BootImageTypeMacOSX, Index: 2}, Name: "boot"}
+	diags := BootImage{ID: BootImageID{ImageType: BootImageTypeHardwareDiagnostics, Index: 3}, Name: "diags"}
+	images := []BootImage{install, boot, diags}
+
+	var l BootImageAttributesFilterList
+	require.True(t, l.Matches(install.ID))
+	require.Equal(t, images, l.Filter(images))
+
+	l = BootImageAttributesFilterList{{ImageType: BootImageTypeMacOSX}, {ImageType: BootImageTypeHardwareDiagnostics}}
+	require.False(t, l.Matches(install.ID))
+	require.Equal(t, []BootImage{boot, diags}, l.Filter(images))
+
+	l = BootImageAttributesFilterList{{IsInstall: true, ImageType: BootImageTypeMacOS9}}
+	require.Empty(t, l.Filter(images))
+}
@@ -64,3 +64,31 @@ func OptVersion(version Version) dhcpv4.Option {
 func OptServerIdentifier(ip net.IP) dhcpv4.Option {
 	return dhcpv4.Option{Code: OptionServerIdentifier, Value: dhcpv4.IP(ip)}
 }
+
+// OptShadowMountPath returns a new BSDP shadow mount path option.
+//
+// The shadow mount path is the URL of the share a NetBoot client mounts to
+// store its shadow file, the writable overlay of the read-only disk image.
+func OptShadowMountPath(path string) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionShadowMountPath, Value: dhcpv4.String(path)}
+}
+
+// OptShadowFilePath returns a new BSDP shadow file path option, the path of
+// the shadow file relative to the shadow mount path.
+func OptShadowFilePath(path string) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionShadowFilePath, Value: dhcpv4.String(path)}
+}
+
+// OptMaxMessageSize returns a new BSDP max message size option, the largest
+// DHCP message the client accepts as an answer.
+func OptMaxMessageSize(size uint16) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionMaxMessageSize, Value: dhcpv4.Uint16(size)}
+}
+
+// OptNetboot1_0Firmware returns a new BSDP Netboot 1.0 firmware option.
+//
+// The option has no value: it flags clients whose firmware only speaks
+// NetBoot 1.0.
+func OptNetboot1_0Firmware() dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionNetboot1_0Firmware, Value: dhcpv4.OptionGeneric{}}
+}
@@ -103,3 +103,58 @@ func TestGetServerIdentifier(t *testing.T) {
 	o = VendorOptions{dhcpv4.Options{}}
 	require.Nil(t, o.ServerIdentifier())
 }
+
+func TestOptShadowPaths(t *testing.T) {
+	o := OptShadowMountPath("afp://192.0.2.1/NetBootClients0")
+	require.Equal(t, OptionShadowMountPath, o.Code, "Code")
+	require.Equal(t, []byte("afp://192.0.2.1/NetBootClients0"), o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "BSDP Shadow Mount Path: afp://192.0.2.1/NetBootClients0", o.String())
+
+	o = OptShadowFilePath("NetBoot001/Shadow")
+	require.Equal(t, OptionShadowFilePath, o.Code, "Code")
+	require.Equal(t, "BSDP Shadow File Path: NetBoot001/Shadow", o.String())
+}
+
+func TestGetShadowPaths(t *testing.T) {
+	o := VendorOptions{dhcpv4.OptionsFromList(
+		OptShadowMountPath("afp://192.0.2.1/NetBootClients0"),
+		OptShadowFilePath("NetBoot001/Shadow"),
+	)}
+	require.Equal(t, "afp://192.0.2.1/NetBootClients0", o.ShadowMountPath())
+	require.Equal(t, "NetBoot001/Shadow", o.ShadowFilePath())
+
+	o = VendorOptions{dhcpv4.Options{}}
+	require.Equal(t, "", o.ShadowMountPath())
+	require.Equal(t, "", o.ShadowFilePath())
+}
+
+func TestOptMaxMessageSize(t *testing.T) {
+	o := OptMaxMessageSize(1500)
+	require.Equal(t, OptionMaxMessageSize, o.Code, "Code")
+	require.Equal(t, []byte{5, 220}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "BSDP Max Message Size: 1500", o.String())
+
+	v := VendorOptions{dhcpv4.OptionsFromList(o)}
+	size, err := v.MaxMessageSize()
+	require.NoError(t, err)
+	require.Equal(t, uint16(1500), size)
+
+	v = VendorOptions{dhcpv4.Options{}}
+	_, err = v.MaxMessageSize()
+	require.Error(t, err, "no max message size present")
+}
+
+func TestOptNetboot1_0Firmware(t *testing.T) {
+	o := OptNetboot1_0Firmware()
+	require.Equal(t, OptionNetboot1_0Firmware, o.Code, "Code")
+	require.Empty(t, o.Value.ToBytes(), "ToBytes")
+
+	v := VendorOptions{dhcpv4.OptionsFromList(o)}
+	require.True(t, v.Netboot1_0Firmware())
+	require.Equal(t, []byte{10, 0}, v.ToBytes())
+	v = VendorOptions{dhcpv4.OptionsFromList(OptMachineName("mac"), o)}
+	require.Equal(t, []byte{10, 0, 130, 3, 'm', 'a', 'c'}, v.ToBytes())
+
+	v = VendorOptions{dhcpv4.Options{}}
+	require.False(t, v.Netboot1_0Firmware())
+}
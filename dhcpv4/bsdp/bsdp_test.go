@@ -74,6 +74,9 @@ func TestNewInformList_NoReplyPort(t *testing.T) {
 	require.NotNil(t, vendorOpts, "vendor opts not present")
 	require.True(t, vendorOpts.Has(OptionMessageType))
 	require.True(t, vendorOpts.Has(OptionVersion))
+	size, err := vendorOpts.MaxMessageSize()
+	require.NoError(t, err)
+	require.Equal(t, uint16(MaxDHCPMessageSize), size)
 
 	mt := vendorOpts.MessageType()
 	require.Equal(t, MessageTypeList, mt)
@@ -101,6 +104,25 @@ func TestNewInformList_ReplyPort(t *testing.T) {
 	require.Equal(t, replyPort, port)
 }
 
+func TestNewInformList_WithVendorOptions(t *testing.T) {
+	filter := []BootImageAttributes{{IsInstall: true, ImageType: BootImageTypeMacOSX}}
+	m, err := NewInformList(net.HardwareAddr{1, 2, 3, 4, 5, 6}, net.IPv4(10, 10, 11, 11), 0,
+		WithVendorOptions(OptBootImageAttributesFilterList(filter...), OptNetboot1_0Firmware()))
+	require.NoError(t, err)
+
+	vendorOpts := GetVendorOptions(m.Options)
+	require.Equal(t, MessageTypeList, vendorOpts.MessageType())
+	require.Equal(t, BootImageAttributesFilterList(filter), vendorOpts.BootImageAttributesFilterList())
+	require.True(t, vendorOpts.Netboot1_0Firmware())
+
+	// the options survive serialization
+	d, err := dhcpv4.FromBytes(m.ToBytes())
+	require.NoError(t, err)
+	vendorOpts = GetVendorOptions(d.Options)
+	require.Equal(t, BootImageAttributesFilterList(filter), vendorOpts.BootImageAttributesFilterList())
+	require.True(t, vendorOpts.Netboot1_0Firmware())
+}
+
 func newAck(hwAddr net.HardwareAddr, transactionID [4]byte) *dhcpv4.DHCPv4 {
 	ack, _ := dhcpv4.New()
 	ack.OpCode = dhcpv4.OpcodeBootReply
@@ -358,6 +380,16 @@ func TestNewReplyForInformSelect(t *testing.T) {
 	vendorOpts := GetVendorOptions(ack.Options)
 	RequireHasOption(t, vendorOpts.Options, OptMessageType(MessageTypeSelect))
 	RequireHasOption(t, vendorOpts.Options, OptSelectedBootImageID(images[0].ID))
+	require.False(t, vendorOpts.Has(OptionShadowMountPath))
+	require.False(t, vendorOpts.Has(OptionShadowFilePath))
+
+	config.ShadowMountPath = "afp://192.0.2.1/NetBootClients0"
+	config.ShadowFilePath = "NetBoot001/Shadow"
+	ack, err = NewReplyForInformSelect(inform, config)
+	require.NoError(t, err)
+	vendorOpts = GetVendorOptions(ack.Options)
+	require.Equal(t, "afp://192.0.2.1/NetBootClients0", vendorOpts.ShadowMountPath())
+	require.Equal(t, "NetBoot001/Shadow", vendorOpts.ShadowFilePath())
 }
 
 func TestMessageTypeForPacket(t *testing.T) {
@@ -408,3 +440,15 @@ func TestMessageTypeForPacket(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVendorClassIdentifier(t *testing.T) {
+	arch, model, err := ParseVendorClassIdentifier("AAPLBSDPC/i386/MacMini6,1")
+	require.NoError(t, err)
+	require.Equal(t, "i386", arch)
+	require.Equal(t, "MacMini6,1", model)
+
+	_, _, err = ParseVendorClassIdentifier("PXEClient:Arch:00000")
+	require.Error(t, err)
+	_, _, err = ParseVendorClassIdentifier("AAPLBSDPC/i386")
+	require.Error(t, err)
+}
@@ -0,0 +1,238 @@
+package bsdp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CatalogImage is a NetBoot image served by a Server, along with the clients
+// it can boot and the files they boot from.
+type CatalogImage struct {
+	BootImage
+
+	// IsDefault marks the image offered as the default boot image to the
+	// clients it supports.
+	IsDefault bool
+
+	// Architectures lists the client architectures the image boots, e.g.
+	// "i386". An empty list matches all architectures.
+	Architectures []string
+
+	// EnabledModels restricts the image to these client models, e.g.
+	// "MacMini6,1". An empty list matches all models not in DisabledModels.
+	EnabledModels  []string
+	DisabledModels []string
+
+	// BootFile is the boot file name handed to the clients that select the
+	// image, and RootPath the location of its disk image.
+	BootFile string
+	RootPath string
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Supports returns whether the image can boot a client of the given
+// architecture and model, as found in its vendor class identifier.
+func (i CatalogImage) Supports(arch, model string) bool {
+	if len(i.Architectures) > 0 && !containsString(i.Architectures, arch) {
+		return false
+	}
+	if containsString(i.DisabledModels, model) {
+		return false
+	}
+	return len(i.EnabledModels) == 0 || containsString(i.EnabledModels, model)
+}
+
+// Catalog is the list of NetBoot images of a Server.
+type Catalog struct {
+	Images []CatalogImage
+}
+
+// NewCatalog returns a Catalog of the given images. Image indexes must be
+// non-zero and unique, kinds at most MaxBootImageType, and names must fit in a
+// boot image list.
+func NewCatalog(images ...CatalogImage) (*Catalog, error) {
+	seen := make(map[uint16]string, len(images))
+	for _, img := range images {
+		if img.ID.Index == 0 {
+			return nil, fmt.Errorf("image %q has no index", img.Name)
+		}
+		if name, ok := seen[img.ID.Index]; ok {
+			return nil, fmt.Errorf("images %q and %q have the same index %d", name, img.Name, img.ID.Index)
+		}
+		if img.ID.ImageType > MaxBootImageType {
+			return nil, fmt.Errorf("image %q has invalid kind %d", img.Name, img.ID.ImageType)
+		}
+		if len(img.Name) > 255 {
+			return nil, fmt.Errorf("image name %q is longer than 255 bytes", img.Name)
+		}
+		seen[img.ID.Index] = img.Name
+	}
+	return &Catalog{Images: images}, nil
+}
+
+// Image returns the image with the given ID, or nil if there is none.
+func (c *Catalog) Image(id BootImageID) *CatalogImage {
+	for i := range c.Images {
+		if c.Images[i].ID == id {
+			return &c.Images[i]
+		}
+	}
+	return nil
+}
+
+// ImagesFor returns the images that can boot a client of the given
+// architecture and model.
+func (c *Catalog) ImagesFor(arch, model string) []CatalogImage {
+	var images []CatalogImage
+	for _, img := range c.Images {
+		if img.Supports(arch, model) {
+			images = append(images, img)
+		}
+	}
+	return images
+}
+
+// defaultImage returns the first image marked as default, or the first image
+// if none is.
+func defaultImage(images []CatalogImage) *CatalogImage {
+	if len(images) == 0 {
+		return nil
+	}
+	for i := range images {
+		if images[i].IsDefault {
+			return &images[i]
+		}
+	}
+	return &images[0]
+}
+
+// LoadCatalog loads a Catalog from path, which is either a directory of
+// NetBoot image directories, a single NetBoot image directory (.nbi), see
+// LoadNBI, or a JSON manifest (.json), see ReadCatalog.
+func LoadCatalog(path string) (*Catalog, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		if strings.ToLower(filepath.Ext(path)) != ".json" {
+			return nil, fmt.Errorf("unknown manifest format for %s, expected .json", path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		c, err := ReadCatalog(f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load manifest %s: %v", path, err)
+		}
+		return c, nil
+	}
+	if strings.HasSuffix(path, ".nbi") {
+		img, err := LoadNBI(path)
+		if err != nil || img == nil {
+			return nil, fmt.Errorf("cannot load NetBoot image %s: %v", path, err)
+		}
+		return NewCatalog(*img)
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var images []CatalogImage
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasSuffix(e.Name(), ".nbi") {
+			continue
+		}
+		img, err := LoadNBI(filepath.Join(path, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if img != nil {
+			images = append(images, *img)
+		}
+	}
+	return NewCatalog(images...)
+}
+
+// ReadCatalog reads a Catalog from a JSON manifest like:
+//
+//	{"images": [{
+//		"index": 1,
+//		"name": "macOS Installer",
+//		"kind": 1,
+//		"install": true,
+//		"default": true,
+//		"architectures": ["i386"],
+//		"enabled_models": ["MacMini6,1"],
+//		"boot_file": "Install.nbi/i386/booter",
+//		"root_path": "http://192.0.2.1/NetBoot/Install.nbi/NetInstall.dmg"
+//	}]}
+//
+// where kind is a BootImageType. Manifests in other formats can be decoded
+// into a Manifest, see Manifest.Catalog.
+func ReadCatalog(r io.Reader) (*Catalog, error) {
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("cannot parse manifest: %v", err)
+	}
+	return m.Catalog()
+}
+
+// Manifest is the serialized form of a Catalog, see ReadCatalog. Its field
+// tags also suit YAML decoders.
+type Manifest struct {
+	Images []ManifestImage `json:"images" yaml:"images"`
+}
+
+// ManifestImage is an image of a Manifest.
+type ManifestImage struct {
+	Index          uint16        `json:"index" yaml:"index"`
+	Name           string        `json:"name" yaml:"name"`
+	Kind           BootImageType `json:"kind" yaml:"kind"`
+	IsInstall      bool          `json:"install" yaml:"install"`
+	IsDefault      bool          `json:"default" yaml:"default"`
+	Architectures  []string      `json:"architectures" yaml:"architectures"`
+	EnabledModels  []string      `json:"enabled_models" yaml:"enabled_models"`
+	DisabledModels []string      `json:"disabled_models" yaml:"disabled_models"`
+	BootFile       string        `json:"boot_file" yaml:"boot_file"`
+	RootPath       string        `json:"root_path" yaml:"root_path"`
+}
+
+// Catalog returns the Catalog of the images of m, see NewCatalog.
+func (m Manifest) Catalog() (*Catalog, error) {
+	images := make([]CatalogImage, 0, len(m.Images))
+	for _, img := range m.Images {
+		images = append(images, CatalogImage{
+			BootImage: BootImage{
+				ID: BootImageID{
+					IsInstall: img.IsInstall,
+					ImageType: img.Kind,
+					Index:     img.Index,
+				},
+				Name: img.Name,
+			},
+			IsDefault:      img.IsDefault,
+			Architectures:  img.Architectures,
+			EnabledModels:  img.EnabledModels,
+			DisabledModels: img.DisabledModels,
+			BootFile:       img.BootFile,
+			RootPath:       img.RootPath,
+		})
+	}
+	return NewCatalog(images...)
+}
@@ -0,0 +1,151 @@
+package bsdp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogImageSupports(t *testing.T) {
+	img := CatalogImage{}
+	require.True(t, img.Supports("i386", "MacMini6,1"))
+
+	img.Architectures = []string{"i386"}
+	require.True(t, img.Supports("i386", "MacMini6,1"))
+	require.False(t, img.Supports("ppc", "MacMini6,1"))
+
+	img.DisabledModels = []string{"MacBookPro11,1"}
+	require.True(t, img.Supports("i386", "MacMini6,1"))
+	require.False(t, img.Supports("i386", "MacBookPro11,1"))
+
+	img.EnabledModels = []string{"MacPro6,1"}
+	require.False(t, img.Supports("i386", "MacMini6,1"))
+	require.True(t, img.Supports("i386", "MacPro6,1"))
+}
+
+func TestNewCatalog(t *testing.T) {
+	a := CatalogImage{BootImage: BootImage{ID: BootImageID{Index: 1}, Name: "a"}}
+	b := CatalogImage{BootImage: BootImage{ID: BootImageID{Index: 2}, Name: "b"}}
+	c, err := NewCatalog(a, b)
+	require.NoError(t, err)
+	require.Equal(t, "b", c.Image(BootImageID{Index: 2}).Name)
+	require.Nil(t, c.Image(BootImageID{Index: 3}))
+
+	// duplicate index
+	_, err = NewCatalog(a, a)
+	require.Error(t, err)
+	// no index
+	_, err = NewCatalog(CatalogImage{})
+	require.Error(t, err)
+	// kind does not fit in a boot image ID
+	_, err = NewCatalog(CatalogImage{BootImage: BootImage{ID: BootImageID{ImageType: 0x80, Index: 1}}})
+	require.Error(t, err)
+}
+
+func TestCatalogImagesFor(t *testing.T) {
+	c, err := NewCatalog(
+		CatalogImage{BootImage: BootImage{ID: BootImageID{Index: 1}, Name: "intel"}, Architectures: []string{"i386"}},
+		CatalogImage{BootImage: BootImage{ID: BootImageID{Index: 2}, Name: "any"}, IsDefault: true},
+		CatalogImage{BootImage: BootImage{ID: BootImageID{Index: 3}, Name: "mini"}, EnabledModels: []string{"MacMini6,1"}},
+	)
+	require.NoError(t, err)
+
+	images := c.ImagesFor("i386", "MacMini6,1")
+	require.Len(t, images, 3)
+	require.Equal(t, "any", defaultImage(images).Name)
+
+	images = c.ImagesFor("ppc", "PowerMac7,2")
+	require.Len(t, images, 1)
+	require.Equal(t, "any", images[0].Name)
+
+	require.Nil(t, defaultImage(nil))
+	require.Equal(t, "intel", defaultImage(c.Images[:1]).Name)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "bsdp")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestLoadCatalogJSON(t *testing.T) {
+	path := filepath.Join(tempDir(t), "catalog.json")
+	writeFile(t, path, `{"images": [{
+		"index": 4096,
+		"name": "macOS Installer",
+		"kind": 1,
+		"install": true,
+		"default": true,
+		"architectures": ["i386"],
+		"enabled_models": ["MacMini6,1"],
+		"boot_file": "Install.nbi/i386/booter",
+		"root_path": "http://192.0.2.1/NetBoot/Install.nbi/NetInstall.dmg"
+	}]}`)
+	c, err := LoadCatalog(path)
+	require.NoError(t, err)
+	require.Equal(t, []CatalogImage{{
+		BootImage: BootImage{
+			ID:   BootImageID{IsInstall: true, ImageType: BootImageTypeMacOSX, Index: 4096},
+			Name: "macOS Installer",
+		},
+		IsDefault:     true,
+		Architectures: []string{"i386"},
+		EnabledModels: []string{"MacMini6,1"},
+		BootFile:      "Install.nbi/i386/booter",
+		RootPath:      "http://192.0.2.1/NetBoot/Install.nbi/NetInstall.dmg",
+	}}, c.Images)
+}
+
+func TestReadCatalog(t *testing.T) {
+	c, err := ReadCatalog(strings.NewReader(`{"images": [
+		{"index": 1, "name": "Diagnostics", "kind": 3, "disabled_models": ["MacMini6,1"]},
+		{"index": 2, "name": "macOS", "kind": 1}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, c.Images, 2)
+	require.Equal(t, BootImageID{ImageType: BootImageTypeHardwareDiagnostics, Index: 1}, c.Images[0].ID)
+	require.Equal(t, []string{"MacMini6,1"}, c.Images[0].DisabledModels)
+	require.Equal(t, "macOS", c.Images[1].Name)
+
+	_, err = ReadCatalog(strings.NewReader(`{"images": [{"index": 1, "name": "a"}, {"index": 1, "name": "b"}]}`))
+	require.Error(t, err)
+}
+
+func TestManifestCatalog(t *testing.T) {
+	c, err := Manifest{Images: []ManifestImage{{Index: 1, Name: "macOS", Kind: BootImageTypeMacOSX, IsDefault: true}}}.Catalog()
+	require.NoError(t, err)
+	require.Equal(t, []CatalogImage{{
+		BootImage: BootImage{ID: BootImageID{ImageType: BootImageTypeMacOSX, Index: 1}, Name: "macOS"},
+		IsDefault: true,
+	}}, c.Images)
+}
+
+func TestLoadCatalogInvalid(t *testing.T) {
+	dir := tempDir(t)
+	_, err := LoadCatalog(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+
+	path := filepath.Join(dir, "catalog.txt")
+	writeFile(t, path, "images: []")
+	_, err = LoadCatalog(path)
+	require.Error(t, err)
+
+	path = filepath.Join(dir, "catalog.json")
+	writeFile(t, path, "{")
+	_, err = LoadCatalog(path)
+	require.Error(t, err)
+
+	path = filepath.Join(dir, "catalog.yaml")
+	writeFile(t, path, "images: []")
+	_, err = LoadCatalog(path)
+	require.Error(t, err)
+}
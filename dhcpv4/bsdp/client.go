@@ -2,73 +2,189 @@ package bsdp
 
 import (
 	"errors"
+	"log"
+	"net"
 
 	"github.com/insomniacslk/dhcp/dhcpv4"
+	"golang.org/x/sys/unix"
 )
 
+// DefaultRetries is the number of times a client retransmits an INFORM that
+// gets no answer.
+const DefaultRetries = 3
+
 // Client represents a BSDP client that can perform BSDP exchanges via the
 // broadcast address.
 type Client struct {
 	dhcpv4.Client
+	// Selector picks the image to boot among the ones offered by the
+	// NetBoot servers. If nil, DefaultImageSelector is used.
+	Selector ImageSelector
+	// AttributesFilter, if not empty, asks the servers to only list the
+	// images with one of the given attributes.
+	AttributesFilter BootImageAttributesFilterList
+	// Retries is the number of times the INFORM[LIST] and the
+	// INFORM[SELECT] are retransmitted when they get no answer.
+	Retries int
 }
 
 // NewClient constructs a new client with default read and write timeouts from
 // dhcpv4.Client.
 func NewClient() *Client {
-	return &Client{Client: dhcpv4.Client{}}
+	return &Client{
+		Client:  *dhcpv4.NewClient(),
+		Retries: DefaultRetries,
+	}
+}
+
+// BootSelection is the outcome of a BSDP exchange: the selected NetBoot server
+// and image, and where the next boot stage loads from.
+type BootSelection struct {
+	// ServerIP is the BSDP server identifier of the selected server.
+	ServerIP net.IP
+	Image    BootImage
+	// NextServer is the server to load BootFile from, usually over TFTP.
+	NextServer net.IP
+	BootFile   string
+	// RootPath locates the disk image of the selected image.
+	RootPath string
+	// ShadowMountPath and ShadowFilePath locate the shadow file of the
+	// client, if the server assigned one.
+	ShadowMountPath string
+	ShadowFilePath  string
 }
 
 // Exchange runs a full BSDP exchange (Inform[list], Ack, Inform[select],
 // Ack). Returns a list of DHCPv4 structures representing the exchange.
+//
+// The ACK[LIST] of all the servers that answer within the read timeout are
+// collected, and the image is picked with c.Selector. Only the ACK[LIST] of
+// the selected server is part of the exchange.
 func (c *Client) Exchange(ifname string) ([]*Packet, error) {
+	conversation, _, err := c.exchange(ifname)
+	return conversation, err
+}
+
+// Select runs a full BSDP exchange like Exchange, and returns the selected
+// server and image.
+func (c *Client) Select(ifname string) (*BootSelection, error) {
+	_, selection, err := c.exchange(ifname)
+	return selection, err
+}
+
+// sendReceive sends packet and waits for the ACKs to it, retransmitting it up
+// to c.Retries times. It returns all the ACKs received within the read
+// timeout if all is true, and the first one otherwise.
+func (c *Client) sendReceive(sendFd, recvFd int, packet *Packet, all bool) ([]*dhcpv4.DHCPv4, error) {
+	var err error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if all {
+			var acks []*dhcpv4.DHCPv4
+			acks, err = c.Client.SendReceiveAll(sendFd, recvFd, packet.v4(), dhcpv4.MessageTypeAck)
+			if err == nil {
+				return acks, nil
+			}
+		} else {
+			var ack *dhcpv4.DHCPv4
+			ack, err = c.Client.SendReceive(sendFd, recvFd, packet.v4(), dhcpv4.MessageTypeAck)
+			if err == nil {
+				return []*dhcpv4.DHCPv4{ack}, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+func (c *Client) exchange(ifname string) ([]*Packet, *BootSelection, error) {
 	conversation := make([]*Packet, 0)
 
 	// Get our file descriptor for the broadcast socket.
 	sendFd, err := dhcpv4.MakeBroadcastSocket(ifname)
 	if err != nil {
-		return conversation, err
+		return conversation, nil, err
 	}
+	defer unix.Close(sendFd)
 	recvFd, err := dhcpv4.MakeListeningSocket(ifname)
 	if err != nil {
-		return conversation, err
+		return conversation, nil, err
 	}
+	defer unix.Close(recvFd)
 
 	// INFORM[LIST]
-	informList, err := NewInformListForInterface(ifname, dhcpv4.ClientPort)
+	var modifiers []dhcpv4.Modifier
+	if len(c.AttributesFilter) > 0 {
+		modifiers = append(modifiers, WithVendorOptions(OptBootImageAttributesFilterList(c.AttributesFilter...)))
+	}
+	informList, err := NewInformListForInterface(ifname, dhcpv4.ClientPort, modifiers...)
 	if err != nil {
-		return conversation, err
+		return conversation, nil, err
 	}
 	conversation = append(conversation, informList)
 
-	// ACK[LIST]
-	ackForList, err := c.Client.SendReceive(sendFd, recvFd, informList.v4(), dhcpv4.MessageTypeAck)
+	// ACK[LIST], from all servers
+	acksForList, err := c.sendReceive(sendFd, recvFd, informList, true)
 	if err != nil {
-		return conversation, err
+		return conversation, nil, err
 	}
-
-	// Rewrite vendor-specific option for pretty printing.
-	conversation = append(conversation, PacketFor(ackForList))
-
-	// Parse boot images sent back by server
-	bootImages, err := ParseBootImageListFromAck(ackForList)
-	if err != nil {
-		return conversation, err
+	offers := make([]*Offer, 0, len(acksForList))
+	for _, ack := range acksForList {
+		offer, err := NewOffer(PacketFor(ack))
+		if err != nil {
+			log.Printf("Ignoring ACK[LIST]: %v", err)
+			continue
+		}
+		offers = append(offers, offer)
 	}
-	if len(bootImages) == 0 {
-		return conversation, errors.New("got no BootImages from server")
+	if len(offers) == 0 {
+		return conversation, nil, errors.New("got no valid ACK[LIST] from servers")
+	}
+	selector := c.Selector
+	if selector == nil {
+		selector = DefaultImageSelector
+	}
+	offer, image, err := ChooseOffer(offers, selector)
+	if err != nil {
+		return conversation, nil, err
 	}
+	conversation = append(conversation, offer.Ack)
 
 	// INFORM[SELECT]
-	informSelect, err := InformSelectForAck(PacketFor(ackForList), dhcpv4.ClientPort, bootImages[0])
+	informSelect, err := InformSelectForAck(offer.Ack, dhcpv4.ClientPort, *image)
 	if err != nil {
-		return conversation, err
+		return conversation, nil, err
+	}
+	// A new transaction, so that late ACK[LIST] are not taken as the answer.
+	if informSelect.TransactionID, err = dhcpv4.GenerateTransactionID(); err != nil {
+		return conversation, nil, err
 	}
 	conversation = append(conversation, informSelect)
 
 	// ACK[SELECT]
-	ackForSelect, err := c.Client.SendReceive(sendFd, recvFd, informSelect.v4(), dhcpv4.MessageTypeAck)
+	acksForSelect, err := c.sendReceive(sendFd, recvFd, informSelect, false)
 	if err != nil {
-		return conversation, err
+		return conversation, nil, err
+	}
+	ackForSelect := PacketFor(acksForSelect[0])
+	conversation = append(conversation, ackForSelect)
+	if MessageTypeFromPacket(ackForSelect.v4()) != MessageTypeSelect {
+		return conversation, nil, errors.New("server did not acknowledge the selected image")
+	}
+	return conversation, selectionFromAck(offer, *image, ackForSelect), nil
+}
+
+// selectionFromAck builds the BootSelection of image on the server of offer
+// from its ACK[SELECT].
+func selectionFromAck(offer *Offer, image BootImage, ack *Packet) *BootSelection {
+	selection := &BootSelection{
+		ServerIP:   offer.ServerIP,
+		Image:      image,
+		NextServer: ack.ServerIPAddr,
+		BootFile:   ack.BootFileName,
+		RootPath:   ack.RootPath(),
+	}
+	if vendorOpts := GetVendorOptions(ack.Options); vendorOpts != nil {
+		selection.ShadowMountPath = vendorOpts.ShadowMountPath()
+		selection.ShadowFilePath = vendorOpts.ShadowFilePath()
 	}
-	return append(conversation, PacketFor(ackForSelect)), nil
+	return selection
 }
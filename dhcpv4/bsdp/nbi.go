@@ -0,0 +1,179 @@
+package bsdp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NBImageInfoFile is the property list that describes a NetBoot image
+// directory.
+const NBImageInfoFile = "NBImageInfo.plist"
+
+// LoadNBI loads the NetBoot image directory dir, as created by System Image
+// Utility. It returns nil if the image is disabled.
+//
+// The BootFile and RootPath of the image are relative to the parent
+// directory of dir, see Server.RootPathPrefix.
+func LoadNBI(dir string) (*CatalogImage, error) {
+	f, err := os.Open(filepath.Join(dir, NBImageInfoFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	v, err := parsePlist(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", f.Name(), err)
+	}
+	info, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not a dictionary", f.Name())
+	}
+	if enabled, ok := info["IsEnabled"].(bool); ok && !enabled {
+		return nil, nil
+	}
+
+	img := CatalogImage{}
+	index, _ := info["Index"].(int64)
+	if index <= 0 || index > 0xffff {
+		return nil, fmt.Errorf("%s: invalid image index %d", f.Name(), index)
+	}
+	img.ID.Index = uint16(index)
+	kind, _ := info["Kind"].(int64)
+	img.ID.ImageType = BootImageType(kind)
+	img.ID.IsInstall, _ = info["IsInstall"].(bool)
+	img.IsDefault, _ = info["IsDefault"].(bool)
+	img.Name, _ = info["Name"].(string)
+	if img.Name == "" {
+		img.Name = strings.TrimSuffix(filepath.Base(dir), ".nbi")
+	}
+	img.Architectures = plistStrings(info["Architectures"])
+	img.EnabledModels = plistStrings(info["EnabledSystemIdentifiers"])
+	img.DisabledModels = plistStrings(info["DisabledSystemIdentifiers"])
+
+	// The booter sits in a directory named after the architecture.
+	name := filepath.Base(dir)
+	arch := "i386"
+	if len(img.Architectures) > 0 {
+		arch = img.Architectures[0]
+	}
+	if bootFile, _ := info["BootFile"].(string); bootFile != "" {
+		img.BootFile = path.Join(name, arch, bootFile)
+	}
+	if rootPath, _ := info["RootPath"].(string); rootPath != "" {
+		img.RootPath = path.Join(name, rootPath)
+	}
+	return &img, nil
+}
+
+func plistStrings(v interface{}) []string {
+	list, _ := v.([]interface{})
+	var s []string
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			s = append(s, str)
+		}
+	}
+	return s
+}
+
+// parsePlist decodes an XML property list. Dictionaries are decoded as
+// map[string]interface{}, arrays as []interface{}, integers as int64, reals
+// as float64, booleans as bool, and strings, dates and data as string.
+func parsePlist(r io.Reader) (interface{}, error) {
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			if se.Name.Local != "plist" {
+				return nil, fmt.Errorf("unexpected element <%s>, expected <plist>", se.Name.Local)
+			}
+			v, _, err := parsePlistValue(d)
+			return v, err
+		}
+	}
+}
+
+// parsePlistValue decodes the next value of d. It returns true if it found
+// the end of the enclosing element instead.
+func parsePlistValue(d *xml.Decoder) (interface{}, bool, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, false, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil, true, nil
+		case xml.StartElement:
+			v, err := parsePlistElement(d, t)
+			return v, false, err
+		}
+	}
+}
+
+func parsePlistElement(d *xml.Decoder, se xml.StartElement) (interface{}, error) {
+	switch se.Name.Local {
+	case "dict":
+		dict := make(map[string]interface{})
+		for {
+			k, end, err := parsePlistValue(d)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return dict, nil
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("dictionary key is %T, expected a string", k)
+			}
+			v, end, err := parsePlistValue(d)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return nil, fmt.Errorf("dictionary key %q has no value", key)
+			}
+			dict[key] = v
+		}
+	case "array":
+		array := []interface{}{}
+		for {
+			v, end, err := parsePlistValue(d)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return array, nil
+			}
+			array = append(array, v)
+		}
+	case "true", "false":
+		if err := d.Skip(); err != nil {
+			return nil, err
+		}
+		return se.Name.Local == "true", nil
+	}
+	var text string
+	if err := d.DecodeElement(&text, &se); err != nil {
+		return nil, err
+	}
+	switch se.Name.Local {
+	case "key", "string", "date", "data":
+		return text, nil
+	case "integer":
+		return strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	case "real":
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	}
+	return nil, fmt.Errorf("unknown property list element <%s>", se.Name.Local)
+}
@@ -0,0 +1,147 @@
+package bsdp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sampleNBImageInfo = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Architectures</key>
+	<array>
+		<string>i386</string>
+	</array>
+	<key>BootFile</key>
+	<string>booter</string>
+	<key>Description</key>
+	<string></string>
+	<key>DisabledSystemIdentifiers</key>
+	<array/>
+	<key>EnabledSystemIdentifiers</key>
+	<array>
+		<string>MacMini6,1</string>
+		<string>MacPro6,1</string>
+	</array>
+	<key>Index</key>
+	<integer>%d</integer>
+	<key>IsDefault</key>
+	<true/>
+	<key>IsEnabled</key>
+	<%s/>
+	<key>IsInstall</key>
+	<true/>
+	<key>Kind</key>
+	<integer>1</integer>
+	<key>Language</key>
+	<string>Default</string>
+	<key>Name</key>
+	<string>%s</string>
+	<key>RootPath</key>
+	<string>NetInstall.dmg</string>
+	<key>Type</key>
+	<string>HTTP</string>
+	<key>osVersion</key>
+	<real>10.13</real>
+</dict>
+</plist>
+`
+
+func writeNBI(t *testing.T, dir, name string, index int, enabled bool) string {
+	nbi := filepath.Join(dir, name+".nbi")
+	require.NoError(t, os.Mkdir(nbi, 0755))
+	isEnabled := "false"
+	if enabled {
+		isEnabled = "true"
+	}
+	info := fmt.Sprintf(sampleNBImageInfo, index, isEnabled, name)
+	writeFile(t, filepath.Join(nbi, NBImageInfoFile), info)
+	return nbi
+}
+
+func TestLoadNBI(t *testing.T) {
+	nbi := writeNBI(t, tempDir(t), "Install", 7, true)
+	img, err := LoadNBI(nbi)
+	require.NoError(t, err)
+	require.Equal(t, &CatalogImage{
+		BootImage: BootImage{
+			ID:   BootImageID{IsInstall: true, ImageType: BootImageTypeMacOSX, Index: 7},
+			Name: "Install",
+		},
+		IsDefault:     true,
+		Architectures: []string{"i386"},
+		EnabledModels: []string{"MacMini6,1", "MacPro6,1"},
+		BootFile:      "Install.nbi/i386/booter",
+		RootPath:      "Install.nbi/NetInstall.dmg",
+	}, img)
+}
+
+func TestLoadNBIDisabled(t *testing.T) {
+	nbi := writeNBI(t, tempDir(t), "Install", 7, false)
+	img, err := LoadNBI(nbi)
+	require.NoError(t, err)
+	require.Nil(t, img)
+}
+
+func TestLoadNBIInvalid(t *testing.T) {
+	dir := tempDir(t)
+	_, err := LoadNBI(dir)
+	require.Error(t, err)
+
+	writeFile(t, filepath.Join(dir, NBImageInfoFile), "<plist><dict><key>Index</key></dict></plist>")
+	_, err = LoadNBI(dir)
+	require.Error(t, err)
+
+	writeFile(t, filepath.Join(dir, NBImageInfoFile), "<plist><array/></plist>")
+	_, err = LoadNBI(dir)
+	require.Error(t, err)
+
+	// no index
+	writeFile(t, filepath.Join(dir, NBImageInfoFile), "<plist><dict><key>Name</key><string>x</string></dict></plist>")
+	_, err = LoadNBI(dir)
+	require.Error(t, err)
+}
+
+func TestLoadCatalogNBIDirectories(t *testing.T) {
+	dir := tempDir(t)
+	writeNBI(t, dir, "Install", 1, true)
+	writeNBI(t, dir, "Diags", 2, true)
+	writeNBI(t, dir, "Old", 3, false)
+	writeFile(t, filepath.Join(dir, "README"), "not an image")
+
+	c, err := LoadCatalog(dir)
+	require.NoError(t, err)
+	require.Len(t, c.Images, 2)
+	require.Equal(t, "Diags", c.Images[0].Name)
+	require.Equal(t, "Install", c.Images[1].Name)
+
+	c, err = LoadCatalog(filepath.Join(dir, "Install.nbi"))
+	require.NoError(t, err)
+	require.Len(t, c.Images, 1)
+
+	_, err = LoadCatalog(filepath.Join(dir, "Old.nbi"))
+	require.Error(t, err)
+}
+
+func TestParsePlist(t *testing.T) {
+	v, err := parsePlist(strings.NewReader(`<plist><dict>
+		<key>a</key><array><integer> 1 </integer><real>2.5</real><false/></array>
+		<key>b</key><string> spaced </string>
+	</dict></plist>`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"a": []interface{}{int64(1), 2.5, false},
+		"b": " spaced ",
+	}, v)
+
+	_, err = parsePlist(strings.NewReader(`<dict/>`))
+	require.Error(t, err)
+	_, err = parsePlist(strings.NewReader(`<plist><unknown/></plist>`))
+	require.Error(t, err)
+}
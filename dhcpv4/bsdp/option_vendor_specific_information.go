@@ -3,8 +3,10 @@ package bsdp
 import (
 	"fmt"
 	"net"
+	"sort"
 
 	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/u-root/u-root/pkg/uio"
 )
 
 // VendorOptions is like dhcpv4.Options, but stringifies using BSDP-specific
@@ -18,6 +20,30 @@ func (v VendorOptions) String() string {
 	return v.Options.ToString(bsdpHumanizer)
 }
 
+// ToBytes serializes the options of v. Unlike dhcpv4.Options, it keeps the
+// options that carry no data, such as the NetBoot 1.0 firmware flag.
+func (v VendorOptions) ToBytes() []byte {
+	codes := make([]int, 0, len(v.Options))
+	for code := range v.Options {
+		codes = append(codes, int(code))
+	}
+	sort.Ints(codes)
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, c := range codes {
+		code := uint8(c)
+		if code == dhcpv4.OptionEnd.Code() {
+			continue
+		}
+		if data := v.Options[code]; len(data) > 0 {
+			dhcpv4.Options{code: data}.Marshal(buf)
+		} else {
+			buf.Write8(code)
+			buf.Write8(0)
+		}
+	}
+	return buf.Data()
+}
+
 // FromBytes parses vendor options from
 func (v *VendorOptions) FromBytes(data []byte) error {
 	v.Options = make(dhcpv4.Options)
@@ -93,6 +119,40 @@ func (v VendorOptions) MachineName() string {
 	return dhcpv4.GetString(OptionMachineName, v.Options)
 }
 
+// ShadowMountPath returns the BSDP shadow mount path in v, if present.
+func (v VendorOptions) ShadowMountPath() string {
+	return dhcpv4.GetString(OptionShadowMountPath, v.Options)
+}
+
+// ShadowFilePath returns the BSDP shadow file path in v, if present.
+func (v VendorOptions) ShadowFilePath() string {
+	return dhcpv4.GetString(OptionShadowFilePath, v.Options)
+}
+
+// MaxMessageSize returns the BSDP max message size in v if present.
+func (v VendorOptions) MaxMessageSize() (uint16, error) {
+	return dhcpv4.GetUint16(OptionMaxMessageSize, v.Options)
+}
+
+// Netboot1_0Firmware returns whether v flags a NetBoot 1.0 client.
+func (v VendorOptions) Netboot1_0Firmware() bool {
+	return v.Options.Has(OptionNetboot1_0Firmware)
+}
+
+// BootImageAttributesFilterList returns the BSDP boot image attributes filter
+// list in v, or nil if the client filters no images.
+func (v VendorOptions) BootImageAttributesFilterList() BootImageAttributesFilterList {
+	val := v.Options.Get(OptionBootImageAttributesFilterList)
+	if val == nil {
+		return nil
+	}
+	var l BootImageAttributesFilterList
+	if err := l.FromBytes(val); err != nil {
+		return nil
+	}
+	return l
+}
+
 // OptVendorOptions returns the BSDP Vendor Specific Info in o.
 func OptVendorOptions(o ...dhcpv4.Option) dhcpv4.Option {
 	return dhcpv4.Option{
@@ -126,20 +186,23 @@ var bsdpHumanizer = dhcpv4.OptionHumanizer{
 func parseOption(code dhcpv4.OptionCode, data []byte) fmt.Stringer {
 	var d dhcpv4.OptionDecoder
 	switch code {
-	case OptionMachineName:
+	case OptionMachineName, OptionShadowMountPath, OptionShadowFilePath:
 		var s dhcpv4.String
 		d = &s
 
 	case OptionServerIdentifier:
 		d = &dhcpv4.IP{}
 
-	case OptionServerPriority, OptionReplyPort:
+	case OptionServerPriority, OptionReplyPort, OptionMaxMessageSize:
 		var u dhcpv4.Uint16
 		d = &u
 
 	case OptionBootImageList:
 		d = &BootImageList{}
 
+	case OptionBootImageAttributesFilterList:
+		d = &BootImageAttributesFilterList{}
+
 	case OptionDefaultBootImageID, OptionSelectedBootImageID:
 		d = &BootImageID{}
 
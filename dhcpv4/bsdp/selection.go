@@ -0,0 +1,155 @@
+package bsdp
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sort"
+)
+
+// Offer is the answer of a NetBoot server to an INFORM[LIST]: the images it
+// can boot the client with.
+type Offer struct {
+	Ack            *Packet
+	ServerIP       net.IP
+	ServerPriority uint16
+	Images         []BootImage
+	DefaultImage   *BootImageID
+	// SelectedImage is the image the client selected on this server
+	// before, if the server remembers it.
+	SelectedImage *BootImageID
+}
+
+// NewOffer parses the ACK[LIST] of a NetBoot server.
+func NewOffer(ack *Packet) (*Offer, error) {
+	vendorOpts := GetVendorOptions(ack.Options)
+	if vendorOpts == nil {
+		return nil, errors.New("NewOffer: could not find vendor-specific option")
+	}
+	if vendorOpts.MessageType() != MessageTypeList {
+		return nil, errors.New("NewOffer: not an ACK[LIST]")
+	}
+	serverIP := vendorOpts.ServerIdentifier()
+	if serverIP == nil {
+		serverIP = ack.ServerIdentifier()
+	}
+	if serverIP == nil {
+		return nil, errors.New("NewOffer: could not find server identifier")
+	}
+	// the priority defaults to 0 if not present
+	priority, _ := vendorOpts.ServerPriority()
+	return &Offer{
+		Ack:            ack,
+		ServerIP:       serverIP,
+		ServerPriority: priority,
+		Images:         vendorOpts.BootImageList(),
+		DefaultImage:   vendorOpts.DefaultBootImageID(),
+		SelectedImage:  vendorOpts.SelectedBootImageID(),
+	}, nil
+}
+
+func (o *Offer) image(id *BootImageID) *BootImage {
+	if id == nil {
+		return nil
+	}
+	for i := range o.Images {
+		if o.Images[i].ID == *id {
+			return &o.Images[i]
+		}
+	}
+	return nil
+}
+
+// ImageSelector is a selection policy: it picks the image to boot among the
+// images of an Offer, or returns nil if none is suitable.
+type ImageSelector func(offer *Offer) *BootImage
+
+// SelectDefault selects the default image of the server.
+func SelectDefault() ImageSelector {
+	return func(offer *Offer) *BootImage {
+		return offer.image(offer.DefaultImage)
+	}
+}
+
+// SelectPrevious selects the image the client selected on the server before.
+func SelectPrevious() ImageSelector {
+	return func(offer *Offer) *BootImage {
+		return offer.image(offer.SelectedImage)
+	}
+}
+
+// SelectByID selects the image with the given ID.
+func SelectByID(id BootImageID) ImageSelector {
+	return func(offer *Offer) *BootImage {
+		return offer.image(&id)
+	}
+}
+
+// SelectByName selects the first image with the given name.
+func SelectByName(name string) ImageSelector {
+	return func(offer *Offer) *BootImage {
+		for i := range offer.Images {
+			if offer.Images[i].Name == name {
+				return &offer.Images[i]
+			}
+		}
+		return nil
+	}
+}
+
+// SelectByKind selects the first image of the given type, preferring the
+// default image of the server if it is of that type.
+func SelectByKind(kind BootImageType) ImageSelector {
+	return func(offer *Offer) *BootImage {
+		if img := offer.image(offer.DefaultImage); img != nil && img.ID.ImageType == kind {
+			return img
+		}
+		for i := range offer.Images {
+			if offer.Images[i].ID.ImageType == kind {
+				return &offer.Images[i]
+			}
+		}
+		return nil
+	}
+}
+
+// SelectFirst selects the image picked by the first of selectors that picks
+// one.
+func SelectFirst(selectors ...ImageSelector) ImageSelector {
+	return func(offer *Offer) *BootImage {
+		for _, selector := range selectors {
+			if img := selector(offer); img != nil {
+				return img
+			}
+		}
+		return nil
+	}
+}
+
+// DefaultImageSelector is the selection policy of clients that have none:
+// the image selected before, or else the default image of the server.
+var DefaultImageSelector = SelectFirst(SelectPrevious(), SelectDefault())
+
+// SortOffers orders offers by decreasing server priority, then by increasing
+// server address, which is the order clients consider them in.
+func SortOffers(offers []*Offer) {
+	sort.SliceStable(offers, func(i, j int) bool {
+		if offers[i].ServerPriority != offers[j].ServerPriority {
+			return offers[i].ServerPriority > offers[j].ServerPriority
+		}
+		return bytes.Compare(offers[i].ServerIP.To16(), offers[j].ServerIP.To16()) < 0
+	})
+}
+
+// ChooseOffer returns the first offer, in the order of SortOffers, for which
+// selector picks an image, along with the image.
+func ChooseOffer(offers []*Offer, selector ImageSelector) (*Offer, *BootImage, error) {
+	sorted := append([]*Offer(nil), offers...)
+	SortOffers(sorted)
+	for _, offer := range sorted {
+		if img := selector(offer); img != nil {
+			return offer, img, nil
+		}
+	}
+	return nil, nil, errors.New("no NetBoot server offers a suitable boot image")
+}
@@ -0,0 +1,159 @@
+package bsdp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	selectionTestImages = []BootImage{
+		{ID: BootImageID{ImageType: BootImageTypeMacOSX, Index: 1}, Name: "macOS"},
+		{ID: BootImageID{ImageType: BootImageTypeHardwareDiagnostics, Index: 2}, Name: "Diags"},
+		{ID: BootImageID{IsInstall: true, ImageType: BootImageTypeMacOSX, Index: 3}, Name: "Installer"},
+	}
+)
+
+func newTestAckForList(t *testing.T, serverIP net.IP, priority uint16, defaultImage, selectedImage *BootImage) *Packet {
+	inform, err := NewInformList(net.HardwareAddr{1, 2, 3, 4, 5, 6}, net.IPv4(192, 0, 2, 10), 0)
+	require.NoError(t, err)
+	ack, err := NewReplyForInformList(inform, ReplyConfig{
+		ServerIP:       serverIP,
+		ServerPriority: priority,
+		Images:         selectionTestImages,
+		DefaultImage:   defaultImage,
+		SelectedImage:  selectedImage,
+	})
+	require.NoError(t, err)
+	return ack
+}
+
+func TestNewOffer(t *testing.T) {
+	ack := newTestAckForList(t, net.IPv4(192, 0, 2, 1), 10, &selectionTestImages[0], &selectionTestImages[1])
+	offer, err := NewOffer(ack)
+	require.NoError(t, err)
+	require.Equal(t, ack, offer.Ack)
+	require.True(t, offer.ServerIP.Equal(net.IPv4(192, 0, 2, 1)))
+	require.Equal(t, uint16(10), offer.ServerPriority)
+	require.Equal(t, selectionTestImages, offer.Images)
+	require.Equal(t, &selectionTestImages[0].ID, offer.DefaultImage)
+	require.Equal(t, &selectionTestImages[1].ID, offer.SelectedImage)
+}
+
+func TestNewOfferInvalid(t *testing.T) {
+	ack, err := dhcpv4.New()
+	require.NoError(t, err)
+	_, err = NewOffer(PacketFor(ack))
+	require.Error(t, err)
+
+	ack.UpdateOption(OptVendorOptions(OptMessageType(MessageTypeSelect)))
+	_, err = NewOffer(PacketFor(ack))
+	require.Error(t, err)
+
+	// no server identifier
+	ack.UpdateOption(OptVendorOptions(OptMessageType(MessageTypeList)))
+	_, err = NewOffer(PacketFor(ack))
+	require.Error(t, err)
+}
+
+func TestImageSelectors(t *testing.T) {
+	offer := &Offer{
+		Images:       selectionTestImages,
+		DefaultImage: &selectionTestImages[2].ID,
+	}
+	require.Equal(t, &selectionTestImages[2], SelectDefault()(offer))
+	require.Nil(t, SelectPrevious()(offer))
+	require.Equal(t, &selectionTestImages[2], DefaultImageSelector(offer))
+
+	require.Equal(t, &selectionTestImages[1], SelectByID(selectionTestImages[1].ID)(offer))
+	require.Nil(t, SelectByID(BootImageID{Index: 42})(offer))
+	require.Equal(t, &selectionTestImages[1], SelectByName("Diags")(offer))
+	require.Nil(t, SelectByName("missing")(offer))
+	// the default image wins among images of the same kind
+	require.Equal(t, &selectionTestImages[2], SelectByKind(BootImageTypeMacOSX)(offer))
+	require.Equal(t, &selectionTestImages[1], SelectByKind(BootImageTypeHardwareDiagnostics)(offer))
+	require.Nil(t, SelectByKind(BootImageTypeMacOS9)(offer))
+
+	offer.SelectedImage = &selectionTestImages[0].ID
+	require.Equal(t, &selectionTestImages[0], SelectPrevious()(offer))
+	require.Equal(t, &selectionTestImages[0], DefaultImageSelector(offer))
+
+	require.Equal(t, &selectionTestImages[1], SelectFirst(SelectByName("missing"), SelectByName("Diags"))(offer))
+	require.Nil(t, SelectFirst()(offer))
+}
+
+func TestSortOffers(t *testing.T) {
+	offers := []*Offer{
+		{ServerIP: net.IPv4(192, 0, 2, 3), ServerPriority: 1},
+		{ServerIP: net.IPv4(192, 0, 2, 2), ServerPriority: 5},
+		{ServerIP: net.IPv4(192, 0, 2, 1), ServerPriority: 1},
+	}
+	SortOffers(offers)
+	require.True(t, offers[0].ServerIP.Equal(net.IPv4(192, 0, 2, 2)))
+	require.True(t, offers[1].ServerIP.Equal(net.IPv4(192, 0, 2, 1)))
+	require.True(t, offers[2].ServerIP.Equal(net.IPv4(192, 0, 2, 3)))
+}
+
+func TestChooseOffer(t *testing.T) {
+	low := &Offer{ServerIP: net.IPv4(192, 0, 2, 1), ServerPriority: 1, Images: selectionTestImages}
+	high := &Offer{ServerIP: net.IPv4(192, 0, 2, 2), ServerPriority: 9, Images: selectionTestImages[:1]}
+	offers := []*Offer{low, high}
+
+	offer, img, err := ChooseOffer(offers, SelectByName("macOS"))
+	require.NoError(t, err)
+	require.Equal(t, high, offer)
+	require.Equal(t, "macOS", img.Name)
+	// the input order is preserved
+	require.Equal(t, low, offers[0])
+
+	// only the low priority server has the image
+	offer, img, err = ChooseOffer(offers, SelectByName("Diags"))
+	require.NoError(t, err)
+	require.Equal(t, low, offer)
+	require.Equal(t, "Diags", img.Name)
+
+	_, _, err = ChooseOffer(offers, SelectByName("missing"))
+	require.Error(t, err)
+}
+
+func TestSelectionFromAck(t *testing.T) {
+	ackForList := newTestAckForList(t, net.IPv4(192, 0, 2, 1), 0, &selectionTestImages[0], nil)
+	offer, err := NewOffer(ackForList)
+	require.NoError(t, err)
+	informSelect, err := InformSelectForAck(ackForList, 0, selectionTestImages[0])
+	require.NoError(t, err)
+	ack, err := NewReplyForInformSelect(informSelect, ReplyConfig{
+		ServerIP:      net.IPv4(192, 0, 2, 1),
+		BootFileName:  "macOS.nbi/i386/booter",
+		Images:        selectionTestImages,
+		SelectedImage: &selectionTestImages[0],
+	})
+	require.NoError(t, err)
+	ack.UpdateOption(dhcpv4.OptRootPath("http://192.0.2.1/NetBoot/macOS.nbi/NetInstall.dmg"))
+	ack.UpdateOption(OptVendorOptions(
+		OptMessageType(MessageTypeSelect),
+		OptShadowMountPath("afp://192.0.2.1/NetBootClients0"),
+		OptShadowFilePath("NetBoot001/Shadow"),
+	))
+
+	selection := selectionFromAck(offer, selectionTestImages[0], ack)
+	require.Equal(t, &BootSelection{
+		ServerIP:   offer.ServerIP,
+		Image:      selectionTestImages[0],
+		NextServer: net.IPv4(192, 0, 2, 1),
+		BootFile:   "macOS.nbi/i386/booter",
+		RootPath:   "http://192.0.2.1/NetBoot/macOS.nbi/NetInstall.dmg",
+
+		ShadowMountPath: "afp://192.0.2.1/NetBootClients0",
+		ShadowFilePath:  "NetBoot001/Shadow",
+	}, selection)
+}
+
+func TestNewClient(t *testing.T) {
+	c := NewClient()
+	require.Equal(t, dhcpv4.DefaultReadTimeout, c.ReadTimeout)
+	require.Equal(t, dhcpv4.DefaultWriteTimeout, c.WriteTimeout)
+	require.Equal(t, DefaultRetries, c.Retries)
+}
@@ -0,0 +1,215 @@
+package bsdp
+
+import (
+	"log"
+	"net"
+	"sync"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+)
+
+// Server is a NetBoot service. It answers the INFORM[LIST] and
+// INFORM[SELECT] requests of BSDP clients with the images of its Catalog that
+// can boot them, and remembers the image each client selected.
+type Server struct {
+	// Catalog holds the images the server offers.
+	Catalog *Catalog
+
+	// ServerIP is the address of the server as sent to clients, and
+	// ServerHostname its name.
+	ServerIP       net.IP
+	ServerHostname string
+	// ServerPriority orders this server among the NetBoot servers that
+	// answer a client.
+	ServerPriority uint16
+	// RootPathPrefix is prepended to the RootPath of the selected image,
+	// e.g. "http://192.0.2.1/NetBoot/" for images loaded with LoadNBI.
+	RootPathPrefix string
+
+	// Addr is the address the server listens on.
+	Addr net.UDPAddr
+
+	mu         sync.Mutex
+	selections map[string]BootImageID
+	srv        *dhcpv4.Server
+}
+
+// NewServer returns a Server for the images in catalog, listening on the DHCP
+// server port of all addresses.
+func NewServer(serverIP net.IP, catalog *Catalog) *Server {
+	return &Server{
+		Catalog:    catalog,
+		ServerIP:   serverIP,
+		Addr:       net.UDPAddr{IP: net.IPv4zero, Port: dhcpv4.ServerPort},
+		selections: make(map[string]BootImageID),
+	}
+}
+
+// ActivateAndServe starts listening for BSDP requests. It returns when the
+// listener fails or the server is closed with Server.Close.
+func (s *Server) ActivateAndServe() error {
+	s.mu.Lock()
+	srv := dhcpv4.NewServer(s.Addr, s.Handle)
+	s.srv = srv
+	s.mu.Unlock()
+	return srv.ActivateAndServe()
+}
+
+// Close stops the listener.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv == nil {
+		return nil
+	}
+	return s.srv.Close()
+}
+
+// SelectedImage returns the ID of the image last selected by the client with
+// the given hardware address, if any.
+func (s *Server) SelectedImage(hwaddr net.HardwareAddr) (BootImageID, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.selections[hwaddr.String()]
+	return id, ok
+}
+
+func (s *Server) setSelectedImage(hwaddr net.HardwareAddr, id BootImageID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.selections == nil {
+		s.selections = make(map[string]BootImageID)
+	}
+	s.selections[hwaddr.String()] = id
+}
+
+// Handle is the dhcpv4.Handler of the server.
+func (s *Server) Handle(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+	if m.MessageType() != dhcpv4.MessageTypeInform {
+		return
+	}
+	arch, model, err := ParseVendorClassIdentifier(m.ClassIdentifier())
+	if err != nil {
+		return
+	}
+	inform := PacketFor(m)
+	images := s.Catalog.ImagesFor(arch, model)
+	if len(images) == 0 {
+		log.Printf("No NetBoot image for client %s (%s/%s)", m.ClientHWAddr, arch, model)
+		return
+	}
+
+	var reply *Packet
+	switch MessageTypeFromPacket(m) {
+	case MessageTypeList:
+		reply, err = s.replyForList(inform, images)
+	case MessageTypeSelect:
+		reply, err = s.replyForSelect(inform, images)
+	default:
+		return
+	}
+	if err != nil {
+		log.Printf("Cannot build BSDP reply for client %s: %v", m.ClientHWAddr, err)
+		return
+	}
+	if reply == nil {
+		return
+	}
+	if _, err := conn.WriteTo(reply.ToBytes(), replyAddr(peer, m)); err != nil {
+		log.Printf("Cannot reply to BSDP client %s: %v", m.ClientHWAddr, err)
+	}
+}
+
+// replyAddr returns the address a reply to m goes to: the client address, on
+// the client's reply port if it asked for one.
+func replyAddr(peer net.Addr, m *dhcpv4.DHCPv4) *net.UDPAddr {
+	ip := m.ClientIPAddr
+	if ip == nil || ip.IsUnspecified() {
+		if udpAddr, ok := peer.(*net.UDPAddr); ok {
+			ip = udpAddr.IP
+		}
+	}
+	port := dhcpv4.ClientPort
+	if vendorOpts := GetVendorOptions(m.Options); vendorOpts != nil {
+		if replyPort, err := vendorOpts.ReplyPort(); err == nil && replyPort != 0 {
+			port = int(replyPort)
+		}
+	}
+	return &net.UDPAddr{IP: ip, Port: port}
+}
+
+func (s *Server) replyConfig(images []CatalogImage) ReplyConfig {
+	config := ReplyConfig{
+		ServerIP:       s.ServerIP,
+		ServerHostname: s.ServerHostname,
+		ServerPriority: s.ServerPriority,
+		DefaultImage:   &defaultImage(images).BootImage,
+	}
+	for _, img := range images {
+		config.Images = append(config.Images, img.BootImage)
+	}
+	return config
+}
+
+func (s *Server) replyForList(inform *Packet, images []CatalogImage) (*Packet, error) {
+	// NetBoot 2.0 clients only want the images with the given attributes.
+	if filter := GetVendorOptions(inform.Options).BootImageAttributesFilterList(); len(filter) > 0 {
+		var filtered []CatalogImage
+		for _, img := range images {
+			if filter.Matches(img.ID) {
+				filtered = append(filtered, img)
+			}
+		}
+		if len(filtered) == 0 {
+			return nil, nil
+		}
+		images = filtered
+	}
+	config := s.replyConfig(images)
+	if id, ok := s.SelectedImage(inform.ClientHWAddr); ok {
+		for _, img := range images {
+			if img.ID == id {
+				config.SelectedImage = &img.BootImage
+				break
+			}
+		}
+	}
+	return NewReplyForInformList(inform, config)
+}
+
+func (s *Server) replyForSelect(inform *Packet, images []CatalogImage) (*Packet, error) {
+	vendorOpts := GetVendorOptions(inform.Options)
+	// The client broadcasts its selection, only the selected server answers.
+	if serverIP := vendorOpts.ServerIdentifier(); serverIP != nil && !serverIP.Equal(s.ServerIP) {
+		return nil, nil
+	}
+	id := vendorOpts.SelectedBootImageID()
+	if id == nil {
+		log.Printf("BSDP client %s selected no image", inform.ClientHWAddr)
+		return nil, nil
+	}
+	var selected *CatalogImage
+	for i := range images {
+		if images[i].ID == *id {
+			selected = &images[i]
+			break
+		}
+	}
+	if selected == nil {
+		log.Printf("BSDP client %s selected unknown or unsupported image %v", inform.ClientHWAddr, id)
+		return nil, nil
+	}
+	s.setSelectedImage(inform.ClientHWAddr, selected.ID)
+
+	config := s.replyConfig(images)
+	config.SelectedImage = &selected.BootImage
+	config.BootFileName = selected.BootFile
+	reply, err := NewReplyForInformSelect(inform, config)
+	if err != nil {
+		return nil, err
+	}
+	if selected.RootPath != "" {
+		reply.UpdateOption(dhcpv4.OptRootPath(s.RootPathPrefix + selected.RootPath))
+	}
+	return reply, nil
+}
@@ -0,0 +1,177 @@
+package bsdp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	serverTestHWAddr = net.HardwareAddr{1, 2, 3, 4, 5, 6}
+	serverTestImages = []CatalogImage{
+		{
+			BootImage:     BootImage{ID: BootImageID{ImageType: BootImageTypeMacOSX, Index: 1}, Name: "macOS"},
+			Architectures: []string{"i386"},
+			BootFile:      "macOS.nbi/i386/booter",
+			RootPath:      "macOS.nbi/NetInstall.dmg",
+		},
+		{
+			BootImage: BootImage{ID: BootImageID{ImageType: BootImageTypeHardwareDiagnostics, Index: 2}, Name: "Diags"},
+			IsDefault: true,
+			BootFile:  "Diags.nbi/i386/booter",
+		},
+		{
+			BootImage:     BootImage{ID: BootImageID{ImageType: BootImageTypeMacOSX, Index: 3}, Name: "Pro only"},
+			EnabledModels: []string{"MacPro6,1"},
+		},
+	}
+)
+
+// serverTestSetup returns a server and the connections it and the client
+// use.
+func serverTestSetup(t *testing.T) (*Server, net.PacketConn, *net.UDPConn) {
+	catalog, err := NewCatalog(serverTestImages...)
+	require.NoError(t, err)
+	s := NewServer(net.IPv4(192, 0, 2, 1), catalog)
+	s.RootPathPrefix = "http://192.0.2.1/NetBoot/"
+
+	serverConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { serverConn.Close() })
+	clientConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { clientConn.Close() })
+	return s, serverConn, clientConn
+}
+
+// withReplyPort sets the reply port of p to the port of conn, which is not
+// privileged and thus refused by NewInformList.
+func withReplyPort(p *Packet, conn *net.UDPConn) *Packet {
+	vendorOpts := GetVendorOptions(p.Options)
+	vendorOpts.Update(OptReplyPort(uint16(conn.LocalAddr().(*net.UDPAddr).Port)))
+	p.UpdateOption(dhcpv4.Option{Code: dhcpv4.OptionVendorSpecificInformation, Value: vendorOpts})
+	return p
+}
+
+func exchange(t *testing.T, s *Server, serverConn net.PacketConn, clientConn *net.UDPConn, p *Packet) *Packet {
+	s.Handle(serverConn, clientConn.LocalAddr(), p.v4())
+	buf := make([]byte, MaxDHCPMessageSize)
+	require.NoError(t, clientConn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := clientConn.ReadFrom(buf)
+	require.NoError(t, err)
+	reply, err := dhcpv4.FromBytes(buf[:n])
+	require.NoError(t, err)
+	return PacketFor(reply)
+}
+
+func requireNoReply(t *testing.T, s *Server, serverConn net.PacketConn, clientConn *net.UDPConn, p *Packet) {
+	s.Handle(serverConn, clientConn.LocalAddr(), p.v4())
+	require.NoError(t, clientConn.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, _, err := clientConn.ReadFrom(make([]byte, MaxDHCPMessageSize))
+	require.Error(t, err)
+}
+
+func TestServerListAndSelect(t *testing.T) {
+	s, serverConn, clientConn := serverTestSetup(t)
+
+	informList, err := NewInformList(serverTestHWAddr, net.IPv4(127, 0, 0, 1), 0)
+	require.NoError(t, err)
+	ackForList := exchange(t, s, serverConn, clientConn, withReplyPort(informList, clientConn))
+	require.Equal(t, dhcpv4.MessageTypeAck, ackForList.MessageType())
+	require.Equal(t, MessageTypeList, MessageTypeFromPacket(ackForList.v4()))
+	vendorOpts := GetVendorOptions(ackForList.Options)
+	// the image for MacPro6,1 only is filtered out
+	require.Equal(t, BootImageList{serverTestImages[0].BootImage, serverTestImages[1].BootImage}, vendorOpts.BootImageList())
+	require.Equal(t, &serverTestImages[1].ID, vendorOpts.DefaultBootImageID())
+	require.Nil(t, vendorOpts.SelectedBootImageID())
+
+	informSelect, err := InformSelectForAck(ackForList, 0, serverTestImages[0].BootImage)
+	require.NoError(t, err)
+	ackForSelect := exchange(t, s, serverConn, clientConn, withReplyPort(informSelect, clientConn))
+	require.Equal(t, MessageTypeSelect, MessageTypeFromPacket(ackForSelect.v4()))
+	require.Equal(t, "macOS.nbi/i386/booter", ackForSelect.BootFileName)
+	require.Equal(t, "http://192.0.2.1/NetBoot/macOS.nbi/NetInstall.dmg", ackForSelect.RootPath())
+	require.Equal(t, &serverTestImages[0].ID, GetVendorOptions(ackForSelect.Options).SelectedBootImageID())
+
+	id, ok := s.SelectedImage(serverTestHWAddr)
+	require.True(t, ok)
+	require.Equal(t, serverTestImages[0].ID, id)
+
+	// the selection is remembered in later lists
+	ackForList = exchange(t, s, serverConn, clientConn, withReplyPort(informList, clientConn))
+	require.Equal(t, &serverTestImages[0].ID, GetVendorOptions(ackForList.Options).SelectedBootImageID())
+}
+
+func TestServerListAttributesFilter(t *testing.T) {
+	s, serverConn, clientConn := serverTestSetup(t)
+
+	filter := OptBootImageAttributesFilterList(BootImageAttributes{ImageType: BootImageTypeMacOSX})
+	informList, err := NewInformList(serverTestHWAddr, net.IPv4(127, 0, 0, 1), 0, WithVendorOptions(filter))
+	require.NoError(t, err)
+	ackForList := exchange(t, s, serverConn, clientConn, withReplyPort(informList, clientConn))
+	vendorOpts := GetVendorOptions(ackForList.Options)
+	require.Equal(t, BootImageList{serverTestImages[0].BootImage}, vendorOpts.BootImageList())
+	// the default image is among the listed ones
+	require.Equal(t, &serverTestImages[0].ID, vendorOpts.DefaultBootImageID())
+
+	// no installable image
+	filter = OptBootImageAttributesFilterList(BootImageAttributes{IsInstall: true, ImageType: BootImageTypeMacOSX})
+	informList, err = NewInformList(serverTestHWAddr, net.IPv4(127, 0, 0, 1), 0, WithVendorOptions(filter))
+	require.NoError(t, err)
+	requireNoReply(t, s, serverConn, clientConn, withReplyPort(informList, clientConn))
+}
+
+func TestServerSelectUnsupportedImage(t *testing.T) {
+	s, serverConn, clientConn := serverTestSetup(t)
+
+	informList, err := NewInformList(serverTestHWAddr, net.IPv4(127, 0, 0, 1), 0)
+	require.NoError(t, err)
+	ackForList := exchange(t, s, serverConn, clientConn, withReplyPort(informList, clientConn))
+
+	// not for this model
+	informSelect, err := InformSelectForAck(ackForList, 0, serverTestImages[2].BootImage)
+	require.NoError(t, err)
+	requireNoReply(t, s, serverConn, clientConn, withReplyPort(informSelect, clientConn))
+	_, ok := s.SelectedImage(serverTestHWAddr)
+	require.False(t, ok)
+}
+
+func TestServerSelectOtherServer(t *testing.T) {
+	s, serverConn, clientConn := serverTestSetup(t)
+
+	ack, err := dhcpv4.New(dhcpv4.WithOption(dhcpv4.OptServerIdentifier(net.IPv4(192, 0, 2, 2))))
+	require.NoError(t, err)
+	informSelect, err := InformSelectForAck(PacketFor(ack), 0, serverTestImages[0].BootImage)
+	require.NoError(t, err)
+	requireNoReply(t, s, serverConn, clientConn, withReplyPort(informSelect, clientConn))
+}
+
+func TestServerIgnoresOtherClients(t *testing.T) {
+	s, serverConn, clientConn := serverTestSetup(t)
+
+	// not a BSDP client
+	inform, err := dhcpv4.NewInform(serverTestHWAddr, net.IPv4(127, 0, 0, 1))
+	require.NoError(t, err)
+	requireNoReply(t, s, serverConn, clientConn, PacketFor(inform))
+
+	// no image for this architecture
+	informList, err := NewInformList(serverTestHWAddr, net.IPv4(127, 0, 0, 1), 0)
+	require.NoError(t, err)
+	informList.UpdateOption(dhcpv4.OptClassIdentifier("AAPLBSDPC/ppc/PowerMac7,2"))
+	s.Catalog.Images = serverTestImages[:1]
+	requireNoReply(t, s, serverConn, clientConn, withReplyPort(informList, clientConn))
+}
+
+func TestReplyAddr(t *testing.T) {
+	peer := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 10), Port: dhcpv4.ClientPort}
+	m, err := dhcpv4.New()
+	require.NoError(t, err)
+	require.Equal(t, &net.UDPAddr{IP: peer.IP, Port: dhcpv4.ClientPort}, replyAddr(peer, m))
+
+	m.ClientIPAddr = net.IPv4(192, 0, 2, 20)
+	m.UpdateOption(OptVendorOptions(OptReplyPort(1000)))
+	require.Equal(t, &net.UDPAddr{IP: m.ClientIPAddr, Port: 1000}, replyAddr(peer, m))
+}
@@ -39,6 +39,7 @@ const (
 	OptionBootImageList                 optionCode = 9
 	OptionNetboot1_0Firmware            optionCode = 10
 	OptionBootImageAttributesFilterList optionCode = 11
+	OptionMaxMessageSize                optionCode = 12
 	OptionShadowMountPath               optionCode = 128
 	OptionShadowFilePath                optionCode = 129
 	OptionMachineName                   optionCode = 130
@@ -58,6 +59,7 @@ var optionCodeToString = map[optionCode]string{
 	OptionBootImageList:                 "BSDP Boot Image List",
 	OptionNetboot1_0Firmware:            "BSDP Netboot 1.0 Firmware",
 	OptionBootImageAttributesFilterList: "BSDP Boot Image Attributes Filter List",
+	OptionMaxMessageSize:                "BSDP Max Message Size",
 	OptionShadowMountPath:               "BSDP Shadow Mount Path",
 	OptionShadowFilePath:                "BSDP Shadow File Path",
 	OptionMachineName:                   "BSDP Machine Name",
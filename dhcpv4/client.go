@@ -36,6 +36,9 @@ type Client struct {
 	ReadTimeout, WriteTimeout time.Duration
 	RemoteAddr                net.Addr
 	LocalAddr                 net.Addr
+	// Authenticator, if set, signs outgoing messages and verifies replies.
+	// Replies that fail verification are ignored.
+	Authenticator Authenticator
 }
 
 // NewClient generates a new client to perform a DHCP exchange with, setting the
@@ -254,6 +257,21 @@ func (c *Client) Exchange(ifname string, modifiers ...Modifier) ([]*DHCPv4, erro
 // response up to some read timeout value. If the message type is not
 // MessageTypeNone, it will wait for a specific message type
 func (c *Client) SendReceive(sendFd, recvFd int, packet *DHCPv4, messageType MessageType) (*DHCPv4, error) {
+	responses, err := c.sendReceive(sendFd, recvFd, packet, messageType, false)
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// SendReceiveAll is like SendReceive, but rather than returning the first
+// response it collects all the responses received within the read timeout,
+// e.g. from several servers. It returns an error if there is none.
+func (c *Client) SendReceiveAll(sendFd, recvFd int, packet *DHCPv4, messageType MessageType) ([]*DHCPv4, error) {
+	return c.sendReceive(sendFd, recvFd, packet, messageType, true)
+}
+
+func (c *Client) sendReceive(sendFd, recvFd int, packet *DHCPv4, messageType MessageType, all bool) ([]*DHCPv4, error) {
 	raddr, err := c.getRemoteUDPAddr()
 	if err != nil {
 		return nil, err
@@ -262,6 +280,11 @@ func (c *Client) SendReceive(sendFd, recvFd int, packet *DHCPv4, messageType Mes
 	if err != nil {
 		return nil, err
 	}
+	if c.Authenticator != nil {
+		if err := c.Authenticator.Sign(packet); err != nil {
+			return nil, err
+		}
+	}
 	packetBytes, err := MakeRawUDPPacket(packet.ToBytes(), *raddr, *laddr)
 	if err != nil {
 		return nil, err
@@ -269,25 +292,39 @@ func (c *Client) SendReceive(sendFd, recvFd int, packet *DHCPv4, messageType Mes
 
 	// Create a goroutine to perform the blocking send, and time it out after
 	// a certain amount of time.
-	var (
-		destination [net.IPv4len]byte
-		response    *DHCPv4
-	)
+	var destination [net.IPv4len]byte
 	copy(destination[:], raddr.IP.To4())
 	remoteAddr := unix.SockaddrInet4{Port: laddr.Port, Addr: destination}
-	recvErrors := make(chan error, 1)
-	go func(errs chan<- error) {
-		// set read timeout
-		timeout := unix.NsecToTimeval(c.ReadTimeout.Nanoseconds())
-		if innerErr := unix.SetsockoptTimeval(recvFd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); innerErr != nil {
-			errs <- innerErr
-			return
-		}
+	type recvResult struct {
+		responses []*DHCPv4
+		err       error
+	}
+	results := make(chan recvResult, 1)
+	go func() {
+		var responses []*DHCPv4
+		deadline := time.Now().Add(c.ReadTimeout)
 		for {
+			// set read timeout
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				if len(responses) == 0 {
+					results <- recvResult{err: unix.EAGAIN}
+					return
+				}
+				break
+			}
+			timeout := unix.NsecToTimeval(remaining.Nanoseconds())
+			if innerErr := unix.SetsockoptTimeval(recvFd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); innerErr != nil {
+				results <- recvResult{err: innerErr}
+				return
+			}
 			buf := make([]byte, MaxUDPReceivedPacketSize)
 			n, _, innerErr := unix.Recvfrom(recvFd, buf, 0)
 			if innerErr != nil {
-				errs <- innerErr
+				if innerErr == unix.EAGAIN && len(responses) > 0 {
+					break
+				}
+				results <- recvResult{err: innerErr}
 				return
 			}
 
@@ -301,6 +338,10 @@ func (c *Client) SendReceive(sendFd, recvFd int, packet *DHCPv4, messageType Mes
 				continue
 			}
 			udph := buf[iph.Len:n]
+			if len(udph) < 8 {
+				// skip truncated UDP headers
+				continue
+			}
 			// check source and destination ports
 			srcPort := int(binary.BigEndian.Uint16(udph[0:2]))
 			expectedSrcPort := ServerPort
@@ -318,13 +359,23 @@ func (c *Client) SendReceive(sendFd, recvFd int, packet *DHCPv4, messageType Mes
 			if dstPort != expectedDstPort {
 				continue
 			}
-			// UDP checksum is not checked
+			// UDP checksum is not checked. The UDP length includes the
+			// 8-byte header.
 			pLen := int(binary.BigEndian.Uint16(udph[4:6]))
-			payload := buf[iph.Len+8 : iph.Len+8+pLen]
+			if pLen < 8 || pLen > len(udph) {
+				// skip packets with an invalid UDP length
+				continue
+			}
+			payload := udph[8:pLen]
 
-			response, innerErr = FromBytes(payload)
+			response, innerErr := FromBytes(payload)
 			if innerErr != nil {
-				errs <- innerErr
+				// when collecting, do not lose the replies of other
+				// servers to one malformed packet
+				if all {
+					continue
+				}
+				results <- recvResult{err: innerErr}
 				return
 			}
 			// check that this is a response to our message
@@ -335,35 +386,43 @@ func (c *Client) SendReceive(sendFd, recvFd int, packet *DHCPv4, messageType Mes
 			if response.OpCode != OpcodeBootReply {
 				continue
 			}
-			// if we are not requested to wait for a specific message type,
-			// return what we have
-			if messageType == MessageTypeNone {
-				break
+			// skip replies that cannot be authenticated
+			if c.Authenticator != nil && c.Authenticator.Verify(response) != nil {
+				continue
+			}
+			// if we are requested to wait for a specific message type,
+			// skip replies of other types
+			if messageType != MessageTypeNone && response.MessageType() != messageType {
+				continue
 			}
-			// break if it's a reply of the desired type, continue otherwise
-			if response.MessageType() == messageType {
+			responses = append(responses, response)
+			if !all {
 				break
 			}
 		}
-		recvErrors <- nil
-	}(recvErrors)
+		results <- recvResult{responses: responses}
+	}()
 
 	// send the request while the goroutine waits for replies
 	if err = unix.Sendto(sendFd, packetBytes, 0, &remoteAddr); err != nil {
 		return nil, err
 	}
 
+	// when collecting, the read timeout ends the receive loop itself
+	var timeout <-chan time.Time
+	if !all {
+		timeout = time.After(c.ReadTimeout)
+	}
 	select {
-	case err = <-recvErrors:
-		if err == unix.EAGAIN {
+	case res := <-results:
+		if res.err == unix.EAGAIN {
 			return nil, errors.New("timed out while listening for replies")
 		}
-		if err != nil {
-			return nil, err
+		if res.err != nil {
+			return nil, res.err
 		}
-	case <-time.After(c.ReadTimeout):
+		return res.responses, nil
+	case <-timeout:
 		return nil, errors.New("timed out while listening for replies")
 	}
-
-	return response, nil
 }
@@ -16,6 +16,7 @@
 package dhcpv4
 
 import (
+	"bytes"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -62,6 +63,11 @@ type DHCPv4 struct {
 	ServerHostName string
 	BootFileName   string
 	Options        Options
+
+	// raw is the packet d was decoded from by FromBytes, if any. Delayed
+	// authentication computes its MAC over the packet as received, since
+	// ToBytes may order and pad the options differently than the sender.
+	raw []byte
 }
 
 // Modifier defines the signature for functions that can modify DHCPv4
@@ -298,6 +304,7 @@ func FromBytes(q []byte) (*DHCPv4, error) {
 	if err := p.Options.fromBytesCheckEnd(buf.Data(), true); err != nil {
 		return nil, err
 	}
+	p.raw = append([]byte(nil), q...)
 	return &p, nil
 }
 
@@ -544,6 +551,13 @@ func (d *DHCPv4) TFTPServerName() string {
 	return GetString(OptionTFTPServerName, d.Options)
 }
 
+// CaptivePortal parses the DHCPv4 Captive-Portal option if present.
+//
+// The Captive-Portal option is described by RFC 8910, Section 2.1.
+func (d *DHCPv4) CaptivePortal() string {
+	return GetString(OptionCaptivePortal, d.Options)
+}
+
 // ClassIdentifier parses the DHCPv4 Class Identifier option if present.
 //
 // The Vendor Class Identifier option is described by RFC 2132, Section 9.13.
@@ -564,6 +578,66 @@ func (d *DHCPv4) ClientArch() []iana.Arch {
 	return archs
 }
 
+// ClientNetworkInterfaceID returns the client network interface identifier
+// option if present.
+//
+// The option is described by RFC 4578, Section 2.2.
+func (d *DHCPv4) ClientNetworkInterfaceID() *NetworkInterfaceID {
+	v := d.Options.Get(OptionClientNetworkInterfaceIdentifier)
+	if v == nil {
+		return nil
+	}
+	var n NetworkInterfaceID
+	if err := n.FromBytes(v); err != nil {
+		return nil
+	}
+	return &n
+}
+
+// ClientMachineID returns the client machine identifier option if present.
+//
+// The option is described by RFC 4578, Section 2.3.
+func (d *DHCPv4) ClientMachineID() *MachineID {
+	v := d.Options.Get(OptionClientMachineIdentifier)
+	if v == nil {
+		return nil
+	}
+	var m MachineID
+	if err := m.FromBytes(v); err != nil {
+		return nil
+	}
+	return &m
+}
+
+// IsPXELinux returns true if d carries the PXELINUX magic option.
+//
+// The option is described by RFC 5071, Section 4.
+func (d *DHCPv4) IsPXELinux() bool {
+	return bytes.Equal(d.Options.Get(OptionPXELinuxMagicString), PXELinuxMagic)
+}
+
+// PXELinuxConfigFile parses the PXELINUX configuration file option if present.
+//
+// The option is described by RFC 5071, Section 5.
+func (d *DHCPv4) PXELinuxConfigFile() string {
+	return GetString(OptionPXELinuxConfigFile, d.Options)
+}
+
+// PXELinuxPathPrefix parses the PXELINUX path prefix option if present.
+//
+// The option is described by RFC 5071, Section 6.
+func (d *DHCPv4) PXELinuxPathPrefix() string {
+	return GetString(OptionPXELinuxPathPrefix, d.Options)
+}
+
+// PXELinuxRebootTime returns the PXELINUX reboot time, or the default value if
+// it is not present.
+//
+// The option is described by RFC 5071, Section 7.
+func (d *DHCPv4) PXELinuxRebootTime(def time.Duration) time.Duration {
+	return getDuration(OptionPXELinuxRebootTime, d.Options, def)
+}
+
 // DomainSearch returns the domain search list if present.
 //
 // The domain search option is described by RFC 3397, Section 2.
@@ -584,15 +658,116 @@ func (d *DHCPv4) DomainSearch() *rfc1035label.Labels {
 //
 // The IP address lease time option is described by RFC 2132, Section 9.2.
 func (d *DHCPv4) IPAddressLeaseTime(def time.Duration) time.Duration {
-	v := d.Options.Get(OptionIPAddressLeaseTime)
+	return getDuration(OptionIPAddressLeaseTime, d.Options, def)
+}
+
+// Authentication returns the authentication option if present.
+//
+// The authentication option is described by RFC 3118, Section 2.
+func (d *DHCPv4) Authentication() *Authentication {
+	v := d.Options.Get(OptionAuthentication)
+	if v == nil {
+		return nil
+	}
+	var a Authentication
+	if err := a.FromBytes(v); err != nil {
+		return nil
+	}
+	return &a
+}
+
+// ClientLastTransactionTime returns the number of seconds since the client
+// last talked to the server, or the given default duration if not present.
+//
+// The client-last-transaction-time option is described by RFC 4388, Section
+// 6.1.
+func (d *DHCPv4) ClientLastTransactionTime(def time.Duration) time.Duration {
+	return getDuration(OptionClientLastTransactionTime, d.Options, def)
+}
+
+// AssociatedIP returns the IP addresses bound to the queried client, if
+// present.
+//
+// The associated-ip option is described by RFC 4388, Section 6.1.
+func (d *DHCPv4) AssociatedIP() []net.IP {
+	return GetIPs(OptionAssociatedIP, d.Options)
+}
+
+// Status returns the status-code option if present.
+//
+// The status-code option is described by RFC 6926, Section 6.2.2.
+func (d *DHCPv4) Status() *Status {
+	v := d.Options.Get(OptionStatusCode)
+	if v == nil {
+		return nil
+	}
+	var s Status
+	if err := s.FromBytes(v); err != nil {
+		return nil
+	}
+	return &s
+}
+
+// BaseTime returns the base-time option, or the zero time if not present.
+//
+// The base-time option is described by RFC 6926, Section 6.2.3.
+func (d *DHCPv4) BaseTime() time.Time {
+	return GetTimestamp(OptionBaseTime, d.Options)
+}
+
+// StartTimeOfState returns the start-time-of-state option, or the given
+// default duration if not present.
+//
+// The start-time-of-state option is described by RFC 6926, Section 6.2.4.
+func (d *DHCPv4) StartTimeOfState(def time.Duration) time.Duration {
+	return getDuration(OptionStartTimeOfState, d.Options, def)
+}
+
+// QueryStartTime returns the query-start-time option, or the zero time if not
+// present.
+//
+// The query-start-time option is described by RFC 6926, Section 6.2.5.
+func (d *DHCPv4) QueryStartTime() time.Time {
+	return GetTimestamp(OptionQueryStartTime, d.Options)
+}
+
+// QueryEndTime returns the query-end-time option, or the zero time if not
+// present.
+//
+// The query-end-time option is described by RFC 6926, Section 6.2.6.
+func (d *DHCPv4) QueryEndTime() time.Time {
+	return GetTimestamp(OptionQueryEndTime, d.Options)
+}
+
+// LeaseState returns the dhcp-state option, or LeaseStateNone if not present.
+//
+// The dhcp-state option is described by RFC 6926, Section 6.2.8.
+func (d *DHCPv4) LeaseState() LeaseState {
+	v := d.Options.Get(OptionDHCPState)
+	if v == nil {
+		return LeaseStateNone
+	}
+	var s LeaseState
+	if err := s.FromBytes(v); err != nil {
+		return LeaseStateNone
+	}
+	return s
+}
+
+// DataSource returns the data-source option, or the zero (local) data
+// source if not present.
+//
+// The data-source option is described by RFC 6926, Section 6.2.9.
+func (d *DHCPv4) DataSource() DataSource {
+	v := d.Options.Get(OptionDataSource)
 	if v == nil {
-		return def
+		return 0
 	}
-	var dur Duration
-	if err := dur.FromBytes(v); err != nil {
-		return def
+	var s DataSource
+	if err := s.FromBytes(v); err != nil {
+		return 0
 	}
-	return time.Duration(dur)
+	return s
 }
 
 // MaxMessageSize returns the DHCP Maximum Message Size if present.
@@ -675,6 +850,35 @@ func (d *DHCPv4) UserClass() *UserClass {
 	return &uc
 }
 
+// ClientIdentifier returns the client identifier option if present.
+//
+// The client identifier option is described by RFC 2132, Section 9.14, and
+// RFC 4361, Section 6.1.
+func (d *DHCPv4) ClientIdentifier() *ClientIdentifier {
+	v := d.Options.Get(OptionClientIdentifier)
+	if v == nil {
+		return nil
+	}
+	var id ClientIdentifier
+	if err := id.FromBytes(v); err != nil {
+		return nil
+	}
+	return &id
+}
+
+// ClientIdentity returns the identifier a server should key the client's
+// bindings on: the client identifier option if present, or else the hardware
+// type and client hardware address, per RFC 2131, Section 4.2.
+func (d *DHCPv4) ClientIdentity() ClientIdentifier {
+	if id := d.ClientIdentifier(); id != nil {
+		return *id
+	}
+	return ClientIdentifier{
+		Type:       uint8(d.HWType),
+		Identifier: d.ClientHWAddr,
+	}
+}
+
 // VIVC returns the vendor-identifying vendor class option if present.
 func (d *DHCPv4) VIVC() VIVCIdentifiers {
 	v := d.Options.Get(OptionVendorIdentifyingVendorClass)
@@ -687,3 +891,17 @@ func (d *DHCPv4) VIVC() VIVCIdentifiers {
 	}
 	return ids
 }
+
+// VIVSO returns the vendor-identifying vendor-specific information option if
+// present.
+func (d *DHCPv4) VIVSO() VIVSOEnterprises {
+	v := d.Options.Get(OptionVendorIdentifyingVendorSpecific)
+	if v == nil {
+		return nil
+	}
+	var e VIVSOEnterprises
+	if err := e.FromBytes(v); err != nil {
+		return nil
+	}
+	return e
+}
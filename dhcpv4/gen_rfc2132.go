@@ -0,0 +1,228 @@
+//go:build ignore
+// +build ignore
+
+// This program generates option_rfc2132_gen.go, the constructors, getters and
+// decoders of the RFC 2132 options, from the table below. Run it with
+// go generate.
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"text/template"
+)
+
+// kind describes how the value of an option is represented.
+type kind struct {
+	// Value is the OptionValue type of the option.
+	Value string
+	// Param is the parameter list of the constructor, and Conv the
+	// conversion of the parameter to Value.
+	Param, Conv string
+	// Result is the result list of the getter, and Get its body.
+	Result, Get string
+}
+
+var (
+	kindIPs = kind{
+		Value: "IPs", Param: "ips ...net.IP", Conv: "IPs(ips)",
+		Result: "[]net.IP", Get: "return GetIPs({{.Code}}, d.Options)",
+	}
+	kindIP = kind{
+		Value: "IP", Param: "ip net.IP", Conv: "IP(ip)",
+		Result: "net.IP", Get: "return GetIP({{.Code}}, d.Options)",
+	}
+	kindString = kind{
+		Value: "String", Param: "s string", Conv: "String(s)",
+		Result: "string", Get: "return GetString({{.Code}}, d.Options)",
+	}
+	kindBool = kind{
+		Value: "Bool", Param: "b bool", Conv: "Bool(b)",
+		Result: "(bool, error)", Get: "return GetBool({{.Code}}, d.Options)",
+	}
+	kindUint8 = kind{
+		Value: "Uint8", Param: "v uint8", Conv: "Uint8(v)",
+		Result: "(uint8, error)", Get: "return GetUint8({{.Code}}, d.Options)",
+	}
+	kindUint16 = kind{
+		Value: "Uint16", Param: "v uint16", Conv: "Uint16(v)",
+		Result: "(uint16, error)", Get: "return GetUint16({{.Code}}, d.Options)",
+	}
+	kindUint16s = kind{
+		Value: "Uint16s", Param: "v ...uint16", Conv: "Uint16s(v)",
+		Result: "[]uint16", Get: "return GetUint16s({{.Code}}, d.Options)",
+	}
+	kindDuration = kind{
+		Value: "Duration", Param: "dur time.Duration", Conv: "Duration(dur)",
+		Result: "time.Duration", Get: "return getDuration({{.Code}}, d.Options, def)",
+	}
+	kindTimeOffset = kind{
+		Value: "TimeOffset", Param: "offset time.Duration", Conv: "TimeOffset(offset)",
+		Result: "time.Duration", Get: "return getTimeOffset({{.Code}}, d.Options, def)",
+	}
+	kindStaticRoutes = kind{
+		Value: "StaticRoutes", Param: "routes ...StaticRoute", Conv: "StaticRoutes(routes)",
+		Result: "[]StaticRoute", Get: "return GetStaticRoutes({{.Code}}, d.Options)",
+	}
+	kindPolicyFilters = kind{
+		Value: "PolicyFilters", Param: "filters ...net.IPNet", Conv: "PolicyFilters(filters)",
+		Result: "[]net.IPNet", Get: "return GetPolicyFilters({{.Code}}, d.Options)",
+	}
+	kindNetBIOSNodeType = kind{
+		Value: "NetBIOSNodeType", Param: "t NetBIOSNodeType", Conv: "t",
+		Result: "(NetBIOSNodeType, error)", Get: getEnum,
+	}
+	kindOverload = kind{
+		Value: "Overload", Param: "o Overload", Conv: "o",
+		Result: "(Overload, error)", Get: getEnum,
+	}
+)
+
+const getEnum = `v := d.Options.Get({{.Code}})
+	if v == nil {
+		return 0, fmt.Errorf("option not present")
+	}
+	var o {{.Kind.Value}}
+	if err := o.FromBytes(v); err != nil {
+		return 0, err
+	}
+	return o, nil`
+
+// option is an entry of the table.
+type option struct {
+	// Code is the name of the option code constant.
+	Code string
+	// Name is used to name the constructor OptName and the getter Name.
+	Name string
+	// Title is the name of the option in RFC 2132.
+	Title string
+	// Section is the section of RFC 2132 describing the option.
+	Section string
+	Kind    kind
+}
+
+// Options with a dedicated file, like the Subnet Mask or the Router option,
+// are not in the table.
+var options = []option{
+	{"OptionTimeOffset", "TimeOffset", "Time Offset", "3.4", kindTimeOffset},
+	{"OptionTimeServer", "TimeServers", "Time Server", "3.6", kindIPs},
+	{"OptionNameServer", "NameServers", "Name Server", "3.7", kindIPs},
+	{"OptionLogServer", "LogServers", "Log Server", "3.9", kindIPs},
+	{"OptionQuoteServer", "QuoteServers", "Cookie Server", "3.10", kindIPs},
+	{"OptionLPRServer", "LPRServers", "LPR Server", "3.11", kindIPs},
+	{"OptionImpressServer", "ImpressServers", "Impress Server", "3.12", kindIPs},
+	{"OptionResourceLocationServer", "ResourceLocationServers", "Resource Location Server", "3.13", kindIPs},
+	{"OptionBootFileSize", "BootFileSize", "Boot File Size", "3.15", kindUint16},
+	{"OptionMeritDumpFile", "MeritDumpFile", "Merit Dump File", "3.16", kindString},
+	{"OptionSwapServer", "SwapServer", "Swap Server", "3.18", kindIP},
+	{"OptionExtensionsPath", "ExtensionsPath", "Extensions Path", "3.20", kindString},
+	{"OptionIPForwarding", "IPForwarding", "IP Forwarding Enable/Disable", "4.1", kindBool},
+	{"OptionNonLocalSourceRouting", "NonLocalSourceRouting", "Non-Local Source Routing Enable/Disable", "4.2", kindBool},
+	{"OptionPolicyFilter", "PolicyFilter", "Policy Filter", "4.3", kindPolicyFilters},
+	{"OptionMaximumDatagramAssemblySize", "MaxDatagramReassemblySize", "Maximum Datagram Reassembly Size", "4.4", kindUint16},
+	{"OptionDefaultIPTTL", "DefaultIPTTL", "Default IP Time-to-live", "4.5", kindUint8},
+	{"OptionPathMTUAgingTimeout", "PathMTUAgingTimeout", "Path MTU Aging Timeout", "4.6", kindDuration},
+	{"OptionPathMTUPlateauTable", "PathMTUPlateauTable", "Path MTU Plateau Table", "4.7", kindUint16s},
+	{"OptionInterfaceMTU", "InterfaceMTU", "Interface MTU", "5.1", kindUint16},
+	{"OptionAllSubnetsAreLocal", "AllSubnetsAreLocal", "All Subnets are Local", "5.2", kindBool},
+	{"OptionPerformMaskDiscovery", "PerformMaskDiscovery", "Perform Mask Discovery", "5.4", kindBool},
+	{"OptionMaskSupplier", "MaskSupplier", "Mask Supplier", "5.5", kindBool},
+	{"OptionPerformRouterDiscovery", "PerformRouterDiscovery", "Perform Router Discovery", "5.6", kindBool},
+	{"OptionRouterSolicitationAddress", "RouterSolicitationAddress", "Router Solicitation Address", "5.7", kindIP},
+	{"OptionStaticRoutingTable", "StaticRoutes", "Static Route", "5.8", kindStaticRoutes},
+	{"OptionTrailerEncapsulation", "TrailerEncapsulation", "Trailer Encapsulation", "6.1", kindBool},
+	{"OptionArpCacheTimeout", "ARPCacheTimeout", "ARP Cache Timeout", "6.2", kindDuration},
+	{"OptionEthernetEncapsulation", "EthernetEncapsulation", "Ethernet Encapsulation", "6.3", kindBool},
+	{"OptionDefaulTCPTTL", "DefaultTCPTTL", "TCP Default TTL", "7.1", kindUint8},
+	{"OptionTCPKeepaliveInterval", "TCPKeepaliveInterval", "TCP Keepalive Interval", "7.2", kindDuration},
+	{"OptionTCPKeepaliveGarbage", "TCPKeepaliveGarbage", "TCP Keepalive Garbage", "7.3", kindBool},
+	{"OptionNetworkInformationServiceDomain", "NISDomain", "Network Information Service Domain", "8.1", kindString},
+	{"OptionNetworkInformationServers", "NISServers", "Network Information Servers", "8.2", kindIPs},
+	{"OptionNetBIOSOverTCPIPNameServer", "NetBIOSNameServers", "NetBIOS over TCP/IP Name Server", "8.5", kindIPs},
+	{"OptionNetBIOSOverTCPIPDatagramDistributionServer", "NetBIOSDatagramDistributionServers", "NetBIOS over TCP/IP Datagram Distribution Server", "8.6", kindIPs},
+	{"OptionNetBIOSOverTCPIPNodeType", "NetBIOSNodeType", "NetBIOS over TCP/IP Node Type", "8.7", kindNetBIOSNodeType},
+	{"OptionNetBIOSOverTCPIPScope", "NetBIOSScope", "NetBIOS over TCP/IP Scope", "8.8", kindString},
+	{"OptionXWindowSystemFontServer", "XWindowFontServers", "X Window System Font Server", "8.9", kindIPs},
+	{"OptionXWindowSystemDisplayManger", "XWindowDisplayManagers", "X Window System Display Manager", "8.10", kindIPs},
+	{"OptionNetworkInformationServicePlusDomain", "NISPlusDomain", "Network Information Service+ Domain", "8.11", kindString},
+	{"OptionNetworkInformationServicePlusServers", "NISPlusServers", "Network Information Service+ Servers", "8.12", kindIPs},
+	{"OptionMobileIPHomeAgent", "MobileIPHomeAgents", "Mobile IP Home Agent", "8.13", kindIPs},
+	{"OptionSimpleMailTransportProtocolServer", "SMTPServers", "Simple Mail Transport Protocol (SMTP) Server", "8.14", kindIPs},
+	{"OptionPostOfficeProtocolServer", "POP3Servers", "Post Office Protocol (POP3) Server", "8.15", kindIPs},
+	{"OptionNetworkNewsTransportProtocolServer", "NNTPServers", "Network News Transport Protocol (NNTP) Server", "8.16", kindIPs},
+	{"OptionDefaultWorldWideWebServer", "WWWServers", "Default World Wide Web (WWW) Server", "8.17", kindIPs},
+	{"OptionDefaultFingerServer", "FingerServers", "Default Finger Server", "8.18", kindIPs},
+	{"OptionDefaultInternetRelayChatServer", "IRCServers", "Default Internet Relay Chat (IRC) Server", "8.19", kindIPs},
+	{"OptionStreetTalkServer", "StreetTalkServers", "StreetTalk Server", "8.20", kindIPs},
+	{"OptionStreetTalkDirectoryAssistanceServer", "STDAServers", "StreetTalk Directory Assistance (STDA) Server", "8.21", kindIPs},
+	{"OptionOptionOverload", "Overload", "Option Overload", "9.3", kindOverload},
+	{"OptionMessage", "Message", "Message", "9.9", kindString},
+	{"OptionRenewTimeValue", "RenewalTime", "Renewal (T1) Time Value", "9.11", kindDuration},
+	{"OptionRebindingTimeValue", "RebindingTime", "Rebinding (T2) Time Value", "9.12", kindDuration},
+}
+
+var tmpl = template.Must(template.New("").Parse(`// Code generated by gen_rfc2132.go; DO NOT EDIT.
+
+package dhcpv4
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// rfc2132Decoders returns the decoder of the options generated from the
+// table, used to humanize them.
+var rfc2132Decoders = map[OptionCode]func() OptionDecoder{
+{{- range .}}
+	{{.Code}}: func() OptionDecoder { return new({{.Kind.Value}}) },
+{{- end}}
+}
+{{range .}}
+// Opt{{.Name}} returns a new DHCPv4 {{.Title}} option.
+//
+// The {{.Title}} option is described by RFC 2132, Section {{.Section}}.
+func Opt{{.Name}}({{.Kind.Param}}) Option {
+	return Option{Code: {{.Code}}, Value: {{.Kind.Conv}}}
+}
+
+// {{.Name}} parses the DHCPv4 {{.Title}} option if present.
+{{- if eq .Kind.Result "time.Duration"}}
+// It returns def if the option is not present.
+{{- end}}
+//
+// The {{.Title}} option is described by RFC 2132, Section {{.Section}}.
+func (d *DHCPv4) {{.Name}}({{if eq .Kind.Result "time.Duration"}}def time.Duration{{end}}) {{.Kind.Result}} {
+	{{.Getter}}
+}
+{{end}}`))
+
+// entry is an option along with its expanded getter body.
+type entry struct {
+	option
+	Getter string
+}
+
+func main() {
+	var entries []entry
+	for _, o := range options {
+		var b bytes.Buffer
+		if err := template.Must(template.New("").Parse(o.Kind.Get)).Execute(&b, o); err != nil {
+			log.Fatal(err)
+		}
+		entries = append(entries, entry{option: o, Getter: b.String()})
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, entries); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated code: %v\n%s", err, b.Bytes())
+	}
+	if err := ioutil.WriteFile("option_rfc2132_gen.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,287 @@
+package leasequery
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+)
+
+// DefaultDialTimeout is the time to wait for the TCP connection to the server
+// to be established.
+const DefaultDialTimeout = 3 * time.Second
+
+// maxMessageLen is the largest message the 2-byte length prefix of RFC 6926,
+// Section 6.1 can frame.
+const maxMessageLen = 0xffff
+
+// WriteMessage writes m to w, framed as described by RFC 6926, Section 6.1.
+func WriteMessage(w io.Writer, m *dhcpv4.DHCPv4) error {
+	b := m.ToBytes()
+	if len(b) > maxMessageLen {
+		return fmt.Errorf("message too long for bulk leasequery: %d bytes", len(b))
+	}
+	frame := make([]byte, 2+len(b))
+	binary.BigEndian.PutUint16(frame, uint16(len(b)))
+	copy(frame[2:], b)
+	_, err := w.Write(frame)
+	return err
+}
+
+// ReadMessage reads a message framed as described by RFC 6926, Section 6.1
+// from r.
+func ReadMessage(r io.Reader) (*dhcpv4.DHCPv4, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint16(hdr[:])
+	if n == 0 {
+		return nil, errors.New("empty bulk leasequery message")
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return dhcpv4.FromBytes(b)
+}
+
+// BulkClient is a DHCPv4 Bulk Leasequery client. Queries are sent one at a
+// time over a single TCP connection.
+type BulkClient struct {
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	RemoteAddr   net.Addr
+
+	conn net.Conn
+}
+
+// NewBulkClient returns a bulk leasequery client for the server at raddr.
+func NewBulkClient(raddr net.Addr) *BulkClient {
+	return &BulkClient{
+		DialTimeout:  DefaultDialTimeout,
+		ReadTimeout:  dhcpv4.DefaultReadTimeout,
+		WriteTimeout: dhcpv4.DefaultWriteTimeout,
+		RemoteAddr:   raddr,
+	}
+}
+
+// Open connects to the server.
+func (c *BulkClient) Open() error {
+	conn, err := net.DialTimeout("tcp4", c.RemoteAddr.String(), c.DialTimeout)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	return nil
+}
+
+// Close closes the connection to the server.
+func (c *BulkClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Query sends q and returns the server's replies. For queries by relay-id or
+// remote-id, it collects replies until the server sends DHCPLEASEQUERYDONE;
+// otherwise it returns the single reply. An error is returned along with
+// the replies received so far if the server reports a failure status.
+func (c *BulkClient) Query(q *dhcpv4.DHCPv4) ([]*dhcpv4.DHCPv4, error) {
+	if c.conn == nil {
+		return nil, fmt.Errorf("bulk leasequery client is not open")
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	if err := WriteMessage(c.conn, q); err != nil {
+		return nil, err
+	}
+	multiple := classify(q).multiple()
+
+	var replies []*dhcpv4.DHCPv4
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+		m, err := ReadMessage(c.conn)
+		if err != nil {
+			return replies, err
+		}
+		if m.TransactionID != q.TransactionID {
+			return replies, fmt.Errorf("unexpected transaction ID %s, want %s", m.TransactionID, q.TransactionID)
+		}
+		if m.MessageType() == dhcpv4.MessageTypeLeaseQueryDone {
+			if s := m.Status(); s != nil && s.Code != dhcpv4.StatusSuccess {
+				return replies, fmt.Errorf("bulk leasequery failed: %s", s)
+			}
+			return replies, nil
+		}
+		replies = append(replies, m)
+		if !multiple {
+			return replies, nil
+		}
+	}
+}
+
+// BulkServer answers DHCPv4 Bulk Leasequeries over TCP from a BulkLeaseStore.
+type BulkServer struct {
+	Store BulkLeaseStore
+
+	// ServerID is sent in the server identifier option of every reply.
+	ServerID net.IP
+
+	listener     net.Listener
+	listenerLock sync.Mutex
+	localAddr    net.TCPAddr
+}
+
+// NewBulkServer initializes and returns a new BulkServer object.
+func NewBulkServer(addr net.TCPAddr, store BulkLeaseStore, serverID net.IP) *BulkServer {
+	return &BulkServer{
+		Store:     store,
+		ServerID:  serverID,
+		localAddr: addr,
+	}
+}
+
+// LocalAddr returns the local address of the listening socket, or nil if not
+// listening.
+func (s *BulkServer) LocalAddr() net.Addr {
+	s.listenerLock.Lock()
+	defer s.listenerLock.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// ActivateAndServe starts listening and serving connections. It only returns
+// on error or after `BulkServer.Close` is called.
+func (s *BulkServer) ActivateAndServe() error {
+	s.listenerLock.Lock()
+	if s.listener != nil {
+		s.listenerLock.Unlock()
+		return fmt.Errorf("bulk leasequery server already listening")
+	}
+	l, err := net.ListenTCP("tcp4", &s.localAddr)
+	if err != nil {
+		s.listenerLock.Unlock()
+		return err
+	}
+	s.listener = l
+	s.listenerLock.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.listenerLock.Lock()
+			closed := s.listener == nil
+			s.listenerLock.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go s.serve(conn)
+	}
+}
+
+// Close stops listening. Connections already accepted are served until the
+// requestor closes them.
+func (s *BulkServer) Close() error {
+	s.listenerLock.Lock()
+	defer s.listenerLock.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.listener = nil
+	return err
+}
+
+func (s *BulkServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		q, err := ReadMessage(conn)
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("leasequery: cannot read from %v: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if err := s.answer(conn, q); err != nil {
+			log.Printf("leasequery: cannot reply to %v: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// answer writes the replies to q to w, as described by RFC 6926, Section 7.
+func (s *BulkServer) answer(w io.Writer, q *dhcpv4.DHCPv4) error {
+	r := &Responder{Store: s.Store, ServerID: s.ServerID}
+	done := func(code dhcpv4.StatusCode, msg string) error {
+		var mods []dhcpv4.Modifier
+		if code != dhcpv4.StatusSuccess {
+			mods = append(mods, dhcpv4.WithOption(dhcpv4.OptStatusCode(code, msg)))
+		}
+		m, err := r.newReply(q, dhcpv4.MessageTypeLeaseQueryDone, mods...)
+		if err != nil {
+			return err
+		}
+		return WriteMessage(w, m)
+	}
+
+	if q.OpCode != dhcpv4.OpcodeBootRequest {
+		return done(dhcpv4.StatusMalformedQuery, "not a request")
+	}
+	now := time.Now()
+	t := classify(q)
+	if t == queryMalformed {
+		return done(dhcpv4.StatusMalformedQuery, "no query criteria")
+	}
+	if !t.multiple() {
+		m, err := r.reply(q, now, true)
+		if err != nil {
+			return done(dhcpv4.StatusUnspecFail, err.Error())
+		}
+		return WriteMessage(w, m)
+	}
+
+	var (
+		leases []*Lease
+		err    error
+	)
+	ri := q.RelayAgentInfo()
+	if t == queryByRelayID {
+		leases, err = s.Store.LeasesByRelayID(ri.Get(dhcpv4.RelayIDSubOption))
+	} else {
+		leases, err = s.Store.LeasesByRemoteID(ri.Get(dhcpv4.AgentRemoteIDSubOption))
+	}
+	if err != nil && err != ErrUnknown {
+		return done(dhcpv4.StatusUnspecFail, err.Error())
+	}
+	start, end := q.QueryStartTime(), q.QueryEndTime()
+	for _, l := range leases {
+		if !start.IsZero() && l.StateSince.Before(start) {
+			continue
+		}
+		if !end.IsZero() && l.StateSince.After(end) {
+			continue
+		}
+		m, err := r.bindingReply(q, l, nil, now, true)
+		if err != nil {
+			return done(dhcpv4.StatusUnspecFail, err.Error())
+		}
+		if err := WriteMessage(w, m); err != nil {
+			return err
+		}
+	}
+	return done(dhcpv4.StatusSuccess, "")
+}
@@ -0,0 +1,114 @@
+package leasequery
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageFraming(t *testing.T) {
+	q, _ := NewBulkQueryByRelayID([]byte("relay1"))
+	var buf bytes.Buffer
+	require.NoError(t, WriteMessage(&buf, q))
+	b := buf.Bytes()
+	require.Equal(t, len(b)-2, int(b[0])<<8|int(b[1]))
+
+	m, err := ReadMessage(&buf)
+	require.NoError(t, err)
+	require.Equal(t, q.TransactionID, m.TransactionID)
+	require.Equal(t, dhcpv4.MessageTypeBulkLeaseQuery, m.MessageType())
+
+	// Truncated.
+	_, err = ReadMessage(bytes.NewReader(b[:len(b)-1]))
+	require.Error(t, err)
+
+	// Empty.
+	_, err = ReadMessage(bytes.NewReader([]byte{0, 0}))
+	require.Error(t, err)
+}
+
+func setUpBulk(t *testing.T) (*BulkServer, *BulkClient) {
+	s := NewBulkServer(net.TCPAddr{IP: net.IP{127, 0, 0, 1}}, newTestStore(), serverID)
+	go func() {
+		_ = s.ActivateAndServe()
+	}()
+	var laddr net.Addr
+	for i := 0; i < 100 && laddr == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		laddr = s.LocalAddr()
+	}
+	require.NotNil(t, laddr, "server did not start")
+
+	c := NewBulkClient(laddr)
+	require.NoError(t, c.Open())
+	return s, c
+}
+
+func TestBulkQueryByRelayID(t *testing.T) {
+	s, c := setUpBulk(t)
+	defer s.Close()
+	defer c.Close()
+
+	q, _ := NewBulkQueryByRelayID([]byte("relay1"))
+	replies, err := c.Query(q)
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	for _, m := range replies {
+		require.Equal(t, dhcpv4.MessageTypeLeaseActive, m.MessageType())
+		require.Equal(t, dhcpv4.LeaseStateActive, m.LeaseState())
+		require.False(t, m.BaseTime().IsZero())
+		require.Equal(t, "local", m.DataSource().String())
+	}
+	require.Equal(t, net.IP{192, 168, 0, 10}, replies[0].ClientIPAddr)
+	require.Equal(t, 2*time.Hour, replies[0].StartTimeOfState(0))
+
+	// Only bindings that changed state in the last 90 minutes.
+	q, _ = NewBulkQueryByRemoteID([]byte("cpe1"),
+		dhcpv4.WithOption(dhcpv4.OptQueryStartTime(time.Now().Add(-90*time.Minute))))
+	replies, err = c.Query(q)
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	require.Equal(t, net.IP{192, 168, 0, 11}, replies[0].ClientIPAddr)
+
+	// No bindings.
+	q, _ = NewBulkQueryByRelayID([]byte("relay2"))
+	replies, err = c.Query(q)
+	require.NoError(t, err)
+	require.Empty(t, replies)
+}
+
+func TestBulkQuerySingle(t *testing.T) {
+	s, c := setUpBulk(t)
+	defer s.Close()
+	defer c.Close()
+
+	q, _ := NewQueryByIP(net.IP{192, 168, 0, 12}, nil, WithBulk)
+	replies, err := c.Query(q)
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	require.Equal(t, dhcpv4.MessageTypeLeaseUnassigned, replies[0].MessageType())
+
+	q, _ = NewQueryByMAC(iana.HWTypeEthernet, mac, nil, WithBulk)
+	replies, err = c.Query(q)
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	require.Equal(t, dhcpv4.MessageTypeLeaseActive, replies[0].MessageType())
+	require.Equal(t, dhcpv4.LeaseStateActive, replies[0].LeaseState())
+	require.Equal(t, time.Second, replies[0].ClientLastTransactionTime(0))
+}
+
+func TestBulkQueryMalformed(t *testing.T) {
+	s, c := setUpBulk(t)
+	defer s.Close()
+	defer c.Close()
+
+	q, _ := dhcpv4.New(dhcpv4.WithHWType(0), WithBulk)
+	replies, err := c.Query(q)
+	require.Error(t, err)
+	require.Empty(t, replies)
+}
@@ -0,0 +1,156 @@
+// Package leasequery implements DHCPv4 Leasequery as described by RFC 4388,
+// and DHCPv4 Bulk Leasequery over TCP as described by RFC 6926.
+//
+// Queries are built with the NewQueryBy* and NewBulkQueryBy* functions. A
+// Responder answers UDP leasequeries and can be used directly as a
+// dhcpv4.Handler, while BulkServer and BulkClient implement the TCP side of
+// Bulk Leasequery. Both server sides look bindings up in a user-provided
+// LeaseStore.
+package leasequery
+
+import (
+	"bytes"
+	"net"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// DefaultRequestedOptions are the options requested by queries built in this
+// package.
+var DefaultRequestedOptions = []dhcpv4.OptionCode{
+	dhcpv4.OptionIPAddressLeaseTime,
+	dhcpv4.OptionClientLastTransactionTime,
+	dhcpv4.OptionAssociatedIP,
+	dhcpv4.OptionRelayAgentInformation,
+}
+
+// queryType is the kind of lookup a leasequery asks for.
+type queryType int
+
+const (
+	queryMalformed queryType = iota
+	queryByIP
+	queryByHWAddr
+	queryByClientID
+	queryByRelayID
+	queryByRemoteID
+)
+
+// multiple returns whether a query of this type may yield more than one
+// binding, in which case a bulk reply ends with DHCPLEASEQUERYDONE.
+func (t queryType) multiple() bool {
+	return t == queryByRelayID || t == queryByRemoteID
+}
+
+// classify returns the type of q. RFC 4388, Section 6.1 orders the checks:
+// ciaddr, then client identifier, then chaddr. The relay-id and remote-id
+// queries of RFC 6926 are only recognized when none of those are set.
+func classify(q *dhcpv4.DHCPv4) queryType {
+	switch {
+	case q.ClientIPAddr != nil && !q.ClientIPAddr.IsUnspecified():
+		return queryByIP
+	case q.ClientIdentifier() != nil:
+		return queryByClientID
+	case len(q.ClientHWAddr) > 0 && !isZero(q.ClientHWAddr):
+		return queryByHWAddr
+	}
+	if q.MessageType() != dhcpv4.MessageTypeBulkLeaseQuery {
+		return queryMalformed
+	}
+	if ri := q.RelayAgentInfo(); ri != nil {
+		switch {
+		case len(ri.Get(dhcpv4.RelayIDSubOption)) > 0:
+			return queryByRelayID
+		case len(ri.Get(dhcpv4.AgentRemoteIDSubOption)) > 0:
+			return queryByRemoteID
+		}
+	}
+	return queryMalformed
+}
+
+func isZero(b []byte) bool {
+	return len(bytes.Trim(b, "\x00")) == 0
+}
+
+// NewQueryByIP builds a DHCPLEASEQUERY asking for the binding of ip. giaddr is
+// the address of the requestor, which the server sends its reply to.
+//
+// See RFC 4388, Section 6.1.
+func NewQueryByIP(ip, giaddr net.IP, modifiers ...dhcpv4.Modifier) (*dhcpv4.DHCPv4, error) {
+	return dhcpv4.New(dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithHWType(0),
+		dhcpv4.WithClientIP(ip),
+		withQuery(dhcpv4.MessageTypeLeaseQuery, giaddr),
+	)...)
+}
+
+// NewQueryByMAC builds a DHCPLEASEQUERY asking for the bindings of the client
+// with the given hardware type and address. giaddr is the address of the
+// requestor, which the server sends its reply to.
+//
+// See RFC 4388, Section 6.1.
+func NewQueryByMAC(hwtype iana.HWType, mac net.HardwareAddr, giaddr net.IP, modifiers ...dhcpv4.Modifier) (*dhcpv4.DHCPv4, error) {
+	return dhcpv4.New(dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithHWType(hwtype),
+		dhcpv4.WithHwAddr(mac),
+		withQuery(dhcpv4.MessageTypeLeaseQuery, giaddr),
+	)...)
+}
+
+// NewQueryByClientID builds a DHCPLEASEQUERY asking for the bindings of the
+// client with the given client identifier (option 61). giaddr is the address
+// of the requestor, which the server sends its reply to.
+//
+// See RFC 4388, Section 6.1.
+func NewQueryByClientID(id dhcpv4.ClientIdentifier, giaddr net.IP, modifiers ...dhcpv4.Modifier) (*dhcpv4.DHCPv4, error) {
+	return dhcpv4.New(dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithHWType(0),
+		dhcpv4.WithClientIdentifier(id),
+		withQuery(dhcpv4.MessageTypeLeaseQuery, giaddr),
+	)...)
+}
+
+// NewBulkQueryByRelayID builds a DHCPBULKLEASEQUERY asking for all bindings
+// established through the relay agent identified by relayID.
+//
+// See RFC 6926, Section 7.2.
+func NewBulkQueryByRelayID(relayID []byte, modifiers ...dhcpv4.Modifier) (*dhcpv4.DHCPv4, error) {
+	return dhcpv4.New(dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithHWType(0),
+		dhcpv4.WithOption(dhcpv4.OptRelayAgentInfo(
+			dhcpv4.OptGeneric(dhcpv4.RelayIDSubOption, relayID),
+		)),
+		withQuery(dhcpv4.MessageTypeBulkLeaseQuery, nil),
+	)...)
+}
+
+// NewBulkQueryByRemoteID builds a DHCPBULKLEASEQUERY asking for all bindings
+// carrying the given relay agent remote-id.
+//
+// See RFC 6926, Section 7.2.
+func NewBulkQueryByRemoteID(remoteID []byte, modifiers ...dhcpv4.Modifier) (*dhcpv4.DHCPv4, error) {
+	return dhcpv4.New(dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithHWType(0),
+		dhcpv4.WithOption(dhcpv4.OptRelayAgentInfo(
+			dhcpv4.OptGeneric(dhcpv4.AgentRemoteIDSubOption, remoteID),
+		)),
+		withQuery(dhcpv4.MessageTypeBulkLeaseQuery, nil),
+	)...)
+}
+
+// WithBulk turns a query into a DHCPBULKLEASEQUERY, for sending it over a
+// Bulk Leasequery connection.
+func WithBulk(d *dhcpv4.DHCPv4) {
+	d.UpdateOption(dhcpv4.OptMessageType(dhcpv4.MessageTypeBulkLeaseQuery))
+}
+
+func withQuery(mt dhcpv4.MessageType, giaddr net.IP) dhcpv4.Modifier {
+	return func(d *dhcpv4.DHCPv4) {
+		if giaddr != nil {
+			d.GatewayIPAddr = giaddr
+		}
+		dhcpv4.WithMessageType(mt)(d)
+		dhcpv4.WithRequestedOptions(DefaultRequestedOptions...)(d)
+	}
+}
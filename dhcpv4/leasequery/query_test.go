@@ -0,0 +1,66 @@
+package leasequery
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+var giaddr = net.IP{10, 0, 0, 1}
+
+func TestNewQueryByIP(t *testing.T) {
+	q, err := NewQueryByIP(net.IP{192, 168, 0, 10}, giaddr)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseQuery, q.MessageType())
+	require.Equal(t, net.IP{192, 168, 0, 10}, q.ClientIPAddr)
+	require.Equal(t, giaddr, q.GatewayIPAddr)
+	require.True(t, q.IsOptionRequested(dhcpv4.OptionAssociatedIP))
+	require.Equal(t, queryByIP, classify(q))
+
+	// Survives the wire without gaining a hardware address.
+	q, err = dhcpv4.FromBytes(q.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, queryByIP, classify(q))
+	require.Empty(t, q.ClientHWAddr)
+}
+
+func TestNewQueryByMAC(t *testing.T) {
+	mac := net.HardwareAddr{0, 1, 2, 3, 4, 5}
+	q, err := NewQueryByMAC(iana.HWTypeEthernet, mac, giaddr)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseQuery, q.MessageType())
+	require.Equal(t, mac, q.ClientHWAddr)
+	require.Equal(t, queryByHWAddr, classify(q))
+}
+
+func TestNewQueryByClientID(t *testing.T) {
+	q, err := NewQueryByClientID(dhcpv4.ClientIdentifier{Type: 1, Identifier: []byte{0, 1, 2, 3, 4, 5}}, giaddr)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 0, 1, 2, 3, 4, 5}, q.GetOneOption(dhcpv4.OptionClientIdentifier))
+	require.Equal(t, queryByClientID, classify(q))
+}
+
+func TestNewBulkQuery(t *testing.T) {
+	q, err := NewBulkQueryByRelayID([]byte("relay1"))
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeBulkLeaseQuery, q.MessageType())
+	require.Equal(t, []byte("relay1"), q.RelayAgentInfo().Get(dhcpv4.RelayIDSubOption))
+	require.Equal(t, queryByRelayID, classify(q))
+
+	q, err = NewBulkQueryByRemoteID([]byte("cpe1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("cpe1"), q.RelayAgentInfo().Get(dhcpv4.AgentRemoteIDSubOption))
+	require.Equal(t, queryByRemoteID, classify(q))
+
+	// Relay-id queries are only valid in Bulk Leasequery.
+	q, err = NewBulkQueryByRelayID([]byte("relay1"), dhcpv4.WithMessageType(dhcpv4.MessageTypeLeaseQuery))
+	require.NoError(t, err)
+	require.Equal(t, queryMalformed, classify(q))
+
+	q, err = NewQueryByIP(net.IP{192, 168, 0, 10}, nil, WithBulk)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeBulkLeaseQuery, q.MessageType())
+}
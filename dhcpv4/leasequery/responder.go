@@ -0,0 +1,172 @@
+package leasequery
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+)
+
+var errMalformed = errors.New("malformed leasequery: no ciaddr, client identifier or chaddr")
+
+// Responder answers DHCPv4 leasequeries from a LeaseStore, as described by
+// RFC 4388, Section 6.4. Its Handle method can be passed to dhcpv4.NewServer.
+type Responder struct {
+	Store LeaseStore
+
+	// ServerID is sent in the server identifier option of every reply.
+	ServerID net.IP
+}
+
+// Handle implements dhcpv4.Handler. Replies are sent back to peer, which is
+// the relay agent or access concentrator that sent the query.
+func (r *Responder) Handle(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+	if m.OpCode != dhcpv4.OpcodeBootRequest || m.MessageType() != dhcpv4.MessageTypeLeaseQuery {
+		return
+	}
+	reply, err := r.Reply(m)
+	if err != nil {
+		log.Printf("leasequery: cannot answer query from %v: %v", peer, err)
+		return
+	}
+	if _, err := conn.WriteTo(reply.ToBytes(), peer); err != nil {
+		log.Printf("leasequery: cannot reply to %v: %v", peer, err)
+	}
+}
+
+// Reply builds the reply to the leasequery q. It returns an error if q is
+// not a valid leasequery or if the lease store fails.
+func (r *Responder) Reply(q *dhcpv4.DHCPv4) (*dhcpv4.DHCPv4, error) {
+	return r.reply(q, time.Now(), false)
+}
+
+func (r *Responder) reply(q *dhcpv4.DHCPv4, now time.Time, bulk bool) (*dhcpv4.DHCPv4, error) {
+	switch mt := q.MessageType(); mt {
+	case dhcpv4.MessageTypeLeaseQuery, dhcpv4.MessageTypeBulkLeaseQuery:
+	default:
+		return nil, fmt.Errorf("not a leasequery: message type %s", mt)
+	}
+
+	switch classify(q) {
+	case queryByIP:
+		lease, err := r.Store.LeaseByIP(q.ClientIPAddr)
+		if err == ErrUnknown {
+			return r.newReply(q, dhcpv4.MessageTypeLeaseUnknown)
+		} else if err != nil {
+			return nil, err
+		}
+		if lease.State != dhcpv4.LeaseStateActive {
+			return r.newReply(q, dhcpv4.MessageTypeLeaseUnassigned)
+		}
+		return r.bindingReply(q, lease, nil, now, bulk)
+
+	case queryByHWAddr:
+		leases, err := r.Store.LeasesByHWAddr(q.HWType, q.ClientHWAddr)
+		return r.clientReply(q, leases, err, now, bulk)
+
+	case queryByClientID:
+		leases, err := r.Store.LeasesByClientID(q.ClientIdentifier().ToBytes())
+		return r.clientReply(q, leases, err, now, bulk)
+	}
+	return nil, errMalformed
+}
+
+// clientReply answers a query by hardware address or client identifier. Per
+// RFC 4388, Section 6.4.2, ciaddr holds the most recently used of the
+// client's active bindings and the associated-ip option lists all of them.
+func (r *Responder) clientReply(q *dhcpv4.DHCPv4, leases []*Lease, err error, now time.Time, bulk bool) (*dhcpv4.DHCPv4, error) {
+	if err != nil && err != ErrUnknown {
+		return nil, err
+	}
+	var (
+		active []*Lease
+		latest *Lease
+	)
+	for _, l := range leases {
+		if l.State != dhcpv4.LeaseStateActive {
+			continue
+		}
+		active = append(active, l)
+		if latest == nil || l.LastTransaction.After(latest.LastTransaction) {
+			latest = l
+		}
+	}
+	if latest == nil {
+		return r.newReply(q, dhcpv4.MessageTypeLeaseUnknown)
+	}
+	return r.bindingReply(q, latest, active, now, bulk)
+}
+
+func (r *Responder) newReply(q *dhcpv4.DHCPv4, mt dhcpv4.MessageType, modifiers ...dhcpv4.Modifier) (*dhcpv4.DHCPv4, error) {
+	return dhcpv4.NewReplyFromRequest(q, dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithHWType(q.HWType),
+		dhcpv4.WithClientIP(q.ClientIPAddr),
+		dhcpv4.WithMessageType(mt),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(r.ServerID)),
+	)...)
+}
+
+// bindingReply describes lease in a reply to q. associated lists all the
+// client's active bindings, and bulk adds the options RFC 6926, Section 7.4
+// requires in Bulk Leasequery replies.
+func (r *Responder) bindingReply(q *dhcpv4.DHCPv4, lease *Lease, associated []*Lease, now time.Time, bulk bool) (*dhcpv4.DHCPv4, error) {
+	mt := dhcpv4.MessageTypeLeaseActive
+	if lease.State != dhcpv4.LeaseStateActive {
+		mt = dhcpv4.MessageTypeLeaseUnassigned
+	}
+	return r.newReply(q, mt, func(d *dhcpv4.DHCPv4) {
+		d.ClientIPAddr = lease.IP
+		d.HWType = lease.HWType
+		d.ClientHWAddr = lease.HWAddr
+		if len(lease.ClientID) > 0 {
+			d.UpdateOption(dhcpv4.OptGeneric(dhcpv4.OptionClientIdentifier, lease.ClientID))
+		}
+		if lease.State == dhcpv4.LeaseStateActive {
+			d.UpdateOption(dhcpv4.OptIPAddressLeaseTime(remaining(lease.Expires, now)))
+		}
+		if !lease.LastTransaction.IsZero() && (bulk || q.IsOptionRequested(dhcpv4.OptionClientLastTransactionTime)) {
+			d.UpdateOption(dhcpv4.OptClientLastTransactionTime(elapsed(lease.LastTransaction, now)))
+		}
+		if len(associated) > 1 && q.IsOptionRequested(dhcpv4.OptionAssociatedIP) {
+			ips := make([]net.IP, 0, len(associated))
+			for _, l := range associated {
+				ips = append(ips, l.IP)
+			}
+			d.UpdateOption(dhcpv4.OptAssociatedIP(ips...))
+		}
+		if lease.RelayAgentInfo != nil && (bulk || q.IsOptionRequested(dhcpv4.OptionRelayAgentInformation)) {
+			d.UpdateOption(dhcpv4.Option{Code: dhcpv4.OptionRelayAgentInformation, Value: *lease.RelayAgentInfo})
+		}
+		if bulk {
+			d.UpdateOption(dhcpv4.OptBaseTime(now))
+			d.UpdateOption(dhcpv4.OptLeaseState(lease.State))
+			if !lease.StateSince.IsZero() {
+				d.UpdateOption(dhcpv4.OptStartTimeOfState(elapsed(lease.StateSince, now)))
+			}
+			if lease.Remote {
+				d.UpdateOption(dhcpv4.OptDataSource(dhcpv4.DataSourceRemote))
+			} else {
+				d.UpdateOption(dhcpv4.OptDataSource(0))
+			}
+		}
+	})
+}
+
+// remaining returns the whole seconds left until t, or zero if t is past.
+func remaining(t, now time.Time) time.Duration {
+	if d := t.Sub(now).Truncate(time.Second); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// elapsed returns the whole seconds since t, or zero if t is in the future.
+func elapsed(t, now time.Time) time.Duration {
+	if d := now.Sub(t).Truncate(time.Second); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,212 @@
+package leasequery
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is a LeaseStore backed by a list of leases. Addresses in the
+// 192.168.0.0/24 pool are known to the server even without a lease.
+type memStore struct {
+	leases []*Lease
+}
+
+var pool = net.IPNet{IP: net.IP{192, 168, 0, 0}, Mask: net.CIDRMask(24, 32)}
+
+func (s *memStore) LeaseByIP(ip net.IP) (*Lease, error) {
+	for _, l := range s.leases {
+		if l.IP.Equal(ip) {
+			return l, nil
+		}
+	}
+	if pool.Contains(ip) {
+		return &Lease{IP: ip, State: dhcpv4.LeaseStateAvailable}, nil
+	}
+	return nil, ErrUnknown
+}
+
+func (s *memStore) LeasesByHWAddr(hwtype iana.HWType, hwaddr net.HardwareAddr) ([]*Lease, error) {
+	return s.filter(func(l *Lease) bool {
+		return l.HWType == hwtype && bytes.Equal(l.HWAddr, hwaddr)
+	})
+}
+
+func (s *memStore) LeasesByClientID(id []byte) ([]*Lease, error) {
+	return s.filter(func(l *Lease) bool { return bytes.Equal(l.ClientID, id) })
+}
+
+func (s *memStore) LeasesByRelayID(id []byte) ([]*Lease, error) {
+	return s.filter(func(l *Lease) bool {
+		return l.RelayAgentInfo != nil && bytes.Equal(l.RelayAgentInfo.Get(dhcpv4.RelayIDSubOption), id)
+	})
+}
+
+func (s *memStore) LeasesByRemoteID(id []byte) ([]*Lease, error) {
+	return s.filter(func(l *Lease) bool {
+		return l.RelayAgentInfo != nil && bytes.Equal(l.RelayAgentInfo.Get(dhcpv4.AgentRemoteIDSubOption), id)
+	})
+}
+
+func (s *memStore) filter(match func(l *Lease) bool) ([]*Lease, error) {
+	var leases []*Lease
+	for _, l := range s.leases {
+		if match(l) {
+			leases = append(leases, l)
+		}
+	}
+	if leases == nil {
+		return nil, ErrUnknown
+	}
+	return leases, nil
+}
+
+var (
+	serverID = net.IP{10, 0, 0, 254}
+	mac      = net.HardwareAddr{0, 1, 2, 3, 4, 5}
+)
+
+func newTestStore() *memStore {
+	now := time.Now()
+	relayInfo := &dhcpv4.RelayOptions{Options: dhcpv4.OptionsFromList(
+		dhcpv4.OptGeneric(dhcpv4.AgentRemoteIDSubOption, []byte("cpe1")),
+		dhcpv4.OptGeneric(dhcpv4.RelayIDSubOption, []byte("relay1")),
+	)}
+	return &memStore{leases: []*Lease{
+		{
+			IP:              net.IP{192, 168, 0, 10},
+			HWType:          iana.HWTypeEthernet,
+			HWAddr:          mac,
+			ClientID:        []byte{1, 0, 1, 2, 3, 4, 5},
+			State:           dhcpv4.LeaseStateActive,
+			Expires:         now.Add(time.Hour),
+			LastTransaction: now.Add(-time.Minute),
+			StateSince:      now.Add(-2 * time.Hour),
+			RelayAgentInfo:  relayInfo,
+		},
+		{
+			IP:              net.IP{192, 168, 0, 11},
+			HWType:          iana.HWTypeEthernet,
+			HWAddr:          mac,
+			State:           dhcpv4.LeaseStateActive,
+			Expires:         now.Add(time.Hour),
+			LastTransaction: now.Add(-time.Second),
+			StateSince:      now.Add(-time.Hour),
+			RelayAgentInfo:  relayInfo,
+		},
+		{
+			IP:         net.IP{192, 168, 0, 12},
+			HWType:     iana.HWTypeEthernet,
+			HWAddr:     net.HardwareAddr{0, 1, 2, 3, 4, 6},
+			State:      dhcpv4.LeaseStateExpired,
+			StateSince: now.Add(-time.Minute),
+			Remote:     true,
+		},
+	}}
+}
+
+func TestReplyByIP(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+
+	q, _ := NewQueryByIP(net.IP{192, 168, 0, 10}, giaddr)
+	m, err := r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseActive, m.MessageType())
+	require.Equal(t, dhcpv4.OpcodeBootReply, m.OpCode)
+	require.Equal(t, q.TransactionID, m.TransactionID)
+	require.Equal(t, giaddr, m.GatewayIPAddr)
+	require.Equal(t, serverID, m.ServerIdentifier())
+	require.Equal(t, net.IP{192, 168, 0, 10}, m.ClientIPAddr)
+	require.Equal(t, mac, m.ClientHWAddr)
+	require.Equal(t, []byte{1, 0, 1, 2, 3, 4, 5}, m.GetOneOption(dhcpv4.OptionClientIdentifier))
+	require.InDelta(t, time.Hour, m.IPAddressLeaseTime(0), float64(time.Second))
+	require.Equal(t, time.Minute, m.ClientLastTransactionTime(0))
+	require.Equal(t, []byte("cpe1"), m.RelayAgentInfo().Get(dhcpv4.AgentRemoteIDSubOption))
+	// Bulk-only options.
+	require.Equal(t, dhcpv4.LeaseStateNone, m.LeaseState())
+
+	q, _ = NewQueryByIP(net.IP{192, 168, 0, 12}, giaddr)
+	m, err = r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseUnassigned, m.MessageType())
+	require.Equal(t, net.IP{192, 168, 0, 12}, m.ClientIPAddr)
+
+	q, _ = NewQueryByIP(net.IP{192, 168, 0, 200}, giaddr)
+	m, err = r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseUnassigned, m.MessageType())
+
+	q, _ = NewQueryByIP(net.IP{172, 16, 0, 1}, giaddr)
+	m, err = r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseUnknown, m.MessageType())
+	require.Equal(t, serverID, m.ServerIdentifier())
+}
+
+func TestReplyByClient(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+
+	q, _ := NewQueryByMAC(iana.HWTypeEthernet, mac, giaddr)
+	m, err := r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseActive, m.MessageType())
+	// The most recently used binding.
+	require.Equal(t, net.IP{192, 168, 0, 11}, m.ClientIPAddr)
+	require.Equal(t, []net.IP{{192, 168, 0, 10}, {192, 168, 0, 11}}, m.AssociatedIP())
+
+	q, _ = NewQueryByMAC(iana.HWTypeEthernet, net.HardwareAddr{0, 1, 2, 3, 4, 6}, giaddr)
+	m, err = r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseUnknown, m.MessageType())
+
+	q, _ = NewQueryByClientID(dhcpv4.ClientIdentifier{Type: 1, Identifier: []byte{0, 1, 2, 3, 4, 5}}, giaddr)
+	m, err = r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseActive, m.MessageType())
+	require.Equal(t, net.IP{192, 168, 0, 10}, m.ClientIPAddr)
+	require.Nil(t, m.AssociatedIP())
+
+	q, _ = NewQueryByClientID(dhcpv4.ClientIdentifier{Type: 1, Identifier: []byte{2, 3}}, giaddr)
+	m, err = r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseUnknown, m.MessageType())
+}
+
+func TestReplyMalformed(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+
+	q, _ := dhcpv4.New(dhcpv4.WithHWType(0), dhcpv4.WithMessageType(dhcpv4.MessageTypeLeaseQuery))
+	_, err := r.Reply(q)
+	require.Error(t, err)
+
+	q, _ = dhcpv4.NewDiscovery(mac)
+	_, err = r.Reply(q)
+	require.Error(t, err)
+}
+
+func TestResponderHandle(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	peer, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer peer.Close()
+
+	q, _ := NewQueryByIP(net.IP{192, 168, 0, 10}, giaddr)
+	r.Handle(conn, peer.LocalAddr(), q)
+
+	peer.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 1500)
+	n, _, err := peer.ReadFrom(buf)
+	require.NoError(t, err)
+	m, err := dhcpv4.FromBytes(buf[:n])
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeLeaseActive, m.MessageType())
+	require.Equal(t, q.TransactionID, m.TransactionID)
+}
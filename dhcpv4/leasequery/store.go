@@ -0,0 +1,70 @@
+package leasequery
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// ErrUnknown is returned by a LeaseStore when it has no knowledge of the
+// queried address or client. It maps to a DHCPLEASEUNKNOWN reply.
+var ErrUnknown = errors.New("no information about the queried binding")
+
+// Lease is a binding between an IP address and a client, as known by a DHCP
+// server.
+type Lease struct {
+	IP       net.IP
+	HWType   iana.HWType
+	HWAddr   net.HardwareAddr
+	ClientID []byte
+
+	// State is the state of the binding. Only LeaseStateActive bindings are
+	// reported as DHCPLEASEACTIVE.
+	State dhcpv4.LeaseState
+
+	// Expires is when an active lease runs out.
+	Expires time.Time
+
+	// LastTransaction is when the server last heard from the client.
+	LastTransaction time.Time
+
+	// StateSince is when the binding entered State. Bulk queries with a
+	// query-start-time or query-end-time are filtered on it.
+	StateSince time.Time
+
+	// RelayAgentInfo is the relay agent information option the binding was
+	// last established with, if any.
+	RelayAgentInfo *dhcpv4.RelayOptions
+
+	// Remote is set when the binding was learned from another server rather
+	// than from local activity.
+	Remote bool
+}
+
+// LeaseStore is the lease database a Responder answers queries from.
+//
+// LeaseByIP returns the binding for ip. For an address the server is
+// authoritative for but that has no binding, it should return a Lease with
+// a state other than LeaseStateActive. It returns ErrUnknown for addresses
+// the server knows nothing about.
+//
+// LeasesByHWAddr and LeasesByClientID return all bindings of a client, and
+// ErrUnknown or an empty list if there are none. Client identifiers are
+// compared in their serialized form, see dhcpv4.ClientIdentifier.ToBytes.
+type LeaseStore interface {
+	LeaseByIP(ip net.IP) (*Lease, error)
+	LeasesByHWAddr(hwtype iana.HWType, hwaddr net.HardwareAddr) ([]*Lease, error)
+	LeasesByClientID(id []byte) ([]*Lease, error)
+}
+
+// BulkLeaseStore is the lease database a BulkServer answers queries from.
+// On top of LeaseStore, it supports the relay-id and remote-id queries
+// described by RFC 6926, Section 7.2.
+type BulkLeaseStore interface {
+	LeaseStore
+	LeasesByRelayID(id []byte) ([]*Lease, error)
+	LeasesByRemoteID(id []byte) ([]*Lease, error)
+}
@@ -145,6 +145,17 @@ func WithDomainSearchList(searchList ...string) Modifier {
 	}))
 }
 
+// WithCaptivePortal adds or updates an OptCaptivePortal
+func WithCaptivePortal(uri string) Modifier {
+	return WithOption(OptCaptivePortal(uri))
+}
+
+// WithClientIdentifier sets the client identifier option, for instance to the
+// RFC 4361 node-specific identifier shared with the DHCPv6 client.
+func WithClientIdentifier(id ClientIdentifier) Modifier {
+	return WithOption(OptClientIdentifier(id))
+}
+
 func WithGeneric(code OptionCode, value []byte) Modifier {
 	return WithOption(OptGeneric(code, value))
 }
@@ -126,6 +126,13 @@ func TestWithNetmask(t *testing.T) {
 	require.Equal(t, net.IPv4Mask(255, 255, 255, 0), d.SubnetMask())
 }
 
+func TestWithCaptivePortal(t *testing.T) {
+	d, err := New(WithCaptivePortal("https://example.org/captive"))
+	require.NoError(t, err)
+
+	require.Equal(t, "https://example.org/captive", d.CaptivePortal())
+}
+
 func TestWithLeaseTime(t *testing.T) {
 	d, err := New(WithLeaseTime(uint32(3600)))
 	require.NoError(t, err)
@@ -0,0 +1,91 @@
+package dhcpv4
+
+import (
+	"fmt"
+
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// AuthProtocol is the protocol field of the authentication option described
+// by RFC 3118, Section 2.
+type AuthProtocol uint8
+
+// Authentication protocols defined by RFC 3118.
+const (
+	AuthProtocolConfigurationToken AuthProtocol = 0
+	AuthProtocolDelayed            AuthProtocol = 1
+)
+
+var authProtocolToString = map[AuthProtocol]string{
+	AuthProtocolConfigurationToken: "configuration token",
+	AuthProtocolDelayed:            "delayed authentication",
+}
+
+// String returns a human-readable protocol name.
+func (p AuthProtocol) String() string {
+	if s, ok := authProtocolToString[p]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(p))
+}
+
+// AuthAlgorithm is the algorithm field of the authentication option described
+// by RFC 3118, Section 2.
+type AuthAlgorithm uint8
+
+// AuthAlgorithmHMACMD5 is the only algorithm defined for delayed
+// authentication by RFC 3118, Section 5.
+const AuthAlgorithmHMACMD5 AuthAlgorithm = 1
+
+// RDM is the replay detection method of the authentication option described
+// by RFC 3118, Section 2.
+type RDM uint8
+
+// RDMMonotonicCounter is the only replay detection method defined by RFC 3118.
+// The replay detection field must increase with every message sent.
+const RDMMonotonicCounter RDM = 0
+
+// Authentication implements the authentication option described by RFC 3118,
+// Section 2.
+type Authentication struct {
+	Protocol        AuthProtocol
+	Algorithm       AuthAlgorithm
+	RDM             RDM
+	ReplayDetection uint64
+	AuthInfo        []byte
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (a Authentication) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	buf.Write8(uint8(a.Protocol))
+	buf.Write8(uint8(a.Algorithm))
+	buf.Write8(uint8(a.RDM))
+	buf.Write64(a.ReplayDetection)
+	buf.WriteBytes(a.AuthInfo)
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (a Authentication) String() string {
+	return fmt.Sprintf("%s, algorithm %d, RDM %d, replay detection %d, info %x",
+		a.Protocol, a.Algorithm, a.RDM, a.ReplayDetection, a.AuthInfo)
+}
+
+// FromBytes parses data into a per RFC 3118, Section 2.
+func (a *Authentication) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	a.Protocol = AuthProtocol(buf.Read8())
+	a.Algorithm = AuthAlgorithm(buf.Read8())
+	a.RDM = RDM(buf.Read8())
+	a.ReplayDetection = buf.Read64()
+	a.AuthInfo = buf.ReadAll()
+	return buf.FinError()
+}
+
+// OptAuthentication returns a new authentication option.
+//
+// The authentication option is described by RFC 3118, Section 2.
+func OptAuthentication(a Authentication) Option {
+	return Option{Code: OptionAuthentication, Value: a}
+}
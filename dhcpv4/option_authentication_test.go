@@ -0,0 +1,47 @@
+package dhcpv4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptAuthentication(t *testing.T) {
+	o := OptAuthentication(Authentication{
+		Protocol:        AuthProtocolDelayed,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             RDMMonotonicCounter,
+		ReplayDetection: 0x0102030405060708,
+		AuthInfo:        []byte{0xaa, 0xbb},
+	})
+	require.Equal(t, OptionAuthentication, o.Code, "Code")
+	require.Equal(t, []byte{1, 1, 0, 1, 2, 3, 4, 5, 6, 7, 8, 0xaa, 0xbb}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Authentication: delayed authentication, algorithm 1, RDM 0, replay detection 72623859790382856, info aabb", o.String(), "String")
+}
+
+func TestParseAuthentication(t *testing.T) {
+	var a Authentication
+	err := a.FromBytes([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 42, 't', 'o', 'k'})
+	require.NoError(t, err)
+	require.Equal(t, Authentication{
+		Protocol:        AuthProtocolConfigurationToken,
+		ReplayDetection: 42,
+		AuthInfo:        []byte("tok"),
+	}, a)
+
+	// Too short.
+	err = a.FromBytes([]byte{1, 1, 0, 0})
+	require.Error(t, err, "short byte stream")
+
+	require.Equal(t, "unknown (7)", AuthProtocol(7).String())
+}
+
+func TestGetAuthentication(t *testing.T) {
+	a := Authentication{Protocol: AuthProtocolDelayed, ReplayDetection: 1, AuthInfo: []byte{}}
+	m, _ := New(WithOption(OptAuthentication(a)))
+	require.Equal(t, &a, m.Authentication())
+
+	// Empty.
+	m, _ = New()
+	require.Nil(t, m.Authentication())
+}
@@ -0,0 +1,128 @@
+package dhcpv4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// ClientIdentifierTypeNodeSpecific is the type of the node-specific client
+// identifiers described by RFC 4361, Section 6.1.
+const ClientIdentifierTypeNodeSpecific uint8 = 255
+
+// ClientIdentifier implements the client identifier option described by RFC
+// 2132, Section 9.14, and its node-specific form described by RFC 4361,
+// Section 6.1.
+type ClientIdentifier struct {
+	// Type is a hardware type, or ClientIdentifierTypeNodeSpecific.
+	Type uint8
+
+	// Identifier is the identifier of any type but
+	// ClientIdentifierTypeNodeSpecific, usually a hardware address.
+	Identifier []byte
+
+	// IAID and DUID are the identity association identifier and DHCP unique
+	// identifier of a ClientIdentifierTypeNodeSpecific identifier. The DUID
+	// is the one the client uses for DHCPv6.
+	IAID uint32
+	DUID *dhcpv6.Duid
+}
+
+// NewNodeSpecificClientIdentifier returns the client identifier described by
+// RFC 4361, Section 6.1, for the given IAID and DUID.
+func NewNodeSpecificClientIdentifier(iaid uint32, duid dhcpv6.Duid) ClientIdentifier {
+	return ClientIdentifier{
+		Type: ClientIdentifierTypeNodeSpecific,
+		IAID: iaid,
+		DUID: &duid,
+	}
+}
+
+// ClientIdentifierForInterface returns a node-specific client identifier for
+// the given network interface.
+//
+// The DUID and IAID come from dhcpv6.InterfaceIdentity, so that the
+// identifier is stable and is the one dhcpv6.NewSolicitForInterface uses for
+// the same interface. Only Ethernet interfaces are supported.
+func ClientIdentifierForInterface(ifname string) (*ClientIdentifier, error) {
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, err
+	}
+	duid, iaid, err := dhcpv6.InterfaceIdentity(iface.HardwareAddr)
+	if err != nil {
+		return nil, fmt.Errorf("interface %s: %v", ifname, err)
+	}
+	id := NewNodeSpecificClientIdentifier(binary.BigEndian.Uint32(iaid[:]), duid)
+	return &id, nil
+}
+
+// Equal reports whether c and o identify the same client.
+func (c ClientIdentifier) Equal(o ClientIdentifier) bool {
+	return bytes.Equal(c.ToBytes(), o.ToBytes())
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (c ClientIdentifier) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	buf.Write8(c.Type)
+	if c.Type == ClientIdentifierTypeNodeSpecific {
+		buf.Write32(c.IAID)
+		if c.DUID != nil {
+			buf.WriteBytes(c.DUID.ToBytes())
+		}
+	} else {
+		buf.WriteBytes(c.Identifier)
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (c ClientIdentifier) String() string {
+	switch {
+	case c.Type == ClientIdentifierTypeNodeSpecific && c.DUID != nil:
+		return fmt.Sprintf("IAID %d, %s", c.IAID, c.DUID)
+	case c.Type == uint8(iana.HWTypeEthernet) && len(c.Identifier) == 6:
+		return fmt.Sprintf("%s, %s", iana.HWTypeEthernet, net.HardwareAddr(c.Identifier))
+	default:
+		return fmt.Sprintf("type %d, %x", c.Type, c.Identifier)
+	}
+}
+
+// FromBytes parses data into c per RFC 2132, Section 9.14, and RFC 4361,
+// Section 6.1.
+func (c *ClientIdentifier) FromBytes(data []byte) error {
+	if len(data) < 2 {
+		return errors.New("client identifier must be at least 2 bytes long")
+	}
+	*c = ClientIdentifier{Type: data[0]}
+	if c.Type != ClientIdentifierTypeNodeSpecific {
+		c.Identifier = append([]byte(nil), data[1:]...)
+		return nil
+	}
+	buf := uio.NewBigEndianBuffer(data[1:])
+	c.IAID = buf.Read32()
+	if err := buf.Error(); err != nil {
+		return err
+	}
+	duid, err := dhcpv6.DuidFromBytes(buf.ReadAll())
+	if err != nil {
+		return err
+	}
+	c.DUID = duid
+	return nil
+}
+
+// OptClientIdentifier returns a new client identifier option.
+//
+// The client identifier option is described by RFC 2132, Section 9.14, and RFC
+// 4361, Section 6.1.
+func OptClientIdentifier(id ClientIdentifier) Option {
+	return Option{Code: OptionClientIdentifier, Value: id}
+}
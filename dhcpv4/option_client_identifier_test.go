@@ -0,0 +1,99 @@
+package dhcpv4
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+var sampleDUID = dhcpv6.Duid{
+	Type:          dhcpv6.DUID_LL,
+	HwType:        iana.HWTypeEthernet,
+	LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+}
+
+func TestOptClientIdentifier(t *testing.T) {
+	o := OptClientIdentifier(ClientIdentifier{Type: 1, Identifier: []byte{0, 1, 2, 3, 4, 5}})
+	require.Equal(t, OptionClientIdentifier, o.Code, "Code")
+	require.Equal(t, []byte{1, 0, 1, 2, 3, 4, 5}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Client identifier: Ethernet, 00:01:02:03:04:05", o.String(), "String")
+
+	o = OptClientIdentifier(ClientIdentifier{Identifier: []byte("host")})
+	require.Equal(t, []byte{0, 'h', 'o', 's', 't'}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Client identifier: type 0, 686f7374", o.String(), "String")
+}
+
+func TestOptClientIdentifierNodeSpecific(t *testing.T) {
+	o := OptClientIdentifier(NewNodeSpecificClientIdentifier(0x01020304, sampleDUID))
+	want := []byte{
+		255,        // node-specific
+		1, 2, 3, 4, // IAID
+		0, 3, 0, 1, // DUID-LL, Ethernet
+		0, 1, 2, 3, 4, 5,
+	}
+	require.Equal(t, want, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Client identifier: IAID 16909060, DUID{type=DUID-LL hwtype=Ethernet hwaddr=00:01:02:03:04:05}", o.String(), "String")
+
+	var id ClientIdentifier
+	require.NoError(t, id.FromBytes(want))
+	require.Equal(t, ClientIdentifierTypeNodeSpecific, id.Type)
+	require.Equal(t, uint32(0x01020304), id.IAID)
+	require.True(t, sampleDUID.Equal(*id.DUID))
+	require.True(t, id.Equal(NewNodeSpecificClientIdentifier(0x01020304, sampleDUID)))
+}
+
+func TestParseClientIdentifier(t *testing.T) {
+	var id ClientIdentifier
+	require.NoError(t, id.FromBytes([]byte{1, 0, 1, 2, 3, 4, 5}))
+	require.Equal(t, ClientIdentifier{Type: 1, Identifier: []byte{0, 1, 2, 3, 4, 5}}, id)
+
+	// Too short.
+	require.Error(t, id.FromBytes([]byte{1}))
+	// Truncated IAID.
+	require.Error(t, id.FromBytes([]byte{255, 1, 2}))
+	// Missing DUID.
+	require.Error(t, id.FromBytes([]byte{255, 1, 2, 3, 4}))
+}
+
+func TestGetClientIdentifier(t *testing.T) {
+	hwaddr := net.HardwareAddr{0xa, 0xb, 0xc, 0xd, 0xe, 0xf}
+	m, _ := New(WithHwAddr(hwaddr))
+	require.Nil(t, m.ClientIdentifier())
+	require.Equal(t, ClientIdentifier{Type: 1, Identifier: []byte(hwaddr)}, m.ClientIdentity())
+
+	id := NewNodeSpecificClientIdentifier(1, sampleDUID)
+	m, _ = New(WithHwAddr(hwaddr), WithClientIdentifier(id))
+	require.True(t, id.Equal(*m.ClientIdentifier()))
+	require.True(t, id.Equal(m.ClientIdentity()))
+}
+
+func TestClientIdentifierForInterface(t *testing.T) {
+	_, err := ClientIdentifierForInterface("not-an-interface")
+	require.Error(t, err)
+
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+	for _, iface := range ifaces {
+		if len(iface.HardwareAddr) != 6 {
+			continue
+		}
+		id, err := ClientIdentifierForInterface(iface.Name)
+		require.NoError(t, err)
+		require.Equal(t, ClientIdentifierTypeNodeSpecific, id.Type)
+		require.Equal(t, iface.HardwareAddr, id.DUID.LinkLayerAddr)
+
+		// The DHCPv6 client of the interface has the same identity.
+		sol, err := dhcpv6.NewSolicitForInterface(iface.Name)
+		require.NoError(t, err)
+		cid := sol.GetOneOption(dhcpv6.OptionClientID).(*dhcpv6.OptClientId)
+		require.True(t, id.DUID.Equal(cid.Cid))
+		iaNa := sol.GetOneOption(dhcpv6.OptionIANA).(*dhcpv6.OptIANA)
+		require.Equal(t, id.IAID, binary.BigEndian.Uint32(iaNa.IaId[:]))
+		return
+	}
+	t.Skip("no interface with a hardware address")
+}
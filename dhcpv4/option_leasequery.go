@@ -0,0 +1,200 @@
+package dhcpv4
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// LeaseState is the state of an IP address binding as carried by the
+// dhcp-state option described by RFC 6926, Section 6.2.8.
+type LeaseState uint8
+
+// Lease states defined by RFC 6926, Section 6.2.8.
+const (
+	// LeaseStateNone is not a real state, it is used by certain functions to
+	// signal that no dhcp-state option is present.
+	LeaseStateNone          LeaseState = 0
+	LeaseStateAvailable     LeaseState = 1
+	LeaseStateActive        LeaseState = 2
+	LeaseStateExpired       LeaseState = 3
+	LeaseStateReleased      LeaseState = 4
+	LeaseStateAbandoned     LeaseState = 5
+	LeaseStateReset         LeaseState = 6
+	LeaseStateRemote        LeaseState = 7
+	LeaseStateTransitioning LeaseState = 8
+)
+
+var leaseStateToString = map[LeaseState]string{
+	LeaseStateAvailable:     "AVAILABLE",
+	LeaseStateActive:        "ACTIVE",
+	LeaseStateExpired:       "EXPIRED",
+	LeaseStateReleased:      "RELEASED",
+	LeaseStateAbandoned:     "ABANDONED",
+	LeaseStateReset:         "RESET",
+	LeaseStateRemote:        "REMOTE",
+	LeaseStateTransitioning: "TRANSITIONING",
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (s LeaseState) ToBytes() []byte {
+	return []byte{byte(s)}
+}
+
+// String returns a human-readable lease state name.
+func (s LeaseState) String() string {
+	if str, ok := leaseStateToString[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(s))
+}
+
+// FromBytes reads a lease state from data as described by RFC 6926, Section
+// 6.2.8.
+func (s *LeaseState) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*s = LeaseState(buf.Read8())
+	return buf.FinError()
+}
+
+// DataSource implements the data-source option described by RFC 6926,
+// Section 6.2.9.
+type DataSource uint8
+
+// DataSourceRemote is set when the information in a leasequery reply was
+// learned from another server rather than from local activity.
+const DataSourceRemote DataSource = 0x01
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (s DataSource) ToBytes() []byte {
+	return []byte{byte(s)}
+}
+
+// String returns a human-readable string for this option.
+func (s DataSource) String() string {
+	if s&DataSourceRemote != 0 {
+		return "remote"
+	}
+	return "local"
+}
+
+// FromBytes reads a data source from data as described by RFC 6926, Section
+// 6.2.9.
+func (s *DataSource) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*s = DataSource(buf.Read8())
+	return buf.FinError()
+}
+
+// Timestamp implements options carrying an absolute time as a 32-bit count of
+// seconds since the Unix epoch, as described by RFC 6926, Sections 6.2.3,
+// 6.2.5 and 6.2.6.
+type Timestamp time.Time
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (t Timestamp) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	buf.Write32(uint32(time.Time(t).Unix()))
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (t Timestamp) String() string {
+	return time.Time(t).UTC().Format(time.RFC3339)
+}
+
+// FromBytes parses a timestamp from data.
+func (t *Timestamp) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*t = Timestamp(time.Unix(int64(buf.Read32()), 0))
+	return buf.FinError()
+}
+
+// GetTimestamp parses a timestamp from code in o. It returns the zero time if
+// the option is absent or malformed.
+func GetTimestamp(code OptionCode, o Options) time.Time {
+	v := o.Get(code)
+	if v == nil {
+		return time.Time{}
+	}
+	var t Timestamp
+	if err := t.FromBytes(v); err != nil {
+		return time.Time{}
+	}
+	return time.Time(t)
+}
+
+// getDuration parses a duration from code in o, returning def if the option
+// is absent or malformed.
+func getDuration(code OptionCode, o Options, def time.Duration) time.Duration {
+	v := o.Get(code)
+	if v == nil {
+		return def
+	}
+	var dur Duration
+	if err := dur.FromBytes(v); err != nil {
+		return def
+	}
+	return time.Duration(dur)
+}
+
+// OptClientLastTransactionTime returns a new client-last-transaction-time
+// option.
+//
+// The option is described by RFC 4388, Section 6.1.
+func OptClientLastTransactionTime(d time.Duration) Option {
+	return Option{Code: OptionClientLastTransactionTime, Value: Duration(d)}
+}
+
+// OptAssociatedIP returns a new associated-ip option.
+//
+// The option is described by RFC 4388, Section 6.1.
+func OptAssociatedIP(ips ...net.IP) Option {
+	return Option{Code: OptionAssociatedIP, Value: IPs(ips)}
+}
+
+// OptBaseTime returns a new base-time option.
+//
+// The option is described by RFC 6926, Section 6.2.3.
+func OptBaseTime(t time.Time) Option {
+	return Option{Code: OptionBaseTime, Value: Timestamp(t)}
+}
+
+// OptStartTimeOfState returns a new start-time-of-state option, which holds
+// the number of seconds between the last state change of a binding and the
+// base-time.
+//
+// The option is described by RFC 6926, Section 6.2.4.
+func OptStartTimeOfState(d time.Duration) Option {
+	return Option{Code: OptionStartTimeOfState, Value: Duration(d)}
+}
+
+// OptQueryStartTime returns a new query-start-time option.
+//
+// The option is described by RFC 6926, Section 6.2.5.
+func OptQueryStartTime(t time.Time) Option {
+	return Option{Code: OptionQueryStartTime, Value: Timestamp(t)}
+}
+
+// OptQueryEndTime returns a new query-end-time option.
+//
+// The option is described by RFC 6926, Section 6.2.6.
+func OptQueryEndTime(t time.Time) Option {
+	return Option{Code: OptionQueryEndTime, Value: Timestamp(t)}
+}
+
+// OptLeaseState returns a new dhcp-state option.
+//
+// The option is described by RFC 6926, Section 6.2.8.
+func OptLeaseState(s LeaseState) Option {
+	return Option{Code: OptionDHCPState, Value: s}
+}
+
+// OptDataSource returns a new data-source option.
+//
+// The option is described by RFC 6926, Section 6.2.9.
+func OptDataSource(s DataSource) Option {
+	return Option{Code: OptionDataSource, Value: s}
+}
@@ -0,0 +1,118 @@
+package dhcpv4
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptClientLastTransactionTime(t *testing.T) {
+	o := OptClientLastTransactionTime(90 * time.Second)
+	require.Equal(t, OptionClientLastTransactionTime, o.Code, "Code")
+	require.Equal(t, []byte{0, 0, 0, 90}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Client Last Transaction Time: 1m30s", o.String(), "String")
+
+	m, _ := New(WithOption(o))
+	require.Equal(t, 90*time.Second, m.ClientLastTransactionTime(0))
+
+	// Too short.
+	m, _ = New(WithGeneric(OptionClientLastTransactionTime, []byte{0, 90}))
+	require.Equal(t, time.Duration(0), m.ClientLastTransactionTime(0))
+
+	// Empty.
+	m, _ = New()
+	require.Equal(t, time.Duration(10), m.ClientLastTransactionTime(10))
+}
+
+func TestOptAssociatedIP(t *testing.T) {
+	o := OptAssociatedIP(net.IP{192, 168, 0, 1}, net.IP{192, 168, 0, 2})
+	require.Equal(t, OptionAssociatedIP, o.Code, "Code")
+	require.Equal(t, []byte{192, 168, 0, 1, 192, 168, 0, 2}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Associated IP: 192.168.0.1, 192.168.0.2", o.String(), "String")
+
+	m, _ := New(WithOption(o))
+	require.Equal(t, []net.IP{{192, 168, 0, 1}, {192, 168, 0, 2}}, m.AssociatedIP())
+
+	m, _ = New()
+	require.Nil(t, m.AssociatedIP())
+}
+
+func TestOptTimestamps(t *testing.T) {
+	ts := time.Unix(1500000000, 0)
+	for _, tt := range []struct {
+		opt  Option
+		code OptionCode
+		get  func(m *DHCPv4) time.Time
+		name string
+	}{
+		{OptBaseTime(ts), OptionBaseTime, (*DHCPv4).BaseTime, "Base Time"},
+		{OptQueryStartTime(ts), OptionQueryStartTime, (*DHCPv4).QueryStartTime, "Query Start Time"},
+		{OptQueryEndTime(ts), OptionQueryEndTime, (*DHCPv4).QueryEndTime, "Query End Time"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.code, tt.opt.Code, "Code")
+			require.Equal(t, []byte{0x59, 0x68, 0x2f, 0x00}, tt.opt.Value.ToBytes(), "ToBytes")
+			require.Equal(t, tt.name+": 2017-07-14T02:40:00Z", tt.opt.String(), "String")
+
+			m, _ := New(WithOption(tt.opt))
+			require.True(t, ts.Equal(tt.get(m)))
+
+			// Too long.
+			m, _ = New(WithGeneric(tt.code, []byte{1, 2, 3, 4, 5}))
+			require.True(t, tt.get(m).IsZero())
+
+			// Empty.
+			m, _ = New()
+			require.True(t, tt.get(m).IsZero())
+		})
+	}
+}
+
+func TestOptStartTimeOfState(t *testing.T) {
+	o := OptStartTimeOfState(time.Hour)
+	require.Equal(t, OptionStartTimeOfState, o.Code, "Code")
+	require.Equal(t, []byte{0, 0, 0x0e, 0x10}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Start Time of State: 1h0m0s", o.String(), "String")
+
+	m, _ := New(WithOption(o))
+	require.Equal(t, time.Hour, m.StartTimeOfState(0))
+}
+
+func TestOptLeaseState(t *testing.T) {
+	o := OptLeaseState(LeaseStateActive)
+	require.Equal(t, OptionDHCPState, o.Code, "Code")
+	require.Equal(t, []byte{2}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "DHCP State: ACTIVE", o.String(), "String")
+
+	m, _ := New(WithOption(o))
+	require.Equal(t, LeaseStateActive, m.LeaseState())
+
+	// Too long.
+	m, _ = New(WithGeneric(OptionDHCPState, []byte{1, 2}))
+	require.Equal(t, LeaseStateNone, m.LeaseState())
+
+	// Empty.
+	m, _ = New()
+	require.Equal(t, LeaseStateNone, m.LeaseState())
+
+	require.Equal(t, "unknown (42)", LeaseState(42).String())
+}
+
+func TestOptDataSource(t *testing.T) {
+	o := OptDataSource(DataSourceRemote)
+	require.Equal(t, OptionDataSource, o.Code, "Code")
+	require.Equal(t, []byte{1}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Data Source: remote", o.String(), "String")
+
+	m, _ := New(WithOption(o))
+	require.Equal(t, DataSourceRemote, m.DataSource())
+
+	m, _ = New(WithOption(OptDataSource(0)))
+	require.Equal(t, "local", m.DataSource().String())
+
+	// Empty.
+	m, _ = New()
+	require.Equal(t, DataSource(0), m.DataSource())
+}
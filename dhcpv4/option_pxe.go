@@ -0,0 +1,96 @@
+package dhcpv4
+
+import (
+	"fmt"
+
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// NetworkInterfaceTypeUNDI is the only network interface type defined by
+// RFC 4578, Section 2.2: the Universal Network Device Interface of PXE.
+const NetworkInterfaceTypeUNDI uint8 = 1
+
+// NetworkInterfaceID implements the client network interface identifier
+// option described by RFC 4578, Section 2.2.
+type NetworkInterfaceID struct {
+	Type         uint8
+	Major, Minor uint8
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (n NetworkInterfaceID) ToBytes() []byte {
+	return []byte{n.Type, n.Major, n.Minor}
+}
+
+// String returns a human-readable string for this option.
+func (n NetworkInterfaceID) String() string {
+	if n.Type == NetworkInterfaceTypeUNDI {
+		return fmt.Sprintf("UNDI %d.%d", n.Major, n.Minor)
+	}
+	return fmt.Sprintf("type %d, %d.%d", n.Type, n.Major, n.Minor)
+}
+
+// FromBytes parses data into n per RFC 4578, Section 2.2.
+func (n *NetworkInterfaceID) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	n.Type = buf.Read8()
+	n.Major = buf.Read8()
+	n.Minor = buf.Read8()
+	return buf.FinError()
+}
+
+// OptClientNetworkInterfaceID returns a new client network interface
+// identifier option for a UNDI of the given version.
+//
+// The option is described by RFC 4578, Section 2.2.
+func OptClientNetworkInterfaceID(major, minor uint8) Option {
+	return Option{
+		Code:  OptionClientNetworkInterfaceIdentifier,
+		Value: NetworkInterfaceID{Type: NetworkInterfaceTypeUNDI, Major: major, Minor: minor},
+	}
+}
+
+// MachineIDTypeUUID is the only machine identifier type defined by RFC 4578,
+// Section 2.3: a 16-byte UUID.
+const MachineIDTypeUUID uint8 = 0
+
+// MachineID implements the client machine identifier option described by RFC
+// 4578, Section 2.3.
+type MachineID struct {
+	Type uint8
+	UUID [16]byte
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (m MachineID) ToBytes() []byte {
+	return append([]byte{m.Type}, m.UUID[:]...)
+}
+
+// String returns a human-readable string for this option.
+func (m MachineID) String() string {
+	u := m.UUID
+	s := fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+	if m.Type != MachineIDTypeUUID {
+		return fmt.Sprintf("type %d, %s", m.Type, s)
+	}
+	return s
+}
+
+// FromBytes parses data into m per RFC 4578, Section 2.3.
+func (m *MachineID) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	m.Type = buf.Read8()
+	buf.ReadBytes(m.UUID[:])
+	return buf.FinError()
+}
+
+// OptClientMachineID returns a new client machine identifier option for the
+// given UUID.
+//
+// The option is described by RFC 4578, Section 2.3.
+func OptClientMachineID(uuid [16]byte) Option {
+	return Option{
+		Code:  OptionClientMachineIdentifier,
+		Value: MachineID{Type: MachineIDTypeUUID, UUID: uuid},
+	}
+}
@@ -0,0 +1,44 @@
+package dhcpv4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptClientNetworkInterfaceID(t *testing.T) {
+	o := OptClientNetworkInterfaceID(2, 1)
+	require.Equal(t, OptionClientNetworkInterfaceIdentifier, o.Code, "Code")
+	require.Equal(t, []byte{1, 2, 1}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Client Network Interface Identifier: UNDI 2.1", o.String(), "String")
+
+	m, _ := New(WithOption(o))
+	require.Equal(t, &NetworkInterfaceID{Type: NetworkInterfaceTypeUNDI, Major: 2, Minor: 1}, m.ClientNetworkInterfaceID())
+
+	var n NetworkInterfaceID
+	require.Error(t, n.FromBytes([]byte{1, 2}), "short byte stream")
+	require.NoError(t, n.FromBytes([]byte{3, 2, 0}))
+	require.Equal(t, "type 3, 2.0", n.String())
+
+	// Empty.
+	m, _ = New()
+	require.Nil(t, m.ClientNetworkInterfaceID())
+}
+
+func TestOptClientMachineID(t *testing.T) {
+	uuid := [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf}
+	o := OptClientMachineID(uuid)
+	require.Equal(t, OptionClientMachineIdentifier, o.Code, "Code")
+	require.Equal(t, append([]byte{0}, uuid[:]...), o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Client Machine Identifier: 00010203-0405-0607-0809-0a0b0c0d0e0f", o.String(), "String")
+
+	m, _ := New(WithOption(o))
+	require.Equal(t, &MachineID{Type: MachineIDTypeUUID, UUID: uuid}, m.ClientMachineID())
+
+	var id MachineID
+	require.Error(t, id.FromBytes(uuid[:]), "short byte stream")
+
+	// Empty.
+	m, _ = New()
+	require.Nil(t, m.ClientMachineID())
+}
@@ -0,0 +1,38 @@
+package dhcpv4
+
+import (
+	"time"
+)
+
+// PXELinuxMagic is the value of the PXELINUX magic option described by RFC
+// 5071, Section 4.
+var PXELinuxMagic = []byte{0xf1, 0x00, 0x74, 0x7e}
+
+// OptPXELinuxMagic returns a new PXELINUX magic option, which tells PXELINUX
+// clients that the server knows the options of RFC 5071.
+//
+// The option is described by RFC 5071, Section 4.
+func OptPXELinuxMagic() Option {
+	return OptGeneric(OptionPXELinuxMagicString, PXELinuxMagic)
+}
+
+// OptPXELinuxConfigFile returns a new PXELINUX configuration file option.
+//
+// The option is described by RFC 5071, Section 5.
+func OptPXELinuxConfigFile(name string) Option {
+	return Option{Code: OptionPXELinuxConfigFile, Value: String(name)}
+}
+
+// OptPXELinuxPathPrefix returns a new PXELINUX path prefix option.
+//
+// The option is described by RFC 5071, Section 6.
+func OptPXELinuxPathPrefix(prefix string) Option {
+	return Option{Code: OptionPXELinuxPathPrefix, Value: String(prefix)}
+}
+
+// OptPXELinuxRebootTime returns a new PXELINUX reboot time option.
+//
+// The option is described by RFC 5071, Section 7.
+func OptPXELinuxRebootTime(d time.Duration) Option {
+	return Option{Code: OptionPXELinuxRebootTime, Value: Duration(d)}
+}
@@ -0,0 +1,30 @@
+package dhcpv4
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptPXELinux(t *testing.T) {
+	m, _ := New(
+		WithOption(OptPXELinuxMagic()),
+		WithOption(OptPXELinuxConfigFile("pxelinux.cfg/default")),
+		WithOption(OptPXELinuxPathPrefix("/tftpboot/")),
+		WithOption(OptPXELinuxRebootTime(5*time.Minute)),
+	)
+	require.True(t, m.IsPXELinux())
+	require.Equal(t, []byte{0xf1, 0x00, 0x74, 0x7e}, m.Options.Get(OptionPXELinuxMagicString))
+	require.Equal(t, "pxelinux.cfg/default", m.PXELinuxConfigFile())
+	require.Equal(t, "/tftpboot/", m.PXELinuxPathPrefix())
+	require.Equal(t, 5*time.Minute, m.PXELinuxRebootTime(0))
+	require.Equal(t, []byte{0, 0, 1, 0x2c}, m.Options.Get(OptionPXELinuxRebootTime))
+	require.Contains(t, m.Summary(), "PXELinux Reboot Time: 5m0s")
+
+	// Empty.
+	m, _ = New()
+	require.False(t, m.IsPXELinux())
+	require.Equal(t, "", m.PXELinuxConfigFile())
+	require.Equal(t, time.Minute, m.PXELinuxRebootTime(time.Minute))
+}
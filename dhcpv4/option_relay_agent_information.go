@@ -4,6 +4,21 @@ import (
 	"fmt"
 )
 
+// Relay Agent Information sub-option codes.
+const (
+	AgentCircuitIDSubOption            GenericOptionCode = 1  // RFC 3046
+	AgentRemoteIDSubOption             GenericOptionCode = 2  // RFC 3046
+	DOCSISDeviceClassSubOption         GenericOptionCode = 4  // RFC 3256
+	LinkSelectionSubOption             GenericOptionCode = 5  // RFC 3527
+	SubscriberIDSubOption              GenericOptionCode = 6  // RFC 3993
+	RADIUSAttributesSubOption          GenericOptionCode = 7  // RFC 4014
+	AuthenticationSubOption            GenericOptionCode = 8  // RFC 4030
+	VendorSpecificInformationSubOption GenericOptionCode = 9  // RFC 4243
+	RelayAgentFlagsSubOption           GenericOptionCode = 10 // RFC 5010
+	ServerIdentifierOverrideSubOption  GenericOptionCode = 11 // RFC 5107
+	RelayIDSubOption                   GenericOptionCode = 12 // RFC 6925
+)
+
 // RelayOptions is like Options, but stringifies using the Relay Agent Specific
 // option space.
 type RelayOptions struct {
@@ -0,0 +1,366 @@
+package dhcpv4
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// The constructors, getters and decoders of the RFC 2132 options that have no
+// dedicated file are generated from the table in gen_rfc2132.go.
+//go:generate go run gen_rfc2132.go
+
+// Bool implements encoding and decoding functions for the flag options of
+// RFC 2132, such as IP Forwarding (Section 4.1).
+type Bool bool
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o Bool) ToBytes() []byte {
+	if o {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// String returns a human-readable string for this option.
+func (o Bool) String() string {
+	return fmt.Sprintf("%t", bool(o))
+}
+
+// FromBytes decodes data into o. Values other than 0 and 1 are invalid.
+func (o *Bool) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	v := buf.Read8()
+	if err := buf.FinError(); err != nil {
+		return err
+	}
+	if v > 1 {
+		return fmt.Errorf("invalid boolean value %d", v)
+	}
+	*o = v == 1
+	return nil
+}
+
+// GetBool parses a boolean from code in o.
+func GetBool(code OptionCode, o Options) (bool, error) {
+	v := o.Get(code)
+	if v == nil {
+		return false, fmt.Errorf("option not present")
+	}
+	var b Bool
+	if err := b.FromBytes(v); err != nil {
+		return false, err
+	}
+	return bool(b), nil
+}
+
+// Uint8 implements encoding and decoding functions for a uint8 as used in
+// RFC 2132, Sections 4.5 and 7.1.
+type Uint8 uint8
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o Uint8) ToBytes() []byte {
+	return []byte{uint8(o)}
+}
+
+// String returns a human-readable string for this option.
+func (o Uint8) String() string {
+	return fmt.Sprintf("%d", uint8(o))
+}
+
+// FromBytes decodes data into o.
+func (o *Uint8) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*o = Uint8(buf.Read8())
+	return buf.FinError()
+}
+
+// GetUint8 parses a uint8 from code in o.
+func GetUint8(code OptionCode, o Options) (uint8, error) {
+	v := o.Get(code)
+	if v == nil {
+		return 0, fmt.Errorf("option not present")
+	}
+	var u Uint8
+	if err := u.FromBytes(v); err != nil {
+		return 0, err
+	}
+	return uint8(u), nil
+}
+
+// Uint16s implements encoding and decoding functions for a list of uint16, as
+// used by the Path MTU Plateau Table option of RFC 2132, Section 4.7.
+type Uint16s []uint16
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o Uint16s) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, v := range o {
+		buf.Write16(v)
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (o Uint16s) String() string {
+	s := make([]string, 0, len(o))
+	for _, v := range o {
+		s = append(s, fmt.Sprintf("%d", v))
+	}
+	return strings.Join(s, ", ")
+}
+
+// FromBytes decodes data into o. The list must not be empty.
+func (o *Uint16s) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	if buf.Len() == 0 {
+		return fmt.Errorf("uint16 list must contain at least one value")
+	}
+	*o = make(Uint16s, 0, buf.Len()/2)
+	for buf.Has(2) {
+		*o = append(*o, buf.Read16())
+	}
+	return buf.FinError()
+}
+
+// GetUint16s parses a list of uint16 from code in o.
+func GetUint16s(code OptionCode, o Options) []uint16 {
+	v := o.Get(code)
+	if v == nil {
+		return nil
+	}
+	var u Uint16s
+	if err := u.FromBytes(v); err != nil {
+		return nil
+	}
+	return []uint16(u)
+}
+
+// TimeOffset implements the Time Offset option described by RFC 2132,
+// Section 3.4: a signed number of seconds from UTC.
+type TimeOffset time.Duration
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o TimeOffset) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	buf.Write32(uint32(int32(time.Duration(o) / time.Second)))
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (o TimeOffset) String() string {
+	return time.Duration(o).String()
+}
+
+// FromBytes decodes data into o.
+func (o *TimeOffset) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*o = TimeOffset(time.Duration(int32(buf.Read32())) * time.Second)
+	return buf.FinError()
+}
+
+func getTimeOffset(code OptionCode, o Options, def time.Duration) time.Duration {
+	v := o.Get(code)
+	if v == nil {
+		return def
+	}
+	var off TimeOffset
+	if err := off.FromBytes(v); err != nil {
+		return def
+	}
+	return time.Duration(off)
+}
+
+// StaticRoute is a route of the Static Route option.
+type StaticRoute struct {
+	Destination net.IP
+	Router      net.IP
+}
+
+// String returns a human-readable string for the route.
+func (r StaticRoute) String() string {
+	return fmt.Sprintf("%s via %s", r.Destination, r.Router)
+}
+
+// StaticRoutes implements the Static Route option described by RFC 2132,
+// Section 5.8. The destinations are classful: the option carries no mask.
+type StaticRoutes []StaticRoute
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o StaticRoutes) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, r := range o {
+		buf.WriteBytes(r.Destination.To4())
+		buf.WriteBytes(r.Router.To4())
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (o StaticRoutes) String() string {
+	s := make([]string, 0, len(o))
+	for _, r := range o {
+		s = append(s, r.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// FromBytes decodes data into o. The list must not be empty.
+func (o *StaticRoutes) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	if buf.Len() == 0 {
+		return fmt.Errorf("static routes must contain at least one route")
+	}
+	*o = make(StaticRoutes, 0, buf.Len()/(2*net.IPv4len))
+	for buf.Has(2 * net.IPv4len) {
+		*o = append(*o, StaticRoute{
+			Destination: net.IP(buf.CopyN(net.IPv4len)),
+			Router:      net.IP(buf.CopyN(net.IPv4len)),
+		})
+	}
+	return buf.FinError()
+}
+
+// GetStaticRoutes parses a list of static routes from code in o.
+func GetStaticRoutes(code OptionCode, o Options) []StaticRoute {
+	v := o.Get(code)
+	if v == nil {
+		return nil
+	}
+	var r StaticRoutes
+	if err := r.FromBytes(v); err != nil {
+		return nil
+	}
+	return []StaticRoute(r)
+}
+
+// PolicyFilters implements the Policy Filter option described by RFC 2132,
+// Section 4.3, a list of address and mask pairs.
+type PolicyFilters []net.IPNet
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o PolicyFilters) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, f := range o {
+		buf.WriteBytes(f.IP.To4())
+		buf.WriteBytes(f.Mask)
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (o PolicyFilters) String() string {
+	s := make([]string, 0, len(o))
+	for _, f := range o {
+		s = append(s, f.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// FromBytes decodes data into o. The list must not be empty.
+func (o *PolicyFilters) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	if buf.Len() == 0 {
+		return fmt.Errorf("policy filters must contain at least one filter")
+	}
+	*o = make(PolicyFilters, 0, buf.Len()/(2*net.IPv4len))
+	for buf.Has(2 * net.IPv4len) {
+		*o = append(*o, net.IPNet{
+			IP:   net.IP(buf.CopyN(net.IPv4len)),
+			Mask: net.IPMask(buf.CopyN(net.IPv4len)),
+		})
+	}
+	return buf.FinError()
+}
+
+// GetPolicyFilters parses a list of policy filters from code in o.
+func GetPolicyFilters(code OptionCode, o Options) []net.IPNet {
+	v := o.Get(code)
+	if v == nil {
+		return nil
+	}
+	var f PolicyFilters
+	if err := f.FromBytes(v); err != nil {
+		return nil
+	}
+	return []net.IPNet(f)
+}
+
+// NetBIOSNodeType is the NetBIOS over TCP/IP node type described by RFC 2132,
+// Section 8.7.
+type NetBIOSNodeType uint8
+
+// NetBIOS node types
+const (
+	NetBIOSNodeTypeB NetBIOSNodeType = 0x1
+	NetBIOSNodeTypeP NetBIOSNodeType = 0x2
+	NetBIOSNodeTypeM NetBIOSNodeType = 0x4
+	NetBIOSNodeTypeH NetBIOSNodeType = 0x8
+)
+
+var netBIOSNodeTypeToString = map[NetBIOSNodeType]string{
+	NetBIOSNodeTypeB: "B-node",
+	NetBIOSNodeTypeP: "P-node",
+	NetBIOSNodeTypeM: "M-node",
+	NetBIOSNodeTypeH: "H-node",
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o NetBIOSNodeType) ToBytes() []byte {
+	return []byte{uint8(o)}
+}
+
+// String returns a human-readable string for this option.
+func (o NetBIOSNodeType) String() string {
+	if s, ok := netBIOSNodeTypeToString[o]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(o))
+}
+
+// FromBytes decodes data into o.
+func (o *NetBIOSNodeType) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*o = NetBIOSNodeType(buf.Read8())
+	return buf.FinError()
+}
+
+// Overload is the value of the Option Overload option described by RFC 2132,
+// Section 9.3: which of the file and sname fields carry options.
+type Overload uint8
+
+// Option overload values
+const (
+	OverloadFile         Overload = 1
+	OverloadSName        Overload = 2
+	OverloadFileAndSName Overload = 3
+)
+
+var overloadToString = map[Overload]string{
+	OverloadFile:         "file",
+	OverloadSName:        "sname",
+	OverloadFileAndSName: "file and sname",
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (o Overload) ToBytes() []byte {
+	return []byte{uint8(o)}
+}
+
+// String returns a human-readable string for this option.
+func (o Overload) String() string {
+	if s, ok := overloadToString[o]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(o))
+}
+
+// FromBytes decodes data into o.
+func (o *Overload) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*o = Overload(buf.Read8())
+	return buf.FinError()
+}
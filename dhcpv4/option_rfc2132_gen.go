@@ -0,0 +1,861 @@
+// Code generated by gen_rfc2132.go; DO NOT EDIT.
+
+package dhcpv4
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// rfc2132Decoders returns the decoder of the options generated from the
+// table, used to humanize them.
+var rfc2132Decoders = map[OptionCode]func() OptionDecoder{
+	OptionTimeOffset:                                 func() OptionDecoder { return new(TimeOffset) },
+	OptionTimeServer:                                 func() OptionDecoder { return new(IPs) },
+	OptionNameServer:                                 func() OptionDecoder { return new(IPs) },
+	OptionLogServer:                                  func() OptionDecoder { return new(IPs) },
+	OptionQuoteServer:                                func() OptionDecoder { return new(IPs) },
+	OptionLPRServer:                                  func() OptionDecoder { return new(IPs) },
+	OptionImpressServer:                              func() OptionDecoder { return new(IPs) },
+	OptionResourceLocationServer:                     func() OptionDecoder { return new(IPs) },
+	OptionBootFileSize:                               func() OptionDecoder { return new(Uint16) },
+	OptionMeritDumpFile:                              func() OptionDecoder { return new(String) },
+	OptionSwapServer:                                 func() OptionDecoder { return new(IP) },
+	OptionExtensionsPath:                             func() OptionDecoder { return new(String) },
+	OptionIPForwarding:                               func() OptionDecoder { return new(Bool) },
+	OptionNonLocalSourceRouting:                      func() OptionDecoder { return new(Bool) },
+	OptionPolicyFilter:                               func() OptionDecoder { return new(PolicyFilters) },
+	OptionMaximumDatagramAssemblySize:                func() OptionDecoder { return new(Uint16) },
+	OptionDefaultIPTTL:                               func() OptionDecoder { return new(Uint8) },
+	OptionPathMTUAgingTimeout:                        func() OptionDecoder { return new(Duration) },
+	OptionPathMTUPlateauTable:                        func() OptionDecoder { return new(Uint16s) },
+	OptionInterfaceMTU:                               func() OptionDecoder { return new(Uint16) },
+	OptionAllSubnetsAreLocal:                         func() OptionDecoder { return new(Bool) },
+	OptionPerformMaskDiscovery:                       func() OptionDecoder { return new(Bool) },
+	OptionMaskSupplier:                               func() OptionDecoder { return new(Bool) },
+	OptionPerformRouterDiscovery:                     func() OptionDecoder { return new(Bool) },
+	OptionRouterSolicitationAddress:                  func() OptionDecoder { return new(IP) },
+	OptionStaticRoutingTable:                         func() OptionDecoder { return new(StaticRoutes) },
+	OptionTrailerEncapsulation:                       func() OptionDecoder { return new(Bool) },
+	OptionArpCacheTimeout:                            func() OptionDecoder { return new(Duration) },
+	OptionEthernetEncapsulation:                      func() OptionDecoder { return new(Bool) },
+	OptionDefaulTCPTTL:                               func() OptionDecoder { return new(Uint8) },
+	OptionTCPKeepaliveInterval:                       func() OptionDecoder { return new(Duration) },
+	OptionTCPKeepaliveGarbage:                        func() OptionDecoder { return new(Bool) },
+	OptionNetworkInformationServiceDomain:            func() OptionDecoder { return new(String) },
+	OptionNetworkInformationServers:                  func() OptionDecoder { return new(IPs) },
+	OptionNetBIOSOverTCPIPNameServer:                 func() OptionDecoder { return new(IPs) },
+	OptionNetBIOSOverTCPIPDatagramDistributionServer: func() OptionDecoder { return new(IPs) },
+	OptionNetBIOSOverTCPIPNodeType:                   func() OptionDecoder { return new(NetBIOSNodeType) },
+	OptionNetBIOSOverTCPIPScope:                      func() OptionDecoder { return new(String) },
+	OptionXWindowSystemFontServer:                    func() OptionDecoder { return new(IPs) },
+	OptionXWindowSystemDisplayManger:                 func() OptionDecoder { return new(IPs) },
+	OptionNetworkInformationServicePlusDomain:        func() OptionDecoder { return new(String) },
+	OptionNetworkInformationServicePlusServers:       func() OptionDecoder { return new(IPs) },
+	OptionMobileIPHomeAgent:                          func() OptionDecoder { return new(IPs) },
+	OptionSimpleMailTransportProtocolServer:          func() OptionDecoder { return new(IPs) },
+	OptionPostOfficeProtocolServer:                   func() OptionDecoder { return new(IPs) },
+	OptionNetworkNewsTransportProtocolServer:         func() OptionDecoder { return new(IPs) },
+	OptionDefaultWorldWideWebServer:                  func() OptionDecoder { return new(IPs) },
+	OptionDefaultFingerServer:                        func() OptionDecoder { return new(IPs) },
+	OptionDefaultInternetRelayChatServer:             func() OptionDecoder { return new(IPs) },
+	OptionStreetTalkServer:                           func() OptionDecoder { return new(IPs) },
+	OptionStreetTalkDirectoryAssistanceServer:        func() OptionDecoder { return new(IPs) },
+	OptionOptionOverload:                             func() OptionDecoder { return new(Overload) },
+	OptionMessage:                                    func() OptionDecoder { return new(String) },
+	OptionRenewTimeValue:                             func() OptionDecoder { return new(Duration) },
+	OptionRebindingTimeValue:                         func() OptionDecoder { return new(Duration) },
+}
+
+// OptTimeOffset returns a new DHCPv4 Time Offset option.
+//
+// The Time Offset option is described by RFC 2132, Section 3.4.
+func OptTimeOffset(offset time.Duration) Option {
+	return Option{Code: OptionTimeOffset, Value: TimeOffset(offset)}
+}
+
+// TimeOffset parses the DHCPv4 Time Offset option if present.
+// It returns def if the option is not present.
+//
+// The Time Offset option is described by RFC 2132, Section 3.4.
+func (d *DHCPv4) TimeOffset(def time.Duration) time.Duration {
+	return getTimeOffset(OptionTimeOffset, d.Options, def)
+}
+
+// OptTimeServers returns a new DHCPv4 Time Server option.
+//
+// The Time Server option is described by RFC 2132, Section 3.6.
+func OptTimeServers(ips ...net.IP) Option {
+	return Option{Code: OptionTimeServer, Value: IPs(ips)}
+}
+
+// TimeServers parses the DHCPv4 Time Server option if present.
+//
+// The Time Server option is described by RFC 2132, Section 3.6.
+func (d *DHCPv4) TimeServers() []net.IP {
+	return GetIPs(OptionTimeServer, d.Options)
+}
+
+// OptNameServers returns a new DHCPv4 Name Server option.
+//
+// The Name Server option is described by RFC 2132, Section 3.7.
+func OptNameServers(ips ...net.IP) Option {
+	return Option{Code: OptionNameServer, Value: IPs(ips)}
+}
+
+// NameServers parses the DHCPv4 Name Server option if present.
+//
+// The Name Server option is described by RFC 2132, Section 3.7.
+func (d *DHCPv4) NameServers() []net.IP {
+	return GetIPs(OptionNameServer, d.Options)
+}
+
+// OptLogServers returns a new DHCPv4 Log Server option.
+//
+// The Log Server option is described by RFC 2132, Section 3.9.
+func OptLogServers(ips ...net.IP) Option {
+	return Option{Code: OptionLogServer, Value: IPs(ips)}
+}
+
+// LogServers parses the DHCPv4 Log Server option if present.
+//
+// The Log Server option is described by RFC 2132, Section 3.9.
+func (d *DHCPv4) LogServers() []net.IP {
+	return GetIPs(OptionLogServer, d.Options)
+}
+
+// OptQuoteServers returns a new DHCPv4 Cookie Server option.
+//
+// The Cookie Server option is described by RFC 2132, Section 3.10.
+func OptQuoteServers(ips ...net.IP) Option {
+	return Option{Code: OptionQuoteServer, Value: IPs(ips)}
+}
+
+// QuoteServers parses the DHCPv4 Cookie Server option if present.
+//
+// The Cookie Server option is described by RFC 2132, Section 3.10.
+func (d *DHCPv4) QuoteServers() []net.IP {
+	return GetIPs(OptionQuoteServer, d.Options)
+}
+
+// OptLPRServers returns a new DHCPv4 LPR Server option.
+//
+// The LPR Server option is described by RFC 2132, Section 3.11.
+func OptLPRServers(ips ...net.IP) Option {
+	return Option{Code: OptionLPRServer, Value: IPs(ips)}
+}
+
+// LPRServers parses the DHCPv4 LPR Server option if present.
+//
+// The LPR Server option is described by RFC 2132, Section 3.11.
+func (d *DHCPv4) LPRServers() []net.IP {
+	return GetIPs(OptionLPRServer, d.Options)
+}
+
+// OptImpressServers returns a new DHCPv4 Impress Server option.
+//
+// The Impress Server option is described by RFC 2132, Section 3.12.
+func OptImpressServers(ips ...net.IP) Option {
+	return Option{Code: OptionImpressServer, Value: IPs(ips)}
+}
+
+// ImpressServers parses the DHCPv4 Impress Server option if present.
+//
+// The Impress Server option is described by RFC 2132, Section 3.12.
+func (d *DHCPv4) ImpressServers() []net.IP {
+	return GetIPs(OptionImpressServer, d.Options)
+}
+
+// OptResourceLocationServers returns a new DHCPv4 Resource Location Server option.
+//
+// The Resource Location Server option is described by RFC 2132, Section 3.13.
+func OptResourceLocationServers(ips ...net.IP) Option {
+	return Option{Code: OptionResourceLocationServer, Value: IPs(ips)}
+}
+
+// ResourceLocationServers parses the DHCPv4 Resource Location Server option if present.
+//
+// The Resource Location Server option is described by RFC 2132, Section 3.13.
+func (d *DHCPv4) ResourceLocationServers() []net.IP {
+	return GetIPs(OptionResourceLocationServer, d.Options)
+}
+
+// OptBootFileSize returns a new DHCPv4 Boot File Size option.
+//
+// The Boot File Size option is described by RFC 2132, Section 3.15.
+func OptBootFileSize(v uint16) Option {
+	return Option{Code: OptionBootFileSize, Value: Uint16(v)}
+}
+
+// BootFileSize parses the DHCPv4 Boot File Size option if present.
+//
+// The Boot File Size option is described by RFC 2132, Section 3.15.
+func (d *DHCPv4) BootFileSize() (uint16, error) {
+	return GetUint16(OptionBootFileSize, d.Options)
+}
+
+// OptMeritDumpFile returns a new DHCPv4 Merit Dump File option.
+//
+// The Merit Dump File option is described by RFC 2132, Section 3.16.
+func OptMeritDumpFile(s string) Option {
+	return Option{Code: OptionMeritDumpFile, Value: String(s)}
+}
+
+// MeritDumpFile parses the DHCPv4 Merit Dump File option if present.
+//
+// The Merit Dump File option is described by RFC 2132, Section 3.16.
+func (d *DHCPv4) MeritDumpFile() string {
+	return GetString(OptionMeritDumpFile, d.Options)
+}
+
+// OptSwapServer returns a new DHCPv4 Swap Server option.
+//
+// The Swap Server option is described by RFC 2132, Section 3.18.
+func OptSwapServer(ip net.IP) Option {
+	return Option{Code: OptionSwapServer, Value: IP(ip)}
+}
+
+// SwapServer parses the DHCPv4 Swap Server option if present.
+//
+// The Swap Server option is described by RFC 2132, Section 3.18.
+func (d *DHCPv4) SwapServer() net.IP {
+	return GetIP(OptionSwapServer, d.Options)
+}
+
+// OptExtensionsPath returns a new DHCPv4 Extensions Path option.
+//
+// The Extensions Path option is described by RFC 2132, Section 3.20.
+func OptExtensionsPath(s string) Option {
+	return Option{Code: OptionExtensionsPath, Value: String(s)}
+}
+
+// ExtensionsPath parses the DHCPv4 Extensions Path option if present.
+//
+// The Extensions Path option is described by RFC 2132, Section 3.20.
+func (d *DHCPv4) ExtensionsPath() string {
+	return GetString(OptionExtensionsPath, d.Options)
+}
+
+// OptIPForwarding returns a new DHCPv4 IP Forwarding Enable/Disable option.
+//
+// The IP Forwarding Enable/Disable option is described by RFC 2132, Section 4.1.
+func OptIPForwarding(b bool) Option {
+	return Option{Code: OptionIPForwarding, Value: Bool(b)}
+}
+
+// IPForwarding parses the DHCPv4 IP Forwarding Enable/Disable option if present.
+//
+// The IP Forwarding Enable/Disable option is described by RFC 2132, Section 4.1.
+func (d *DHCPv4) IPForwarding() (bool, error) {
+	return GetBool(OptionIPForwarding, d.Options)
+}
+
+// OptNonLocalSourceRouting returns a new DHCPv4 Non-Local Source Routing Enable/Disable option.
+//
+// The Non-Local Source Routing Enable/Disable option is described by RFC 2132, Section 4.2.
+func OptNonLocalSourceRouting(b bool) Option {
+	return Option{Code: OptionNonLocalSourceRouting, Value: Bool(b)}
+}
+
+// NonLocalSourceRouting parses the DHCPv4 Non-Local Source Routing Enable/Disable option if present.
+//
+// The Non-Local Source Routing Enable/Disable option is described by RFC 2132, Section 4.2.
+func (d *DHCPv4) NonLocalSourceRouting() (bool, error) {
+	return GetBool(OptionNonLocalSourceRouting, d.Options)
+}
+
+// OptPolicyFilter returns a new DHCPv4 Policy Filter option.
+//
+// The Policy Filter option is described by RFC 2132, Section 4.3.
+func OptPolicyFilter(filters ...net.IPNet) Option {
+	return Option{Code: OptionPolicyFilter, Value: PolicyFilters(filters)}
+}
+
+// PolicyFilter parses the DHCPv4 Policy Filter option if present.
+//
+// The Policy Filter option is described by RFC 2132, Section 4.3.
+func (d *DHCPv4) PolicyFilter() []net.IPNet {
+	return GetPolicyFilters(OptionPolicyFilter, d.Options)
+}
+
+// OptMaxDatagramReassemblySize returns a new DHCPv4 Maximum Datagram Reassembly Size option.
+//
+// The Maximum Datagram Reassembly Size option is described by RFC 2132, Section 4.4.
+func OptMaxDatagramReassemblySize(v uint16) Option {
+	return Option{Code: OptionMaximumDatagramAssemblySize, Value: Uint16(v)}
+}
+
+// MaxDatagramReassemblySize parses the DHCPv4 Maximum Datagram Reassembly Size option if present.
+//
+// The Maximum Datagram Reassembly Size option is described by RFC 2132, Section 4.4.
+func (d *DHCPv4) MaxDatagramReassemblySize() (uint16, error) {
+	return GetUint16(OptionMaximumDatagramAssemblySize, d.Options)
+}
+
+// OptDefaultIPTTL returns a new DHCPv4 Default IP Time-to-live option.
+//
+// The Default IP Time-to-live option is described by RFC 2132, Section 4.5.
+func OptDefaultIPTTL(v uint8) Option {
+	return Option{Code: OptionDefaultIPTTL, Value: Uint8(v)}
+}
+
+// DefaultIPTTL parses the DHCPv4 Default IP Time-to-live option if present.
+//
+// The Default IP Time-to-live option is described by RFC 2132, Section 4.5.
+func (d *DHCPv4) DefaultIPTTL() (uint8, error) {
+	return GetUint8(OptionDefaultIPTTL, d.Options)
+}
+
+// OptPathMTUAgingTimeout returns a new DHCPv4 Path MTU Aging Timeout option.
+//
+// The Path MTU Aging Timeout option is described by RFC 2132, Section 4.6.
+func OptPathMTUAgingTimeout(dur time.Duration) Option {
+	return Option{Code: OptionPathMTUAgingTimeout, Value: Duration(dur)}
+}
+
+// PathMTUAgingTimeout parses the DHCPv4 Path MTU Aging Timeout option if present.
+// It returns def if the option is not present.
+//
+// The Path MTU Aging Timeout option is described by RFC 2132, Section 4.6.
+func (d *DHCPv4) PathMTUAgingTimeout(def time.Duration) time.Duration {
+	return getDuration(OptionPathMTUAgingTimeout, d.Options, def)
+}
+
+// OptPathMTUPlateauTable returns a new DHCPv4 Path MTU Plateau Table option.
+//
+// The Path MTU Plateau Table option is described by RFC 2132, Section 4.7.
+func OptPathMTUPlateauTable(v ...uint16) Option {
+	return Option{Code: OptionPathMTUPlateauTable, Value: Uint16s(v)}
+}
+
+// PathMTUPlateauTable parses the DHCPv4 Path MTU Plateau Table option if present.
+//
+// The Path MTU Plateau Table option is described by RFC 2132, Section 4.7.
+func (d *DHCPv4) PathMTUPlateauTable() []uint16 {
+	return GetUint16s(OptionPathMTUPlateauTable, d.Options)
+}
+
+// OptInterfaceMTU returns a new DHCPv4 Interface MTU option.
+//
+// The Interface MTU option is described by RFC 2132, Section 5.1.
+func OptInterfaceMTU(v uint16) Option {
+	return Option{Code: OptionInterfaceMTU, Value: Uint16(v)}
+}
+
+// InterfaceMTU parses the DHCPv4 Interface MTU option if present.
+//
+// The Interface MTU option is described by RFC 2132, Section 5.1.
+func (d *DHCPv4) InterfaceMTU() (uint16, error) {
+	return GetUint16(OptionInterfaceMTU, d.Options)
+}
+
+// OptAllSubnetsAreLocal returns a new DHCPv4 All Subnets are Local option.
+//
+// The All Subnets are Local option is described by RFC 2132, Section 5.2.
+func OptAllSubnetsAreLocal(b bool) Option {
+	return Option{Code: OptionAllSubnetsAreLocal, Value: Bool(b)}
+}
+
+// AllSubnetsAreLocal parses the DHCPv4 All Subnets are Local option if present.
+//
+// The All Subnets are Local option is described by RFC 2132, Section 5.2.
+func (d *DHCPv4) AllSubnetsAreLocal() (bool, error) {
+	return GetBool(OptionAllSubnetsAreLocal, d.Options)
+}
+
+// OptPerformMaskDiscovery returns a new DHCPv4 Perform Mask Discovery option.
+//
+// The Perform Mask Discovery option is described by RFC 2132, Section 5.4.
+func OptPerformMaskDiscovery(b bool) Option {
+	return Option{Code: OptionPerformMaskDiscovery, Value: Bool(b)}
+}
+
+// PerformMaskDiscovery parses the DHCPv4 Perform Mask Discovery option if present.
+//
+// The Perform Mask Discovery option is described by RFC 2132, Section 5.4.
+func (d *DHCPv4) PerformMaskDiscovery() (bool, error) {
+	return GetBool(OptionPerformMaskDiscovery, d.Options)
+}
+
+// OptMaskSupplier returns a new DHCPv4 Mask Supplier option.
+//
+// The Mask Supplier option is described by RFC 2132, Section 5.5.
+func OptMaskSupplier(b bool) Option {
+	return Option{Code: OptionMaskSupplier, Value: Bool(b)}
+}
+
+// MaskSupplier parses the DHCPv4 Mask Supplier option if present.
+//
+// The Mask Supplier option is described by RFC 2132, Section 5.5.
+func (d *DHCPv4) MaskSupplier() (bool, error) {
+	return GetBool(OptionMaskSupplier, d.Options)
+}
+
+// OptPerformRouterDiscovery returns a new DHCPv4 Perform Router Discovery option.
+//
+// The Perform Router Discovery option is described by RFC 2132, Section 5.6.
+func OptPerformRouterDiscovery(b bool) Option {
+	return Option{Code: OptionPerformRouterDiscovery, Value: Bool(b)}
+}
+
+// PerformRouterDiscovery parses the DHCPv4 Perform Router Discovery option if present.
+//
+// The Perform Router Discovery option is described by RFC 2132, Section 5.6.
+func (d *DHCPv4) PerformRouterDiscovery() (bool, error) {
+	return GetBool(OptionPerformRouterDiscovery, d.Options)
+}
+
+// OptRouterSolicitationAddress returns a new DHCPv4 Router Solicitation Address option.
+//
+// The Router Solicitation Address option is described by RFC 2132, Section 5.7.
+func OptRouterSolicitationAddress(ip net.IP) Option {
+	return Option{Code: OptionRouterSolicitationAddress, Value: IP(ip)}
+}
+
+// RouterSolicitationAddress parses the DHCPv4 Router Solicitation Address option if present.
+//
+// The Router Solicitation Address option is described by RFC 2132, Section 5.7.
+func (d *DHCPv4) RouterSolicitationAddress() net.IP {
+	return GetIP(OptionRouterSolicitationAddress, d.Options)
+}
+
+// OptStaticRoutes returns a new DHCPv4 Static Route option.
+//
+// The Static Route option is described by RFC 2132, Section 5.8.
+func OptStaticRoutes(routes ...StaticRoute) Option {
+	return Option{Code: OptionStaticRoutingTable, Value: StaticRoutes(routes)}
+}
+
+// StaticRoutes parses the DHCPv4 Static Route option if present.
+//
+// The Static Route option is described by RFC 2132, Section 5.8.
+func (d *DHCPv4) StaticRoutes() []StaticRoute {
+	return GetStaticRoutes(OptionStaticRoutingTable, d.Options)
+}
+
+// OptTrailerEncapsulation returns a new DHCPv4 Trailer Encapsulation option.
+//
+// The Trailer Encapsulation option is described by RFC 2132, Section 6.1.
+func OptTrailerEncapsulation(b bool) Option {
+	return Option{Code: OptionTrailerEncapsulation, Value: Bool(b)}
+}
+
+// TrailerEncapsulation parses the DHCPv4 Trailer Encapsulation option if present.
+//
+// The Trailer Encapsulation option is described by RFC 2132, Section 6.1.
+func (d *DHCPv4) TrailerEncapsulation() (bool, error) {
+	return GetBool(OptionTrailerEncapsulation, d.Options)
+}
+
+// OptARPCacheTimeout returns a new DHCPv4 ARP Cache Timeout option.
+//
+// The ARP Cache Timeout option is described by RFC 2132, Section 6.2.
+func OptARPCacheTimeout(dur time.Duration) Option {
+	return Option{Code: OptionArpCacheTimeout, Value: Duration(dur)}
+}
+
+// ARPCacheTimeout parses the DHCPv4 ARP Cache Timeout option if present.
+// It returns def if the option is not present.
+//
+// The ARP Cache Timeout option is described by RFC 2132, Section 6.2.
+func (d *DHCPv4) ARPCacheTimeout(def time.Duration) time.Duration {
+	return getDuration(OptionArpCacheTimeout, d.Options, def)
+}
+
+// OptEthernetEncapsulation returns a new DHCPv4 Ethernet Encapsulation option.
+//
+// The Ethernet Encapsulation option is described by RFC 2132, Section 6.3.
+func OptEthernetEncapsulation(b bool) Option {
+	return Option{Code: OptionEthernetEncapsulation, Value: Bool(b)}
+}
+
+// EthernetEncapsulation parses the DHCPv4 Ethernet Encapsulation option if present.
+//
+// The Ethernet Encapsulation option is described by RFC 2132, Section 6.3.
+func (d *DHCPv4) EthernetEncapsulation() (bool, error) {
+	return GetBool(OptionEthernetEncapsulation, d.Options)
+}
+
+// OptDefaultTCPTTL returns a new DHCPv4 TCP Default TTL option.
+//
+// The TCP Default TTL option is described by RFC 2132, Section 7.1.
+func OptDefaultTCPTTL(v uint8) Option {
+	return Option{Code: OptionDefaulTCPTTL, Value: Uint8(v)}
+}
+
+// DefaultTCPTTL parses the DHCPv4 TCP Default TTL option if present.
+//
+// The TCP Default TTL option is described by RFC 2132, Section 7.1.
+func (d *DHCPv4) DefaultTCPTTL() (uint8, error) {
+	return GetUint8(OptionDefaulTCPTTL, d.Options)
+}
+
+// OptTCPKeepaliveInterval returns a new DHCPv4 TCP Keepalive Interval option.
+//
+// The TCP Keepalive Interval option is described by RFC 2132, Section 7.2.
+func OptTCPKeepaliveInterval(dur time.Duration) Option {
+	return Option{Code: OptionTCPKeepaliveInterval, Value: Duration(dur)}
+}
+
+// TCPKeepaliveInterval parses the DHCPv4 TCP Keepalive Interval option if present.
+// It returns def if the option is not present.
+//
+// The TCP Keepalive Interval option is described by RFC 2132, Section 7.2.
+func (d *DHCPv4) TCPKeepaliveInterval(def time.Duration) time.Duration {
+	return getDuration(OptionTCPKeepaliveInterval, d.Options, def)
+}
+
+// OptTCPKeepaliveGarbage returns a new DHCPv4 TCP Keepalive Garbage option.
+//
+// The TCP Keepalive Garbage option is described by RFC 2132, Section 7.3.
+func OptTCPKeepaliveGarbage(b bool) Option {
+	return Option{Code: OptionTCPKeepaliveGarbage, Value: Bool(b)}
+}
+
+// TCPKeepaliveGarbage parses the DHCPv4 TCP Keepalive Garbage option if present.
+//
+// The TCP Keepalive Garbage option is described by RFC 2132, Section 7.3.
+func (d *DHCPv4) TCPKeepaliveGarbage() (bool, error) {
+	return GetBool(OptionTCPKeepaliveGarbage, d.Options)
+}
+
+// OptNISDomain returns a new DHCPv4 Network Information Service Domain option.
+//
+// The Network Information Service Domain option is described by RFC 2132, Section 8.1.
+func OptNISDomain(s string) Option {
+	return Option{Code: OptionNetworkInformationServiceDomain, Value: String(s)}
+}
+
+// NISDomain parses the DHCPv4 Network Information Service Domain option if present.
+//
+// The Network Information Service Domain option is described by RFC 2132, Section 8.1.
+func (d *DHCPv4) NISDomain() string {
+	return GetString(OptionNetworkInformationServiceDomain, d.Options)
+}
+
+// OptNISServers returns a new DHCPv4 Network Information Servers option.
+//
+// The Network Information Servers option is described by RFC 2132, Section 8.2.
+func OptNISServers(ips ...net.IP) Option {
+	return Option{Code: OptionNetworkInformationServers, Value: IPs(ips)}
+}
+
+// NISServers parses the DHCPv4 Network Information Servers option if present.
+//
+// The Network Information Servers option is described by RFC 2132, Section 8.2.
+func (d *DHCPv4) NISServers() []net.IP {
+	return GetIPs(OptionNetworkInformationServers, d.Options)
+}
+
+// OptNetBIOSNameServers returns a new DHCPv4 NetBIOS over TCP/IP Name Server option.
+//
+// The NetBIOS over TCP/IP Name Server option is described by RFC 2132, Section 8.5.
+func OptNetBIOSNameServers(ips ...net.IP) Option {
+	return Option{Code: OptionNetBIOSOverTCPIPNameServer, Value: IPs(ips)}
+}
+
+// NetBIOSNameServers parses the DHCPv4 NetBIOS over TCP/IP Name Server option if present.
+//
+// The NetBIOS over TCP/IP Name Server option is described by RFC 2132, Section 8.5.
+func (d *DHCPv4) NetBIOSNameServers() []net.IP {
+	return GetIPs(OptionNetBIOSOverTCPIPNameServer, d.Options)
+}
+
+// OptNetBIOSDatagramDistributionServers returns a new DHCPv4 NetBIOS over TCP/IP Datagram Distribution Server option.
+//
+// The NetBIOS over TCP/IP Datagram Distribution Server option is described by RFC 2132, Section 8.6.
+func OptNetBIOSDatagramDistributionServers(ips ...net.IP) Option {
+	return Option{Code: OptionNetBIOSOverTCPIPDatagramDistributionServer, Value: IPs(ips)}
+}
+
+// NetBIOSDatagramDistributionServers parses the DHCPv4 NetBIOS over TCP/IP Datagram Distribution Server option if present.
+//
+// The NetBIOS over TCP/IP Datagram Distribution Server option is described by RFC 2132, Section 8.6.
+func (d *DHCPv4) NetBIOSDatagramDistributionServers() []net.IP {
+	return GetIPs(OptionNetBIOSOverTCPIPDatagramDistributionServer, d.Options)
+}
+
+// OptNetBIOSNodeType returns a new DHCPv4 NetBIOS over TCP/IP Node Type option.
+//
+// The NetBIOS over TCP/IP Node Type option is described by RFC 2132, Section 8.7.
+func OptNetBIOSNodeType(t NetBIOSNodeType) Option {
+	return Option{Code: OptionNetBIOSOverTCPIPNodeType, Value: t}
+}
+
+// NetBIOSNodeType parses the DHCPv4 NetBIOS over TCP/IP Node Type option if present.
+//
+// The NetBIOS over TCP/IP Node Type option is described by RFC 2132, Section 8.7.
+func (d *DHCPv4) NetBIOSNodeType() (NetBIOSNodeType, error) {
+	v := d.Options.Get(OptionNetBIOSOverTCPIPNodeType)
+	if v == nil {
+		return 0, fmt.Errorf("option not present")
+	}
+	var o NetBIOSNodeType
+	if err := o.FromBytes(v); err != nil {
+		return 0, err
+	}
+	return o, nil
+}
+
+// OptNetBIOSScope returns a new DHCPv4 NetBIOS over TCP/IP Scope option.
+//
+// The NetBIOS over TCP/IP Scope option is described by RFC 2132, Section 8.8.
+func OptNetBIOSScope(s string) Option {
+	return Option{Code: OptionNetBIOSOverTCPIPScope, Value: String(s)}
+}
+
+// NetBIOSScope parses the DHCPv4 NetBIOS over TCP/IP Scope option if present.
+//
+// The NetBIOS over TCP/IP Scope option is described by RFC 2132, Section 8.8.
+func (d *DHCPv4) NetBIOSScope() string {
+	return GetString(OptionNetBIOSOverTCPIPScope, d.Options)
+}
+
+// OptXWindowFontServers returns a new DHCPv4 X Window System Font Server option.
+//
+// The X Window System Font Server option is described by RFC 2132, Section 8.9.
+func OptXWindowFontServers(ips ...net.IP) Option {
+	return Option{Code: OptionXWindowSystemFontServer, Value: IPs(ips)}
+}
+
+// XWindowFontServers parses the DHCPv4 X Window System Font Server option if present.
+//
+// The X Window System Font Server option is described by RFC 2132, Section 8.9.
+func (d *DHCPv4) XWindowFontServers() []net.IP {
+	return GetIPs(OptionXWindowSystemFontServer, d.Options)
+}
+
+// OptXWindowDisplayManagers returns a new DHCPv4 X Window System Display Manager option.
+//
+// The X Window System Display Manager option is described by RFC 2132, Section 8.10.
+func OptXWindowDisplayManagers(ips ...net.IP) Option {
+	return Option{Code: OptionXWindowSystemDisplayManger, Value: IPs(ips)}
+}
+
+// XWindowDisplayManagers parses the DHCPv4 X Window System Display Manager option if present.
+//
+// The X Window System Display Manager option is described by RFC 2132, Section 8.10.
+func (d *DHCPv4) XWindowDisplayManagers() []net.IP {
+	return GetIPs(OptionXWindowSystemDisplayManger, d.Options)
+}
+
+// OptNISPlusDomain returns a new DHCPv4 Network Information Service+ Domain option.
+//
+// The Network Information Service+ Domain option is described by RFC 2132, Section 8.11.
+func OptNISPlusDomain(s string) Option {
+	return Option{Code: OptionNetworkInformationServicePlusDomain, Value: String(s)}
+}
+
+// NISPlusDomain parses the DHCPv4 Network Information Service+ Domain option if present.
+//
+// The Network Information Service+ Domain option is described by RFC 2132, Section 8.11.
+func (d *DHCPv4) NISPlusDomain() string {
+	return GetString(OptionNetworkInformationServicePlusDomain, d.Options)
+}
+
+// OptNISPlusServers returns a new DHCPv4 Network Information Service+ Servers option.
+//
+// The Network Information Service+ Servers option is described by RFC 2132, Section 8.12.
+func OptNISPlusServers(ips ...net.IP) Option {
+	return Option{Code: OptionNetworkInformationServicePlusServers, Value: IPs(ips)}
+}
+
+// NISPlusServers parses the DHCPv4 Network Information Service+ Servers option if present.
+//
+// The Network Information Service+ Servers option is described by RFC 2132, Section 8.12.
+func (d *DHCPv4) NISPlusServers() []net.IP {
+	return GetIPs(OptionNetworkInformationServicePlusServers, d.Options)
+}
+
+// OptMobileIPHomeAgents returns a new DHCPv4 Mobile IP Home Agent option.
+//
+// The Mobile IP Home Agent option is described by RFC 2132, Section 8.13.
+func OptMobileIPHomeAgents(ips ...net.IP) Option {
+	return Option{Code: OptionMobileIPHomeAgent, Value: IPs(ips)}
+}
+
+// MobileIPHomeAgents parses the DHCPv4 Mobile IP Home Agent option if present.
+//
+// The Mobile IP Home Agent option is described by RFC 2132, Section 8.13.
+func (d *DHCPv4) MobileIPHomeAgents() []net.IP {
+	return GetIPs(OptionMobileIPHomeAgent, d.Options)
+}
+
+// OptSMTPServers returns a new DHCPv4 Simple Mail Transport Protocol (SMTP) Server option.
+//
+// The Simple Mail Transport Protocol (SMTP) Server option is described by RFC 2132, Section 8.14.
+func OptSMTPServers(ips ...net.IP) Option {
+	return Option{Code: OptionSimpleMailTransportProtocolServer, Value: IPs(ips)}
+}
+
+// SMTPServers parses the DHCPv4 Simple Mail Transport Protocol (SMTP) Server option if present.
+//
+// The Simple Mail Transport Protocol (SMTP) Server option is described by RFC 2132, Section 8.14.
+func (d *DHCPv4) SMTPServers() []net.IP {
+	return GetIPs(OptionSimpleMailTransportProtocolServer, d.Options)
+}
+
+// OptPOP3Servers returns a new DHCPv4 Post Office Protocol (POP3) Server option.
+//
+// The Post Office Protocol (POP3) Server option is described by RFC 2132, Section 8.15.
+func OptPOP3Servers(ips ...net.IP) Option {
+	return Option{Code: OptionPostOfficeProtocolServer, Value: IPs(ips)}
+}
+
+// POP3Servers parses the DHCPv4 Post Office Protocol (POP3) Server option if present.
+//
+// The Post Office Protocol (POP3) Server option is described by RFC 2132, Section 8.15.
+func (d *DHCPv4) POP3Servers() []net.IP {
+	return GetIPs(OptionPostOfficeProtocolServer, d.Options)
+}
+
+// OptNNTPServers returns a new DHCPv4 Network News Transport Protocol (NNTP) Server option.
+//
+// The Network News Transport Protocol (NNTP) Server option is described by RFC 2132, Section 8.16.
+func OptNNTPServers(ips ...net.IP) Option {
+	return Option{Code: OptionNetworkNewsTransportProtocolServer, Value: IPs(ips)}
+}
+
+// NNTPServers parses the DHCPv4 Network News Transport Protocol (NNTP) Server option if present.
+//
+// The Network News Transport Protocol (NNTP) Server option is described by RFC 2132, Section 8.16.
+func (d *DHCPv4) NNTPServers() []net.IP {
+	return GetIPs(OptionNetworkNewsTransportProtocolServer, d.Options)
+}
+
+// OptWWWServers returns a new DHCPv4 Default World Wide Web (WWW) Server option.
+//
+// The Default World Wide Web (WWW) Server option is described by RFC 2132, Section 8.17.
+func OptWWWServers(ips ...net.IP) Option {
+	return Option{Code: OptionDefaultWorldWideWebServer, Value: IPs(ips)}
+}
+
+// WWWServers parses the DHCPv4 Default World Wide Web (WWW) Server option if present.
+//
+// The Default World Wide Web (WWW) Server option is described by RFC 2132, Section 8.17.
+func (d *DHCPv4) WWWServers() []net.IP {
+	return GetIPs(OptionDefaultWorldWideWebServer, d.Options)
+}
+
+// OptFingerServers returns a new DHCPv4 Default Finger Server option.
+//
+// The Default Finger Server option is described by RFC 2132, Section 8.18.
+func OptFingerServers(ips ...net.IP) Option {
+	return Option{Code: OptionDefaultFingerServer, Value: IPs(ips)}
+}
+
+// FingerServers parses the DHCPv4 Default Finger Server option if present.
+//
+// The Default Finger Server option is described by RFC 2132, Section 8.18.
+func (d *DHCPv4) FingerServers() []net.IP {
+	return GetIPs(OptionDefaultFingerServer, d.Options)
+}
+
+// OptIRCServers returns a new DHCPv4 Default Internet Relay Chat (IRC) Server option.
+//
+// The Default Internet Relay Chat (IRC) Server option is described by RFC 2132, Section 8.19.
+func OptIRCServers(ips ...net.IP) Option {
+	return Option{Code: OptionDefaultInternetRelayChatServer, Value: IPs(ips)}
+}
+
+// IRCServers parses the DHCPv4 Default Internet Relay Chat (IRC) Server option if present.
+//
+// The Default Internet Relay Chat (IRC) Server option is described by RFC 2132, Section 8.19.
+func (d *DHCPv4) IRCServers() []net.IP {
+	return GetIPs(OptionDefaultInternetRelayChatServer, d.Options)
+}
+
+// OptStreetTalkServers returns a new DHCPv4 StreetTalk Server option.
+//
+// The StreetTalk Server option is described by RFC 2132, Section 8.20.
+func OptStreetTalkServers(ips ...net.IP) Option {
+	return Option{Code: OptionStreetTalkServer, Value: IPs(ips)}
+}
+
+// StreetTalkServers parses the DHCPv4 StreetTalk Server option if present.
+//
+// The StreetTalk Server option is described by RFC 2132, Section 8.20.
+func (d *DHCPv4) StreetTalkServers() []net.IP {
+	return GetIPs(OptionStreetTalkServer, d.Options)
+}
+
+// OptSTDAServers returns a new DHCPv4 StreetTalk Directory Assistance (STDA) Server option.
+//
+// The StreetTalk Directory Assistance (STDA) Server option is described by RFC 2132, Section 8.21.
+func OptSTDAServers(ips ...net.IP) Option {
+	return Option{Code: OptionStreetTalkDirectoryAssistanceServer, Value: IPs(ips)}
+}
+
+// STDAServers parses the DHCPv4 StreetTalk Directory Assistance (STDA) Server option if present.
+//
+// The StreetTalk Directory Assistance (STDA) Server option is described by RFC 2132, Section 8.21.
+func (d *DHCPv4) STDAServers() []net.IP {
+	return GetIPs(OptionStreetTalkDirectoryAssistanceServer, d.Options)
+}
+
+// OptOverload returns a new DHCPv4 Option Overload option.
+//
+// The Option Overload option is described by RFC 2132, Section 9.3.
+func OptOverload(o Overload) Option {
+	return Option{Code: OptionOptionOverload, Value: o}
+}
+
+// Overload parses the DHCPv4 Option Overload option if present.
+//
+// The Option Overload option is described by RFC 2132, Section 9.3.
+func (d *DHCPv4) Overload() (Overload, error) {
+	v := d.Options.Get(OptionOptionOverload)
+	if v == nil {
+		return 0, fmt.Errorf("option not present")
+	}
+	var o Overload
+	if err := o.FromBytes(v); err != nil {
+		return 0, err
+	}
+	return o, nil
+}
+
+// OptMessage returns a new DHCPv4 Message option.
+//
+// The Message option is described by RFC 2132, Section 9.9.
+func OptMessage(s string) Option {
+	return Option{Code: OptionMessage, Value: String(s)}
+}
+
+// Message parses the DHCPv4 Message option if present.
+//
+// The Message option is described by RFC 2132, Section 9.9.
+func (d *DHCPv4) Message() string {
+	return GetString(OptionMessage, d.Options)
+}
+
+// OptRenewalTime returns a new DHCPv4 Renewal (T1) Time Value option.
+//
+// The Renewal (T1) Time Value option is described by RFC 2132, Section 9.11.
+func OptRenewalTime(dur time.Duration) Option {
+	return Option{Code: OptionRenewTimeValue, Value: Duration(dur)}
+}
+
+// RenewalTime parses the DHCPv4 Renewal (T1) Time Value option if present.
+// It returns def if the option is not present.
+//
+// The Renewal (T1) Time Value option is described by RFC 2132, Section 9.11.
+func (d *DHCPv4) RenewalTime(def time.Duration) time.Duration {
+	return getDuration(OptionRenewTimeValue, d.Options, def)
+}
+
+// OptRebindingTime returns a new DHCPv4 Rebinding (T2) Time Value option.
+//
+// The Rebinding (T2) Time Value option is described by RFC 2132, Section 9.12.
+func OptRebindingTime(dur time.Duration) Option {
+	return Option{Code: OptionRebindingTimeValue, Value: Duration(dur)}
+}
+
+// RebindingTime parses the DHCPv4 Rebinding (T2) Time Value option if present.
+// It returns def if the option is not present.
+//
+// The Rebinding (T2) Time Value option is described by RFC 2132, Section 9.12.
+func (d *DHCPv4) RebindingTime(def time.Duration) time.Duration {
+	return getDuration(OptionRebindingTimeValue, d.Options, def)
+}
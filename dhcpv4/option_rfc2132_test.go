@@ -0,0 +1,484 @@
+package dhcpv4
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustGet(v interface{}, err error) interface{} {
+	if err != nil {
+		return err
+	}
+	return v
+}
+
+func TestRFC2132OptionsRoundTrip(t *testing.T) {
+	ip1, ip2 := net.IP{192, 0, 2, 1}, net.IP{192, 0, 2, 2}
+	tested := make(map[OptionCode]bool)
+	for _, tt := range []struct {
+		opt  Option
+		data []byte
+		str  string
+		get  func(d *DHCPv4) interface{}
+		want interface{}
+	}{
+		{
+			opt:  OptTimeOffset(-time.Hour),
+			data: []byte{0xff, 0xff, 0xf1, 0xf0},
+			str:  "-1h0m0s",
+			get:  func(d *DHCPv4) interface{} { return d.TimeOffset(0) },
+			want: -time.Hour,
+		},
+		{
+			opt:  OptTimeServers(ip1, ip2),
+			data: []byte{192, 0, 2, 1, 192, 0, 2, 2},
+			str:  "192.0.2.1, 192.0.2.2",
+			get:  func(d *DHCPv4) interface{} { return d.TimeServers() },
+			want: []net.IP{ip1, ip2},
+		},
+		{
+			opt:  OptNameServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.NameServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptLogServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.LogServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptQuoteServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.QuoteServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptLPRServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.LPRServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptImpressServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.ImpressServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptResourceLocationServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.ResourceLocationServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptBootFileSize(4096),
+			data: []byte{0x10, 0x00},
+			str:  "4096",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.BootFileSize()) },
+			want: uint16(4096),
+		},
+		{
+			opt:  OptMeritDumpFile("/var/crash/core"),
+			data: []byte("/var/crash/core"),
+			str:  "/var/crash/core",
+			get:  func(d *DHCPv4) interface{} { return d.MeritDumpFile() },
+			want: "/var/crash/core",
+		},
+		{
+			opt:  OptSwapServer(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.SwapServer() },
+			want: ip1,
+		},
+		{
+			opt:  OptExtensionsPath("/ext"),
+			data: []byte("/ext"),
+			str:  "/ext",
+			get:  func(d *DHCPv4) interface{} { return d.ExtensionsPath() },
+			want: "/ext",
+		},
+		{
+			opt:  OptIPForwarding(true),
+			data: []byte{1},
+			str:  "true",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.IPForwarding()) },
+			want: true,
+		},
+		{
+			opt:  OptNonLocalSourceRouting(false),
+			data: []byte{0},
+			str:  "false",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.NonLocalSourceRouting()) },
+			want: false,
+		},
+		{
+			opt: OptPolicyFilter(
+				net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.IPMask{255, 0, 0, 0}},
+				net.IPNet{IP: net.IP{192, 168, 0, 0}, Mask: net.IPMask{255, 255, 0, 0}},
+			),
+			data: []byte{10, 0, 0, 0, 255, 0, 0, 0, 192, 168, 0, 0, 255, 255, 0, 0},
+			str:  "10.0.0.0/8, 192.168.0.0/16",
+			get:  func(d *DHCPv4) interface{} { return d.PolicyFilter() },
+			want: []net.IPNet{
+				{IP: net.IP{10, 0, 0, 0}, Mask: net.IPMask{255, 0, 0, 0}},
+				{IP: net.IP{192, 168, 0, 0}, Mask: net.IPMask{255, 255, 0, 0}},
+			},
+		},
+		{
+			opt:  OptMaxDatagramReassemblySize(1500),
+			data: []byte{0x05, 0xdc},
+			str:  "1500",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.MaxDatagramReassemblySize()) },
+			want: uint16(1500),
+		},
+		{
+			opt:  OptDefaultIPTTL(64),
+			data: []byte{64},
+			str:  "64",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.DefaultIPTTL()) },
+			want: uint8(64),
+		},
+		{
+			opt:  OptPathMTUAgingTimeout(10 * time.Minute),
+			data: []byte{0, 0, 0x02, 0x58},
+			str:  "10m0s",
+			get:  func(d *DHCPv4) interface{} { return d.PathMTUAgingTimeout(0) },
+			want: 10 * time.Minute,
+		},
+		{
+			opt:  OptPathMTUPlateauTable(68, 296, 576),
+			data: []byte{0, 68, 0x01, 0x28, 0x02, 0x40},
+			str:  "68, 296, 576",
+			get:  func(d *DHCPv4) interface{} { return d.PathMTUPlateauTable() },
+			want: []uint16{68, 296, 576},
+		},
+		{
+			opt:  OptInterfaceMTU(9000),
+			data: []byte{0x23, 0x28},
+			str:  "9000",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.InterfaceMTU()) },
+			want: uint16(9000),
+		},
+		{
+			opt:  OptAllSubnetsAreLocal(true),
+			data: []byte{1},
+			str:  "true",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.AllSubnetsAreLocal()) },
+			want: true,
+		},
+		{
+			opt:  OptPerformMaskDiscovery(true),
+			data: []byte{1},
+			str:  "true",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.PerformMaskDiscovery()) },
+			want: true,
+		},
+		{
+			opt:  OptMaskSupplier(false),
+			data: []byte{0},
+			str:  "false",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.MaskSupplier()) },
+			want: false,
+		},
+		{
+			opt:  OptPerformRouterDiscovery(true),
+			data: []byte{1},
+			str:  "true",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.PerformRouterDiscovery()) },
+			want: true,
+		},
+		{
+			opt:  OptRouterSolicitationAddress(net.IP{224, 0, 0, 2}),
+			data: []byte{224, 0, 0, 2},
+			str:  "224.0.0.2",
+			get:  func(d *DHCPv4) interface{} { return d.RouterSolicitationAddress() },
+			want: net.IP{224, 0, 0, 2},
+		},
+		{
+			opt: OptStaticRoutes(
+				StaticRoute{Destination: net.IP{10, 0, 0, 0}, Router: ip1},
+				StaticRoute{Destination: net.IP{172, 16, 0, 0}, Router: ip2},
+			),
+			data: []byte{10, 0, 0, 0, 192, 0, 2, 1, 172, 16, 0, 0, 192, 0, 2, 2},
+			str:  "10.0.0.0 via 192.0.2.1, 172.16.0.0 via 192.0.2.2",
+			get:  func(d *DHCPv4) interface{} { return d.StaticRoutes() },
+			want: []StaticRoute{
+				{Destination: net.IP{10, 0, 0, 0}, Router: ip1},
+				{Destination: net.IP{172, 16, 0, 0}, Router: ip2},
+			},
+		},
+		{
+			opt:  OptTrailerEncapsulation(false),
+			data: []byte{0},
+			str:  "false",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.TrailerEncapsulation()) },
+			want: false,
+		},
+		{
+			opt:  OptARPCacheTimeout(time.Minute),
+			data: []byte{0, 0, 0, 60},
+			str:  "1m0s",
+			get:  func(d *DHCPv4) interface{} { return d.ARPCacheTimeout(0) },
+			want: time.Minute,
+		},
+		{
+			opt:  OptEthernetEncapsulation(true),
+			data: []byte{1},
+			str:  "true",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.EthernetEncapsulation()) },
+			want: true,
+		},
+		{
+			opt:  OptDefaultTCPTTL(128),
+			data: []byte{128},
+			str:  "128",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.DefaultTCPTTL()) },
+			want: uint8(128),
+		},
+		{
+			opt:  OptTCPKeepaliveInterval(2 * time.Hour),
+			data: []byte{0, 0, 0x1c, 0x20},
+			str:  "2h0m0s",
+			get:  func(d *DHCPv4) interface{} { return d.TCPKeepaliveInterval(0) },
+			want: 2 * time.Hour,
+		},
+		{
+			opt:  OptTCPKeepaliveGarbage(true),
+			data: []byte{1},
+			str:  "true",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.TCPKeepaliveGarbage()) },
+			want: true,
+		},
+		{
+			opt:  OptNISDomain("nis.example.com"),
+			data: []byte("nis.example.com"),
+			str:  "nis.example.com",
+			get:  func(d *DHCPv4) interface{} { return d.NISDomain() },
+			want: "nis.example.com",
+		},
+		{
+			opt:  OptNISServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.NISServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptNetBIOSNameServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.NetBIOSNameServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptNetBIOSDatagramDistributionServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.NetBIOSDatagramDistributionServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptNetBIOSNodeType(NetBIOSNodeTypeH),
+			data: []byte{8},
+			str:  "H-node",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.NetBIOSNodeType()) },
+			want: NetBIOSNodeTypeH,
+		},
+		{
+			opt:  OptNetBIOSScope("scope"),
+			data: []byte("scope"),
+			str:  "scope",
+			get:  func(d *DHCPv4) interface{} { return d.NetBIOSScope() },
+			want: "scope",
+		},
+		{
+			opt:  OptXWindowFontServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.XWindowFontServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptXWindowDisplayManagers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.XWindowDisplayManagers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptNISPlusDomain("nisplus.example.com"),
+			data: []byte("nisplus.example.com"),
+			str:  "nisplus.example.com",
+			get:  func(d *DHCPv4) interface{} { return d.NISPlusDomain() },
+			want: "nisplus.example.com",
+		},
+		{
+			opt:  OptNISPlusServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.NISPlusServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptMobileIPHomeAgents(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.MobileIPHomeAgents() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptSMTPServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.SMTPServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptPOP3Servers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.POP3Servers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptNNTPServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.NNTPServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptWWWServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.WWWServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptFingerServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.FingerServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptIRCServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.IRCServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptStreetTalkServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.StreetTalkServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptSTDAServers(ip1),
+			data: []byte{192, 0, 2, 1},
+			str:  "192.0.2.1",
+			get:  func(d *DHCPv4) interface{} { return d.STDAServers() },
+			want: []net.IP{ip1},
+		},
+		{
+			opt:  OptOverload(OverloadFileAndSName),
+			data: []byte{3},
+			str:  "file and sname",
+			get:  func(d *DHCPv4) interface{} { return mustGet(d.Overload()) },
+			want: OverloadFileAndSName,
+		},
+		{
+			opt:  OptMessage("no address available"),
+			data: []byte("no address available"),
+			str:  "no address available",
+			get:  func(d *DHCPv4) interface{} { return d.Message() },
+			want: "no address available",
+		},
+		{
+			opt:  OptRenewalTime(30 * time.Minute),
+			data: []byte{0, 0, 0x07, 0x08},
+			str:  "30m0s",
+			get:  func(d *DHCPv4) interface{} { return d.RenewalTime(0) },
+			want: 30 * time.Minute,
+		},
+		{
+			opt:  OptRebindingTime(45 * time.Minute),
+			data: []byte{0, 0, 0x0a, 0x8c},
+			str:  "45m0s",
+			get:  func(d *DHCPv4) interface{} { return d.RebindingTime(0) },
+			want: 45 * time.Minute,
+		},
+	} {
+		tested[tt.opt.Code] = true
+		t.Run(tt.opt.Code.String(), func(t *testing.T) {
+			require.Equal(t, tt.data, tt.opt.Value.ToBytes())
+
+			// Through the wire format, back to the typed value.
+			m, err := New(WithOption(tt.opt))
+			require.NoError(t, err)
+			parsed, err := FromBytes(m.ToBytes())
+			require.NoError(t, err)
+			require.Equal(t, tt.want, tt.get(parsed))
+
+			// Humanized in Summary.
+			require.Equal(t, tt.str, parseOption(tt.opt.Code, tt.data).String())
+			require.Contains(t, parsed.Summary(), tt.opt.String())
+		})
+	}
+	// Every option of the generated table must have a round-trip test.
+	for code := range rfc2132Decoders {
+		require.True(t, tested[code], "no round-trip test for %s", code)
+	}
+}
+
+func TestRFC2132OptionsAbsent(t *testing.T) {
+	m, err := New()
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, m.TimeOffset(5*time.Second))
+	require.Equal(t, time.Minute, m.RenewalTime(time.Minute))
+	require.Nil(t, m.StaticRoutes())
+	require.Nil(t, m.PathMTUPlateauTable())
+	_, err = m.InterfaceMTU()
+	require.Error(t, err)
+	_, err = m.IPForwarding()
+	require.Error(t, err)
+	_, err = m.DefaultIPTTL()
+	require.Error(t, err)
+	_, err = m.NetBIOSNodeType()
+	require.Error(t, err)
+}
+
+func TestRFC2132OptionsInvalid(t *testing.T) {
+	var b Bool
+	require.Error(t, b.FromBytes([]byte{2}))
+	require.Error(t, b.FromBytes([]byte{}))
+
+	var u Uint16s
+	require.Error(t, u.FromBytes([]byte{}))
+	require.Error(t, u.FromBytes([]byte{0, 1, 2}))
+
+	var r StaticRoutes
+	require.Error(t, r.FromBytes([]byte{10, 0, 0, 0, 192, 0, 2}))
+
+	var f PolicyFilters
+	require.Error(t, f.FromBytes([]byte{}))
+
+	// Invalid values fall back to the generic representation.
+	require.Equal(t, "[2]", parseOption(OptionIPForwarding, []byte{2}).String())
+	require.Equal(t, "unknown (3)", NetBIOSNodeType(3).String())
+	require.Equal(t, "unknown (4)", Overload(4).String())
+}
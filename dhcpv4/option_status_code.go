@@ -0,0 +1,74 @@
+package dhcpv4
+
+import (
+	"fmt"
+
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// StatusCode is the code carried by the DHCPv4 status-code option, as defined
+// by RFC 6926, Section 6.2.2.
+type StatusCode uint8
+
+// Status codes defined by RFC 6926, Section 6.2.2.
+const (
+	StatusSuccess         StatusCode = 0
+	StatusUnspecFail      StatusCode = 1
+	StatusQueryTerminated StatusCode = 2
+	StatusMalformedQuery  StatusCode = 3
+	StatusNotAllowed      StatusCode = 4
+)
+
+var statusCodeToString = map[StatusCode]string{
+	StatusSuccess:         "Success",
+	StatusUnspecFail:      "UnspecFail",
+	StatusQueryTerminated: "QueryTerminated",
+	StatusMalformedQuery:  "MalformedQuery",
+	StatusNotAllowed:      "NotAllowed",
+}
+
+// String returns a human-readable status code name.
+func (s StatusCode) String() string {
+	if str, ok := statusCodeToString[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("unknown (%d)", uint8(s))
+}
+
+// Status implements the status-code option described by RFC 6926, Section
+// 6.2.2.
+type Status struct {
+	Code    StatusCode
+	Message string
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (s Status) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	buf.Write8(uint8(s.Code))
+	buf.WriteBytes([]byte(s.Message))
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (s Status) String() string {
+	if s.Message == "" {
+		return s.Code.String()
+	}
+	return fmt.Sprintf("%s: %s", s.Code, s.Message)
+}
+
+// FromBytes parses data into s per RFC 6926, Section 6.2.2.
+func (s *Status) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	s.Code = StatusCode(buf.Read8())
+	s.Message = string(buf.ReadAll())
+	return buf.FinError()
+}
+
+// OptStatusCode returns a new DHCPv4 status-code option.
+//
+// The status-code option is described by RFC 6926, Section 6.2.2.
+func OptStatusCode(code StatusCode, message string) Option {
+	return Option{Code: OptionStatusCode, Value: Status{Code: code, Message: message}}
+}
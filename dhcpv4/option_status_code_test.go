@@ -0,0 +1,40 @@
+package dhcpv4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptStatusCode(t *testing.T) {
+	o := OptStatusCode(StatusNotAllowed, "go away")
+	require.Equal(t, OptionStatusCode, o.Code, "Code")
+	require.Equal(t, []byte{4, 'g', 'o', ' ', 'a', 'w', 'a', 'y'}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Status Code: NotAllowed: go away", o.String(), "String")
+
+	o = OptStatusCode(StatusSuccess, "")
+	require.Equal(t, []byte{0}, o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Status Code: Success", o.String(), "String")
+}
+
+func TestParseStatus(t *testing.T) {
+	var s Status
+	err := s.FromBytes([]byte{3, 'b', 'a', 'd'})
+	require.NoError(t, err)
+	require.Equal(t, Status{Code: StatusMalformedQuery, Message: "bad"}, s)
+
+	// Too short.
+	err = s.FromBytes([]byte{})
+	require.Error(t, err, "empty byte stream")
+
+	require.Equal(t, "unknown (42)", StatusCode(42).String())
+}
+
+func TestGetStatus(t *testing.T) {
+	m, _ := New(WithOption(OptStatusCode(StatusQueryTerminated, "shutting down")))
+	require.Equal(t, &Status{Code: StatusQueryTerminated, Message: "shutting down"}, m.Status())
+
+	// Empty.
+	m, _ = New()
+	require.Nil(t, m.Status())
+}
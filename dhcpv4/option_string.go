@@ -66,6 +66,14 @@ func OptTFTPServerName(name string) Option {
 	return Option{Code: OptionTFTPServerName, Value: String(name)}
 }
 
+// OptCaptivePortal returns a new DHCPv4 Captive-Portal option.
+//
+// The Captive-Portal option, which carries the URI of the captive portal API
+// endpoint, is described by RFC 8910, Section 2.1.
+func OptCaptivePortal(uri string) Option {
+	return Option{Code: OptionCaptivePortal, Value: String(uri)}
+}
+
 // OptClassIdentifier returns a new DHCPv4 Class Identifier option.
 //
 // The Vendor Class Identifier option is described by RFC 2132, Section 9.13.
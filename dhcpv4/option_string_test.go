@@ -95,3 +95,20 @@ func TestParseOptClassIdentifier(t *testing.T) {
 	m, _ = New()
 	require.Equal(t, "", m.ClassIdentifier())
 }
+
+func TestOptCaptivePortal(t *testing.T) {
+	o := OptCaptivePortal("https://example.org/captive")
+	require.Equal(t, OptionCaptivePortal, o.Code, "Code")
+	require.Equal(t, []byte("https://example.org/captive"), o.Value.ToBytes(), "ToBytes")
+	require.Equal(t, "Captive Portal: https://example.org/captive", o.String())
+	require.Equal(t, OptionURL, o.Code)
+}
+
+func TestParseOptCaptivePortal(t *testing.T) {
+	m, _ := New(WithGeneric(OptionCaptivePortal, []byte("https://example.org/captive")))
+	require.Equal(t, "https://example.org/captive", m.CaptivePortal())
+	require.Contains(t, m.Summary(), "Captive Portal: https://example.org/captive")
+
+	m, _ = New()
+	require.Equal(t, "", m.CaptivePortal())
+}
@@ -0,0 +1,257 @@
+package dhcpv4
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// Enterprise numbers of vendors whose VIVSO sub-options are known.
+const (
+	EnterpriseBroadbandForum uint32 = 3561
+	EnterpriseCableLabs      uint32 = 4491
+)
+
+// Broadband Forum VIVSO sub-option codes, described by TR-069 and TR-111.
+const (
+	BBFDeviceManufacturerOUISubOption  GenericOptionCode = 1
+	BBFDeviceSerialNumberSubOption     GenericOptionCode = 2
+	BBFDeviceProductClassSubOption     GenericOptionCode = 3
+	BBFGatewayManufacturerOUISubOption GenericOptionCode = 4
+	BBFGatewaySerialNumberSubOption    GenericOptionCode = 5
+	BBFGatewayProductClassSubOption    GenericOptionCode = 6
+	BBFACSURLSubOption                 GenericOptionCode = 11
+	BBFProvisioningCodeSubOption       GenericOptionCode = 12
+)
+
+// CableLabs VIVSO sub-option codes, described by CL-SP-CANN-DHCP-Reg.
+const (
+	CableLabsOptionRequestSubOption GenericOptionCode = 1
+	CableLabsTFTPServersSubOption   GenericOptionCode = 2
+)
+
+// maxVIVSODataLen is the maximum length of the sub-options carried by one
+// instance of an enterprise, whose length field is a single byte.
+const maxVIVSODataLen = math.MaxUint8
+
+// VIVSOEnterprise holds the vendor-specific sub-options of one enterprise.
+type VIVSOEnterprise struct {
+	// EntID is the enterprise ID.
+	EntID   uint32
+	Options Options
+}
+
+// OptVIVSO returns a new vendor-identifying vendor-specific information
+// option.
+//
+// The option is described by RFC 3925.
+func OptVIVSO(enterprises ...VIVSOEnterprise) Option {
+	return Option{
+		Code:  OptionVendorIdentifyingVendorSpecific,
+		Value: VIVSOEnterprises(enterprises),
+	}
+}
+
+// VIVSOEnterprises implements encoding and decoding methods for the
+// vendor-identifying vendor-specific information option described in RFC
+// 3925.
+type VIVSOEnterprises []VIVSOEnterprise
+
+// Get returns the sub-options of the enterprise entID, or nil if there are
+// none.
+func (e VIVSOEnterprises) Get(entID uint32) Options {
+	for _, ent := range e {
+		if ent.EntID == entID {
+			return ent.Options
+		}
+	}
+	return nil
+}
+
+// FromBytes parses data into e per RFC 3925. The sub-options of the
+// instances of a same enterprise are concatenated, undoing the split done by
+// ToBytes.
+func (e *VIVSOEnterprises) FromBytes(data []byte) error {
+	var (
+		entIDs  []uint32
+		subData = make(map[uint32][]byte)
+	)
+	buf := uio.NewBigEndianBuffer(data)
+	for buf.Has(5) {
+		entID := buf.Read32()
+		dataLen := int(buf.Read8())
+		d := buf.Consume(dataLen)
+		if d == nil {
+			break
+		}
+		if _, ok := subData[entID]; !ok {
+			entIDs = append(entIDs, entID)
+		}
+		subData[entID] = append(subData[entID], d...)
+	}
+	if err := buf.FinError(); err != nil {
+		return err
+	}
+	for _, entID := range entIDs {
+		opts := make(Options)
+		if err := opts.FromBytes(subData[entID]); err != nil {
+			return err
+		}
+		*e = append(*e, VIVSOEnterprise{EntID: entID, Options: opts})
+	}
+	return nil
+}
+
+// ToBytes returns a serialized stream of bytes for this option. Sub-options
+// longer than the 255 bytes one instance of an enterprise can carry are split
+// across several instances of it.
+func (e VIVSOEnterprises) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, ent := range e {
+		data := ent.Options.ToBytes()
+		for {
+			n := len(data)
+			if n > maxVIVSODataLen {
+				n = maxVIVSODataLen
+			}
+			buf.Write32(ent.EntID)
+			buf.Write8(uint8(n))
+			buf.WriteBytes(data[:n])
+			data = data[n:]
+			if len(data) == 0 {
+				break
+			}
+		}
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option, interpreting the
+// sub-options of enterprises registered with RegisterVIVSODecoder.
+func (e VIVSOEnterprises) String() string {
+	var b strings.Builder
+	for _, ent := range e {
+		dec, ok := getVIVSODecoder(ent.EntID)
+		if ok {
+			fmt.Fprintf(&b, "    %s (%d):\n", dec.Name, ent.EntID)
+		} else {
+			fmt.Fprintf(&b, "    unknown (%d):\n", ent.EntID)
+			dec.Humanizer = genericHumanizer
+		}
+		for _, line := range strings.SplitAfter(ent.Options.ToString(dec.Humanizer), "\n") {
+			if line != "" {
+				b.WriteString("    " + line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// VIVSODecoder interprets the sub-options of one enterprise in the
+// vendor-identifying vendor-specific information option.
+type VIVSODecoder struct {
+	// Name is the name of the enterprise.
+	Name      string
+	Humanizer OptionHumanizer
+}
+
+var (
+	vivsoDecodersMu sync.RWMutex
+	vivsoDecoders   = map[uint32]VIVSODecoder{
+		EnterpriseBroadbandForum: {Name: "Broadband Forum", Humanizer: bbfHumanizer},
+		EnterpriseCableLabs:      {Name: "CableLabs", Humanizer: cableLabsHumanizer},
+	}
+)
+
+// RegisterVIVSODecoder registers the decoder used to print the sub-options of
+// the enterprise entID, replacing any previous one.
+func RegisterVIVSODecoder(entID uint32, dec VIVSODecoder) {
+	vivsoDecodersMu.Lock()
+	defer vivsoDecodersMu.Unlock()
+	vivsoDecoders[entID] = dec
+}
+
+func getVIVSODecoder(entID uint32) (VIVSODecoder, bool) {
+	vivsoDecodersMu.RLock()
+	defer vivsoDecodersMu.RUnlock()
+	dec, ok := vivsoDecoders[entID]
+	return dec, ok
+}
+
+// genericHumanizer prints sub-options of an unknown option space.
+var genericHumanizer = OptionHumanizer{
+	ValueHumanizer: func(code OptionCode, data []byte) fmt.Stringer {
+		return OptionGeneric{data}
+	},
+	CodeHumanizer: func(c uint8) OptionCode {
+		return GenericOptionCode(c)
+	},
+}
+
+// namedOptionCode is an option code with a name outside of the DHCP option
+// space.
+type namedOptionCode struct {
+	code uint8
+	name string
+}
+
+// Code implements OptionCode.Code.
+func (o namedOptionCode) Code() uint8 {
+	return o.code
+}
+
+// String returns the option's name.
+func (o namedOptionCode) String() string {
+	return o.name
+}
+
+// namedCodeHumanizer returns a CodeHumanizer for the given code names.
+func namedCodeHumanizer(names map[GenericOptionCode]string) func(uint8) OptionCode {
+	return func(c uint8) OptionCode {
+		if name, ok := names[GenericOptionCode(c)]; ok {
+			return namedOptionCode{code: c, name: name}
+		}
+		return GenericOptionCode(c)
+	}
+}
+
+var bbfHumanizer = OptionHumanizer{
+	ValueHumanizer: func(code OptionCode, data []byte) fmt.Stringer {
+		if _, ok := code.(namedOptionCode); ok {
+			var s String
+			if s.FromBytes(data) == nil {
+				return s
+			}
+		}
+		return OptionGeneric{data}
+	},
+	CodeHumanizer: namedCodeHumanizer(map[GenericOptionCode]string{
+		BBFDeviceManufacturerOUISubOption:  "Device Manufacturer OUI",
+		BBFDeviceSerialNumberSubOption:     "Device Serial Number",
+		BBFDeviceProductClassSubOption:     "Device Product Class",
+		BBFGatewayManufacturerOUISubOption: "Gateway Manufacturer OUI",
+		BBFGatewaySerialNumberSubOption:    "Gateway Serial Number",
+		BBFGatewayProductClassSubOption:    "Gateway Product Class",
+		BBFACSURLSubOption:                 "ACS URL",
+		BBFProvisioningCodeSubOption:       "Provisioning Code",
+	}),
+}
+
+var cableLabsHumanizer = OptionHumanizer{
+	ValueHumanizer: func(code OptionCode, data []byte) fmt.Stringer {
+		if code.Code() == CableLabsTFTPServersSubOption.Code() {
+			var ips IPs
+			if ips.FromBytes(data) == nil {
+				return ips
+			}
+		}
+		return OptionGeneric{data}
+	},
+	CodeHumanizer: namedCodeHumanizer(map[GenericOptionCode]string{
+		CableLabsOptionRequestSubOption: "Option Request",
+		CableLabsTFTPServersSubOption:   "TFTP Servers",
+	}),
+}
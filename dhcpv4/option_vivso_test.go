@@ -0,0 +1,115 @@
+package dhcpv4
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	sampleVIVSOOpt = VIVSOEnterprises{
+		VIVSOEnterprise{EntID: EnterpriseBroadbandForum, Options: OptionsFromList(
+			OptGeneric(BBFDeviceManufacturerOUISubOption, []byte("00D09E")),
+			OptGeneric(BBFDeviceSerialNumberSubOption, []byte("42")),
+		)},
+		VIVSOEnterprise{EntID: EnterpriseCableLabs, Options: OptionsFromList(
+			OptGeneric(CableLabsTFTPServersSubOption, []byte{10, 0, 0, 1}),
+		)},
+	}
+	sampleVIVSOOptRaw = []byte{
+		0x0, 0x0, 0xd, 0xe9, // enterprise id 3561
+		0xc,                                    // length
+		0x1, 0x6, '0', '0', 'D', '0', '9', 'E', // device manufacturer OUI
+		0x2, 0x2, '4', '2', // device serial number
+		0x0, 0x0, 0x11, 0x8b, // enterprise id 4491
+		0x6,                   // length
+		0x2, 0x4, 10, 0, 0, 1, // TFTP servers
+	}
+)
+
+func TestOptVIVSOInterfaceMethods(t *testing.T) {
+	opt := OptVIVSO(sampleVIVSOOpt...)
+	require.Equal(t, OptionVendorIdentifyingVendorSpecific, opt.Code, "Code")
+	require.Equal(t, sampleVIVSOOptRaw, opt.Value.ToBytes(), "ToBytes")
+	want := "Vendor-Identifying Vendor-Specific:\n" +
+		"    Broadband Forum (3561):\n" +
+		"        Device Manufacturer OUI: 00D09E\n" +
+		"        Device Serial Number: 42\n" +
+		"    CableLabs (4491):\n" +
+		"        TFTP Servers: 10.0.0.1\n"
+	require.Equal(t, want, opt.String())
+}
+
+func TestOptVIVSOLong(t *testing.T) {
+	long := VIVSOEnterprises{
+		VIVSOEnterprise{EntID: 1, Options: OptionsFromList(
+			OptGeneric(GenericOptionCode(1), make([]byte, 200)),
+			OptGeneric(GenericOptionCode(2), make([]byte, 200)),
+		)},
+		VIVSOEnterprise{EntID: 2, Options: OptionsFromList(
+			OptGeneric(GenericOptionCode(1), []byte{1}),
+		)},
+	}
+	data := long.ToBytes()
+	// the 404 bytes of the first enterprise take two instances of it
+	require.Equal(t, []byte{0, 0, 0, 1, 255}, data[:5])
+	require.Equal(t, []byte{0, 0, 0, 1, 149}, data[5+255:5+255+5])
+	require.Equal(t, []byte{0, 0, 0, 2, 3, 1, 1, 1}, data[2*5+404:])
+
+	// and survive a round trip through a packet
+	m, _ := New(WithOption(OptVIVSO(long...)))
+	p, err := FromBytes(m.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, long, p.VIVSO())
+}
+
+func TestParseOptVIVSO(t *testing.T) {
+	m, _ := New(WithGeneric(OptionVendorIdentifyingVendorSpecific, sampleVIVSOOptRaw))
+	o := m.VIVSO()
+	require.Equal(t, sampleVIVSOOpt, o)
+	require.Equal(t, []byte("42"), o.Get(EnterpriseBroadbandForum).Get(BBFDeviceSerialNumberSubOption))
+	require.Nil(t, o.Get(9))
+
+	// Data len too long
+	data := make([]byte, len(sampleVIVSOOptRaw))
+	copy(data, sampleVIVSOOptRaw)
+	data[4] = 40
+	m, _ = New(WithGeneric(OptionVendorIdentifyingVendorSpecific, data))
+	require.Nil(t, m.VIVSO(), "should get error from bad length")
+
+	// Truncated sub-option
+	m, _ = New(WithGeneric(OptionVendorIdentifyingVendorSpecific, []byte{0, 0, 0, 9, 2, 1, 4}))
+	require.Nil(t, m.VIVSO(), "should get error from bad sub-option length")
+
+	// Empty
+	m, _ = New()
+	require.Nil(t, m.VIVSO())
+}
+
+func TestVIVSODecoderRegistry(t *testing.T) {
+	e := VIVSOEnterprises{{EntID: 9, Options: OptionsFromList(OptGeneric(GenericOptionCode(1), []byte("ios")))}}
+	require.Equal(t, "    unknown (9):\n        unknown (1): [105 111 115]\n", e.String())
+
+	RegisterVIVSODecoder(9, VIVSODecoder{
+		Name: "Cisco",
+		Humanizer: OptionHumanizer{
+			ValueHumanizer: func(code OptionCode, data []byte) fmt.Stringer {
+				return String(data)
+			},
+			CodeHumanizer: func(c uint8) OptionCode {
+				return GenericOptionCode(c)
+			},
+		},
+	})
+	defer func() {
+		vivsoDecodersMu.Lock()
+		delete(vivsoDecoders, 9)
+		vivsoDecodersMu.Unlock()
+	}()
+	require.Equal(t, "    Cisco (9):\n        unknown (1): ios\n", e.String())
+
+	m, _ := New(WithHwAddr(net.HardwareAddr{1, 2, 3, 4, 5, 6}), WithOption(OptVIVSO(e...)))
+	require.Contains(t, m.Summary(), "Cisco (9):\n            unknown (1): ios\n")
+}
@@ -291,7 +291,8 @@ type OptionDecoder interface {
 func getOption(code OptionCode, data []byte, vendorDecoder OptionDecoder) fmt.Stringer {
 	var d OptionDecoder
 	switch code {
-	case OptionRouter, OptionDomainNameServer, OptionNTPServers, OptionServerIdentifier:
+	case OptionRouter, OptionDomainNameServer, OptionNTPServers, OptionServerIdentifier,
+		OptionAssociatedIP:
 		d = &IPs{}
 
 	case OptionBroadcastAddress, OptionRequestedIPAddress:
@@ -311,7 +312,8 @@ func getOption(code OptionCode, data []byte, vendorDecoder OptionDecoder) fmt.St
 		d = &OptionCodeList{}
 
 	case OptionHostName, OptionDomainName, OptionRootPath,
-		OptionClassIdentifier, OptionTFTPServerName, OptionBootfileName:
+		OptionClassIdentifier, OptionTFTPServerName, OptionBootfileName,
+		OptionPXELinuxConfigFile, OptionPXELinuxPathPrefix, OptionCaptivePortal:
 		var s String
 		d = &s
 
@@ -321,10 +323,29 @@ func getOption(code OptionCode, data []byte, vendorDecoder OptionDecoder) fmt.St
 	case OptionDNSDomainSearchList:
 		d = &rfc1035label.Labels{}
 
-	case OptionIPAddressLeaseTime:
+	case OptionIPAddressLeaseTime, OptionClientLastTransactionTime, OptionStartTimeOfState,
+		OptionPXELinuxRebootTime:
 		var dur Duration
 		d = &dur
 
+	case OptionBaseTime, OptionQueryStartTime, OptionQueryEndTime:
+		var t Timestamp
+		d = &t
+
+	case OptionStatusCode:
+		d = &Status{}
+
+	case OptionAuthentication:
+		d = &Authentication{}
+
+	case OptionDHCPState:
+		var s LeaseState
+		d = &s
+
+	case OptionDataSource:
+		var s DataSource
+		d = &s
+
 	case OptionMaximumDHCPMessageSize:
 		var u Uint16
 		d = &u
@@ -332,11 +353,28 @@ func getOption(code OptionCode, data []byte, vendorDecoder OptionDecoder) fmt.St
 	case OptionUserClassInformation:
 		d = &UserClass{}
 
+	case OptionClientIdentifier:
+		d = &ClientIdentifier{}
+
+	case OptionClientNetworkInterfaceIdentifier:
+		d = &NetworkInterfaceID{}
+
+	case OptionClientMachineIdentifier:
+		d = &MachineID{}
+
 	case OptionVendorIdentifyingVendorClass:
 		d = &VIVCIdentifiers{}
 
+	case OptionVendorIdentifyingVendorSpecific:
+		d = &VIVSOEnterprises{}
+
 	case OptionVendorSpecificInformation:
 		d = vendorDecoder
+
+	default:
+		if newDecoder, ok := rfc2132Decoders[code]; ok {
+			d = newDecoder()
+		}
 	}
 	if d != nil && d.FromBytes(data) == nil {
 		return d
@@ -19,7 +19,7 @@ func TestParseOption(t *testing.T) {
 		{
 			code:  OptionNameServer,
 			value: []byte{192, 168, 1, 254},
-			want:  "[192 168 1 254]",
+			want:  "192.168.1.254",
 		},
 		{
 			code:  OptionSubnetMask,
@@ -0,0 +1,10 @@
+/*
+The pxe package implements the vendor-specific options of the Preboot
+Execution Environment (PXE), carried in DHCP option 43 between PXE clients and
+boot or proxyDHCP servers.
+
+The options are defined by the PXE specification, version 2.1:
+http://www.pix.net/software/pxeboot/archive/pxespec.pdf
+*/
+
+package pxe
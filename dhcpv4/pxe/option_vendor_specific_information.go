@@ -0,0 +1,159 @@
+package pxe
+
+import (
+	"fmt"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+)
+
+// optEnd terminates the PXE vendor options.
+const optEnd = 255
+
+// VendorOptions is like dhcpv4.Options, but stringifies using PXE-specific
+// option codes.
+type VendorOptions struct {
+	dhcpv4.Options
+}
+
+// String prints the contained options using PXE-specific option code parsing.
+func (v VendorOptions) String() string {
+	return v.Options.ToString(pxeHumanizer)
+}
+
+// ToBytes returns a serialized stream of bytes for the options, terminated
+// by an end option as required by the PXE specification.
+func (v VendorOptions) ToBytes() []byte {
+	return append(v.Options.ToBytes(), optEnd)
+}
+
+// FromBytes parses vendor options from data.
+func (v *VendorOptions) FromBytes(data []byte) error {
+	v.Options = make(dhcpv4.Options)
+	return v.Options.FromBytes(data)
+}
+
+// DiscoveryControl returns the PXE discovery control bits in v, or 0 if not
+// present.
+func (v VendorOptions) DiscoveryControl() DiscoveryControl {
+	var c DiscoveryControl
+	if val := v.Options.Get(OptionDiscoveryControl); val != nil {
+		if err := c.FromBytes(val); err != nil {
+			return 0
+		}
+	}
+	return c
+}
+
+// BootServers returns the PXE boot servers in v.
+func (v VendorOptions) BootServers() BootServers {
+	val := v.Options.Get(OptionBootServers)
+	if val == nil {
+		return nil
+	}
+	var b BootServers
+	if err := b.FromBytes(val); err != nil {
+		return nil
+	}
+	return b
+}
+
+// BootMenu returns the PXE boot menu in v.
+func (v VendorOptions) BootMenu() BootMenu {
+	val := v.Options.Get(OptionBootMenu)
+	if val == nil {
+		return nil
+	}
+	var m BootMenu
+	if err := m.FromBytes(val); err != nil {
+		return nil
+	}
+	return m
+}
+
+// MenuPrompt returns the PXE menu prompt in v if present.
+func (v VendorOptions) MenuPrompt() *MenuPrompt {
+	val := v.Options.Get(OptionMenuPrompt)
+	if val == nil {
+		return nil
+	}
+	var p MenuPrompt
+	if err := p.FromBytes(val); err != nil {
+		return nil
+	}
+	return &p
+}
+
+// BootItem returns the PXE boot item in v if present.
+func (v VendorOptions) BootItem() *BootItem {
+	val := v.Options.Get(OptionBootItem)
+	if val == nil {
+		return nil
+	}
+	var b BootItem
+	if err := b.FromBytes(val); err != nil {
+		return nil
+	}
+	return &b
+}
+
+// OptVendorOptions returns a new PXE Vendor Specific Info option.
+func OptVendorOptions(o ...dhcpv4.Option) dhcpv4.Option {
+	return dhcpv4.Option{
+		Code:  dhcpv4.OptionVendorSpecificInformation,
+		Value: VendorOptions{dhcpv4.OptionsFromList(o...)},
+	}
+}
+
+// GetVendorOptions returns the PXE Vendor Specific Info in o.
+func GetVendorOptions(o dhcpv4.Options) *VendorOptions {
+	v := o.Get(dhcpv4.OptionVendorSpecificInformation)
+	if v == nil {
+		return nil
+	}
+	var vo VendorOptions
+	if err := vo.FromBytes(v); err != nil {
+		return nil
+	}
+	return &vo
+}
+
+var pxeHumanizer = dhcpv4.OptionHumanizer{
+	ValueHumanizer: parseOption,
+	CodeHumanizer: func(c uint8) dhcpv4.OptionCode {
+		return optionCode(c)
+	},
+}
+
+// parseOption is similar to dhcpv4.parseOption, except that it interprets
+// option codes based on the PXE-specific options.
+func parseOption(code dhcpv4.OptionCode, data []byte) fmt.Stringer {
+	var d dhcpv4.OptionDecoder
+	switch code {
+	case OptionMTFTPIP, OptionDiscoveryMulticast:
+		d = &dhcpv4.IP{}
+
+	case OptionMTFTPClientPort, OptionMTFTPServerPort:
+		var u dhcpv4.Uint16
+		d = &u
+
+	case OptionDiscoveryControl:
+		var c DiscoveryControl
+		d = &c
+
+	case OptionBootServers:
+		d = &BootServers{}
+
+	case OptionBootMenu:
+		d = &BootMenu{}
+
+	case OptionMenuPrompt:
+		d = &MenuPrompt{}
+
+	case OptionBootItem:
+		d = &BootItem{}
+	}
+	if d != nil && d.FromBytes(data) == nil {
+		return d
+	}
+	return dhcpv4.OptionGeneric{Data: data}
+}
@@ -0,0 +1,51 @@
+package pxe
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptVendorOptions(t *testing.T) {
+	o := OptVendorOptions(
+		OptDiscoveryControl(UseBootFile),
+		OptMenuPrompt("boot", 0),
+	)
+	require.Equal(t, dhcpv4.OptionVendorSpecificInformation, o.Code)
+	require.Equal(t, []byte{6, 1, 8, 10, 5, 0, 'b', 'o', 'o', 't', 255}, o.Value.ToBytes())
+	want := "Vendor Specific Information:\n" +
+		"    PXE Discovery Control: 0x08 (use boot file)\n" +
+		"    PXE Menu Prompt: 'boot' (timeout 0s)\n"
+	require.Equal(t, want, o.String())
+}
+
+func TestGetVendorOptions(t *testing.T) {
+	m, _ := dhcpv4.New(dhcpv4.WithOption(OptVendorOptions(
+		OptDiscoveryControl(OnlyListedBootServers),
+		OptBootServers(BootServer{Type: 1, IPs: []net.IP{{10, 0, 0, 1}}}),
+		OptBootMenu(MenuItem{Type: 1, Description: "Linux"}),
+		OptMenuPrompt("choose", time.Second),
+		OptBootItem(1, 0),
+	)))
+	vo := GetVendorOptions(m.Options)
+	require.NotNil(t, vo)
+	require.Equal(t, OnlyListedBootServers, vo.DiscoveryControl())
+	require.Equal(t, BootServers{{Type: 1, IPs: []net.IP{{10, 0, 0, 1}}}}, vo.BootServers())
+	require.Equal(t, BootMenu{{Type: 1, Description: "Linux"}}, vo.BootMenu())
+	require.Equal(t, &MenuPrompt{Prompt: "choose", Timeout: time.Second}, vo.MenuPrompt())
+	require.Equal(t, &BootItem{Type: 1}, vo.BootItem())
+
+	// Empty.
+	vo = GetVendorOptions(dhcpv4.Options{43: {255}})
+	require.NotNil(t, vo)
+	require.Equal(t, DiscoveryControl(0), vo.DiscoveryControl())
+	require.Nil(t, vo.BootServers())
+	require.Nil(t, vo.BootMenu())
+	require.Nil(t, vo.MenuPrompt())
+	require.Nil(t, vo.BootItem())
+
+	require.Nil(t, GetVendorOptions(dhcpv4.Options{}))
+}
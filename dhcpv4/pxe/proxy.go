@@ -0,0 +1,159 @@
+package pxe
+
+import (
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// ProxyPort is the port of proxyDHCP servers that PXE clients send boot
+// server requests to, as described by the PXE specification, Section 2.2.5.
+const ProxyPort = 4011
+
+// ProxyServer is a proxyDHCP service. It gives PXE clients boot information
+// next to a DHCP server it does not control, which assigns the addresses.
+//
+// It answers PXE DISCOVERs on the DHCP server port with OFFERs carrying no
+// address, and PXE REQUESTs on ProxyPort with ACKs.
+type ProxyServer struct {
+	// Config is the boot configuration handed out. Its BootFileName is
+	// the boot file of clients whose architecture is not in BootFiles.
+	Config ReplyConfig
+	// BootFiles maps client architectures to boot files.
+	BootFiles map[iana.Arch]string
+
+	// DHCPAddr and BootAddr are the addresses the server listens on for
+	// DHCP and boot server requests respectively.
+	DHCPAddr, BootAddr net.UDPAddr
+
+	mu         sync.Mutex
+	dhcp, boot *dhcpv4.Server
+}
+
+// NewProxyServer returns a ProxyServer for the given configuration, listening
+// on the DHCP server port and on ProxyPort of all addresses.
+func NewProxyServer(config ReplyConfig, bootFiles map[iana.Arch]string) *ProxyServer {
+	return &ProxyServer{
+		Config:    config,
+		BootFiles: bootFiles,
+		DHCPAddr:  net.UDPAddr{IP: net.IPv4zero, Port: dhcpv4.ServerPort},
+		BootAddr:  net.UDPAddr{IP: net.IPv4zero, Port: ProxyPort},
+	}
+}
+
+// ActivateAndServe starts listening for DHCP and boot server requests. It
+// returns when either listener fails or the server is closed with
+// ProxyServer.Close, and both listeners are stopped when it returns.
+func (s *ProxyServer) ActivateAndServe() error {
+	s.mu.Lock()
+	dhcp := dhcpv4.NewServer(s.DHCPAddr, s.HandleDHCP)
+	boot := dhcpv4.NewServer(s.BootAddr, s.HandleBoot)
+	s.dhcp, s.boot = dhcp, boot
+	s.mu.Unlock()
+
+	type result struct {
+		srv *dhcpv4.Server
+		err error
+	}
+	results := make(chan result, 2)
+	for _, srv := range []*dhcpv4.Server{dhcp, boot} {
+		go func(srv *dhcpv4.Server) {
+			results <- result{srv: srv, err: srv.ActivateAndServe()}
+		}(srv)
+	}
+	first := <-results
+
+	s.mu.Lock()
+	s.dhcp, s.boot = nil, nil
+	s.mu.Unlock()
+	other := dhcp
+	if first.srv == dhcp {
+		other = boot
+	}
+	// The other listener may not be bound yet, and closing it before would
+	// not stop it: wait until it either listens or fails.
+	for other.LocalAddr() == nil {
+		select {
+		case <-results:
+			return first.err
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	other.Close()
+	<-results
+	return first.err
+}
+
+// Close stops both listeners.
+func (s *ProxyServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for _, srv := range []*dhcpv4.Server{s.dhcp, s.boot} {
+		if srv == nil {
+			continue
+		}
+		if cerr := srv.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// BootFileFor returns the boot file for the architecture of the PXE client
+// that sent request.
+func (s *ProxyServer) BootFileFor(request *dhcpv4.DHCPv4) string {
+	for _, arch := range request.ClientArch() {
+		if f, ok := s.BootFiles[arch]; ok {
+			return f
+		}
+	}
+	return s.Config.BootFileName
+}
+
+// HandleDHCP is the dhcpv4.Handler of the DHCP server port. It answers PXE
+// DISCOVERs, broadcasting the OFFER since the client has no address yet, or
+// sending it to the relay agent.
+func (s *ProxyServer) HandleDHCP(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+	if !IsPXERequest(m) || m.MessageType() != dhcpv4.MessageTypeDiscover {
+		return
+	}
+	dest := &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpv4.ClientPort}
+	if m.GatewayIPAddr != nil && !m.GatewayIPAddr.IsUnspecified() {
+		dest = &net.UDPAddr{IP: m.GatewayIPAddr, Port: dhcpv4.ServerPort}
+	}
+	s.reply(conn, dest, m)
+}
+
+// HandleBoot is the dhcpv4.Handler of ProxyPort. It answers the REQUESTs and
+// INFORMs that PXE clients unicast once they have an address.
+func (s *ProxyServer) HandleBoot(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+	if !IsPXERequest(m) {
+		return
+	}
+	switch m.MessageType() {
+	case dhcpv4.MessageTypeRequest, dhcpv4.MessageTypeInform:
+		s.reply(conn, peer, m)
+	}
+}
+
+func (s *ProxyServer) reply(conn net.PacketConn, dest net.Addr, m *dhcpv4.DHCPv4) {
+	config := s.Config
+	config.BootFileName = s.BootFileFor(m)
+	if config.BootFileName == "" && len(config.Menu) == 0 {
+		log.Printf("No boot file for PXE client %s with architecture %v", m.ClientHWAddr, m.ClientArch())
+		return
+	}
+	reply, err := NewProxyReply(m, config)
+	if err != nil {
+		log.Printf("Cannot build proxyDHCP reply: %v", err)
+		return
+	}
+	if _, err := conn.WriteTo(reply.ToBytes(), dest); err != nil {
+		log.Printf("Cannot reply to PXE client %s: %v", m.ClientHWAddr, err)
+	}
+}
@@ -0,0 +1,132 @@
+package pxe
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a net.PacketConn that records what is written to it.
+type recorder struct {
+	net.PacketConn
+	dest net.Addr
+	data []byte
+}
+
+func (r *recorder) WriteTo(b []byte, addr net.Addr) (int, error) {
+	r.dest = addr
+	r.data = append([]byte(nil), b...)
+	return len(b), nil
+}
+
+func newTestProxyServer() *ProxyServer {
+	return NewProxyServer(ReplyConfig{ServerIP: serverIP, BootFileName: "pxelinux.0"}, map[iana.Arch]string{
+		iana.EFI_X86_64: "grubx64.efi",
+	})
+}
+
+func TestProxyServerBootFileFor(t *testing.T) {
+	s := newTestProxyServer()
+	m := newPXERequest(t, dhcpv4.MessageTypeDiscover)
+	require.Equal(t, "pxelinux.0", s.BootFileFor(m))
+
+	m.UpdateOption(dhcpv4.OptClientArch(iana.EFI_X86_64))
+	require.Equal(t, "grubx64.efi", s.BootFileFor(m))
+
+	s.Config.BootFileName = ""
+	m.UpdateOption(dhcpv4.OptClientArch(iana.EFI_IA32))
+	require.Equal(t, "", s.BootFileFor(m))
+}
+
+func TestProxyServerHandleDHCP(t *testing.T) {
+	s := newTestProxyServer()
+
+	// Broadcast OFFER.
+	var conn recorder
+	s.HandleDHCP(&conn, nil, newPXERequest(t, dhcpv4.MessageTypeDiscover, dhcpv4.WithOption(dhcpv4.OptClientArch(iana.EFI_X86_64))))
+	require.Equal(t, &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpv4.ClientPort}, conn.dest)
+	reply, err := dhcpv4.FromBytes(conn.data)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeOffer, reply.MessageType())
+	require.Equal(t, "grubx64.efi", reply.BootFileName)
+	require.True(t, reply.YourIPAddr.IsUnspecified())
+
+	// Relayed.
+	conn = recorder{}
+	s.HandleDHCP(&conn, nil, newPXERequest(t, dhcpv4.MessageTypeDiscover, dhcpv4.WithRelay(net.IP{10, 0, 0, 1})))
+	require.Equal(t, &net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: dhcpv4.ServerPort}, conn.dest)
+
+	// Ignored: not a DISCOVER, not a PXE client, no boot file.
+	conn = recorder{}
+	s.HandleDHCP(&conn, nil, newPXERequest(t, dhcpv4.MessageTypeRequest))
+	require.Nil(t, conn.data)
+	m, _ := dhcpv4.New(dhcpv4.WithMessageType(dhcpv4.MessageTypeDiscover))
+	s.HandleDHCP(&conn, nil, m)
+	require.Nil(t, conn.data)
+	s.Config.BootFileName = ""
+	s.HandleDHCP(&conn, nil, newPXERequest(t, dhcpv4.MessageTypeDiscover))
+	require.Nil(t, conn.data)
+}
+
+func TestProxyServerActivateAndServe(t *testing.T) {
+	s := newTestProxyServer()
+	s.DHCPAddr = net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	s.BootAddr = net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	go s.ActivateAndServe()
+	defer s.Close()
+
+	var baddr net.Addr
+	for baddr == nil {
+		time.Sleep(10 * time.Millisecond)
+		s.mu.Lock()
+		if s.boot != nil {
+			baddr = s.boot.LocalAddr()
+		}
+		s.mu.Unlock()
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	req := newPXERequest(t, dhcpv4.MessageTypeRequest)
+	_, err = conn.WriteTo(req.ToBytes(), baddr)
+	require.NoError(t, err)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1500)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	reply, err := dhcpv4.FromBytes(buf[:n])
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeAck, reply.MessageType())
+	require.Equal(t, req.TransactionID, reply.TransactionID)
+	require.Equal(t, "pxelinux.0", reply.BootFileName)
+}
+
+func TestProxyServerActivateAndServeBindFailure(t *testing.T) {
+	// a free port for the DHCP listener
+	free, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	dhcpAddr := *free.LocalAddr().(*net.UDPAddr)
+	require.NoError(t, free.Close())
+	// a port already in use for the boot listener
+	busy, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer busy.Close()
+
+	s := newTestProxyServer()
+	s.DHCPAddr = dhcpAddr
+	s.BootAddr = *busy.LocalAddr().(*net.UDPAddr)
+	require.Error(t, s.ActivateAndServe())
+	require.NoError(t, s.Close())
+
+	// the DHCP listener was stopped
+	conn, err := net.ListenUDP("udp4", &dhcpAddr)
+	require.NoError(t, err)
+	conn.Close()
+}
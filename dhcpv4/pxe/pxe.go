@@ -0,0 +1,100 @@
+package pxe
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+)
+
+// ReplyConfig is a struct containing the boot configuration a PXE or
+// proxyDHCP server hands out.
+type ReplyConfig struct {
+	ServerIP                     net.IP
+	ServerHostname, BootFileName string
+
+	// DiscoveryControl tells the client how to discover boot servers. If it
+	// is zero and there is no boot menu, the client is told to download
+	// BootFileName right away.
+	DiscoveryControl DiscoveryControl
+	BootServers      []BootServer
+
+	// Menu is the boot menu shown to the user, along with Prompt.
+	Menu   []MenuItem
+	Prompt *MenuPrompt
+}
+
+// IsPXERequest returns true if d was sent by a PXE client, according to its
+// vendor class identifier.
+func IsPXERequest(d *dhcpv4.DHCPv4) bool {
+	return d.OpCode == dhcpv4.OpcodeBootRequest &&
+		strings.HasPrefix(d.ClassIdentifier(), ClassIdentifier)
+}
+
+// NewProxyReply builds the reply of a proxyDHCP server to a PXE client, as
+// described by the PXE specification, Section 2.2.5: an OFFER to a DISCOVER,
+// or an ACK to a REQUEST or INFORM, that carries boot information but no IP
+// address.
+func NewProxyReply(request *dhcpv4.DHCPv4, config ReplyConfig, modifiers ...dhcpv4.Modifier) (*dhcpv4.DHCPv4, error) {
+	if !IsPXERequest(request) {
+		return nil, errors.New("not a request from a PXE client")
+	}
+	if config.ServerIP.To4() == nil {
+		return nil, errors.New("server IP must be an IPv4 address")
+	}
+	var mt dhcpv4.MessageType
+	switch request.MessageType() {
+	case dhcpv4.MessageTypeDiscover:
+		mt = dhcpv4.MessageTypeOffer
+	case dhcpv4.MessageTypeRequest, dhcpv4.MessageTypeInform:
+		mt = dhcpv4.MessageTypeAck
+	default:
+		return nil, fmt.Errorf("cannot reply to a %s", request.MessageType())
+	}
+
+	vendorOpts := []dhcpv4.Option{}
+	control := config.DiscoveryControl
+	if control == 0 && len(config.Menu) == 0 && config.BootFileName != "" {
+		control = UseBootFile
+	}
+	if control != 0 {
+		vendorOpts = append(vendorOpts, OptDiscoveryControl(control))
+	}
+	if len(config.BootServers) > 0 {
+		vendorOpts = append(vendorOpts, OptBootServers(config.BootServers...))
+	}
+	if len(config.Menu) > 0 {
+		prompt := MenuPrompt{}
+		if config.Prompt != nil {
+			prompt = *config.Prompt
+		}
+		vendorOpts = append(vendorOpts,
+			OptBootMenu(config.Menu...),
+			OptMenuPrompt(prompt.Prompt, prompt.Timeout),
+		)
+	}
+	if vo := GetVendorOptions(request.Options); vo != nil {
+		if item := vo.BootItem(); item != nil {
+			vendorOpts = append(vendorOpts, OptBootItem(item.Type, item.Layer))
+		}
+	}
+
+	reply, err := dhcpv4.NewReplyFromRequest(request, dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithMessageType(mt),
+		dhcpv4.WithServerIP(config.ServerIP),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(config.ServerIP)),
+		dhcpv4.WithOption(dhcpv4.OptClassIdentifier(ClassIdentifier)),
+		dhcpv4.WithOption(OptVendorOptions(vendorOpts...)),
+	)...)
+	if err != nil {
+		return nil, err
+	}
+	reply.ServerHostName = config.ServerHostname
+	reply.BootFileName = config.BootFileName
+	if id := request.ClientMachineID(); id != nil {
+		reply.UpdateOption(dhcpv4.Option{Code: dhcpv4.OptionClientMachineIdentifier, Value: id})
+	}
+	return reply, nil
+}
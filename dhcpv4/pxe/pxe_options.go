@@ -0,0 +1,237 @@
+package pxe
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/u-root/u-root/pkg/uio"
+)
+
+// DiscoveryControl is a bit field that tells a PXE client how to discover
+// boot servers.
+type DiscoveryControl uint8
+
+// Discovery control bits defined by the PXE specification, Table 2-1.
+const (
+	// DisableBroadcastDiscovery stops the client from broadcasting to
+	// discover boot servers.
+	DisableBroadcastDiscovery DiscoveryControl = 1 << 0
+	// DisableMulticastDiscovery stops the client from using multicast to
+	// discover boot servers.
+	DisableMulticastDiscovery DiscoveryControl = 1 << 1
+	// OnlyListedBootServers restricts the client to the boot servers of the
+	// boot servers option.
+	OnlyListedBootServers DiscoveryControl = 1 << 2
+	// UseBootFile tells the client to download the boot file of the offer
+	// directly, without boot server discovery.
+	UseBootFile DiscoveryControl = 1 << 3
+)
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (c DiscoveryControl) ToBytes() []byte {
+	return []byte{uint8(c)}
+}
+
+// String returns a human-readable string for this option.
+func (c DiscoveryControl) String() string {
+	var s []string
+	if c&DisableBroadcastDiscovery != 0 {
+		s = append(s, "no broadcast")
+	}
+	if c&DisableMulticastDiscovery != 0 {
+		s = append(s, "no multicast")
+	}
+	if c&OnlyListedBootServers != 0 {
+		s = append(s, "listed servers only")
+	}
+	if c&UseBootFile != 0 {
+		s = append(s, "use boot file")
+	}
+	return fmt.Sprintf("0x%02x (%s)", uint8(c), strings.Join(s, ", "))
+}
+
+// FromBytes parses data into c.
+func (c *DiscoveryControl) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	*c = DiscoveryControl(buf.Read8())
+	return buf.FinError()
+}
+
+// OptDiscoveryControl returns a new PXE discovery control option.
+func OptDiscoveryControl(c DiscoveryControl) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionDiscoveryControl, Value: c}
+}
+
+// BootServer is a boot server type and the addresses of the servers of that
+// type.
+type BootServer struct {
+	Type BootServerType
+	IPs  []net.IP
+}
+
+// BootServers implements the PXE boot servers option.
+type BootServers []BootServer
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (b BootServers) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, s := range b {
+		buf.Write16(uint16(s.Type))
+		buf.Write8(uint8(len(s.IPs)))
+		for _, ip := range s.IPs {
+			buf.WriteBytes(ip.To4())
+		}
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (b BootServers) String() string {
+	s := make([]string, 0, len(b))
+	for _, server := range b {
+		s = append(s, fmt.Sprintf("%s: %s", server.Type, dhcpv4.IPs(server.IPs)))
+	}
+	return strings.Join(s, "; ")
+}
+
+// FromBytes parses data into b.
+func (b *BootServers) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	for buf.Has(3) {
+		s := BootServer{Type: BootServerType(buf.Read16())}
+		n := int(buf.Read8())
+		for i := 0; i < n; i++ {
+			s.IPs = append(s.IPs, net.IP(buf.CopyN(net.IPv4len)))
+		}
+		*b = append(*b, s)
+	}
+	return buf.FinError()
+}
+
+// OptBootServers returns a new PXE boot servers option.
+func OptBootServers(servers ...BootServer) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionBootServers, Value: BootServers(servers)}
+}
+
+// MenuItem is an entry of the PXE boot menu: a boot server type and the
+// description shown to the user.
+type MenuItem struct {
+	Type        BootServerType
+	Description string
+}
+
+// BootMenu implements the PXE boot menu option.
+type BootMenu []MenuItem
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (m BootMenu) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	for _, item := range m {
+		buf.Write16(uint16(item.Type))
+		buf.Write8(uint8(len(item.Description)))
+		buf.WriteBytes([]byte(item.Description))
+	}
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (m BootMenu) String() string {
+	s := make([]string, 0, len(m))
+	for _, item := range m {
+		s = append(s, fmt.Sprintf("%d:'%s'", uint16(item.Type), item.Description))
+	}
+	return strings.Join(s, ", ")
+}
+
+// FromBytes parses data into m.
+func (m *BootMenu) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	for buf.Has(3) {
+		t := BootServerType(buf.Read16())
+		n := int(buf.Read8())
+		*m = append(*m, MenuItem{Type: t, Description: string(buf.CopyN(n))})
+	}
+	return buf.FinError()
+}
+
+// OptBootMenu returns a new PXE boot menu option.
+func OptBootMenu(items ...MenuItem) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionBootMenu, Value: BootMenu(items)}
+}
+
+// MenuTimeoutForever is the menu prompt timeout that waits for the user
+// forever.
+const MenuTimeoutForever = 255 * time.Second
+
+// MenuPrompt implements the PXE menu prompt option: the prompt shown before
+// the boot menu, and how long the client waits for the user.
+//
+// A zero timeout selects the first menu item right away, and a timeout of
+// MenuTimeoutForever or more waits forever.
+type MenuPrompt struct {
+	Timeout time.Duration
+	Prompt  string
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (p MenuPrompt) ToBytes() []byte {
+	timeout := p.Timeout
+	if timeout > MenuTimeoutForever {
+		timeout = MenuTimeoutForever
+	}
+	return append([]byte{uint8(timeout / time.Second)}, p.Prompt...)
+}
+
+// String returns a human-readable string for this option.
+func (p MenuPrompt) String() string {
+	return fmt.Sprintf("'%s' (timeout %s)", p.Prompt, p.Timeout)
+}
+
+// FromBytes parses data into p.
+func (p *MenuPrompt) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	p.Timeout = time.Duration(buf.Read8()) * time.Second
+	p.Prompt = string(buf.ReadAll())
+	return buf.Error()
+}
+
+// OptMenuPrompt returns a new PXE menu prompt option.
+func OptMenuPrompt(prompt string, timeout time.Duration) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionMenuPrompt, Value: MenuPrompt{Timeout: timeout, Prompt: prompt}}
+}
+
+// BootItem implements the PXE boot item option, which clients send to boot
+// servers to select a menu item and a layer of its boot image.
+type BootItem struct {
+	Type  BootServerType
+	Layer uint16
+}
+
+// ToBytes returns a serialized stream of bytes for this option.
+func (b BootItem) ToBytes() []byte {
+	buf := uio.NewBigEndianBuffer(nil)
+	buf.Write16(uint16(b.Type))
+	buf.Write16(b.Layer)
+	return buf.Data()
+}
+
+// String returns a human-readable string for this option.
+func (b BootItem) String() string {
+	return fmt.Sprintf("%s, layer %d", b.Type, b.Layer)
+}
+
+// FromBytes parses data into b.
+func (b *BootItem) FromBytes(data []byte) error {
+	buf := uio.NewBigEndianBuffer(data)
+	b.Type = BootServerType(buf.Read16())
+	b.Layer = buf.Read16()
+	return buf.FinError()
+}
+
+// OptBootItem returns a new PXE boot item option.
+func OptBootItem(t BootServerType, layer uint16) dhcpv4.Option {
+	return dhcpv4.Option{Code: OptionBootItem, Value: BootItem{Type: t, Layer: layer}}
+}
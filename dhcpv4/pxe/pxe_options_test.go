@@ -0,0 +1,95 @@
+package pxe
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptDiscoveryControl(t *testing.T) {
+	o := OptDiscoveryControl(DisableMulticastDiscovery | UseBootFile)
+	require.Equal(t, OptionDiscoveryControl, o.Code)
+	require.Equal(t, []byte{0x0a}, o.Value.ToBytes())
+	require.Equal(t, "PXE Discovery Control: 0x0a (no multicast, use boot file)", o.String())
+
+	var c DiscoveryControl
+	require.NoError(t, c.FromBytes([]byte{7}))
+	require.Equal(t, DisableBroadcastDiscovery|DisableMulticastDiscovery|OnlyListedBootServers, c)
+	require.Error(t, c.FromBytes([]byte{}))
+}
+
+func TestOptBootServers(t *testing.T) {
+	o := OptBootServers(
+		BootServer{Type: BootServerBootstrap, IPs: []net.IP{net.IPv4(10, 0, 0, 1), net.IPv4(10, 0, 0, 2)}},
+		BootServer{Type: 7, IPs: []net.IP{net.IPv4(10, 0, 0, 3)}},
+	)
+	want := []byte{
+		0, 0, 2, 10, 0, 0, 1, 10, 0, 0, 2,
+		0, 7, 1, 10, 0, 0, 3,
+	}
+	require.Equal(t, OptionBootServers, o.Code)
+	require.Equal(t, want, o.Value.ToBytes())
+	require.Equal(t, "PXE Boot Servers: PXE bootstrap server: 10.0.0.1, 10.0.0.2; type 7: 10.0.0.3", o.String())
+
+	var b BootServers
+	require.NoError(t, b.FromBytes(want))
+	require.Equal(t, BootServers{
+		{Type: BootServerBootstrap, IPs: []net.IP{{10, 0, 0, 1}, {10, 0, 0, 2}}},
+		{Type: 7, IPs: []net.IP{{10, 0, 0, 3}}},
+	}, b)
+
+	// Truncated.
+	b = nil
+	require.Error(t, b.FromBytes(want[:9]))
+}
+
+func TestOptBootMenu(t *testing.T) {
+	o := OptBootMenu(
+		MenuItem{Type: BootServerBootstrap, Description: "Local"},
+		MenuItem{Type: 0x8000, Description: "Install"},
+	)
+	want := []byte{
+		0, 0, 5, 'L', 'o', 'c', 'a', 'l',
+		0x80, 0, 7, 'I', 'n', 's', 't', 'a', 'l', 'l',
+	}
+	require.Equal(t, OptionBootMenu, o.Code)
+	require.Equal(t, want, o.Value.ToBytes())
+	require.Equal(t, "PXE Boot Menu: 0:'Local', 32768:'Install'", o.String())
+
+	var m BootMenu
+	require.NoError(t, m.FromBytes(want))
+	require.Equal(t, BootMenu{{Type: 0, Description: "Local"}, {Type: 0x8000, Description: "Install"}}, m)
+
+	// Truncated.
+	m = nil
+	require.Error(t, m.FromBytes(want[:5]))
+}
+
+func TestOptMenuPrompt(t *testing.T) {
+	o := OptMenuPrompt("Press F8", 10*time.Second)
+	require.Equal(t, OptionMenuPrompt, o.Code)
+	require.Equal(t, []byte{10, 'P', 'r', 'e', 's', 's', ' ', 'F', '8'}, o.Value.ToBytes())
+	require.Equal(t, "PXE Menu Prompt: 'Press F8' (timeout 10s)", o.String())
+
+	o = OptMenuPrompt("", time.Hour)
+	require.Equal(t, []byte{255}, o.Value.ToBytes())
+
+	var p MenuPrompt
+	require.NoError(t, p.FromBytes([]byte{0, 'g', 'o'}))
+	require.Equal(t, MenuPrompt{Prompt: "go"}, p)
+	require.Error(t, p.FromBytes([]byte{}))
+}
+
+func TestOptBootItem(t *testing.T) {
+	o := OptBootItem(0x8000, 1)
+	require.Equal(t, OptionBootItem, o.Code)
+	require.Equal(t, []byte{0x80, 0, 0, 1}, o.Value.ToBytes())
+	require.Equal(t, "PXE Boot Item: type 32768, layer 1", o.String())
+
+	var b BootItem
+	require.NoError(t, b.FromBytes([]byte{0, 0, 0, 0}))
+	require.Equal(t, BootItem{}, b)
+	require.Error(t, b.FromBytes([]byte{0, 0, 0}))
+}
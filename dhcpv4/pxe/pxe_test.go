@@ -0,0 +1,91 @@
+package pxe
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	serverIP = net.IP{192, 168, 0, 1}
+	uuid     = [16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0xa, 0xb, 0xc, 0xd, 0xe, 0xf}
+)
+
+func newPXERequest(t *testing.T, mt dhcpv4.MessageType, modifiers ...dhcpv4.Modifier) *dhcpv4.DHCPv4 {
+	m, err := dhcpv4.New(dhcpv4.PrependModifiers(modifiers,
+		dhcpv4.WithHwAddr(net.HardwareAddr{1, 2, 3, 4, 5, 6}),
+		dhcpv4.WithMessageType(mt),
+		dhcpv4.WithOption(dhcpv4.OptClassIdentifier("PXEClient:Arch:00000:UNDI:002001")),
+		dhcpv4.WithOption(dhcpv4.OptClientMachineID(uuid)),
+	)...)
+	require.NoError(t, err)
+	return m
+}
+
+func TestIsPXERequest(t *testing.T) {
+	require.True(t, IsPXERequest(newPXERequest(t, dhcpv4.MessageTypeDiscover)))
+
+	m, _ := dhcpv4.New(dhcpv4.WithOption(dhcpv4.OptClassIdentifier("MSFT 5.0")))
+	require.False(t, IsPXERequest(m))
+}
+
+func TestNewProxyReplyBootFile(t *testing.T) {
+	req := newPXERequest(t, dhcpv4.MessageTypeDiscover)
+	reply, err := NewProxyReply(req, ReplyConfig{
+		ServerIP:     serverIP,
+		BootFileName: "pxelinux.0",
+	})
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.OpcodeBootReply, reply.OpCode)
+	require.Equal(t, req.TransactionID, reply.TransactionID)
+	require.Equal(t, dhcpv4.MessageTypeOffer, reply.MessageType())
+	require.True(t, reply.YourIPAddr.IsUnspecified())
+	require.True(t, serverIP.Equal(reply.ServerIPAddr))
+	require.True(t, serverIP.Equal(reply.ServerIdentifier()))
+	require.Equal(t, "pxelinux.0", reply.BootFileName)
+	require.Equal(t, ClassIdentifier, reply.ClassIdentifier())
+	require.Equal(t, &dhcpv4.MachineID{UUID: uuid}, reply.ClientMachineID())
+
+	vo := GetVendorOptions(reply.Options)
+	require.NotNil(t, vo)
+	require.Equal(t, UseBootFile, vo.DiscoveryControl())
+	require.Nil(t, vo.BootMenu())
+}
+
+func TestNewProxyReplyMenu(t *testing.T) {
+	req := newPXERequest(t, dhcpv4.MessageTypeRequest,
+		dhcpv4.WithOption(OptVendorOptions(OptBootItem(0x8000, 0))))
+	reply, err := NewProxyReply(req, ReplyConfig{
+		ServerIP:         serverIP,
+		BootFileName:     "install.0",
+		DiscoveryControl: DisableMulticastDiscovery,
+		BootServers:      []BootServer{{Type: 0x8000, IPs: []net.IP{serverIP}}},
+		Menu:             []MenuItem{{Type: 0x8000, Description: "Install"}},
+		Prompt:           &MenuPrompt{Prompt: "Press F8", Timeout: 5 * time.Second},
+	})
+	require.NoError(t, err)
+	require.Equal(t, dhcpv4.MessageTypeAck, reply.MessageType())
+
+	vo := GetVendorOptions(reply.Options)
+	require.NotNil(t, vo)
+	require.Equal(t, DisableMulticastDiscovery, vo.DiscoveryControl())
+	require.Equal(t, BootMenu{{Type: 0x8000, Description: "Install"}}, vo.BootMenu())
+	require.Equal(t, &MenuPrompt{Prompt: "Press F8", Timeout: 5 * time.Second}, vo.MenuPrompt())
+	require.Equal(t, &BootItem{Type: 0x8000}, vo.BootItem())
+	require.Len(t, vo.BootServers(), 1)
+}
+
+func TestNewProxyReplyErrors(t *testing.T) {
+	m, _ := dhcpv4.New(dhcpv4.WithMessageType(dhcpv4.MessageTypeDiscover))
+	_, err := NewProxyReply(m, ReplyConfig{ServerIP: serverIP})
+	require.Error(t, err, "not a PXE client")
+
+	_, err = NewProxyReply(newPXERequest(t, dhcpv4.MessageTypeDiscover), ReplyConfig{})
+	require.Error(t, err, "no server IP")
+
+	_, err = NewProxyReply(newPXERequest(t, dhcpv4.MessageTypeRelease), ReplyConfig{ServerIP: serverIP})
+	require.Error(t, err, "unexpected message type")
+}
@@ -0,0 +1,76 @@
+package pxe
+
+import (
+	"fmt"
+)
+
+// ClassIdentifier is the prefix of the vendor class identifier (DHCP option
+// 60) sent by PXE clients, and the vendor class identifier of PXE server
+// replies.
+const ClassIdentifier = "PXEClient"
+
+// optionCode are PXE option codes.
+//
+// optionCode implements the dhcpv4.OptionCode interface.
+type optionCode uint8
+
+func (o optionCode) Code() uint8 {
+	return uint8(o)
+}
+
+func (o optionCode) String() string {
+	if s, ok := optionCodeToString[o]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown (%d)", o)
+}
+
+// Options (occur as sub-options of DHCP option 43).
+const (
+	OptionMTFTPIP            optionCode = 1
+	OptionMTFTPClientPort    optionCode = 2
+	OptionMTFTPServerPort    optionCode = 3
+	OptionMTFTPTimeout       optionCode = 4
+	OptionMTFTPDelay         optionCode = 5
+	OptionDiscoveryControl   optionCode = 6
+	OptionDiscoveryMulticast optionCode = 7
+	OptionBootServers        optionCode = 8
+	OptionBootMenu           optionCode = 9
+	OptionMenuPrompt         optionCode = 10
+	OptionMulticastAddrAlloc optionCode = 11
+	OptionCredentialTypes    optionCode = 12
+	OptionBootItem           optionCode = 71
+)
+
+// optionCodeToString maps PXE OptionCodes to human-readable strings
+// describing what they are.
+var optionCodeToString = map[optionCode]string{
+	OptionMTFTPIP:            "PXE MTFTP IP",
+	OptionMTFTPClientPort:    "PXE MTFTP Client Port",
+	OptionMTFTPServerPort:    "PXE MTFTP Server Port",
+	OptionMTFTPTimeout:       "PXE MTFTP Timeout",
+	OptionMTFTPDelay:         "PXE MTFTP Delay",
+	OptionDiscoveryControl:   "PXE Discovery Control",
+	OptionDiscoveryMulticast: "PXE Discovery Multicast Address",
+	OptionBootServers:        "PXE Boot Servers",
+	OptionBootMenu:           "PXE Boot Menu",
+	OptionMenuPrompt:         "PXE Menu Prompt",
+	OptionMulticastAddrAlloc: "PXE Multicast Address Allocation",
+	OptionCredentialTypes:    "PXE Credential Types",
+	OptionBootItem:           "PXE Boot Item",
+}
+
+// BootServerType identifies a kind of boot server, in the boot servers, boot
+// menu and boot item options.
+type BootServerType uint16
+
+// BootServerBootstrap is the PXE bootstrap server type, defined by the PXE
+// specification, Table 2-2.
+const BootServerBootstrap BootServerType = 0
+
+func (t BootServerType) String() string {
+	if t == BootServerBootstrap {
+		return "PXE bootstrap server"
+	}
+	return fmt.Sprintf("type %d", uint16(t))
+}
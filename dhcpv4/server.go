@@ -1,7 +1,6 @@
 package dhcpv4
 
 import (
-	"fmt"
 	"log"
 	"net"
 	"sync"
@@ -94,16 +93,8 @@ func (s *Server) ActivateAndServe() error {
 	}
 	s.conn = conn
 	s.connMutex.Unlock()
-	var (
-		pc *net.UDPConn
-		ok bool
-	)
-	if pc, ok = s.conn.(*net.UDPConn); !ok {
-		return fmt.Errorf("error: not an UDPConn")
-	}
-	if pc == nil {
-		return fmt.Errorf("ActivateAndServe: invalid nil PacketConn")
-	}
+	// s.conn is reset by Close, so only use the local connection from now on
+	pc := conn
 	log.Printf("Server listening on %s", pc.LocalAddr())
 	log.Print("Ready to handle requests")
 	for {
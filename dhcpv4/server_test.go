@@ -3,6 +3,7 @@
 package dhcpv4
 
 import (
+	"encoding/binary"
 	"log"
 	"math/rand"
 	"net"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/insomniacslk/dhcp/interfaces"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
 )
 
 func init() {
@@ -124,3 +126,95 @@ func TestServerActivateAndServe(t *testing.T) {
 		require.Equal(t, hwaddr, p.ClientHWAddr)
 	}
 }
+
+func TestServerAuthentication(t *testing.T) {
+	key := []byte("secret")
+	c, s := setUpClientAndServer(WithAuthentication(NewDelayedAuth(1, key), DORAHandler))
+	defer s.Close()
+	c.Authenticator = NewDelayedAuth(1, key)
+
+	ifaces, err := interfaces.GetLoopbackInterfaces()
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(ifaces))
+
+	conv, err := c.Exchange(ifaces[0].Name)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(conv))
+	for _, p := range conv {
+		require.NotNil(t, p.Authentication())
+	}
+}
+
+func TestSendReceiveAllSkipsMalformedReplies(t *testing.T) {
+	c, s := setUpClientAndServer(func(conn net.PacketConn, peer net.Addr, m *DHCPv4) {
+		if _, err := conn.WriteTo([]byte{1, 2, 3}, peer); err != nil {
+			log.Printf("Cannot reply to client: %v", err)
+		}
+		DORAHandler(conn, peer, m)
+	})
+	defer s.Close()
+	c.ReadTimeout = 500 * time.Millisecond
+
+	ifaces, err := interfaces.GetLoopbackInterfaces()
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(ifaces))
+	sfd, err := makeRawSocket(ifaces[0].Name)
+	require.NoError(t, err)
+	defer unix.Close(sfd)
+	rfd, err := makeListeningSocketWithCustomPort(ifaces[0].Name, c.LocalAddr.(*net.UDPAddr).Port)
+	require.NoError(t, err)
+	defer unix.Close(rfd)
+
+	discover, err := NewDiscovery(net.HardwareAddr{0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	offers, err := c.SendReceiveAll(sfd, rfd, discover, MessageTypeOffer)
+	require.NoError(t, err)
+	require.Len(t, offers, 1)
+	require.Equal(t, discover.TransactionID, offers[0].TransactionID)
+}
+
+func TestSendReceiveSkipsInvalidUDPLength(t *testing.T) {
+	ifaces, err := interfaces.GetLoopbackInterfaces()
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(ifaces))
+
+	c, s := setUpClientAndServer(func(conn net.PacketConn, peer net.Addr, m *DHCPv4) {
+		fd, err := makeRawSocket(ifaces[0].Name)
+		if err != nil {
+			log.Printf("Cannot create raw socket: %v", err)
+			return
+		}
+		defer unix.Close(fd)
+		src := *conn.LocalAddr().(*net.UDPAddr)
+		dst := *peer.(*net.UDPAddr)
+		// UDP lengths shorter than the header and longer than the packet
+		for _, l := range []uint16{4, 1000} {
+			p, err := MakeRawUDPPacket([]byte{1, 2, 3}, dst, src)
+			if err != nil {
+				log.Printf("Cannot make raw packet: %v", err)
+				return
+			}
+			binary.BigEndian.PutUint16(p[24:26], l)
+			var addr unix.SockaddrInet4
+			copy(addr.Addr[:], dst.IP.To4())
+			if err := unix.Sendto(fd, p, 0, &addr); err != nil {
+				log.Printf("Cannot send raw packet: %v", err)
+			}
+		}
+		DORAHandler(conn, peer, m)
+	})
+	defer s.Close()
+
+	sfd, err := makeRawSocket(ifaces[0].Name)
+	require.NoError(t, err)
+	defer unix.Close(sfd)
+	rfd, err := makeListeningSocketWithCustomPort(ifaces[0].Name, c.LocalAddr.(*net.UDPAddr).Port)
+	require.NoError(t, err)
+	defer unix.Close(rfd)
+
+	discover, err := NewDiscovery(net.HardwareAddr{0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	offer, err := c.SendReceive(sfd, rfd, discover, MessageTypeOffer)
+	require.NoError(t, err)
+	require.Equal(t, discover.TransactionID, offer.TransactionID)
+}
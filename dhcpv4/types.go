@@ -36,6 +36,16 @@ const (
 	MessageTypeNak      MessageType = 6
 	MessageTypeRelease  MessageType = 7
 	MessageTypeInform   MessageType = 8
+
+	// RFC 4388
+	MessageTypeLeaseQuery      MessageType = 10
+	MessageTypeLeaseUnassigned MessageType = 11
+	MessageTypeLeaseUnknown    MessageType = 12
+	MessageTypeLeaseActive     MessageType = 13
+
+	// RFC 6926
+	MessageTypeBulkLeaseQuery MessageType = 14
+	MessageTypeLeaseQueryDone MessageType = 15
 )
 
 // ToBytes returns the serialized version of this option described by RFC 2132,
@@ -69,6 +79,13 @@ var messageTypeToString = map[MessageType]string{
 	MessageTypeNak:      "NAK",
 	MessageTypeRelease:  "RELEASE",
 	MessageTypeInform:   "INFORM",
+
+	MessageTypeLeaseQuery:      "LEASEQUERY",
+	MessageTypeLeaseUnassigned: "LEASEUNASSIGNED",
+	MessageTypeLeaseUnknown:    "LEASEUNKNOWN",
+	MessageTypeLeaseActive:     "LEASEACTIVE",
+	MessageTypeBulkLeaseQuery:  "BULKLEASEQUERY",
+	MessageTypeLeaseQueryDone:  "LEASEQUERYDONE",
 }
 
 // OpcodeType represents a DHCPv4 opcode.
@@ -241,7 +258,9 @@ const (
 	// Options 102-111 returned in RFC 3679
 	OptionNetInfoParentServerAddress optionCode = 112
 	OptionNetInfoParentServerTag     optionCode = 113
-	OptionURL                        optionCode = 114
+	OptionCaptivePortal              optionCode = 114
+	// Deprecated: use OptionCaptivePortal, which RFC 8910 assigned code 114.
+	OptionURL = OptionCaptivePortal
 	// Option 115 returned in RFC 3679
 	OptionAutoConfigure                   optionCode = 116
 	OptionNameServiceSearch               optionCode = 117
@@ -404,7 +423,7 @@ var optionCodeToString = map[OptionCode]string{
 	// Options 102-111 returned in RFC 3679
 	OptionNetInfoParentServerAddress: "NetInfo Parent Server Address",
 	OptionNetInfoParentServerTag:     "NetInfo Parent Server Tag",
-	OptionURL:                        "URL",
+	OptionCaptivePortal:              "Captive Portal",
 	// Option 115 returned in RFC 3679
 	OptionAutoConfigure:                   "Auto-Configure",
 	OptionNameServiceSearch:               "Name Service Search",
@@ -440,7 +459,7 @@ var optionCodeToString = map[OptionCode]string{
 	OptionStartTimeOfState:  "Start Time of State",
 	OptionQueryStartTime:    "Query Start Time",
 	OptionQueryEndTime:      "Query End Time",
-	OptionDHCPState:         "DHCP Staet",
+	OptionDHCPState:         "DHCP State",
 	OptionDataSource:        "Data Source",
 	// Options 158-174 returned in RFC 3679
 	OptionEtherboot:                        "Etherboot",
@@ -2,6 +2,7 @@ package async
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
@@ -11,7 +12,17 @@ import (
 	"github.com/insomniacslk/dhcp/dhcpv6"
 )
 
+// ErrNoResponse is returned by SendContext when no response arrives within
+// ReadTimeout.
+var ErrNoResponse = errors.New("no response received")
+
 // Client implements an asynchronous DHCPv6 client
+//
+// A single receiver loop reads all the incoming packets and dispatches them
+// to the pending transactions by transaction ID, so any number of exchanges
+// can be in flight at the same time. A transaction stays pending until its
+// context is done, or for ReadTimeout after its packet is sent, and gets all
+// the replies received in the meantime.
 type Client struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
@@ -19,14 +30,86 @@ type Client struct {
 	RemoteAddr   net.Addr
 	IgnoreErrors bool
 
-	connection   *net.UDPConn
-	cancel       context.CancelFunc
-	stopping     *sync.WaitGroup
-	receiveQueue chan dhcpv6.DHCPv6
-	sendQueue    chan dhcpv6.DHCPv6
-	packetsLock  sync.Mutex
-	packets      map[uint32]*promise.Promise
-	errors       chan error
+	// ReadBufferSize, if not zero, sets the size of the socket receive
+	// buffer. Clients with many exchanges in flight need a large one to
+	// absorb bursts of replies.
+	ReadBufferSize int
+
+	// Unsolicited, if set, is called from the receiver loop with the
+	// packets that match no pending transaction, e.g. replies that arrive
+	// after their transaction expired.
+	Unsolicited func(dhcpv6.DHCPv6)
+
+	connection  *net.UDPConn
+	cancel      context.CancelFunc
+	done        <-chan struct{}
+	stopping    *sync.WaitGroup
+	sendQueue   chan dhcpv6.DHCPv6
+	packetsLock sync.Mutex
+	packets     map[uint32]*transaction
+	errors      chan error
+}
+
+// Reply is a response received by SendAll, or the error that ended the
+// exchange.
+type Reply struct {
+	Packet dhcpv6.DHCPv6
+	Err    error
+}
+
+// replyQueueSize is the number of replies a transaction holds for a slow
+// reader before dropping new ones.
+const replyQueueSize = 16
+
+// transaction is a pending exchange. Its replies are queued until it ends.
+type transaction struct {
+	replies chan Reply
+	done    chan struct{}
+	mu      sync.Mutex
+	ended   bool
+	timer   *time.Timer
+}
+
+func newTransaction() *transaction {
+	return &transaction{
+		replies: make(chan Reply, replyQueueSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// deliver queues r. It never blocks the receiver loop: r is dropped if the
+// queue is full.
+func (t *transaction) deliver(r Reply) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ended {
+		return
+	}
+	select {
+	case t.replies <- r:
+	default:
+	}
+}
+
+// end closes the reply queue, after queueing err if not nil.
+func (t *transaction) end(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ended {
+		return
+	}
+	if err != nil {
+		select {
+		case t.replies <- Reply{Err: err}:
+		default:
+		}
+	}
+	t.ended = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	close(t.replies)
+	close(t.done)
 }
 
 // NewClient creates an asynchronous client
@@ -66,15 +149,22 @@ func (c *Client) Open(bufferSize int) error {
 	if err != nil {
 		return err
 	}
+	if c.ReadBufferSize > 0 {
+		if err := c.connection.SetReadBuffer(c.ReadBufferSize); err != nil {
+			c.connection.Close()
+			return err
+		}
+	}
 	c.stopping = new(sync.WaitGroup)
 	c.sendQueue = make(chan dhcpv6.DHCPv6, bufferSize)
-	c.receiveQueue = make(chan dhcpv6.DHCPv6, bufferSize)
-	c.packets = make(map[uint32]*promise.Promise)
+	c.packets = make(map[uint32]*transaction)
 	c.packetsLock = sync.Mutex{}
 	c.errors = make(chan error)
 
 	var ctx context.Context
 	ctx, c.cancel = context.WithCancel(context.Background())
+	c.done = ctx.Done()
+	c.stopping.Add(2)
 	go c.receiverLoop(ctx)
 	go c.senderLoop(ctx)
 
@@ -83,16 +173,20 @@ func (c *Client) Open(bufferSize int) error {
 
 // Close stops the client
 func (c *Client) Close() {
-	// Wait for sender and receiver loops
-	c.stopping.Add(2)
+	// Closing the connection unblocks the receiver loop.
 	c.cancel()
+	c.connection.Close()
 	c.stopping.Wait()
 
-	close(c.sendQueue)
-	close(c.receiveQueue)
 	close(c.errors)
 
-	c.connection.Close()
+	c.packetsLock.Lock()
+	packets := c.packets
+	c.packets = make(map[uint32]*transaction)
+	c.packetsLock.Unlock()
+	for _, t := range packets {
+		t.end(nil)
+	}
 }
 
 // Errors returns a channel where runtime errors are posted
@@ -102,19 +196,35 @@ func (c *Client) Errors() <-chan error {
 
 func (c *Client) addError(err error) {
 	if !c.IgnoreErrors {
-		c.errors <- err
+		select {
+		case c.errors <- err:
+		case <-c.done:
+		}
 	}
 }
 
 func (c *Client) receiverLoop(ctx context.Context) {
 	defer func() { c.stopping.Done() }()
+	oobdata := []byte{}
+	buffer := make([]byte, dhcpv6.MaxUDPReceivedPacketSize)
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		case packet := <-c.receiveQueue:
-			c.receive(packet)
+		n, _, _, _, err := c.connection.ReadMsgUDP(buffer, oobdata)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			c.addError(fmt.Errorf("Error receiving the message: %s", err))
+			continue
 		}
+		// The parsed packet may keep references to its data.
+		received, err := dhcpv6.FromBytes(append([]byte(nil), buffer[:n]...))
+		if err != nil {
+			// skip non-DHCP packets
+			continue
+		}
+		c.dispatch(received)
 	}
 }
 
@@ -136,50 +246,25 @@ func (c *Client) send(packet dhcpv6.DHCPv6) {
 		c.addError(fmt.Errorf("Warning: This should never happen, there is no transaction ID on %s", packet))
 		return
 	}
-	c.packetsLock.Lock()
-	p := c.packets[transactionID]
-	c.packetsLock.Unlock()
 
 	raddr, err := c.remoteAddr()
 	if err != nil {
-		p.Reject(err)
+		c.failTransaction(transactionID, err)
 		return
 	}
 
 	c.connection.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
 	_, err = c.connection.WriteTo(packet.ToBytes(), raddr)
 	if err != nil {
-		p.Reject(err)
+		c.failTransaction(transactionID, err)
 		return
 	}
 
-	c.receiveQueue <- packet
+	c.startTransaction(transactionID)
 }
 
-func (c *Client) receive(_ dhcpv6.DHCPv6) {
-	var (
-		oobdata  = []byte{}
-		received dhcpv6.DHCPv6
-	)
-
-	c.connection.SetReadDeadline(time.Now().Add(c.ReadTimeout))
-	for {
-		buffer := make([]byte, dhcpv6.MaxUDPReceivedPacketSize)
-		n, _, _, _, err := c.connection.ReadMsgUDP(buffer, oobdata)
-		if err != nil {
-			if err, ok := err.(net.Error); !ok || !err.Timeout() {
-				c.addError(fmt.Errorf("Error receiving the message: %s", err))
-			}
-			return
-		}
-		received, err = dhcpv6.FromBytes(buffer[:n])
-		if err != nil {
-			// skip non-DHCP packets
-			continue
-		}
-		break
-	}
-
+// dispatch hands received over to its pending transaction, if any.
+func (c *Client) dispatch(received dhcpv6.DHCPv6) {
 	transactionID, err := dhcpv6.GetTransactionID(received)
 	if err != nil {
 		c.addError(fmt.Errorf("Unable to get a transactionID for %s: %s", received, err))
@@ -187,11 +272,81 @@ func (c *Client) receive(_ dhcpv6.DHCPv6) {
 	}
 
 	c.packetsLock.Lock()
-	if p, ok := c.packets[transactionID]; ok {
-		delete(c.packets, transactionID)
-		p.Resolve(received)
+	t, ok := c.packets[transactionID]
+	c.packetsLock.Unlock()
+	if ok {
+		t.deliver(Reply{Packet: received})
+	} else if c.Unsolicited != nil {
+		c.Unsolicited(received)
+	}
+}
+
+func (c *Client) addTransaction(id uint32, t *transaction) {
+	c.packetsLock.Lock()
+	old, ok := c.packets[id]
+	c.packets[id] = t
+	c.packetsLock.Unlock()
+	if ok {
+		old.end(nil)
+	}
+}
+
+// startTransaction expires the transaction id ReadTimeout from now.
+func (c *Client) startTransaction(id uint32) {
+	c.packetsLock.Lock()
+	t, ok := c.packets[id]
+	c.packetsLock.Unlock()
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.ended {
+		t.timer = time.AfterFunc(c.ReadTimeout, func() {
+			c.endTransaction(id, t, nil)
+		})
+	}
+}
+
+// endTransaction ends t, and removes it if it is still the transaction
+// pending for id.
+func (c *Client) endTransaction(id uint32, t *transaction, err error) {
+	c.packetsLock.Lock()
+	if c.packets[id] == t {
+		delete(c.packets, id)
 	}
 	c.packetsLock.Unlock()
+	t.end(err)
+}
+
+func (c *Client) failTransaction(id uint32, err error) {
+	c.packetsLock.Lock()
+	t, ok := c.packets[id]
+	c.packetsLock.Unlock()
+	if ok {
+		c.endTransaction(id, t, err)
+	}
+}
+
+// start queues message for sending in a new transaction with the given ID,
+// which ends when ctx is done.
+func (c *Client) start(ctx context.Context, id uint32, message dhcpv6.DHCPv6) *transaction {
+	t := newTransaction()
+	c.addTransaction(id, t)
+	select {
+	case c.sendQueue <- message:
+	case <-ctx.Done():
+		c.endTransaction(id, t, nil)
+		return t
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.endTransaction(id, t, nil)
+		case <-t.done:
+		}
+	}()
+	return t
 }
 
 func (c *Client) remoteAddr() (*net.UDPAddr, error) {
@@ -205,8 +360,49 @@ func (c *Client) remoteAddr() (*net.UDPAddr, error) {
 	return nil, fmt.Errorf("Invalid remote address: %v not a net.UDPAddr", c.RemoteAddr)
 }
 
+// SendAll sends message asynchronously, and returns a channel with all the
+// responses to it, e.g. the ADVERTISEs of all servers to a SOLICIT. The
+// channel is closed when ctx is done or ReadTimeout after message is sent,
+// whichever comes first. If message cannot be sent, the channel gets a Reply
+// with the error. Replies that the caller does not read in time are dropped.
+func (c *Client) SendAll(ctx context.Context, message dhcpv6.DHCPv6, modifiers ...dhcpv6.Modifier) <-chan Reply {
+	for _, mod := range modifiers {
+		message = mod(message)
+	}
+
+	transactionID, err := dhcpv6.GetTransactionID(message)
+	if err != nil {
+		replies := make(chan Reply, 1)
+		replies <- Reply{Err: err}
+		close(replies)
+		return replies
+	}
+	return c.start(ctx, transactionID, message).replies
+}
+
+// SendContext sends message and waits for the first response to it. It
+// returns ctx.Err() if ctx is done first, and ErrNoResponse if no response
+// arrives within ReadTimeout.
+func (c *Client) SendContext(ctx context.Context, message dhcpv6.DHCPv6, modifiers ...dhcpv6.Modifier) (dhcpv6.DHCPv6, error) {
+	sendCtx, cancel := context.WithCancel(ctx)
+	// Later responses are not needed.
+	defer cancel()
+	r, ok := <-c.SendAll(sendCtx, message, modifiers...)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoResponse
+	}
+	return r.Packet, r.Err
+}
+
 // Send inserts a message to the queue to be sent asynchronously.
-// Returns a future which resolves to response and error.
+// Returns a future which resolves to the first response and error, e.g. the
+// first ADVERTISE to a SOLICIT. If no response arrives within ReadTimeout,
+// the future is never resolved.
+//
+// Send is an adapter to SendContext for callers of the future-based API.
 func (c *Client) Send(message dhcpv6.DHCPv6, modifiers ...dhcpv6.Modifier) *promise.Future {
 	for _, mod := range modifiers {
 		message = mod(message)
@@ -218,9 +414,20 @@ func (c *Client) Send(message dhcpv6.DHCPv6, modifiers ...dhcpv6.Modifier) *prom
 	}
 
 	p := promise.NewPromise()
-	c.packetsLock.Lock()
-	c.packets[transactionID] = p
-	c.packetsLock.Unlock()
-	c.sendQueue <- message
+	ctx, cancel := context.WithCancel(context.Background())
+	// The message is queued now, so that Send keeps the order of messages.
+	replies := c.start(ctx, transactionID, message).replies
+	go func() {
+		defer cancel()
+		r, ok := <-replies
+		if !ok {
+			return
+		}
+		if r.Err != nil {
+			_ = p.Reject(r.Err)
+		} else {
+			_ = p.Resolve(r.Packet)
+		}
+	}()
 	return p.Future
 }
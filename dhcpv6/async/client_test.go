@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fanliao/go-promise"
 	"github.com/insomniacslk/dhcp/dhcpv6"
 	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
@@ -151,3 +152,161 @@ func TestSend(t *testing.T) {
 	}
 	require.True(t, passed, "All attempts to TestSend timed out")
 }
+
+// echo starts a server which answers each request with copies of itself, and
+// returns its address.
+func echo(ctx context.Context, t *testing.T, copies int) *net.UDPAddr {
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadBuffer(1<<22))
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go func() {
+		buffer := make([]byte, dhcpv6.MaxUDPReceivedPacketSize)
+		for {
+			n, src, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			for i := 0; i < copies; i++ {
+				if _, err := conn.WriteTo(buffer[:n], src); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+func openLoopbackClient(t *testing.T, remote *net.UDPAddr) *Client {
+	c := NewClient()
+	c.LocalAddr = &net.UDPAddr{IP: net.IPv6loopback}
+	c.RemoteAddr = remote
+	// absorb the bursts of replies of the echo server
+	c.ReadBufferSize = 1 << 22
+	require.NoError(t, c.Open(16))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestSendConcurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := openLoopbackClient(t, echo(ctx, t, 1))
+
+	const n = 200
+	messages := make([]dhcpv6.DHCPv6, n)
+	futures := make([]*promise.Future, n)
+	for i := range messages {
+		m, err := dhcpv6.NewMessage()
+		require.NoError(t, err)
+		messages[i] = m
+		futures[i] = c.Send(m)
+	}
+	for i, f := range futures {
+		response, err, timeout := f.GetOrTimeout(2000)
+		require.False(t, timeout)
+		require.NoError(t, err)
+		require.Equal(t, messages[i].ToBytes(), response.(dhcpv6.DHCPv6).ToBytes())
+	}
+}
+
+func TestSendSeveralReplies(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := openLoopbackClient(t, echo(ctx, t, 3))
+	c.ReadTimeout = 200 * time.Millisecond
+	unsolicited := make(chan dhcpv6.DHCPv6, 10)
+	c.Unsolicited = func(m dhcpv6.DHCPv6) { unsolicited <- m }
+
+	m, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	var replies []dhcpv6.DHCPv6
+	for r := range c.SendAll(context.Background(), m) {
+		require.NoError(t, r.Err)
+		replies = append(replies, r.Packet)
+	}
+	// e.g. the ADVERTISEs of several servers, until the transaction
+	// expires after ReadTimeout
+	require.Len(t, replies, 3)
+	for _, reply := range replies {
+		require.Equal(t, m.ToBytes(), reply.ToBytes())
+	}
+	requireNoTransactions(t, c)
+
+	// late replies are unsolicited
+	c.sendQueue <- m
+	select {
+	case reply := <-unsolicited:
+		require.Equal(t, m.ToBytes(), reply.ToBytes())
+	case <-time.After(time.Second):
+		t.Fatal("no unsolicited reply")
+	}
+}
+
+func requireNoTransactions(t *testing.T, c *Client) {
+	require.Eventually(t, func() bool {
+		c.packetsLock.Lock()
+		defer c.packetsLock.Unlock()
+		return len(c.packets) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSendContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := openLoopbackClient(t, echo(ctx, t, 1))
+
+	m, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	reply, err := c.SendContext(context.Background(), m)
+	require.NoError(t, err)
+	require.Equal(t, m.ToBytes(), reply.ToBytes())
+	requireNoTransactions(t, c)
+}
+
+func TestSendContextNoResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// nobody answers on the remote address
+	c := openLoopbackClient(t, echo(ctx, t, 0))
+	c.ReadTimeout = 50 * time.Millisecond
+
+	m, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	_, err = c.SendContext(context.Background(), m)
+	require.Equal(t, ErrNoResponse, err)
+	requireNoTransactions(t, c)
+}
+
+func TestSendContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := openLoopbackClient(t, echo(ctx, t, 0))
+
+	m, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	sendCtx, sendCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer sendCancel()
+	_, err = c.SendContext(sendCtx, m)
+	require.Equal(t, context.DeadlineExceeded, err)
+	// the pending transaction is removed
+	requireNoTransactions(t, c)
+}
+
+func TestSendAllError(t *testing.T) {
+	c := openLoopbackClient(t, nil)
+	// not a net.UDPAddr
+	c.RemoteAddr = &net.IPAddr{IP: net.IPv6loopback}
+
+	m, err := dhcpv6.NewMessage()
+	require.NoError(t, err)
+	replies := c.SendAll(context.Background(), m)
+	r, ok := <-replies
+	require.True(t, ok)
+	require.Error(t, r.Err)
+	_, ok = <-replies
+	require.False(t, ok)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net"
 	"time"
+
+	"github.com/insomniacslk/dhcp/iana"
 )
 
 // Client constants
@@ -12,6 +14,7 @@ const (
 	DefaultWriteTimeout       = 3 * time.Second // time to wait for write calls
 	DefaultReadTimeout        = 3 * time.Second // time to wait for read calls
 	DefaultInterfaceUpTimeout = 3 * time.Second // time to wait before a network interface goes up
+	DefaultAdvertiseTimeout   = 1 * time.Second // suggested time to collect advertisements, the initial SOL_TIMEOUT of RFC 3315
 	MaxUDPReceivedPacketSize  = 8192            // arbitrary size. Theoretically could be up to 65kb
 )
 
@@ -27,6 +30,12 @@ type Client struct {
 	WriteTimeout time.Duration
 	LocalAddr    net.Addr
 	RemoteAddr   net.Addr
+	// AdvertiseTimeout is how long Solicit collects Advertise messages before
+	// selecting the one with the highest preference. An Advertise with the
+	// maximum preference is selected as soon as it arrives. If zero, the
+	// default, the first Advertise is selected; set it to e.g.
+	// DefaultAdvertiseTimeout to choose among several servers.
+	AdvertiseTimeout time.Duration
 }
 
 // NewClient returns a Client with default settings
@@ -75,6 +84,12 @@ func (c *Client) Exchange(ifname string, modifiers ...Modifier) ([]DHCPv6, error
 }
 
 func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageType) (DHCPv6, error) {
+	return c.sendReceiveTo(ifname, packet, expectedType, nil)
+}
+
+// sendReceiveTo is like sendReceive, but sends the packet to the given server
+// address, if not nil, instead of the client's RemoteAddr.
+func (c *Client) sendReceiveTo(ifname string, packet DHCPv6, expectedType MessageType, server *net.UDPAddr) (DHCPv6, error) {
 	if packet == nil {
 		return nil, fmt.Errorf("Packet to send cannot be nil")
 	}
@@ -88,12 +103,21 @@ func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageT
 			expectedType = MessageTypeRelayReply
 		} else if packet.Type() == MessageTypeLeaseQuery {
 			expectedType = MessageTypeLeaseQueryReply
+		} else if packet.Type() == MessageTypeRenew ||
+			packet.Type() == MessageTypeRebind ||
+			packet.Type() == MessageTypeInformationRequest {
+			expectedType = MessageTypeReply
 		} // and probably more
 	}
-	// if no LocalAddr is specified, get the interface's link-local address
+	// if no LocalAddr is specified, get the interface's link-local address, or
+	// a global one to reach a server directly
 	var laddr net.UDPAddr
 	if c.LocalAddr == nil {
-		llAddr, err := GetLinkLocalAddr(ifname)
+		getAddr := GetLinkLocalAddr
+		if server != nil && !server.IP.IsLinkLocalUnicast() {
+			getAddr = GetGlobalAddr
+		}
+		llAddr, err := getAddr(ifname)
 		if err != nil {
 			return nil, err
 		}
@@ -108,7 +132,9 @@ func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageT
 
 	// if no RemoteAddr is specified, use AllDHCPRelayAgentsAndServers
 	var raddr net.UDPAddr
-	if c.RemoteAddr == nil {
+	if server != nil {
+		raddr = *server
+	} else if c.RemoteAddr == nil {
 		raddr = net.UDPAddr{IP: AllDHCPRelayAgentsAndServers, Port: DefaultServerPort}
 	} else {
 		if addr, ok := c.RemoteAddr.(*net.UDPAddr); ok {
@@ -144,13 +170,45 @@ func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageT
 	}
 
 	// wait for a reply
+	readDeadline := time.Now().Add(c.ReadTimeout)
+	conn.SetReadDeadline(readDeadline)
+	defer conn.Close()
+	adv, err := receive(conn, packet, expectedType)
+	if err != nil {
+		return nil, err
+	}
+	if packet.Type() != MessageTypeSolicit || adv.Type() != MessageTypeAdvertise || c.AdvertiseTimeout <= 0 {
+		return adv, nil
+	}
+
+	// collect advertisements and select the most preferred one, as described
+	// in RFC 3315, Section 17.1.2
+	collectDeadline := time.Now().Add(c.AdvertiseTimeout)
+	if collectDeadline.After(readDeadline) {
+		collectDeadline = readDeadline
+	}
+	conn.SetReadDeadline(collectDeadline)
+	for preference(adv) < MaxPreference {
+		next, err := receive(conn, packet, MessageTypeAdvertise)
+		if err != nil {
+			// the collection time expired
+			break
+		}
+		if preference(next) > preference(adv) {
+			adv = next
+		}
+	}
+	return adv, nil
+}
+
+// receive reads from conn until it gets a response to packet of the expected
+// type, or any response if expectedType is MessageTypeNone.
+func receive(conn *net.UDPConn, packet DHCPv6, expectedType MessageType) (DHCPv6, error) {
 	oobdata := []byte{} // ignoring oob data
-	conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
 	var (
 		adv       DHCPv6
 		isMessage bool
 	)
-	defer conn.Close()
 	msg, ok := packet.(*DHCPv6Message)
 	if ok {
 		isMessage = true
@@ -185,9 +243,65 @@ func (c *Client) sendReceive(ifname string, packet DHCPv6, expectedType MessageT
 	return adv, nil
 }
 
+// preference returns the server preference of an Advertise, looking into
+// relay messages if needed.
+func preference(adv DHCPv6) uint8 {
+	if relay, ok := adv.(*DHCPv6Relay); ok {
+		inner, err := relay.GetInnerMessage()
+		if err != nil {
+			return 0
+		}
+		adv = inner
+	}
+	if msg, ok := adv.(*DHCPv6Message); ok {
+		return msg.Preference()
+	}
+	return 0
+}
+
+// serverUnicastAddr returns the address to send messages to when the server
+// allowed it with a Server Unicast option in msg, and the client has no
+// RemoteAddr configured. It returns nil otherwise.
+func (c *Client) serverUnicastAddr(msg DHCPv6) *net.UDPAddr {
+	if c.RemoteAddr != nil {
+		return nil
+	}
+	m, ok := msg.(*DHCPv6Message)
+	if !ok {
+		return nil
+	}
+	ip := m.ServerUnicast()
+	if ip == nil {
+		return nil
+	}
+	return &net.UDPAddr{IP: ip, Port: DefaultServerPort}
+}
+
+// sendReceiveUnicast sends packet directly to the server if msg, the last
+// message received from it, allowed so with a Server Unicast option. If the
+// server answers with a UseMulticast status, or the unicast exchange fails,
+// the packet is sent again to the multicast address.
+func (c *Client) sendReceiveUnicast(ifname string, packet, msg DHCPv6) (DHCPv6, error) {
+	if server := c.serverUnicastAddr(msg); server != nil {
+		reply, err := c.sendReceiveTo(ifname, packet, MessageTypeNone, server)
+		if err == nil && !isUseMulticast(reply) {
+			return reply, nil
+		}
+	}
+	return c.sendReceive(ifname, packet, MessageTypeNone)
+}
+
+// isUseMulticast returns true if reply carries a UseMulticast status code.
+func isUseMulticast(reply DHCPv6) bool {
+	sc, ok := reply.GetOneOption(OptionStatusCode).(*OptStatusCode)
+	return ok && sc.StatusCode == iana.StatusUseMulticast
+}
+
 // Solicit sends a Solicit, returns the Solicit, an Advertise (if not nil), and
 // an error if any. The modifiers will be applied to the Solicit before sending
-// it, see modifiers.go
+// it, see modifiers.go. The first Advertise received is returned, unless
+// AdvertiseTimeout is set: then the Advertise with the highest preference
+// received within it is returned.
 func (c *Client) Solicit(ifname string, modifiers ...Modifier) (DHCPv6, DHCPv6, error) {
 	solicit, err := NewSolicitForInterface(ifname)
 	if err != nil {
@@ -200,9 +314,23 @@ func (c *Client) Solicit(ifname string, modifiers ...Modifier) (DHCPv6, DHCPv6,
 	return solicit, advertise, err
 }
 
+// InformationRequest sends an Information-Request, to get configuration
+// parameters without addresses. It returns the Information-Request, a Reply
+// (if not nil), and an error if any. The modifiers will be applied to the
+// Information-Request before sending it, see modifiers.go.
+func (c *Client) InformationRequest(ifname string, modifiers ...Modifier) (DHCPv6, DHCPv6, error) {
+	request, err := NewInformationRequestForInterface(ifname, modifiers...)
+	if err != nil {
+		return nil, nil, err
+	}
+	reply, err := c.sendReceive(ifname, request, MessageTypeNone)
+	return request, reply, err
+}
+
 // Request sends a Request built from an Advertise. It returns the Request, a
 // Reply (if not nil), and an error if any. The modifiers will be applied to
-// the Request before sending it, see modifiers.go
+// the Request before sending it, see modifiers.go. If the Advertise carries a
+// Server Unicast option, the Request is sent directly to the server.
 func (c *Client) Request(ifname string, advertise DHCPv6, modifiers ...Modifier) (DHCPv6, DHCPv6, error) {
 	request, err := NewRequestFromAdvertise(advertise)
 	if err != nil {
@@ -211,6 +339,83 @@ func (c *Client) Request(ifname string, advertise DHCPv6, modifiers ...Modifier)
 	for _, mod := range modifiers {
 		request = mod(request)
 	}
-	reply, err := c.sendReceive(ifname, request, MessageTypeNone)
+	reply, err := c.sendReceiveUnicast(ifname, request, advertise)
 	return request, reply, err
 }
+
+// LeaseQuery sends a LEASEQUERY for the given query, using a DUID-LLT built
+// from the interface's hardware address as requestor identifier. It returns
+// the LEASEQUERY, a LEASEQUERY-REPLY (if not nil), and an error if any. The
+// modifiers will be applied to the LEASEQUERY before sending it.
+func (c *Client) LeaseQuery(ifname string, query *OptLQQuery, modifiers ...Modifier) (DHCPv6, DHCPv6, error) {
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, nil, err
+	}
+	duid := Duid{
+		Type:          DUID_LLT,
+		HwType:        iana.HWTypeEthernet,
+		Time:          GetTime(),
+		LinkLayerAddr: iface.HardwareAddr,
+	}
+	lq, err := NewLeaseQuery(duid, query, modifiers...)
+	if err != nil {
+		return nil, nil, err
+	}
+	reply, err := c.sendReceive(ifname, lq, MessageTypeNone)
+	return lq, reply, err
+}
+
+// Reconfigure handles a RECONFIGURE received from a server. packet is the
+// RECONFIGURE as received, reply is the last REPLY received from that server,
+// which carried the reconfigure key, and lastReplay is the replay detection
+// value of the last authenticated message received from it. If the
+// RECONFIGURE is authentic and comes from the server the client is bound to,
+// Reconfigure sends the RENEW, REBIND or INFORMATION-REQUEST the server asked
+// for. It returns the message sent, a Reply (if not nil), and an error if any.
+// The modifiers will be applied to the message before sending it.
+//
+// On success, the caller should record the replay detection value of the
+// RECONFIGURE as the new lastReplay.
+func (c *Client) Reconfigure(ifname string, packet []byte, reply DHCPv6, lastReplay uint64, modifiers ...Modifier) (DHCPv6, DHCPv6, error) {
+	key, _, err := GetReconfigureKey(reply)
+	if err != nil {
+		return nil, nil, err
+	}
+	msgType, err := VerifyReconfigure(packet, key, lastReplay)
+	if err != nil {
+		return nil, nil, err
+	}
+	reconf, err := FromBytes(packet)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkReconfigureIDs(reconf, reply); err != nil {
+		return nil, nil, err
+	}
+	var msg DHCPv6
+	switch msgType {
+	case MessageTypeRenew:
+		msg, err = NewRenewFromReply(reply, modifiers...)
+	case MessageTypeRebind:
+		msg, err = NewRebindFromReply(reply, modifiers...)
+	default:
+		// an INFORMATION-REQUEST sent in response to a RECONFIGURE names the
+		// server that sent it, see RFC 8415 Section 18.2.10.1
+		sid := reconf.GetOneOption(OptionServerID).(*OptServerId)
+		msg, err = NewInformationRequestFromReply(reply,
+			append([]Modifier{WithServerID(sid.Sid)}, modifiers...)...)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	var newReply DHCPv6
+	if msgType == MessageTypeRenew {
+		// a RENEW may be sent directly to the server, unlike a REBIND or an
+		// INFORMATION-REQUEST
+		newReply, err = c.sendReceiveUnicast(ifname, msg, reply)
+	} else {
+		newReply, err = c.sendReceive(ifname, msg, MessageTypeNone)
+	}
+	return msg, newReply, err
+}
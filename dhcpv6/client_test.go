@@ -1,8 +1,11 @@
 package dhcpv6
 
 import (
+	"net"
 	"testing"
+	"time"
 
+	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,3 +15,125 @@ func TestNewClient(t *testing.T) {
 	require.Equal(t, DefaultReadTimeout, c.ReadTimeout)
 	require.Equal(t, DefaultWriteTimeout, c.WriteTimeout)
 }
+
+// advertiseServer answers a single Solicit with Advertise messages carrying
+// the given preferences, and returns its address.
+func advertiseServer(t *testing.T, prefs ...uint8) *net.UDPAddr {
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, MaxUDPReceivedPacketSize)
+		n, peer, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		solicit, err := FromBytes(buf[:n])
+		if err != nil {
+			return
+		}
+		for _, pref := range prefs {
+			adv, err := NewAdvertiseFromSolicit(solicit, WithPreference(pref))
+			if err != nil {
+				return
+			}
+			conn.WriteTo(adv.ToBytes(), peer)
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+func newLoopbackClient(server *net.UDPAddr) *Client {
+	c := NewClient()
+	c.LocalAddr = &net.UDPAddr{IP: net.IPv6loopback}
+	c.RemoteAddr = server
+	return c
+}
+
+func TestClientSelectsPreferredAdvertise(t *testing.T) {
+	c := newLoopbackClient(advertiseServer(t, 10, 50, 20))
+	c.AdvertiseTimeout = 200 * time.Millisecond
+	solicit, err := NewSolicitWithCID(Duid{Type: DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5}})
+	require.NoError(t, err)
+	adv, err := c.sendReceive("lo", solicit, MessageTypeNone)
+	require.NoError(t, err)
+	require.Equal(t, uint8(50), adv.(*DHCPv6Message).Preference())
+}
+
+func TestClientStopsAtMaxPreference(t *testing.T) {
+	c := newLoopbackClient(advertiseServer(t, 10, MaxPreference, 20))
+	c.AdvertiseTimeout = 5 * time.Second
+	solicit, err := NewSolicitWithCID(Duid{Type: DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5}})
+	require.NoError(t, err)
+	start := time.Now()
+	adv, err := c.sendReceive("lo", solicit, MessageTypeNone)
+	require.NoError(t, err)
+	require.Equal(t, MaxPreference, adv.(*DHCPv6Message).Preference())
+	require.True(t, time.Since(start) < time.Second, "the client waited for the collection time to expire")
+}
+
+func TestClientFirstAdvertiseWithoutTimeout(t *testing.T) {
+	c := newLoopbackClient(advertiseServer(t, 10, 50))
+	// collecting advertisements is opt-in
+	require.Zero(t, c.AdvertiseTimeout)
+	solicit, err := NewSolicitWithCID(Duid{Type: DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5}})
+	require.NoError(t, err)
+	adv, err := c.sendReceive("lo", solicit, MessageTypeNone)
+	require.NoError(t, err)
+	require.Equal(t, uint8(10), adv.(*DHCPv6Message).Preference())
+}
+
+func TestClientServerUnicastAddr(t *testing.T) {
+	c := NewClient()
+	adv := &DHCPv6Message{}
+	adv.SetMessage(MessageTypeAdvertise)
+	require.Nil(t, c.serverUnicastAddr(adv))
+
+	adv.AddOption(&OptUnicast{ServerAddress: net.ParseIP("2001:db8::1")})
+	require.Equal(t, &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: DefaultServerPort}, c.serverUnicastAddr(adv))
+
+	// an explicit remote address takes precedence
+	c.RemoteAddr = &net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: DefaultServerPort}
+	require.Nil(t, c.serverUnicastAddr(adv))
+}
+
+func TestIsUseMulticast(t *testing.T) {
+	reply := &DHCPv6Message{}
+	reply.SetMessage(MessageTypeReply)
+	require.False(t, isUseMulticast(reply))
+	reply.AddOption(&OptStatusCode{StatusCode: iana.StatusUseMulticast})
+	require.True(t, isUseMulticast(reply))
+}
+
+func TestClientInformationRequest(t *testing.T) {
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, MaxUDPReceivedPacketSize)
+		n, peer, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		ir, err := FromBytes(buf[:n])
+		if err != nil {
+			return
+		}
+		reply, err := NewReplyFromDHCPv6Message(ir)
+		if err != nil {
+			return
+		}
+		conn.WriteTo(reply.ToBytes(), peer)
+	}()
+
+	c := newLoopbackClient(conn.LocalAddr().(*net.UDPAddr))
+	ir, reply, err := c.InformationRequest("lo")
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeInformationRequest, ir.Type())
+	require.Equal(t, MessageTypeReply, reply.Type())
+	require.Equal(t, ir.(*DHCPv6Message).TransactionID(), reply.(*DHCPv6Message).TransactionID())
+}
@@ -183,8 +183,14 @@ func DecapsulateRelayIndex(l DHCPv6, index int) (DHCPv6, error) {
 
 // EncapsulateRelay creates a DHCPv6Relay message containing the passed DHCPv6
 // message as payload. The passed message type must be  either RELAY_FORW or
-// RELAY_REPL
-func EncapsulateRelay(d DHCPv6, mType MessageType, linkAddr, peerAddr net.IP) (DHCPv6, error) {
+// RELAY_REPL. The modifiers are applied to the relay message, which lets a
+// relay agent insert its own options.
+//
+// EncapsulateRelay does not insert the client link-layer address option of
+// RFC 6939 by itself, since it does not see the frame the client's message
+// arrived in: a relay agent directly connected to the client has to pass
+// WithClientLinkLayerAddress with the frame's source address.
+func EncapsulateRelay(d DHCPv6, mType MessageType, linkAddr, peerAddr net.IP, modifiers ...Modifier) (DHCPv6, error) {
 	if mType != MessageTypeRelayForward && mType != MessageTypeRelayReply {
 		return nil, fmt.Errorf("Message type must be either RELAY_FORW or RELAY_REPL")
 	}
@@ -201,7 +207,11 @@ func EncapsulateRelay(d DHCPv6, mType MessageType, linkAddr, peerAddr net.IP) (D
 	}
 	orm := OptRelayMsg{relayMessage: d}
 	outer.AddOption(&orm)
-	return &outer, nil
+	var relay DHCPv6 = &outer
+	for _, mod := range modifiers {
+		relay = mod(relay)
+	}
+	return relay, nil
 }
 
 // IsUsingUEFI function takes a DHCPv6 message and returns true if
@@ -108,6 +108,28 @@ func TestNewMessage(t *testing.T) {
 	require.Empty(t, d.(*DHCPv6Message).options)
 }
 
+func TestEncapsulateRelayWithModifiers(t *testing.T) {
+	m := DHCPv6Message{}
+	mac := net.HardwareAddr{0x24, 0x8a, 0x07, 0x56, 0xdc, 0xa4}
+	r, err := EncapsulateRelay(&m, MessageTypeRelayForward, net.IPv6loopback, net.IPv6linklocalallnodes,
+		WithClientLinkLayerAddress(iana.HWTypeEthernet, mac),
+	)
+	require.NoError(t, err)
+	relay := r.(*DHCPv6Relay)
+	require.Equal(t, mac, relay.ClientLinkLayerAddress())
+	require.NotNil(t, relay.GetOneOption(OptionRelayMsg))
+
+	// the option survives serialization
+	parsed, err := FromBytes(r.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, mac, parsed.(*DHCPv6Relay).ClientLinkLayerAddress())
+
+	// an untyped option is ignored
+	relay = &DHCPv6Relay{}
+	relay.AddOption(&OptionGeneric{OptionCode: OptionClientLinkLayerAddr, OptionData: []byte{0, 1, 2}})
+	require.Nil(t, relay.ClientLinkLayerAddress())
+}
+
 func TestDecapsulateRelayIndex(t *testing.T) {
 	m := DHCPv6Message{}
 	r1, err := EncapsulateRelay(&m, MessageTypeRelayForward, net.IPv6linklocalallnodes, net.IPv6interfacelocalallnodes)
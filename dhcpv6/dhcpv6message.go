@@ -98,9 +98,65 @@ func NewSolicitWithCID(duid Duid, modifiers ...Modifier) (DHCPv6, error) {
 	return d, nil
 }
 
-// NewSolicitForInterface creates a new SOLICIT message with DUID-LLT, using the
-// given network interface's hardware address and current time
+// NewSolicitForInterface creates a new SOLICIT message for the given network
+// interface. On Ethernet interfaces, the DUID and the IAID of the IA_NA come
+// from InterfaceIdentity, and match the DHCPv4 client identifier of the
+// interface. Other interfaces get a DUID-LLT using their hardware address and
+// the current time.
 func NewSolicitForInterface(ifname string, modifiers ...Modifier) (DHCPv6, error) {
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, err
+	}
+	duid, iaid, err := InterfaceIdentity(iface.HardwareAddr)
+	if err != nil {
+		duid = Duid{
+			Type:          DUID_LLT,
+			HwType:        iana.HWTypeEthernet,
+			Time:          GetTime(),
+			LinkLayerAddr: iface.HardwareAddr,
+		}
+		return NewSolicitWithCID(duid, modifiers...)
+	}
+	d, err := NewSolicitWithCID(duid)
+	if err != nil {
+		return nil, err
+	}
+	if iaNa, ok := d.GetOneOption(OptionIANA).(*OptIANA); ok {
+		iaNa.IaId = iaid
+	}
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
+// NewInformationRequestWithCID creates a new INFORMATION-REQUEST message with
+// CID, asking for configuration parameters only, see RFC 8415 Section 18.2.6.
+func NewInformationRequestWithCID(duid Duid, modifiers ...Modifier) (DHCPv6, error) {
+	d, err := NewMessage()
+	if err != nil {
+		return nil, err
+	}
+	d.(*DHCPv6Message).SetMessage(MessageTypeInformationRequest)
+	d.AddOption(&OptClientId{Cid: duid})
+	oro := new(OptRequestedOption)
+	oro.SetRequestedOptions([]OptionCode{
+		OptionDNSRecursiveNameServer,
+		OptionDomainSearchList,
+	})
+	d.AddOption(oro)
+	d.AddOption(&OptElapsedTime{})
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
+// NewInformationRequestForInterface creates a new INFORMATION-REQUEST
+// message with DUID-LLT, using the given network interface's hardware address
+// and current time.
+func NewInformationRequestForInterface(ifname string, modifiers ...Modifier) (DHCPv6, error) {
 	iface, err := net.InterfaceByName(ifname)
 	if err != nil {
 		return nil, err
@@ -111,7 +167,7 @@ func NewSolicitForInterface(ifname string, modifiers ...Modifier) (DHCPv6, error
 		Time:          GetTime(),
 		LinkLayerAddr: iface.HardwareAddr,
 	}
-	return NewSolicitWithCID(duid, modifiers...)
+	return NewInformationRequestWithCID(duid, modifiers...)
 }
 
 // NewAdvertiseFromSolicit creates a new ADVERTISE packet based on an SOLICIT packet.
@@ -240,6 +296,129 @@ func NewReplyFromDHCPv6Message(message DHCPv6, modifiers ...Modifier) (DHCPv6, e
 	return d, nil
 }
 
+// NewRenewFromReply creates a new RENEW packet extending the leases granted
+// by a REPLY packet.
+func NewRenewFromReply(reply DHCPv6, modifiers ...Modifier) (DHCPv6, error) {
+	return newMessageFromReply(MessageTypeRenew, reply, modifiers...)
+}
+
+// NewRebindFromReply creates a new REBIND packet extending the leases granted
+// by a REPLY packet.
+func NewRebindFromReply(reply DHCPv6, modifiers ...Modifier) (DHCPv6, error) {
+	return newMessageFromReply(MessageTypeRebind, reply, modifiers...)
+}
+
+// NewInformationRequestFromReply creates a new INFORMATION-REQUEST packet
+// for the client that received a REPLY packet.
+func NewInformationRequestFromReply(reply DHCPv6, modifiers ...Modifier) (DHCPv6, error) {
+	return newMessageFromReply(MessageTypeInformationRequest, reply, modifiers...)
+}
+
+func newMessageFromReply(messageType MessageType, reply DHCPv6, modifiers ...Modifier) (DHCPv6, error) {
+	if reply == nil {
+		return nil, errors.New("REPLY cannot be nil")
+	}
+	if reply.Type() != MessageTypeReply {
+		return nil, errors.New("The passed REPLY must have REPLY type set")
+	}
+	if _, ok := reply.(*DHCPv6Message); !ok {
+		return nil, errors.New("The passed REPLY must be of DHCPv6Message type")
+	}
+	d, err := NewMessage()
+	if err != nil {
+		return nil, err
+	}
+	msg := d.(*DHCPv6Message)
+	msg.SetMessage(messageType)
+	// add Client ID
+	cid := reply.GetOneOption(OptionClientID)
+	if cid == nil {
+		return nil, fmt.Errorf("Client ID cannot be nil in REPLY when building %v", messageType)
+	}
+	msg.AddOption(cid)
+	// a RENEW goes to the server that granted the leases, a REBIND to any
+	// server, see RFC 8415 Section 18.2.4 and 18.2.5
+	if messageType == MessageTypeRenew {
+		sid := reply.GetOneOption(OptionServerID)
+		if sid == nil {
+			return nil, errors.New("Server ID cannot be nil in REPLY when building RENEW")
+		}
+		msg.AddOption(sid)
+	}
+	msg.AddOption(&OptElapsedTime{})
+	if messageType != MessageTypeInformationRequest {
+		for _, ia := range reply.GetOption(OptionIANA) {
+			msg.AddOption(ia)
+		}
+		for _, ia := range reply.GetOption(OptionIAPD) {
+			msg.AddOption(ia)
+		}
+	}
+	oro := OptRequestedOption{}
+	oro.SetRequestedOptions([]OptionCode{
+		OptionDNSRecursiveNameServer,
+		OptionDomainSearchList,
+	})
+	msg.AddOption(&oro)
+
+	// apply modifiers
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
+// NewLeaseQuery creates a new LEASEQUERY message for the given query. duid
+// identifies the requestor.
+func NewLeaseQuery(duid Duid, query *OptLQQuery, modifiers ...Modifier) (DHCPv6, error) {
+	if query == nil {
+		return nil, errors.New("LQ query cannot be nil")
+	}
+	d, err := NewMessage()
+	if err != nil {
+		return nil, err
+	}
+	d.(*DHCPv6Message).SetMessage(MessageTypeLeaseQuery)
+	d.AddOption(&OptClientId{Cid: duid})
+	d.AddOption(query)
+	// Apply modifiers
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
+// NewLeaseQueryReplyFromLeaseQuery creates a new LEASEQUERY-REPLY packet based
+// on a LEASEQUERY packet.
+func NewLeaseQueryReplyFromLeaseQuery(query DHCPv6, modifiers ...Modifier) (DHCPv6, error) {
+	if query == nil {
+		return nil, errors.New("LEASEQUERY cannot be nil")
+	}
+	if query.Type() != MessageTypeLeaseQuery {
+		return nil, errors.New("The passed LEASEQUERY must have LEASEQUERY type set")
+	}
+	msg, ok := query.(*DHCPv6Message)
+	if !ok {
+		return nil, errors.New("The passed LEASEQUERY must be of DHCPv6Message type")
+	}
+	rep := DHCPv6Message{}
+	rep.SetMessage(MessageTypeLeaseQueryReply)
+	rep.SetTransactionID(msg.TransactionID())
+	// add Client ID
+	cid := msg.GetOneOption(OptionClientID)
+	if cid == nil {
+		return nil, errors.New("Client ID cannot be nil in LEASEQUERY when building LEASEQUERY-REPLY")
+	}
+	rep.AddOption(cid)
+
+	// apply modifiers
+	d := DHCPv6(&rep)
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
 func (d *DHCPv6Message) Type() MessageType {
 	return d.messageType
 }
@@ -321,6 +500,70 @@ func (d *DHCPv6Message) IsOptionRequested(requested OptionCode) bool {
 	return false
 }
 
+// NTPServer returns the NTP Server option of the message, or nil if there is
+// none or it cannot be parsed.
+func (d *DHCPv6Message) NTPServer() *OptNTPServer {
+	if o, ok := d.GetOneOption(OptionNTPServer).(*OptNTPServer); ok {
+		return o
+	}
+	return nil
+}
+
+// SNTPServers returns the addresses of the SNTP Server List option of the
+// message, or nil if there is none.
+func (d *DHCPv6Message) SNTPServers() []net.IP {
+	if o, ok := d.GetOneOption(OptionSNTPServerList).(*OptSNTPServerList); ok {
+		return o.Servers
+	}
+	return nil
+}
+
+// Preference returns the server preference of the message, which is 0 if the
+// message carries no Preference option.
+func (d *DHCPv6Message) Preference() uint8 {
+	if o, ok := d.GetOneOption(OptionPreference).(*OptPreference); ok {
+		return o.Preference
+	}
+	return 0
+}
+
+// ServerUnicast returns the address of the Server Unicast option of the
+// message, or nil if there is none.
+func (d *DHCPv6Message) ServerUnicast() net.IP {
+	if o, ok := d.GetOneOption(OptionUnicast).(*OptUnicast); ok {
+		return o.ServerAddress
+	}
+	return nil
+}
+
+// AFTRName returns the name of the DS-Lite AFTR of the message, or an empty
+// string if there is none.
+func (d *DHCPv6Message) AFTRName() string {
+	if o, ok := d.GetOneOption(OptionAFTRName).(*OptAFTRName); ok {
+		return o.Name
+	}
+	return ""
+}
+
+// CaptivePortal returns the captive portal API URI of the message, or an
+// empty string if there is none.
+func (d *DHCPv6Message) CaptivePortal() string {
+	if o, ok := d.GetOneOption(OptionCaptivePortal).(*OptCaptivePortal); ok {
+		return o.URI
+	}
+	return ""
+}
+
+// S46Container returns the S46 container option with the given code
+// (OptionS46ContMapE, OptionS46ContMapT or OptionS46ContLW), or nil if the
+// message does not carry it.
+func (d *DHCPv6Message) S46Container(code OptionCode) *OptS46Container {
+	if o, ok := d.GetOneOption(code).(*OptS46Container); ok {
+		return o
+	}
+	return nil
+}
+
 func (d *DHCPv6Message) String() string {
 	return fmt.Sprintf("DHCPv6Message(messageType=%v transactionID=0x%06x, %d options)",
 		d.Type().String(), d.TransactionID(), len(d.options),
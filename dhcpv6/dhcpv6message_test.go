@@ -1,8 +1,10 @@
 package dhcpv6
 
 import (
+	"net"
 	"testing"
 
+	"github.com/insomniacslk/dhcp/iana"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,3 +34,51 @@ func TestIsOptionRequested(t *testing.T) {
 	msg2.AddOption(&optro)
 	require.True(t, msg2.IsOptionRequested(OptionDNSRecursiveNameServer))
 }
+
+func TestNewInformationRequestWithCID(t *testing.T) {
+	duid := Duid{
+		Type:          DUID_LL,
+		HwType:        iana.HWTypeEthernet,
+		LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+	}
+	ir, err := NewInformationRequestWithCID(duid, WithArchType(iana.EFI_X86_64))
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeInformationRequest, ir.Type())
+	require.Equal(t, &OptClientId{Cid: duid}, ir.GetOneOption(OptionClientID))
+	require.True(t, ir.(*DHCPv6Message).IsOptionRequested(OptionDNSRecursiveNameServer))
+	require.NotNil(t, ir.GetOneOption(OptionElapsedTime))
+	require.Nil(t, ir.GetOneOption(OptionIANA))
+	require.NotNil(t, ir.GetOneOption(OptionClientArchType))
+}
+
+func TestNewLeaseQuery(t *testing.T) {
+	duid := Duid{
+		Type:          DUID_LL,
+		HwType:        iana.HWTypeEthernet,
+		LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+	}
+	query := &OptLQQuery{QueryType: QueryByAddress, LinkAddress: net.IPv6zero}
+	query.AddOption(&OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::10")})
+
+	lq, err := NewLeaseQuery(duid, query)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeLeaseQuery, lq.Type())
+	require.Equal(t, &OptClientId{Cid: duid}, lq.GetOneOption(OptionClientID))
+	require.Equal(t, query, lq.GetOneOption(OptionLQQuery))
+
+	parsed, err := FromBytes(lq.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, lq.ToBytes(), parsed.ToBytes())
+
+	_, err = NewLeaseQuery(duid, nil)
+	require.Error(t, err)
+
+	reply, err := NewLeaseQueryReplyFromLeaseQuery(lq)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeLeaseQueryReply, reply.Type())
+	require.Equal(t, lq.(*DHCPv6Message).TransactionID(), reply.(*DHCPv6Message).TransactionID())
+	require.Equal(t, &OptClientId{Cid: duid}, reply.GetOneOption(OptionClientID))
+
+	_, err = NewLeaseQueryReplyFromLeaseQuery(reply)
+	require.Error(t, err)
+}
@@ -135,6 +135,16 @@ func (r *DHCPv6Relay) IsRelay() bool {
 	return true
 }
 
+// ClientLinkLayerAddress returns the link-layer address in the
+// OptClientLinkLayerAddress of this relay message, or nil if not present.
+func (r *DHCPv6Relay) ClientLinkLayerAddress() net.HardwareAddr {
+	opt, ok := r.GetOneOption(OptionClientLinkLayerAddr).(*OptClientLinkLayerAddress)
+	if !ok {
+		return nil
+	}
+	return opt.LinkLayerAddress
+}
+
 // Recurse into a relay message and extract and return the inner DHCPv6Message.
 // Return nil if none found (e.g. not a relay message).
 func (d *DHCPv6Relay) GetInnerMessage() (DHCPv6, error) {
@@ -77,6 +77,26 @@ func (d Duid) Equal(o Duid) bool {
 	return true
 }
 
+// InterfaceIdentity returns the DUID-LL and the IAID that identify a client
+// on a network interface with the given Ethernet hardware address. Both only
+// depend on the address, so that they are stable and shared by the DHCPv6
+// client of the interface and its DHCPv4 client identifier, as described by
+// RFC 4361. Other hardware addresses are rejected, since their hardware type
+// cannot be told from the address.
+func InterfaceIdentity(hwaddr net.HardwareAddr) (Duid, [4]byte, error) {
+	var iaid [4]byte
+	if len(hwaddr) != 6 {
+		return Duid{}, iaid, fmt.Errorf("unsupported non-Ethernet hardware address %q", hwaddr.String())
+	}
+	copy(iaid[:], hwaddr[len(hwaddr)-len(iaid):])
+	duid := Duid{
+		Type:          DUID_LL,
+		HwType:        iana.HWTypeEthernet,
+		LinkLayerAddr: hwaddr,
+	}
+	return duid, iaid, nil
+}
+
 // ToBytes serializes a Duid object.
 func (d *Duid) ToBytes() []byte {
 	if d.Type == DUID_LLT {
@@ -146,3 +146,20 @@ func TestDuidEqualNotEqual(t *testing.T) {
 	}
 	require.False(t, d.Equal(o))
 }
+
+func TestInterfaceIdentity(t *testing.T) {
+	hwaddr := net.HardwareAddr{0, 1, 2, 3, 4, 5}
+	duid, iaid, err := InterfaceIdentity(hwaddr)
+	require.NoError(t, err)
+	require.Equal(t, DUID_LL, duid.Type)
+	require.Equal(t, iana.HWTypeEthernet, duid.HwType)
+	require.Equal(t, hwaddr, duid.LinkLayerAddr)
+	require.Equal(t, [4]byte{2, 3, 4, 5}, iaid)
+
+	_, _, err = InterfaceIdentity(nil)
+	require.Error(t, err)
+
+	// IP over InfiniBand uses 20-byte link-layer addresses.
+	_, _, err = InterfaceIdentity(make(net.HardwareAddr, 20))
+	require.Error(t, err)
+}
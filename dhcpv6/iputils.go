@@ -61,15 +61,29 @@ func GetMacAddressFromEUI64(ip net.IP) (net.HardwareAddr, error) {
 	return mac, nil
 }
 
-// ExtractMAC looks into the inner most PeerAddr field in the RelayInfo header
-// which contains the EUI-64 address of the client making the request, populated
-// by the dhcp relay, it is possible to extract the mac address from that IP.
+// ExtractMAC looks for the MAC address of the client in a relayed packet:
+// first in the Client Link-Layer Address option of every relay hop, from the
+// outermost to the innermost, as inserted by the relay directly connected to
+// the client (RFC 6939). Then it looks into the inner most PeerAddr field in
+// the RelayInfo header which contains the EUI-64 address of the client making
+// the request, populated by the dhcp relay, it is possible to extract the mac
+// address from that IP.
 // If that fails, it looks for the MAC addressed embededded in the DUID.
 // Note that this only works with type DuidLL and DuidLLT.
 // If a mac address cannot be found an error will be returned.
 func ExtractMAC(packet DHCPv6) (net.HardwareAddr, error) {
 	msg := packet
 	if packet.IsRelay() {
+		for hop := packet; hop.IsRelay(); {
+			if mac := hop.(*DHCPv6Relay).ClientLinkLayerAddress(); mac != nil {
+				return mac, nil
+			}
+			var err error
+			hop, err = DecapsulateRelay(hop)
+			if err != nil {
+				return nil, err
+			}
+		}
 		inner, err := DecapsulateRelayIndex(packet, -1)
 		if err != nil {
 			return nil, err
@@ -93,3 +107,26 @@ func ExtractMAC(packet DHCPv6) (net.HardwareAddr, error) {
 	}
 	return duid.LinkLayerAddr, nil
 }
+
+// ipv6AddressesToBytes serializes a list of IPv6 addresses, as carried by
+// options like OptSIPServerAddressList
+func ipv6AddressesToBytes(addrs []net.IP) []byte {
+	buf := make([]byte, 0, len(addrs)*net.IPv6len)
+	for _, addr := range addrs {
+		buf = append(buf, addr.To16()...)
+	}
+	return buf
+}
+
+// parseIPv6Addresses parses a list of IPv6 addresses serialized by
+// ipv6AddressesToBytes
+func parseIPv6Addresses(data []byte) ([]net.IP, error) {
+	if len(data)%net.IPv6len != 0 {
+		return nil, fmt.Errorf("length is not a multiple of %d", net.IPv6len)
+	}
+	var addrs []net.IP
+	for i := 0; i < len(data); i += net.IPv6len {
+		addrs = append(addrs, append(net.IP(nil), data[i:i+net.IPv6len]...))
+	}
+	return addrs, nil
+}
@@ -145,6 +145,37 @@ func Test_ExtractMAC(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, mac.String(), "aa:aa:aa:aa:aa:aa")
 
+	// MAC from the Client Link-Layer Address option takes precedence over the
+	// DUID, on any relay hop
+	llmac := net.HardwareAddr{0x24, 0x8a, 0x07, 0x56, 0xdc, 0xa4}
+	relay, err = EncapsulateRelay(solicit, MessageTypeRelayForward, net.IPv6zero, net.IPv6zero,
+		WithClientLinkLayerAddress(iana.HWTypeEthernet, llmac),
+	)
+	require.NoError(t, err)
+	mac, err = ExtractMAC(relay)
+	require.NoError(t, err)
+	require.Equal(t, llmac, mac)
+	relay, err = EncapsulateRelay(relay, MessageTypeRelayForward, net.IPv6zero, net.IPv6zero)
+	require.NoError(t, err)
+	mac, err = ExtractMAC(relay)
+	require.NoError(t, err)
+	require.Equal(t, llmac, mac)
+
+	// DUID-UUID and no EUI-64 peer address, only the option carries the MAC
+	solicit, err = NewMessage(WithClientID(Duid{Type: DUID_UUID, Uuid: make([]byte, 16)}))
+	require.NoError(t, err)
+	relay, err = EncapsulateRelay(solicit, MessageTypeRelayForward, net.IPv6zero, net.ParseIP("fe80::1"))
+	require.NoError(t, err)
+	_, err = ExtractMAC(relay)
+	require.Error(t, err)
+	relay, err = EncapsulateRelay(solicit, MessageTypeRelayForward, net.IPv6zero, net.ParseIP("fe80::1"),
+		WithClientLinkLayerAddress(iana.HWTypeEthernet, llmac),
+	)
+	require.NoError(t, err)
+	mac, err = ExtractMAC(relay)
+	require.NoError(t, err)
+	require.Equal(t, llmac, mac)
+
 	// no client ID
 	solicit, err = NewMessage()
 	require.NoError(t, err)
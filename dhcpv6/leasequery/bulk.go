@@ -0,0 +1,269 @@
+package leasequery
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// DefaultDialTimeout is the time to wait for the TCP connection to the server
+// to be established.
+const DefaultDialTimeout = 3 * time.Second
+
+// maxMessageLen is the largest message the 2-byte length prefix of RFC 5460,
+// Section 5.2 can frame.
+const maxMessageLen = 0xffff
+
+// WriteMessage writes m to w, framed as described by RFC 5460, Section 5.2.
+func WriteMessage(w io.Writer, m dhcpv6.DHCPv6) error {
+	b := m.ToBytes()
+	if len(b) > maxMessageLen {
+		return fmt.Errorf("message too long for bulk leasequery: %d bytes", len(b))
+	}
+	frame := make([]byte, 2+len(b))
+	binary.BigEndian.PutUint16(frame, uint16(len(b)))
+	copy(frame[2:], b)
+	_, err := w.Write(frame)
+	return err
+}
+
+// ReadMessage reads a message framed as described by RFC 5460, Section 5.2
+// from r.
+func ReadMessage(r io.Reader) (dhcpv6.DHCPv6, error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint16(hdr[:])
+	if n == 0 {
+		return nil, errors.New("empty bulk leasequery message")
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return dhcpv6.FromBytes(b)
+}
+
+// BulkClient is a DHCPv6 Bulk Leasequery client. Queries are sent one at a
+// time over a single TCP connection.
+type BulkClient struct {
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	RemoteAddr   net.Addr
+
+	conn net.Conn
+}
+
+// NewBulkClient returns a bulk leasequery client for the server at raddr.
+func NewBulkClient(raddr net.Addr) *BulkClient {
+	return &BulkClient{
+		DialTimeout:  DefaultDialTimeout,
+		ReadTimeout:  dhcpv6.DefaultReadTimeout,
+		WriteTimeout: dhcpv6.DefaultWriteTimeout,
+		RemoteAddr:   raddr,
+	}
+}
+
+// Open connects to the server.
+func (c *BulkClient) Open() error {
+	conn, err := net.DialTimeout("tcp6", c.RemoteAddr.String(), c.DialTimeout)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	return nil
+}
+
+// Close closes the connection to the server.
+func (c *BulkClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Query sends the LEASEQUERY q and returns the server's replies: the
+// LEASEQUERY-REPLY followed by any LEASEQUERY-DATA. The closing
+// LEASEQUERY-DONE is not returned. An error is returned along with the
+// replies received so far if the server reports a failure status.
+func (c *BulkClient) Query(q dhcpv6.DHCPv6) ([]dhcpv6.DHCPv6, error) {
+	if c.conn == nil {
+		return nil, errors.New("bulk leasequery client is not open")
+	}
+	xid, err := dhcpv6.GetTransactionID(q)
+	if err != nil {
+		return nil, err
+	}
+	var queryType dhcpv6.LQQueryType
+	if opt := q.GetOneOption(dhcpv6.OptionLQQuery); opt != nil {
+		queryType = opt.(*dhcpv6.OptLQQuery).QueryType
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	if err := WriteMessage(c.conn, q); err != nil {
+		return nil, err
+	}
+
+	var replies []dhcpv6.DHCPv6
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+		m, err := ReadMessage(c.conn)
+		if err != nil {
+			return replies, err
+		}
+		if mxid, err := dhcpv6.GetTransactionID(m); err != nil || mxid != xid {
+			return replies, fmt.Errorf("unexpected reply to transaction 0x%06x", xid)
+		}
+		if err := statusError(m); err != nil {
+			return replies, err
+		}
+		switch m.Type() {
+		case dhcpv6.MessageTypeLeaseQueryReply:
+			if len(replies) > 0 {
+				return replies, errors.New("unexpected LEASEQUERY-REPLY")
+			}
+			replies = append(replies, m)
+			// A single binding, or none, is sent without LEASEQUERY-DONE.
+			if !multiple(queryType) || m.GetOneOption(dhcpv6.OptionClientData) == nil {
+				return replies, nil
+			}
+		case dhcpv6.MessageTypeLeaseQueryData:
+			if len(replies) == 0 {
+				return replies, errors.New("LEASEQUERY-DATA before LEASEQUERY-REPLY")
+			}
+			replies = append(replies, m)
+		case dhcpv6.MessageTypeLeaseQueryDone:
+			return replies, nil
+		default:
+			return replies, fmt.Errorf("unexpected message type %s", m.Type())
+		}
+	}
+}
+
+func statusError(m dhcpv6.DHCPv6) error {
+	opt := m.GetOneOption(dhcpv6.OptionStatusCode)
+	if opt == nil {
+		return nil
+	}
+	sc := opt.(*dhcpv6.OptStatusCode)
+	if sc.StatusCode == iana.StatusSuccess {
+		return nil
+	}
+	return fmt.Errorf("bulk leasequery failed: %s: %s", sc.StatusCode, sc.StatusMessage)
+}
+
+// BulkServer answers DHCPv6 Bulk Leasequeries over TCP from a LeaseStore.
+type BulkServer struct {
+	Store LeaseStore
+
+	// ServerID is sent in the server identifier option of every reply.
+	ServerID dhcpv6.Duid
+
+	listener     net.Listener
+	listenerLock sync.Mutex
+	localAddr    net.TCPAddr
+}
+
+// NewBulkServer initializes and returns a new BulkServer object.
+func NewBulkServer(addr net.TCPAddr, store LeaseStore, serverID dhcpv6.Duid) *BulkServer {
+	return &BulkServer{
+		Store:     store,
+		ServerID:  serverID,
+		localAddr: addr,
+	}
+}
+
+// LocalAddr returns the local address of the listening socket, or nil if not
+// listening.
+func (s *BulkServer) LocalAddr() net.Addr {
+	s.listenerLock.Lock()
+	defer s.listenerLock.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// ActivateAndServe starts listening and serving connections. It only returns
+// on error or after `BulkServer.Close` is called.
+func (s *BulkServer) ActivateAndServe() error {
+	s.listenerLock.Lock()
+	if s.listener != nil {
+		s.listenerLock.Unlock()
+		return errors.New("bulk leasequery server already listening")
+	}
+	l, err := net.ListenTCP("tcp6", &s.localAddr)
+	if err != nil {
+		s.listenerLock.Unlock()
+		return err
+	}
+	s.listener = l
+	s.listenerLock.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.listenerLock.Lock()
+			closed := s.listener == nil
+			s.listenerLock.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go s.serve(conn)
+	}
+}
+
+// Close stops listening. Connections already accepted are served until the
+// requestor closes them.
+func (s *BulkServer) Close() error {
+	s.listenerLock.Lock()
+	defer s.listenerLock.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.listener = nil
+	return err
+}
+
+func (s *BulkServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := &Responder{Store: s.Store, ServerID: s.ServerID}
+	for {
+		q, err := ReadMessage(conn)
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("leasequery: cannot read from %v: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if q.Type() != dhcpv6.MessageTypeLeaseQuery {
+			log.Printf("leasequery: ignoring %s from %v", q.Type(), conn.RemoteAddr())
+			continue
+		}
+		replies, err := r.replies(q, true)
+		if err != nil {
+			log.Printf("leasequery: cannot answer query from %v: %v", conn.RemoteAddr(), err)
+			return
+		}
+		for _, m := range replies {
+			if err := WriteMessage(conn, m); err != nil {
+				log.Printf("leasequery: cannot reply to %v: %v", conn.RemoteAddr(), err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package leasequery
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageFraming(t *testing.T) {
+	q := newQuery(t, dhcpv6.QueryByRelayID, &dhcpv6.OptRelayId{Rid: relayID})
+	var buf bytes.Buffer
+	require.NoError(t, WriteMessage(&buf, q))
+	b := buf.Bytes()
+	require.Equal(t, len(b)-2, int(b[0])<<8|int(b[1]))
+
+	m, err := ReadMessage(&buf)
+	require.NoError(t, err)
+	require.Equal(t, q.ToBytes(), m.ToBytes())
+
+	// Truncated.
+	_, err = ReadMessage(bytes.NewReader(b[:len(b)-1]))
+	require.Error(t, err)
+
+	// Empty.
+	_, err = ReadMessage(bytes.NewReader([]byte{0, 0}))
+	require.Error(t, err)
+}
+
+func setUpBulk(t *testing.T) (*BulkServer, *BulkClient) {
+	s := NewBulkServer(net.TCPAddr{IP: net.IPv6loopback}, newTestStore(), serverID)
+	go func() {
+		_ = s.ActivateAndServe()
+	}()
+	var laddr net.Addr
+	for i := 0; i < 100 && laddr == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		laddr = s.LocalAddr()
+	}
+	require.NotNil(t, laddr, "server did not start")
+
+	c := NewBulkClient(laddr)
+	require.NoError(t, c.Open())
+	return s, c
+}
+
+func TestBulkQueryMultiple(t *testing.T) {
+	s, c := setUpBulk(t)
+	defer s.Close()
+	defer c.Close()
+
+	for _, q := range []dhcpv6.DHCPv6{
+		newQuery(t, dhcpv6.QueryByRelayID, &dhcpv6.OptRelayId{Rid: relayID}),
+		newQuery(t, dhcpv6.QueryByLinkAddress),
+	} {
+		replies, err := c.Query(q)
+		require.NoError(t, err)
+		require.Len(t, replies, 3)
+		require.Equal(t, dhcpv6.MessageTypeLeaseQueryReply, replies[0].Type())
+		require.Equal(t, dhcpv6.MessageTypeLeaseQueryData, replies[1].Type())
+		require.Equal(t, dhcpv6.MessageTypeLeaseQueryData, replies[2].Type())
+		for _, m := range replies {
+			require.NotNil(t, m.GetOneOption(dhcpv6.OptionClientData))
+			require.NotNil(t, m.GetOneOption(dhcpv6.OptionLQRelayData))
+		}
+	}
+
+	// No bindings: a lone LEASEQUERY-REPLY.
+	replies, err := c.Query(newQuery(t, dhcpv6.QueryByRelayID, &dhcpv6.OptRelayId{Rid: duidLL(0)}))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	require.Nil(t, replies[0].GetOneOption(dhcpv6.OptionClientData))
+}
+
+func TestBulkQuerySingle(t *testing.T) {
+	s, c := setUpBulk(t)
+	defer s.Close()
+	defer c.Close()
+
+	replies, err := c.Query(newQuery(t, dhcpv6.QueryByClientID, &dhcpv6.OptClientId{Cid: duidLL(11)}))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	require.Equal(t, dhcpv6.MessageTypeLeaseQueryReply, replies[0].Type())
+
+	// The connection is reusable after an error.
+	_, err = c.Query(newQuery(t, dhcpv6.QueryByRemoteID))
+	require.Error(t, err)
+	replies, err = c.Query(newQuery(t, dhcpv6.QueryByAddress, &dhcpv6.OptIAAddress{IPv6Addr: newBinding(10).addr}))
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+}
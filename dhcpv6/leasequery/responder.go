@@ -0,0 +1,154 @@
+package leasequery
+
+import (
+	"errors"
+	"log"
+	"net"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// Responder answers DHCPv6 leasequeries from a LeaseStore, as described by
+// RFC 5007, Section 4.3. Its Handle method can be passed to
+// dhcpv6.NewServer.
+type Responder struct {
+	Store LeaseStore
+
+	// ServerID is sent in the server identifier option of every reply.
+	ServerID dhcpv6.Duid
+}
+
+// Handle implements dhcpv6.Handler. Replies are sent back to peer.
+func (r *Responder) Handle(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+	if m.Type() != dhcpv6.MessageTypeLeaseQuery {
+		return
+	}
+	reply, err := r.Reply(m)
+	if err != nil {
+		log.Printf("leasequery: cannot answer query from %v: %v", peer, err)
+		return
+	}
+	if _, err := conn.WriteTo(reply.ToBytes(), peer); err != nil {
+		log.Printf("leasequery: cannot reply to %v: %v", peer, err)
+	}
+}
+
+// Reply builds the LEASEQUERY-REPLY to the leasequery q. Failed lookups are
+// reported to the requestor with a status code; an error is only returned if
+// no reply can be built.
+//
+// The query types introduced by RFC 5460 are only answered over Bulk
+// Leasequery, see BulkServer.
+func (r *Responder) Reply(q dhcpv6.DHCPv6) (dhcpv6.DHCPv6, error) {
+	replies, err := r.replies(q, false)
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// multiple returns whether a query of type t may match more than one client.
+func multiple(t dhcpv6.LQQueryType) bool {
+	switch t {
+	case dhcpv6.QueryByRelayID, dhcpv6.QueryByLinkAddress, dhcpv6.QueryByRemoteID:
+		return true
+	}
+	return false
+}
+
+// replies builds the messages answering q: a LEASEQUERY-REPLY, then for bulk
+// queries matching several clients, one LEASEQUERY-DATA per extra binding
+// and a LEASEQUERY-DONE, as described by RFC 5460, Section 3.
+func (r *Responder) replies(q dhcpv6.DHCPv6, bulk bool) ([]dhcpv6.DHCPv6, error) {
+	msg, ok := q.(*dhcpv6.DHCPv6Message)
+	if !ok {
+		return nil, errors.New("leasequery must be of DHCPv6Message type")
+	}
+	fail := func(code iana.StatusCode, err error) ([]dhcpv6.DHCPv6, error) {
+		reply, err := r.newReply(msg, &dhcpv6.OptStatusCode{
+			StatusCode:    code,
+			StatusMessage: []byte(err.Error()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []dhcpv6.DHCPv6{reply}, nil
+	}
+
+	opt := msg.GetOneOption(dhcpv6.OptionLQQuery)
+	if opt == nil {
+		return fail(iana.StatusMalformedQuery, errors.New("no LQ query option"))
+	}
+	query := opt.(*dhcpv6.OptLQQuery)
+	if multiple(query.QueryType) && !bulk {
+		return fail(iana.StatusUnknownQueryType, ErrUnknownQueryType)
+	}
+	bindings, err := r.Store.Lookup(query)
+	switch err {
+	case nil:
+	case ErrUnknownQueryType:
+		return fail(iana.StatusUnknownQueryType, err)
+	case ErrMalformedQuery:
+		return fail(iana.StatusMalformedQuery, err)
+	case ErrNotConfigured:
+		return fail(iana.StatusNotConfigured, err)
+	case ErrNotAllowed:
+		return fail(iana.StatusNotAllowed, err)
+	default:
+		return fail(iana.StatusUnspecFail, err)
+	}
+
+	if len(bindings) == 0 {
+		reply, err := r.newReply(msg)
+		if err != nil {
+			return nil, err
+		}
+		return []dhcpv6.DHCPv6{reply}, nil
+	}
+	reply, err := r.newReply(msg, bindingOptions(bindings[0])...)
+	if err != nil {
+		return nil, err
+	}
+	replies := []dhcpv6.DHCPv6{reply}
+	if !multiple(query.QueryType) {
+		return replies, nil
+	}
+	for _, b := range bindings[1:] {
+		replies = append(replies, newMessage(dhcpv6.MessageTypeLeaseQueryData, msg, bindingOptions(b)...))
+	}
+	return append(replies, newMessage(dhcpv6.MessageTypeLeaseQueryDone, msg)), nil
+}
+
+func (r *Responder) newReply(q *dhcpv6.DHCPv6Message, options ...dhcpv6.Option) (dhcpv6.DHCPv6, error) {
+	return dhcpv6.NewLeaseQueryReplyFromLeaseQuery(q,
+		dhcpv6.WithServerID(r.ServerID),
+		func(d dhcpv6.DHCPv6) dhcpv6.DHCPv6 {
+			for _, opt := range options {
+				d.AddOption(opt)
+			}
+			return d
+		},
+	)
+}
+
+func newMessage(mt dhcpv6.MessageType, q *dhcpv6.DHCPv6Message, options ...dhcpv6.Option) dhcpv6.DHCPv6 {
+	m := dhcpv6.DHCPv6Message{}
+	m.SetMessage(mt)
+	m.SetTransactionID(q.TransactionID())
+	for _, opt := range options {
+		m.AddOption(opt)
+	}
+	return &m
+}
+
+func bindingOptions(b *Binding) []dhcpv6.Option {
+	var options []dhcpv6.Option
+	if b.ClientData != nil {
+		options = append(options, b.ClientData)
+	}
+	if b.RelayData != nil {
+		options = append(options, b.RelayData)
+	}
+	return options
+}
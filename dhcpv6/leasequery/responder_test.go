@@ -0,0 +1,210 @@
+package leasequery
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is a LeaseStore backed by a list of bindings, each learned on a
+// link through a relay agent.
+type memStore struct {
+	bindings []memBinding
+}
+
+type memBinding struct {
+	Binding
+	clientID dhcpv6.Duid
+	addr     net.IP
+	link     net.IP
+	relayID  dhcpv6.Duid
+}
+
+func (s *memStore) Lookup(query *dhcpv6.OptLQQuery) ([]*Binding, error) {
+	var match func(b *memBinding) bool
+	switch query.QueryType {
+	case dhcpv6.QueryByAddress:
+		opt := query.GetOneOption(dhcpv6.OptionIAAddr)
+		if opt == nil {
+			return nil, ErrMalformedQuery
+		}
+		addr := opt.(*dhcpv6.OptIAAddress).IPv6Addr
+		match = func(b *memBinding) bool { return b.addr.Equal(addr) }
+	case dhcpv6.QueryByClientID:
+		opt := query.GetOneOption(dhcpv6.OptionClientID)
+		if opt == nil {
+			return nil, ErrMalformedQuery
+		}
+		cid := opt.(*dhcpv6.OptClientId).Cid
+		match = func(b *memBinding) bool { return b.clientID.Equal(cid) }
+	case dhcpv6.QueryByRelayID:
+		opt := query.GetOneOption(dhcpv6.OptionRelayID)
+		if opt == nil {
+			return nil, ErrMalformedQuery
+		}
+		rid := opt.(*dhcpv6.OptRelayId).Rid
+		match = func(b *memBinding) bool { return b.relayID.Equal(rid) }
+	case dhcpv6.QueryByLinkAddress:
+		match = func(b *memBinding) bool { return b.link.Equal(query.LinkAddress) }
+	case dhcpv6.QueryByRemoteID:
+		return nil, errors.New("remote-id not indexed")
+	default:
+		return nil, ErrUnknownQueryType
+	}
+	var bindings []*Binding
+	for i := range s.bindings {
+		if match(&s.bindings[i]) {
+			bindings = append(bindings, &s.bindings[i].Binding)
+		}
+	}
+	return bindings, nil
+}
+
+func duidLL(last byte) dhcpv6.Duid {
+	return dhcpv6.Duid{
+		Type:          dhcpv6.DUID_LL,
+		HwType:        iana.HWTypeEthernet,
+		LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, last},
+	}
+}
+
+var (
+	serverID    = duidLL(0xfe)
+	requestorID = duidLL(0xfd)
+	relayID     = duidLL(0xfc)
+	link        = net.ParseIP("2001:db8::")
+)
+
+func newBinding(last byte) memBinding {
+	cid := duidLL(last)
+	addr := net.ParseIP("2001:db8::")
+	addr[15] = last
+	cd := &dhcpv6.OptClientData{}
+	cd.AddOption(&dhcpv6.OptClientId{Cid: cid})
+	cd.AddOption(&dhcpv6.OptIAAddress{IPv6Addr: addr, PreferredLifetime: 1800, ValidLifetime: 3600})
+	cd.AddOption(&dhcpv6.OptCLTTime{CLTTime: 60})
+	return memBinding{
+		Binding: Binding{
+			ClientData: cd,
+			RelayData:  &dhcpv6.OptLQRelayData{PeerAddress: net.ParseIP("2001:db8::1")},
+		},
+		clientID: cid,
+		addr:     addr,
+		link:     link,
+		relayID:  relayID,
+	}
+}
+
+func newTestStore() *memStore {
+	return &memStore{bindings: []memBinding{newBinding(10), newBinding(11), newBinding(12)}}
+}
+
+func newQuery(t *testing.T, qt dhcpv6.LQQueryType, options ...dhcpv6.Option) dhcpv6.DHCPv6 {
+	query := &dhcpv6.OptLQQuery{QueryType: qt, LinkAddress: net.IPv6zero, Options: options}
+	if qt == dhcpv6.QueryByLinkAddress {
+		query.LinkAddress = link
+	}
+	q, err := dhcpv6.NewLeaseQuery(requestorID, query)
+	require.NoError(t, err)
+	return q
+}
+
+func status(m dhcpv6.DHCPv6) iana.StatusCode {
+	opt := m.GetOneOption(dhcpv6.OptionStatusCode)
+	if opt == nil {
+		return iana.StatusSuccess
+	}
+	return opt.(*dhcpv6.OptStatusCode).StatusCode
+}
+
+func TestReplyByAddress(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+
+	b := newBinding(11)
+	q := newQuery(t, dhcpv6.QueryByAddress, &dhcpv6.OptIAAddress{IPv6Addr: b.addr})
+	m, err := r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, dhcpv6.MessageTypeLeaseQueryReply, m.Type())
+	require.Equal(t, q.(*dhcpv6.DHCPv6Message).TransactionID(), m.(*dhcpv6.DHCPv6Message).TransactionID())
+	require.Equal(t, &dhcpv6.OptServerId{Sid: serverID}, m.GetOneOption(dhcpv6.OptionServerID))
+	require.Equal(t, &dhcpv6.OptClientId{Cid: requestorID}, m.GetOneOption(dhcpv6.OptionClientID))
+	require.Equal(t, iana.StatusSuccess, status(m))
+	require.True(t, bytes.Equal(b.ClientData.ToBytes(), m.GetOneOption(dhcpv6.OptionClientData).ToBytes()))
+	require.NotNil(t, m.GetOneOption(dhcpv6.OptionLQRelayData))
+
+	// No binding.
+	q = newQuery(t, dhcpv6.QueryByAddress, &dhcpv6.OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::99")})
+	m, err = r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, iana.StatusSuccess, status(m))
+	require.Nil(t, m.GetOneOption(dhcpv6.OptionClientData))
+}
+
+func TestReplyByClientID(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+
+	q := newQuery(t, dhcpv6.QueryByClientID, &dhcpv6.OptClientId{Cid: duidLL(12)})
+	m, err := r.Reply(q)
+	require.NoError(t, err)
+	cd := m.GetOneOption(dhcpv6.OptionClientData)
+	require.NotNil(t, cd)
+	require.Equal(t, newBinding(12).addr, cd.(*dhcpv6.OptClientData).GetOneOption(dhcpv6.OptionIAAddr).(*dhcpv6.OptIAAddress).IPv6Addr)
+}
+
+func TestReplyErrors(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+
+	for _, tt := range []struct {
+		name string
+		q    dhcpv6.DHCPv6
+		want iana.StatusCode
+	}{
+		{"missing address", newQuery(t, dhcpv6.QueryByAddress), iana.StatusMalformedQuery},
+		{"unknown type", newQuery(t, 42), iana.StatusUnknownQueryType},
+		{"bulk only", newQuery(t, dhcpv6.QueryByRelayID, &dhcpv6.OptRelayId{Rid: relayID}), iana.StatusUnknownQueryType},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := r.Reply(tt.q)
+			require.NoError(t, err)
+			require.Equal(t, dhcpv6.MessageTypeLeaseQueryReply, m.Type())
+			require.Equal(t, tt.want, status(m))
+		})
+	}
+
+	// No LQ query option at all.
+	q, err := dhcpv6.NewMessage(func(d dhcpv6.DHCPv6) dhcpv6.DHCPv6 {
+		d.(*dhcpv6.DHCPv6Message).SetMessage(dhcpv6.MessageTypeLeaseQuery)
+		d.AddOption(&dhcpv6.OptClientId{Cid: requestorID})
+		return d
+	})
+	require.NoError(t, err)
+	m, err := r.Reply(q)
+	require.NoError(t, err)
+	require.Equal(t, iana.StatusMalformedQuery, status(m))
+}
+
+func TestResponderHandle(t *testing.T) {
+	r := &Responder{Store: newTestStore(), ServerID: serverID}
+	conn, err := net.ListenPacket("udp6", "[::1]:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	peer, err := net.ListenPacket("udp6", "[::1]:0")
+	require.NoError(t, err)
+	defer peer.Close()
+
+	q := newQuery(t, dhcpv6.QueryByClientID, &dhcpv6.OptClientId{Cid: duidLL(10)})
+	r.Handle(conn, peer.LocalAddr(), q)
+
+	buf := make([]byte, dhcpv6.MaxUDPReceivedPacketSize)
+	n, _, err := peer.ReadFrom(buf)
+	require.NoError(t, err)
+	m, err := dhcpv6.FromBytes(buf[:n])
+	require.NoError(t, err)
+	require.Equal(t, dhcpv6.MessageTypeLeaseQueryReply, m.Type())
+	require.NotNil(t, m.GetOneOption(dhcpv6.OptionClientData))
+}
@@ -0,0 +1,42 @@
+// Package leasequery implements the server side of DHCPv6 Leasequery as
+// described by RFC 5007, and DHCPv6 Bulk Leasequery over TCP as described by
+// RFC 5460.
+//
+// A Responder answers UDP leasequeries and can be used directly as a
+// dhcpv6.Handler, while BulkServer and BulkClient implement the TCP side of
+// Bulk Leasequery. Queries are built with dhcpv6.NewLeaseQuery, and the
+// server sides look bindings up in a user-provided LeaseStore.
+package leasequery
+
+import (
+	"errors"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+)
+
+// Errors a LeaseStore can return to have the server reply with the matching
+// status code. Any other error is reported as UnspecFail.
+var (
+	ErrUnknownQueryType = errors.New("unknown query type")
+	ErrMalformedQuery   = errors.New("malformed query")
+	ErrNotConfigured    = errors.New("server not configured for the queried link")
+	ErrNotAllowed       = errors.New("query not allowed")
+)
+
+// Binding holds what a server knows about one client's bindings.
+type Binding struct {
+	// ClientData carries the client's OptClientId, OptIAAddress and
+	// OptIAPrefix options and an OptCLTTime.
+	ClientData *dhcpv6.OptClientData
+
+	// RelayData is the relay message the client's last request came in, if
+	// any.
+	RelayData *dhcpv6.OptLQRelayData
+}
+
+// LeaseStore is the lease database a Responder or BulkServer answers
+// queries from. Lookup returns the bindings matching query, or no binding if
+// there are none.
+type LeaseStore interface {
+	Lookup(query *dhcpv6.OptLQQuery) ([]*Binding, error)
+}
@@ -82,6 +82,54 @@ func WithIANA(addrs ...OptIAAddress) Modifier {
 	}
 }
 
+// WithIATA adds or updates an OptIATA option with the provided IAAddress
+// options
+func WithIATA(addrs ...OptIAAddress) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		opt := d.GetOneOption(OptionIATA)
+		if opt == nil {
+			opt = &OptIATA{}
+		}
+		iaTa := opt.(*OptIATA)
+		for _, addr := range addrs {
+			addr := addr
+			iaTa.AddOption(&addr)
+		}
+		d.UpdateOption(iaTa)
+		return d
+	}
+}
+
+// WithPreference adds or updates an OptPreference
+func WithPreference(pref uint8) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptPreference{Preference: pref})
+		return d
+	}
+}
+
+// WithServerUnicast adds or updates an OptUnicast, allowing clients to send
+// their messages directly to the server at the given address
+func WithServerUnicast(addr net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptUnicast{ServerAddress: addr})
+		return d
+	}
+}
+
+// WithClientLinkLayerAddress adds or updates an OptClientLinkLayerAddress. It
+// is meant for Relay-Forward messages built by the relay agent directly
+// connected to the client, which has to pass it to EncapsulateRelay itself.
+func WithClientLinkLayerAddress(htype iana.HWType, lladdr net.HardwareAddr) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptClientLinkLayerAddress{
+			LinkLayerType:    htype,
+			LinkLayerAddress: lladdr,
+		})
+		return d
+	}
+}
+
 // WithDNS adds or updates an OptDNSRecursiveNameServer
 func WithDNS(dnses ...net.IP) Modifier {
 	return func(d DHCPv6) DHCPv6 {
@@ -93,6 +141,132 @@ func WithDNS(dnses ...net.IP) Modifier {
 	}
 }
 
+// WithNTPServers adds or updates an OptNTPServer with the given server
+// addresses
+func WithNTPServers(servers ...net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		ontp := OptNTPServer{}
+		for _, s := range servers {
+			ontp.Suboptions = append(ontp.Suboptions, NTPSuboption{Code: NTPSuboptionSrvAddr, IP: s})
+		}
+		d.UpdateOption(&ontp)
+		return d
+	}
+}
+
+// WithSNTPServers adds or updates an OptSNTPServerList
+func WithSNTPServers(servers ...net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		osntp := OptSNTPServerList{
+			Servers: append([]net.IP{}, servers...),
+		}
+		d.UpdateOption(&osntp)
+		return d
+	}
+}
+
+// WithAFTRName adds or updates an OptAFTRName
+func WithAFTRName(name string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptAFTRName{Name: name})
+		return d
+	}
+}
+
+// WithCaptivePortal adds or updates an OptCaptivePortal
+func WithCaptivePortal(uri string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptCaptivePortal{URI: uri})
+		return d
+	}
+}
+
+// WithSIPServerDomainNames adds or updates an OptSIPServerDomainNameList
+func WithSIPServerDomainNames(names ...string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptSIPServerDomainNameList{
+			DomainNameList: &rfc1035label.Labels{Labels: names},
+		})
+		return d
+	}
+}
+
+// WithSIPServerAddresses adds or updates an OptSIPServerAddressList
+func WithSIPServerAddresses(addrs ...net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptSIPServerAddressList{Addresses: append([]net.IP{}, addrs...)})
+		return d
+	}
+}
+
+// WithNISServers adds or updates an OptNISServers
+func WithNISServers(addrs ...net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptNISServers{Addresses: append([]net.IP{}, addrs...)})
+		return d
+	}
+}
+
+// WithNISPServers adds or updates an OptNISPServers
+func WithNISPServers(addrs ...net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptNISPServers{Addresses: append([]net.IP{}, addrs...)})
+		return d
+	}
+}
+
+// WithNISDomainName adds or updates an OptNISDomainName
+func WithNISDomainName(name string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptNISDomainName{DomainName: name})
+		return d
+	}
+}
+
+// WithNISPDomainName adds or updates an OptNISPDomainName
+func WithNISPDomainName(name string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptNISPDomainName{DomainName: name})
+		return d
+	}
+}
+
+// WithBCMCSControllerDomainNames adds or updates an
+// OptBCMCSControllerDomainNameList
+func WithBCMCSControllerDomainNames(names ...string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptBCMCSControllerDomainNameList{
+			DomainNameList: &rfc1035label.Labels{Labels: names},
+		})
+		return d
+	}
+}
+
+// WithBCMCSControllerAddresses adds or updates an
+// OptBCMCSControllerAddressList
+func WithBCMCSControllerAddresses(addrs ...net.IP) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptBCMCSControllerAddressList{Addresses: append([]net.IP{}, addrs...)})
+		return d
+	}
+}
+
+// WithPOSIXTimezone adds or updates an OptNewPOSIXTimezone
+func WithPOSIXTimezone(tz string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptNewPOSIXTimezone{Timezone: tz})
+		return d
+	}
+}
+
+// WithTZDBTimezone adds or updates an OptNewTZDBTimezone
+func WithTZDBTimezone(tz string) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptNewTZDBTimezone{Timezone: tz})
+		return d
+	}
+}
+
 // WithDomainSearchList adds or updates an OptDomainSearchList
 func WithDomainSearchList(searchlist ...string) Modifier {
 	return func(d DHCPv6) DHCPv6 {
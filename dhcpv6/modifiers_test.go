@@ -92,3 +92,100 @@ func TestWithDomainSearchList(t *testing.T) {
 	require.Equal(t, "slackware.it", labels[0])
 	require.Equal(t, "dhcp.slackware.it", labels[1])
 }
+
+func TestWithNTPServers(t *testing.T) {
+	d := WithNTPServers(net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2"))(&DHCPv6Message{})
+	require.Equal(t, 1, len(d.Options()))
+	ontp := d.Options()[0].(*OptNTPServer)
+	require.Equal(t, OptionNTPServer, ontp.Code())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")}, ontp.Addresses())
+	require.Equal(t, NTPSuboptionSrvAddr, ontp.Suboptions[0].Code)
+}
+
+func TestWithSNTPServers(t *testing.T) {
+	d := WithSNTPServers(net.ParseIP("2001:db8::1"))(&DHCPv6Message{})
+	require.Equal(t, 1, len(d.Options()))
+	osntp := d.Options()[0].(*OptSNTPServerList)
+	require.Equal(t, OptionSNTPServerList, osntp.Code())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, osntp.Servers)
+}
+
+func TestWithCaptivePortal(t *testing.T) {
+	d := WithCaptivePortal("https://example.org/captive")(&DHCPv6Message{})
+	require.Equal(t, 1, len(d.Options()))
+	require.Equal(t, "https://example.org/captive", d.(*DHCPv6Message).CaptivePortal())
+	require.Contains(t, d.Summary(), "OptCaptivePortal{uri=https://example.org/captive}")
+}
+
+func TestWithIATA(t *testing.T) {
+	d := WithIATA(OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1")})(&DHCPv6Message{})
+	require.Equal(t, 1, len(d.Options()))
+	iata := d.Options()[0].(*OptIATA)
+	require.Equal(t, OptionIATA, iata.Code())
+	require.Equal(t, net.ParseIP("2001:db8::1"), iata.GetOneOption(OptionIAAddr).(*OptIAAddress).IPv6Addr)
+
+	// adding to an existing IA_TA keeps a single option
+	d = WithIATA(OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::2")})(d)
+	require.Equal(t, 1, len(d.Options()))
+}
+
+func TestWithPreference(t *testing.T) {
+	d := WithPreference(42)(&DHCPv6Message{})
+	require.Equal(t, 1, len(d.Options()))
+	require.Equal(t, uint8(42), d.(*DHCPv6Message).Preference())
+	d = WithPreference(MaxPreference)(d)
+	require.Equal(t, 1, len(d.Options()))
+	require.Equal(t, MaxPreference, d.(*DHCPv6Message).Preference())
+}
+
+func TestWithServerUnicast(t *testing.T) {
+	d := WithServerUnicast(net.ParseIP("2001:db8::1"))(&DHCPv6Message{})
+	require.Equal(t, 1, len(d.Options()))
+	require.Equal(t, net.ParseIP("2001:db8::1"), d.(*DHCPv6Message).ServerUnicast())
+}
+
+func TestWithSIPServers(t *testing.T) {
+	d := WithSIPServerDomainNames("sip.example.com")(&DHCPv6Message{})
+	d = WithSIPServerAddresses(net.ParseIP("2001:db8::1"))(d)
+	require.Equal(t, 2, len(d.Options()))
+	require.Equal(t, []string{"sip.example.com"}, d.GetOneOption(OptionSIPServersDomainNameList).(*OptSIPServerDomainNameList).DomainNameList.Labels)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, d.GetOneOption(OptionSIPServersIPv6AddressList).(*OptSIPServerAddressList).Addresses)
+}
+
+func TestWithNIS(t *testing.T) {
+	d := WithNISServers(net.ParseIP("2001:db8::1"))(&DHCPv6Message{})
+	d = WithNISPServers(net.ParseIP("2001:db8::2"))(d)
+	d = WithNISDomainName("nis.example.com")(d)
+	d = WithNISPDomainName("nisplus.example.com")(d)
+	require.Equal(t, 4, len(d.Options()))
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, d.GetOneOption(OptionNISServers).(*OptNISServers).Addresses)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::2")}, d.GetOneOption(OptionNISPServers).(*OptNISPServers).Addresses)
+	require.Equal(t, "nis.example.com", d.GetOneOption(OptionNISDomainName).(*OptNISDomainName).DomainName)
+	require.Equal(t, "nisplus.example.com", d.GetOneOption(OptionNISPDomainName).(*OptNISPDomainName).DomainName)
+}
+
+func TestWithBCMCSControllers(t *testing.T) {
+	d := WithBCMCSControllerDomainNames("bcmcs.example.com")(&DHCPv6Message{})
+	d = WithBCMCSControllerAddresses(net.ParseIP("2001:db8::1"))(d)
+	require.Equal(t, 2, len(d.Options()))
+	require.Equal(t, []string{"bcmcs.example.com"}, d.GetOneOption(OptionBCMCSControllerDomainNameList).(*OptBCMCSControllerDomainNameList).DomainNameList.Labels)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, d.GetOneOption(OptionBCMCSControllerIPv6AddressList).(*OptBCMCSControllerAddressList).Addresses)
+}
+
+func TestWithTimezones(t *testing.T) {
+	d := WithPOSIXTimezone("CET-1CEST")(&DHCPv6Message{})
+	d = WithTZDBTimezone("Europe/Zurich")(d)
+	require.Equal(t, 2, len(d.Options()))
+	require.Equal(t, "CET-1CEST", d.GetOneOption(OptionNewPOSIXTimezone).(*OptNewPOSIXTimezone).Timezone)
+	require.Equal(t, "Europe/Zurich", d.GetOneOption(OptionNewTZDBTimezone).(*OptNewTZDBTimezone).Timezone)
+}
+
+func TestWithClientLinkLayerAddress(t *testing.T) {
+	mac := net.HardwareAddr{0x24, 0x8a, 0x07, 0x56, 0xdc, 0xa4}
+	d := WithClientLinkLayerAddress(iana.HWTypeEthernet, mac)(&DHCPv6Relay{})
+	require.Equal(t, 1, len(d.Options()))
+	opt := d.Options()[0].(*OptClientLinkLayerAddress)
+	require.Equal(t, OptionClientLinkLayerAddr, opt.Code())
+	require.Equal(t, iana.HWTypeEthernet, opt.LinkLayerType)
+	require.Equal(t, mac, opt.LinkLayerAddress)
+}
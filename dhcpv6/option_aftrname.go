@@ -0,0 +1,56 @@
+package dhcpv6
+
+// This module defines the OptAFTRName structure.
+// https://www.ietf.org/rfc/rfc6334.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/insomniacslk/dhcp/rfc1035label"
+)
+
+// OptAFTRName represents an AFTR-Name option, the name of the Address Family
+// Transition Router a DS-Lite B4 element tunnels its IPv4 traffic to
+type OptAFTRName struct {
+	Name string
+}
+
+// Code returns the option code
+func (op *OptAFTRName) Code() OptionCode {
+	return OptionAFTRName
+}
+
+func (op *OptAFTRName) data() []byte {
+	return (&rfc1035label.Labels{Labels: []string{op.Name}}).ToBytes()
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptAFTRName) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionAFTRName))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.data()...)
+}
+
+// Length returns the option length
+func (op *OptAFTRName) Length() int {
+	return len(op.data())
+}
+
+func (op *OptAFTRName) String() string {
+	return fmt.Sprintf("OptAFTRName{name=%v}", op.Name)
+}
+
+// ParseOptAFTRName builds an OptAFTRName structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptAFTRName(data []byte) (*OptAFTRName, error) {
+	labels, err := rfc1035label.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels.Labels) != 1 {
+		return nil, fmt.Errorf("Invalid OptAFTRName data: expected exactly one name, got %d", len(labels.Labels))
+	}
+	return &OptAFTRName{Name: labels.Labels[0]}, nil
+}
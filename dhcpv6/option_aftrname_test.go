@@ -0,0 +1,40 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var sampleOptAFTRNameData = []byte{
+	4, 'a', 'f', 't', 'r', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+}
+
+func TestParseOptAFTRName(t *testing.T) {
+	opt, err := ParseOptAFTRName(sampleOptAFTRNameData)
+	require.NoError(t, err)
+	require.Equal(t, OptionAFTRName, opt.Code())
+	require.Equal(t, "aftr.example.com", opt.Name)
+	require.Equal(t, len(sampleOptAFTRNameData), opt.Length())
+	require.Equal(t, "OptAFTRName{name=aftr.example.com}", opt.String())
+}
+
+func TestOptAFTRNameToBytes(t *testing.T) {
+	opt := OptAFTRName{Name: "aftr.example.com"}
+	expected := append([]byte{0, 64, 0, byte(len(sampleOptAFTRNameData))}, sampleOptAFTRNameData...)
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestParseOptAFTRNameInvalid(t *testing.T) {
+	_, err := ParseOptAFTRName([]byte{4, 'a', 'f'})
+	require.Error(t, err)
+	_, err = ParseOptAFTRName([]byte{})
+	require.Error(t, err)
+}
+
+func TestParseOptionAFTRName(t *testing.T) {
+	data := append([]byte{0, 64, 0, byte(len(sampleOptAFTRNameData))}, sampleOptAFTRNameData...)
+	opt, err := ParseOption(data)
+	require.NoError(t, err)
+	require.IsType(t, &OptAFTRName{}, opt)
+}
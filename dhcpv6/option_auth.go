@@ -0,0 +1,100 @@
+package dhcpv6
+
+// This module defines the OptAuth structure.
+// https://www.ietf.org/rfc/rfc8415.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// AuthProtocol is the authentication protocol used in an OptAuth
+type AuthProtocol uint8
+
+// Authentication protocols, as defined by RFC 8415
+const (
+	AuthProtocolDelayed        AuthProtocol = 2
+	AuthProtocolReconfigureKey AuthProtocol = 3
+)
+
+// AuthProtocolToString maps an AuthProtocol to a name
+var AuthProtocolToString = map[AuthProtocol]string{
+	AuthProtocolDelayed:        "Delayed Authentication",
+	AuthProtocolReconfigureKey: "Reconfigure Key",
+}
+
+func (p AuthProtocol) String() string {
+	if s, ok := AuthProtocolToString[p]; ok {
+		return s
+	}
+	return "Unknown"
+}
+
+// AuthAlgorithm is the algorithm used to compute the authentication
+// information of an OptAuth
+type AuthAlgorithm uint8
+
+// Authentication algorithms, as defined by RFC 8415
+const (
+	AuthAlgorithmHMACMD5 AuthAlgorithm = 1
+)
+
+// RDM is the replay detection method used in an OptAuth
+type RDM uint8
+
+// Replay detection methods, as defined by RFC 8415
+const (
+	RDMMonotonicCounter RDM = 0
+)
+
+// OptAuth represents an Authentication option
+type OptAuth struct {
+	Protocol        AuthProtocol
+	Algorithm       AuthAlgorithm
+	RDM             RDM
+	ReplayDetection uint64
+	AuthInfo        []byte
+}
+
+// Code returns the option code
+func (op *OptAuth) Code() OptionCode {
+	return OptionAuth
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptAuth) ToBytes() []byte {
+	buf := make([]byte, 15)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionAuth))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf[4] = uint8(op.Protocol)
+	buf[5] = uint8(op.Algorithm)
+	buf[6] = uint8(op.RDM)
+	binary.BigEndian.PutUint64(buf[7:15], op.ReplayDetection)
+	buf = append(buf, op.AuthInfo...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptAuth) Length() int {
+	return 11 + len(op.AuthInfo)
+}
+
+func (op *OptAuth) String() string {
+	return fmt.Sprintf("OptAuth{protocol=%v, algorithm=%v, rdm=%v, replaydetection=%v, authinfo=%v}",
+		op.Protocol, op.Algorithm, op.RDM, op.ReplayDetection, op.AuthInfo)
+}
+
+// ParseOptAuth builds an OptAuth structure from a sequence of bytes. The
+// input data does not include option code and length bytes.
+func ParseOptAuth(data []byte) (*OptAuth, error) {
+	if len(data) < 11 {
+		return nil, fmt.Errorf("Invalid OptAuth data: length is shorter than 11")
+	}
+	opt := OptAuth{}
+	opt.Protocol = AuthProtocol(data[0])
+	opt.Algorithm = AuthAlgorithm(data[1])
+	opt.RDM = RDM(data[2])
+	opt.ReplayDetection = binary.BigEndian.Uint64(data[3:11])
+	opt.AuthInfo = append([]byte(nil), data[11:]...)
+	return &opt, nil
+}
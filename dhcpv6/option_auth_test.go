@@ -0,0 +1,51 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptAuth(t *testing.T) {
+	data := []byte{
+		3,                      // protocol
+		1,                      // algorithm
+		0,                      // RDM
+		0, 0, 0, 0, 0, 0, 0, 7, // replay detection
+		1, 0xaa, 0xbb, // auth info
+	}
+	opt, err := ParseOptAuth(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionAuth, opt.Code())
+	require.Equal(t, AuthProtocolReconfigureKey, opt.Protocol)
+	require.Equal(t, AuthAlgorithmHMACMD5, opt.Algorithm)
+	require.Equal(t, RDMMonotonicCounter, opt.RDM)
+	require.Equal(t, uint64(7), opt.ReplayDetection)
+	require.Equal(t, []byte{1, 0xaa, 0xbb}, opt.AuthInfo)
+	require.Equal(t, 14, opt.Length())
+	require.Contains(t, opt.String(), "protocol=Reconfigure Key")
+}
+
+func TestParseOptAuthInvalid(t *testing.T) {
+	_, err := ParseOptAuth([]byte{3, 1, 0, 0, 0, 0})
+	require.Error(t, err, "A short OptAuth should return an error")
+}
+
+func TestOptAuthToBytes(t *testing.T) {
+	opt := OptAuth{
+		Protocol:        AuthProtocolDelayed,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             RDMMonotonicCounter,
+		ReplayDetection: 0x0102030405060708,
+		AuthInfo:        []byte{0xaa},
+	}
+	expected := []byte{
+		0, 11, // OptionAuth
+		0, 12, // length
+		2, 1, 0,
+		1, 2, 3, 4, 5, 6, 7, 8,
+		0xaa,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+	require.Equal(t, "Unknown", AuthProtocol(42).String())
+}
@@ -0,0 +1,91 @@
+package dhcpv6
+
+// This module defines the OptBCMCSControllerDomainNameList and
+// OptBCMCSControllerAddressList structures.
+// https://www.ietf.org/rfc/rfc4280.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/insomniacslk/dhcp/rfc1035label"
+)
+
+// OptBCMCSControllerDomainNameList represents a Broadcast and Multicast
+// Service Controller Domain Name List option
+type OptBCMCSControllerDomainNameList struct {
+	DomainNameList *rfc1035label.Labels
+}
+
+// Code returns the option code
+func (op *OptBCMCSControllerDomainNameList) Code() OptionCode {
+	return OptionBCMCSControllerDomainNameList
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptBCMCSControllerDomainNameList) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionBCMCSControllerDomainNameList))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.DomainNameList.ToBytes()...)
+}
+
+// Length returns the option length
+func (op *OptBCMCSControllerDomainNameList) Length() int {
+	return len(op.DomainNameList.ToBytes())
+}
+
+func (op *OptBCMCSControllerDomainNameList) String() string {
+	return fmt.Sprintf("OptBCMCSControllerDomainNameList{domainnames=%v}", op.DomainNameList.Labels)
+}
+
+// ParseOptBCMCSControllerDomainNameList builds an
+// OptBCMCSControllerDomainNameList structure from a sequence of bytes. The
+// input data does not include option code and length bytes.
+func ParseOptBCMCSControllerDomainNameList(data []byte) (*OptBCMCSControllerDomainNameList, error) {
+	labels, err := rfc1035label.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OptBCMCSControllerDomainNameList{DomainNameList: labels}, nil
+}
+
+// OptBCMCSControllerAddressList represents a Broadcast and Multicast Service
+// Controller IPv6 Address option
+type OptBCMCSControllerAddressList struct {
+	Addresses []net.IP
+}
+
+// Code returns the option code
+func (op *OptBCMCSControllerAddressList) Code() OptionCode {
+	return OptionBCMCSControllerIPv6AddressList
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptBCMCSControllerAddressList) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionBCMCSControllerIPv6AddressList))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, ipv6AddressesToBytes(op.Addresses)...)
+}
+
+// Length returns the option length
+func (op *OptBCMCSControllerAddressList) Length() int {
+	return len(op.Addresses) * net.IPv6len
+}
+
+func (op *OptBCMCSControllerAddressList) String() string {
+	return fmt.Sprintf("OptBCMCSControllerAddressList{addresses=%v}", op.Addresses)
+}
+
+// ParseOptBCMCSControllerAddressList builds an OptBCMCSControllerAddressList
+// structure from a sequence of bytes. The input data does not include option
+// code and length bytes.
+func ParseOptBCMCSControllerAddressList(data []byte) (*OptBCMCSControllerAddressList, error) {
+	addrs, err := parseIPv6Addresses(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OptBCMCSControllerAddressList data: %v", err)
+	}
+	return &OptBCMCSControllerAddressList{Addresses: addrs}, nil
+}
@@ -0,0 +1,60 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/rfc1035label"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptBCMCSControllerDomainNameList(t *testing.T) {
+	data := []byte{5, 'b', 'c', 'm', 'c', 's', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}
+	opt, err := ParseOptBCMCSControllerDomainNameList(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionBCMCSControllerDomainNameList, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, []string{"bcmcs.example.com"}, opt.DomainNameList.Labels)
+}
+
+func TestOptBCMCSControllerDomainNameListToBytes(t *testing.T) {
+	opt := OptBCMCSControllerDomainNameList{
+		DomainNameList: &rfc1035label.Labels{Labels: []string{"bcmcs.example.com"}},
+	}
+	expected := []byte{
+		0, 33, // OptionBCMCSControllerDomainNameList
+		0, 19, // length
+		5, 'b', 'c', 'm', 'c', 's', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bcmcs.example.com"}, parsed.(*OptBCMCSControllerDomainNameList).DomainNameList.Labels)
+}
+
+func TestParseOptBCMCSControllerAddressList(t *testing.T) {
+	data := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	opt, err := ParseOptBCMCSControllerAddressList(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionBCMCSControllerIPv6AddressList, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, opt.Addresses)
+
+	_, err = ParseOptBCMCSControllerAddressList(data[:8])
+	require.Error(t, err)
+}
+
+func TestOptBCMCSControllerAddressListToBytes(t *testing.T) {
+	opt := OptBCMCSControllerAddressList{Addresses: []net.IP{net.ParseIP("2001:db8::1")}}
+	expected := []byte{
+		0, 34, // OptionBCMCSControllerIPv6AddressList
+		0, 16, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
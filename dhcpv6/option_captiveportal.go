@@ -0,0 +1,43 @@
+package dhcpv6
+
+// This module defines the OptCaptivePortal structure.
+// https://www.ietf.org/rfc/rfc8910.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptCaptivePortal represents a Captive-Portal option, the URI of the API
+// endpoint a client queries to learn about the captive portal of the network
+type OptCaptivePortal struct {
+	URI string
+}
+
+// Code returns the option code
+func (op *OptCaptivePortal) Code() OptionCode {
+	return OptionCaptivePortal
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptCaptivePortal) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionCaptivePortal))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(len(op.URI)))
+	return append(buf, op.URI...)
+}
+
+// Length returns the option length in bytes
+func (op *OptCaptivePortal) Length() int {
+	return len(op.URI)
+}
+
+func (op *OptCaptivePortal) String() string {
+	return fmt.Sprintf("OptCaptivePortal{uri=%s}", op.URI)
+}
+
+// ParseOptCaptivePortal builds an OptCaptivePortal structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptCaptivePortal(data []byte) (*OptCaptivePortal, error) {
+	return &OptCaptivePortal{URI: string(data)}, nil
+}
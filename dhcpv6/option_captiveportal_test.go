@@ -0,0 +1,27 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptCaptivePortal(t *testing.T) {
+	data := []byte("https://example.org/captive")
+	opt, err := ParseOptCaptivePortal(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionCaptivePortal, opt.Code())
+	require.Equal(t, "https://example.org/captive", opt.URI)
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, "OptCaptivePortal{uri=https://example.org/captive}", opt.String())
+}
+
+func TestOptCaptivePortalToBytes(t *testing.T) {
+	opt := OptCaptivePortal{URI: "https://example.org/captive"}
+	expected := append([]byte{0, 103, 0, 27}, "https://example.org/captive"...)
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
@@ -0,0 +1,74 @@
+package dhcpv6
+
+// This module defines the OptClientData structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptClientData represents an OptionClientData. It holds the bindings of a
+// single client in a leasequery reply: its OptClientId, OptIAAddress and
+// OptIAPrefix options and an OptCLTTime.
+type OptClientData struct {
+	Options []Option
+}
+
+// Code returns the option code
+func (op *OptClientData) Code() OptionCode {
+	return OptionClientData
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptClientData) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionClientData))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	for _, opt := range op.Options {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptClientData) Length() int {
+	l := 0
+	for _, opt := range op.Options {
+		l += 4 + opt.Length()
+	}
+	return l
+}
+
+func (op *OptClientData) String() string {
+	return fmt.Sprintf("OptClientData{options=%v}", op.Options)
+}
+
+// AddOption adds an option at the end of the client data options
+func (op *OptClientData) AddOption(opt Option) {
+	op.Options = append(op.Options, opt)
+}
+
+// GetOption will get all the options of the given type from the Options
+// field
+func (op *OptClientData) GetOption(code OptionCode) []Option {
+	return getOptions(op.Options, code, false)
+}
+
+// GetOneOption will get an option of the give type from the Options field, if
+// it is present. It will return `nil` otherwise
+func (op *OptClientData) GetOneOption(code OptionCode) Option {
+	return getOption(op.Options, code)
+}
+
+// ParseOptClientData builds an OptClientData structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptClientData(data []byte) (*OptClientData, error) {
+	var err error
+	opt := OptClientData{}
+	opt.Options, err = OptionsFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &opt, nil
+}
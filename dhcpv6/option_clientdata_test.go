@@ -0,0 +1,49 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptClientData(t *testing.T) {
+	data := []byte{
+		0, 1, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5, // client ID
+		0, 46, 0, 4, 0, 0, 0, 60, // CLT time
+	}
+	opt, err := ParseOptClientData(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionClientData, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Len(t, opt.Options, 2)
+	require.Equal(t, &OptCLTTime{CLTTime: 60}, opt.GetOneOption(OptionCLTTime))
+	require.Len(t, opt.GetOption(OptionClientID), 1)
+	require.Nil(t, opt.GetOneOption(OptionIAAddr))
+}
+
+func TestParseOptClientDataInvalid(t *testing.T) {
+	_, err := ParseOptClientData([]byte{0, 46, 0, 4, 0})
+	require.Error(t, err, "Broken client data options should return an error")
+}
+
+func TestOptClientDataToBytes(t *testing.T) {
+	opt := OptClientData{}
+	opt.AddOption(&OptIAAddress{
+		IPv6Addr:          net.ParseIP("2001:db8::10"),
+		PreferredLifetime: 1,
+		ValidLifetime:     2,
+	})
+	opt.AddOption(&OptCLTTime{CLTTime: 60})
+	expected := []byte{
+		0, 45, // OptionClientData
+		0, 36, // length
+		0, 5, 0, 24, // OptionIAAddr
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+		0, 0, 0, 1,
+		0, 0, 0, 2,
+		0, 46, 0, 4, 0, 0, 0, 60, // OptionCLTTime
+	}
+	require.Equal(t, expected, opt.ToBytes())
+	require.Contains(t, opt.String(), "OptCLTTime{clttime=60}")
+}
@@ -0,0 +1,58 @@
+package dhcpv6
+
+// This module defines the OptClientLinkLayerAddress structure.
+// https://www.ietf.org/rfc/rfc6939.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// OptClientLinkLayerAddress represents a Client Link-Layer Address option. It
+// is inserted by the relay agent directly connected to the client into the
+// Relay-Forward message, and carries the client's link-layer address as seen
+// by that relay.
+type OptClientLinkLayerAddress struct {
+	LinkLayerType    iana.HWType
+	LinkLayerAddress net.HardwareAddr
+}
+
+// Code returns the option code
+func (op *OptClientLinkLayerAddress) Code() OptionCode {
+	return OptionClientLinkLayerAddr
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptClientLinkLayerAddress) ToBytes() []byte {
+	buf := make([]byte, 6)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionClientLinkLayerAddr))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	binary.BigEndian.PutUint16(buf[4:6], uint16(op.LinkLayerType))
+	return append(buf, op.LinkLayerAddress...)
+}
+
+// Length returns the option length
+func (op *OptClientLinkLayerAddress) Length() int {
+	return 2 + len(op.LinkLayerAddress)
+}
+
+func (op *OptClientLinkLayerAddress) String() string {
+	return fmt.Sprintf("OptClientLinkLayerAddress{linklayertype=%v, linklayeraddress=%v}",
+		op.LinkLayerType, op.LinkLayerAddress)
+}
+
+// ParseOptClientLinkLayerAddress builds an OptClientLinkLayerAddress structure
+// from a sequence of bytes. The input data does not include option code and
+// length bytes.
+func ParseOptClientLinkLayerAddress(data []byte) (*OptClientLinkLayerAddress, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("Invalid OptClientLinkLayerAddress data: length is %d, expected at least 2", len(data))
+	}
+	return &OptClientLinkLayerAddress{
+		LinkLayerType:    iana.HWType(binary.BigEndian.Uint16(data[0:2])),
+		LinkLayerAddress: net.HardwareAddr(append([]byte(nil), data[2:]...)),
+	}, nil
+}
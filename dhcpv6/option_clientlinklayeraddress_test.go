@@ -0,0 +1,46 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptClientLinkLayerAddress(t *testing.T) {
+	data := []byte{
+		0, 1, // ethernet
+		0x24, 0x8a, 0x07, 0x56, 0xdc, 0xa4,
+	}
+	opt, err := ParseOptClientLinkLayerAddress(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionClientLinkLayerAddr, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, iana.HWTypeEthernet, opt.LinkLayerType)
+	require.Equal(t, net.HardwareAddr{0x24, 0x8a, 0x07, 0x56, 0xdc, 0xa4}, opt.LinkLayerAddress)
+	require.Equal(t, "OptClientLinkLayerAddress{linklayertype=Ethernet, linklayeraddress=24:8a:07:56:dc:a4}", opt.String())
+}
+
+func TestParseOptClientLinkLayerAddressInvalidLength(t *testing.T) {
+	_, err := ParseOptClientLinkLayerAddress([]byte{0})
+	require.Error(t, err)
+}
+
+func TestOptClientLinkLayerAddressToBytes(t *testing.T) {
+	opt := OptClientLinkLayerAddress{
+		LinkLayerType:    iana.HWTypeEthernet,
+		LinkLayerAddress: net.HardwareAddr{0x24, 0x8a, 0x07, 0x56, 0xdc, 0xa4},
+	}
+	expected := []byte{
+		0, 79, // OptionClientLinkLayerAddr
+		0, 8, // length
+		0, 1, // ethernet
+		0x24, 0x8a, 0x07, 0x56, 0xdc, 0xa4,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
@@ -0,0 +1,49 @@
+package dhcpv6
+
+// This module defines the OptCLTTime structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptCLTTime represents an OptionCLTTime, the number of seconds since the
+// server last communicated with the client.
+type OptCLTTime struct {
+	CLTTime uint32
+}
+
+// Code returns the option code
+func (op *OptCLTTime) Code() OptionCode {
+	return OptionCLTTime
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptCLTTime) ToBytes() []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionCLTTime))
+	binary.BigEndian.PutUint16(buf[2:4], 4)
+	binary.BigEndian.PutUint32(buf[4:8], op.CLTTime)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptCLTTime) Length() int {
+	return 4
+}
+
+func (op *OptCLTTime) String() string {
+	return fmt.Sprintf("OptCLTTime{clttime=%v}", op.CLTTime)
+}
+
+// ParseOptCLTTime builds an OptCLTTime structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptCLTTime(data []byte) (*OptCLTTime, error) {
+	if len(data) != 4 {
+		return nil, fmt.Errorf("Invalid CLT time data length. Expected 4 bytes, got %v", len(data))
+	}
+	opt := OptCLTTime{}
+	opt.CLTTime = binary.BigEndian.Uint32(data)
+	return &opt, nil
+}
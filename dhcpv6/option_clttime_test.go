@@ -0,0 +1,26 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptCLTTime(t *testing.T) {
+	opt, err := ParseOptCLTTime([]byte{0, 0, 0x0e, 0x10})
+	require.NoError(t, err)
+	require.Equal(t, OptionCLTTime, opt.Code())
+	require.Equal(t, uint32(3600), opt.CLTTime)
+	require.Equal(t, 4, opt.Length())
+	require.Equal(t, "OptCLTTime{clttime=3600}", opt.String())
+}
+
+func TestParseOptCLTTimeInvalid(t *testing.T) {
+	_, err := ParseOptCLTTime([]byte{0, 0, 0x0e})
+	require.Error(t, err, "A short CLT time should return an error")
+}
+
+func TestOptCLTTimeToBytes(t *testing.T) {
+	opt := OptCLTTime{CLTTime: 3600}
+	require.Equal(t, []byte{0, 46, 0, 4, 0, 0, 0x0e, 0x10}, opt.ToBytes())
+}
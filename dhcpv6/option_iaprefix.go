@@ -78,6 +78,78 @@ func (op *OptIAPrefix) DelOption(code OptionCode) {
 	op.Options = delOption(op.Options, code)
 }
 
+// maxSubPrefixes caps the number of prefixes returned by SubPrefixes.
+const maxSubPrefixes = 1 << 16
+
+// prefix returns the delegated prefix as a net.IPNet.
+func (op *OptIAPrefix) prefix() net.IPNet {
+	return net.IPNet{
+		IP:   op.ipv6Prefix,
+		Mask: net.CIDRMask(int(op.prefixLength), 128),
+	}
+}
+
+// ExcludedPrefix returns the prefix excluded from the delegated prefix by an
+// OptPDExclude, or nil if the option is not present.
+func (op *OptIAPrefix) ExcludedPrefix() (*net.IPNet, error) {
+	opt := op.GetOneOption(OptionPDExclude)
+	if opt == nil {
+		return nil, nil
+	}
+	return opt.(*OptPDExclude).Prefix(op.prefix())
+}
+
+// SetExcludedPrefix adds or replaces the OptPDExclude of the delegated prefix.
+// It returns an error if excluded does not lie within the delegated prefix.
+func (op *OptIAPrefix) SetExcludedPrefix(excluded net.IPNet) error {
+	opt, err := NewOptPDExclude(op.prefix(), excluded)
+	if err != nil {
+		return err
+	}
+	op.DelOption(OptionPDExclude)
+	op.Options = append(op.Options, opt)
+	return nil
+}
+
+// SubPrefixes splits the delegated prefix into prefixes of the given length,
+// for a requesting router to assign to its downstream links. The prefixes that
+// overlap the excluded prefix, if any, are left out as required by RFC 6603.
+func (op *OptIAPrefix) SubPrefixes(length int) ([]net.IPNet, error) {
+	plen := int(op.prefixLength)
+	if length < plen || length > 128 {
+		return nil, fmt.Errorf("cannot split a /%d into /%d prefixes", plen, length)
+	}
+	if length-plen > 16 {
+		return nil, fmt.Errorf("a /%d contains more than %d /%d prefixes", plen, maxSubPrefixes, length)
+	}
+	excluded, err := op.ExcludedPrefix()
+	if err != nil {
+		return nil, err
+	}
+	base := op.prefix()
+	base.IP = op.ipv6Prefix.To16().Mask(base.Mask)
+	if base.IP == nil {
+		return nil, fmt.Errorf("invalid IPv6 prefix %v", op.ipv6Prefix)
+	}
+	n := 1 << uint(length-plen)
+	prefixes := make([]net.IPNet, 0, n)
+	for i := 0; i < n; i++ {
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, base.IP)
+		for b := 0; b < length-plen; b++ {
+			if i>>uint(length-plen-1-b)&1 == 1 {
+				setBit(ip, plen+b)
+			}
+		}
+		sub := net.IPNet{IP: ip, Mask: net.CIDRMask(length, 128)}
+		if excluded != nil && (sub.Contains(excluded.IP) || excluded.Contains(sub.IP)) {
+			continue
+		}
+		prefixes = append(prefixes, sub)
+	}
+	return prefixes, nil
+}
+
 // build an OptIAPrefix structure from a sequence of bytes.
 // The input data does not include option code and length bytes.
 func ParseOptIAPrefix(data []byte) (*OptIAPrefix, error) {
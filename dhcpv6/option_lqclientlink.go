@@ -0,0 +1,54 @@
+package dhcpv6
+
+// This module defines the OptLQClientLink structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// OptLQClientLink represents an OptionLQClientLink. It lists the links on
+// which the queried client has bindings, when the query did not specify one.
+type OptLQClientLink struct {
+	LinkAddresses []net.IP
+}
+
+// Code returns the option code
+func (op *OptLQClientLink) Code() OptionCode {
+	return OptionLQClientLink
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptLQClientLink) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionLQClientLink))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	for _, addr := range op.LinkAddresses {
+		buf = append(buf, addr.To16()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptLQClientLink) Length() int {
+	return len(op.LinkAddresses) * net.IPv6len
+}
+
+func (op *OptLQClientLink) String() string {
+	return fmt.Sprintf("OptLQClientLink{linkaddresses=%v}", op.LinkAddresses)
+}
+
+// ParseOptLQClientLink builds an OptLQClientLink structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptLQClientLink(data []byte) (*OptLQClientLink, error) {
+	if len(data)%net.IPv6len != 0 {
+		return nil, fmt.Errorf("Invalid OptLQClientLink data: length is not a multiple of %d", net.IPv6len)
+	}
+	opt := OptLQClientLink{}
+	for i := 0; i < len(data); i += net.IPv6len {
+		opt.LinkAddresses = append(opt.LinkAddresses, append(net.IP(nil), data[i:i+net.IPv6len]...))
+	}
+	return &opt, nil
+}
@@ -0,0 +1,36 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptLQClientLink(t *testing.T) {
+	data := []byte{
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2,
+	}
+	opt, err := ParseOptLQClientLink(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionLQClientLink, opt.Code())
+	require.Equal(t, 32, opt.Length())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")}, opt.LinkAddresses)
+	require.Contains(t, opt.String(), "linkaddresses=[2001:db8::1 2001:db8::2]")
+}
+
+func TestParseOptLQClientLinkInvalid(t *testing.T) {
+	_, err := ParseOptLQClientLink([]byte{0x20, 0x01, 0x0d, 0xb8})
+	require.Error(t, err, "An invalid link address should return an error")
+}
+
+func TestOptLQClientLinkToBytes(t *testing.T) {
+	opt := OptLQClientLink{LinkAddresses: []net.IP{net.ParseIP("2001:db8::1")}}
+	expected := []byte{
+		0, 48, // OptionLQClientLink
+		0, 16, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
@@ -0,0 +1,65 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptLQQuery(t *testing.T) {
+	data := []byte{
+		2, // QueryByClientID
+
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, // link address
+		0, 1, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5, // client ID
+	}
+	opt, err := ParseOptLQQuery(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionLQQuery, opt.Code())
+	require.Equal(t, QueryByClientID, opt.QueryType)
+	require.Equal(t, net.ParseIP("2001:db8::1"), opt.LinkAddress)
+	require.Equal(t, len(data), opt.Length())
+	cid := opt.GetOneOption(OptionClientID)
+	require.NotNil(t, cid)
+	require.Equal(t, DUID_LL, cid.(*OptClientId).Cid.Type)
+	require.Contains(t, opt.String(), "querytype=QUERY_BY_CLIENTID")
+}
+
+func TestParseOptLQQueryInvalid(t *testing.T) {
+	_, err := ParseOptLQQuery([]byte{1, 0, 0, 0})
+	require.Error(t, err, "A short LQ query should return an error")
+
+	data := make([]byte, 17)
+	data = append(data, 0, 5, 0, 24) // truncated option
+	_, err = ParseOptLQQuery(data)
+	require.Error(t, err, "Broken query options should return an error")
+}
+
+func TestOptLQQueryToBytes(t *testing.T) {
+	opt := OptLQQuery{
+		QueryType:   QueryByAddress,
+		LinkAddress: net.IPv6zero,
+	}
+	opt.AddOption(&OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::10")})
+	expected := []byte{
+		0, 44, // OptionLQQuery
+		0, 45, // length
+		1,                                              // QueryByAddress
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // link address
+		0, 5, 0, 24, // OptionIAAddr
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+		0, 0, 0, 0,
+		0, 0, 0, 0,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(opt.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, opt.ToBytes(), parsed.ToBytes())
+}
+
+func TestLQQueryTypeString(t *testing.T) {
+	require.Equal(t, "QUERY_BY_REMOTE_ID", QueryByRemoteID.String())
+	require.Equal(t, "Unknown", LQQueryType(42).String())
+}
@@ -0,0 +1,67 @@
+package dhcpv6
+
+// This module defines the OptLQRelayData structure.
+// https://www.ietf.org/rfc/rfc5007.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// OptLQRelayData represents an OptionLQRelayData. It holds the relay message
+// the server last received for a client, and the address of the relay agent
+// closest to the server that forwarded it.
+type OptLQRelayData struct {
+	PeerAddress  net.IP
+	RelayMessage DHCPv6
+}
+
+// Code returns the option code
+func (op *OptLQRelayData) Code() OptionCode {
+	return OptionLQRelayData
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptLQRelayData) ToBytes() []byte {
+	buf := make([]byte, 20)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionLQRelayData))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	copy(buf[4:20], op.PeerAddress.To16())
+	if op.RelayMessage != nil {
+		buf = append(buf, op.RelayMessage.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptLQRelayData) Length() int {
+	l := net.IPv6len
+	if op.RelayMessage != nil {
+		l += op.RelayMessage.Length()
+	}
+	return l
+}
+
+func (op *OptLQRelayData) String() string {
+	return fmt.Sprintf("OptLQRelayData{peeraddress=%v, relaymessage=%v}",
+		op.PeerAddress, op.RelayMessage)
+}
+
+// ParseOptLQRelayData builds an OptLQRelayData structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptLQRelayData(data []byte) (*OptLQRelayData, error) {
+	var err error
+	if len(data) < net.IPv6len {
+		return nil, fmt.Errorf("Invalid LQ relay data length. Expected at least 16 bytes, got %v", len(data))
+	}
+	opt := OptLQRelayData{}
+	opt.PeerAddress = append(net.IP(nil), data[:net.IPv6len]...)
+	if len(data) > net.IPv6len {
+		opt.RelayMessage, err = FromBytes(data[net.IPv6len:])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &opt, nil
+}
@@ -0,0 +1,43 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptLQRelayData(t *testing.T) {
+	data := []byte{
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, // peer address
+		12, 0, // RELAY-FORW, hop count
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // link address
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // peer address
+		0, 37, 0, 6, 0, 0, 0, 1, 0xaa, 0xbb, // remote ID
+	}
+	opt, err := ParseOptLQRelayData(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionLQRelayData, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, net.ParseIP("2001:db8::1"), opt.PeerAddress)
+	require.Equal(t, MessageTypeRelayForward, opt.RelayMessage.Type())
+	rid := opt.RelayMessage.GetOneOption(OptionRemoteID)
+	require.NotNil(t, rid)
+	require.Equal(t, []byte{0xaa, 0xbb}, rid.(*OptRemoteId).RemoteID())
+	require.Equal(t, data, opt.ToBytes()[4:])
+}
+
+func TestParseOptLQRelayDataInvalid(t *testing.T) {
+	_, err := ParseOptLQRelayData([]byte{0x20, 0x01, 0x0d, 0xb8})
+	require.Error(t, err, "A short LQ relay data should return an error")
+}
+
+func TestOptLQRelayDataToBytes(t *testing.T) {
+	opt := OptLQRelayData{PeerAddress: net.ParseIP("2001:db8::1")}
+	expected := []byte{
+		0, 47, // OptionLQRelayData
+		0, 16, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
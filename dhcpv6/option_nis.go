@@ -0,0 +1,179 @@
+package dhcpv6
+
+// This module defines the OptNISServers, OptNISPServers, OptNISDomainName and
+// OptNISPDomainName structures.
+// https://www.ietf.org/rfc/rfc3898.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/insomniacslk/dhcp/rfc1035label"
+)
+
+// OptNISServers represents a Network Information Service (NIS) Servers
+// option
+type OptNISServers struct {
+	Addresses []net.IP
+}
+
+// Code returns the option code
+func (op *OptNISServers) Code() OptionCode {
+	return OptionNISServers
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISServers) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionNISServers))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, ipv6AddressesToBytes(op.Addresses)...)
+}
+
+// Length returns the option length
+func (op *OptNISServers) Length() int {
+	return len(op.Addresses) * net.IPv6len
+}
+
+func (op *OptNISServers) String() string {
+	return fmt.Sprintf("OptNISServers{addresses=%v}", op.Addresses)
+}
+
+// ParseOptNISServers builds an OptNISServers structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNISServers(data []byte) (*OptNISServers, error) {
+	addrs, err := parseIPv6Addresses(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OptNISServers data: %v", err)
+	}
+	return &OptNISServers{Addresses: addrs}, nil
+}
+
+// OptNISPServers represents a Network Information Service V2 (NIS+) Servers
+// option
+type OptNISPServers struct {
+	Addresses []net.IP
+}
+
+// Code returns the option code
+func (op *OptNISPServers) Code() OptionCode {
+	return OptionNISPServers
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISPServers) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionNISPServers))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, ipv6AddressesToBytes(op.Addresses)...)
+}
+
+// Length returns the option length
+func (op *OptNISPServers) Length() int {
+	return len(op.Addresses) * net.IPv6len
+}
+
+func (op *OptNISPServers) String() string {
+	return fmt.Sprintf("OptNISPServers{addresses=%v}", op.Addresses)
+}
+
+// ParseOptNISPServers builds an OptNISPServers structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNISPServers(data []byte) (*OptNISPServers, error) {
+	addrs, err := parseIPv6Addresses(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OptNISPServers data: %v", err)
+	}
+	return &OptNISPServers{Addresses: addrs}, nil
+}
+
+// OptNISDomainName represents a Network Information Service (NIS) Domain
+// Name option
+type OptNISDomainName struct {
+	DomainName string
+}
+
+// Code returns the option code
+func (op *OptNISDomainName) Code() OptionCode {
+	return OptionNISDomainName
+}
+
+func (op *OptNISDomainName) data() []byte {
+	return (&rfc1035label.Labels{Labels: []string{op.DomainName}}).ToBytes()
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISDomainName) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionNISDomainName))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.data()...)
+}
+
+// Length returns the option length
+func (op *OptNISDomainName) Length() int {
+	return len(op.data())
+}
+
+func (op *OptNISDomainName) String() string {
+	return fmt.Sprintf("OptNISDomainName{domainname=%v}", op.DomainName)
+}
+
+// ParseOptNISDomainName builds an OptNISDomainName structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNISDomainName(data []byte) (*OptNISDomainName, error) {
+	labels, err := rfc1035label.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels.Labels) != 1 {
+		return nil, fmt.Errorf("Invalid OptNISDomainName data: expected exactly one domain name, got %d", len(labels.Labels))
+	}
+	return &OptNISDomainName{DomainName: labels.Labels[0]}, nil
+}
+
+// OptNISPDomainName represents a Network Information Service V2 (NIS+)
+// Domain Name option
+type OptNISPDomainName struct {
+	DomainName string
+}
+
+// Code returns the option code
+func (op *OptNISPDomainName) Code() OptionCode {
+	return OptionNISPDomainName
+}
+
+func (op *OptNISPDomainName) data() []byte {
+	return (&rfc1035label.Labels{Labels: []string{op.DomainName}}).ToBytes()
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNISPDomainName) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionNISPDomainName))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.data()...)
+}
+
+// Length returns the option length
+func (op *OptNISPDomainName) Length() int {
+	return len(op.data())
+}
+
+func (op *OptNISPDomainName) String() string {
+	return fmt.Sprintf("OptNISPDomainName{domainname=%v}", op.DomainName)
+}
+
+// ParseOptNISPDomainName builds an OptNISPDomainName structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNISPDomainName(data []byte) (*OptNISPDomainName, error) {
+	labels, err := rfc1035label.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels.Labels) != 1 {
+		return nil, fmt.Errorf("Invalid OptNISPDomainName data: expected exactly one domain name, got %d", len(labels.Labels))
+	}
+	return &OptNISPDomainName{DomainName: labels.Labels[0]}, nil
+}
@@ -0,0 +1,92 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptNISServers(t *testing.T) {
+	data := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	opt, err := ParseOptNISServers(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionNISServers, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, opt.Addresses)
+
+	_, err = ParseOptNISServers(data[:15])
+	require.Error(t, err)
+}
+
+func TestParseOptNISPServers(t *testing.T) {
+	data := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2}
+	opt, err := ParseOptNISPServers(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionNISPServers, opt.Code())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::2")}, opt.Addresses)
+
+	_, err = ParseOptNISPServers(data[:15])
+	require.Error(t, err)
+}
+
+func TestOptNISServersToBytes(t *testing.T) {
+	opt := OptNISServers{Addresses: []net.IP{net.ParseIP("2001:db8::1")}}
+	expected := []byte{
+		0, 27, // OptionNISServers
+		0, 16, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
+
+func TestOptNISPServersToBytes(t *testing.T) {
+	opt := OptNISPServers{Addresses: []net.IP{net.ParseIP("2001:db8::2")}}
+	parsed, err := ParseOption(opt.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
+
+func TestParseOptNISDomainName(t *testing.T) {
+	data := []byte{3, 'n', 'i', 's', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}
+	opt, err := ParseOptNISDomainName(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionNISDomainName, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, "nis.example.com", opt.DomainName)
+	require.Equal(t, "OptNISDomainName{domainname=nis.example.com}", opt.String())
+}
+
+func TestParseOptNISDomainNameInvalid(t *testing.T) {
+	// two domain names
+	_, err := ParseOptNISDomainName([]byte{1, 'a', 0, 1, 'b', 0})
+	require.Error(t, err)
+	_, err = ParseOptNISPDomainName([]byte{})
+	require.Error(t, err)
+}
+
+func TestOptNISDomainNameToBytes(t *testing.T) {
+	opt := OptNISDomainName{DomainName: "nis.example.com"}
+	expected := []byte{
+		0, 29, // OptionNISDomainName
+		0, 17, // length
+		3, 'n', 'i', 's', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
+
+func TestOptNISPDomainNameToBytes(t *testing.T) {
+	opt := OptNISPDomainName{DomainName: "nisplus.example.com"}
+	parsed, err := ParseOption(opt.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+	require.Equal(t, OptionNISPDomainName, parsed.Code())
+}
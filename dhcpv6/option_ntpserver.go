@@ -0,0 +1,181 @@
+package dhcpv6
+
+// This module defines the OptNTPServer structure.
+// https://www.ietf.org/rfc/rfc5908.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/rfc1035label"
+)
+
+// NTPSuboptionCode is the code of a suboption of OptNTPServer
+type NTPSuboptionCode uint16
+
+// NTP suboption codes, as defined in RFC 5908, Section 4
+const (
+	NTPSuboptionSrvAddr NTPSuboptionCode = 1
+	NTPSuboptionMCAddr  NTPSuboptionCode = 2
+	NTPSuboptionSrvFQDN NTPSuboptionCode = 3
+)
+
+// NTPSuboptionCodeToString maps an NTPSuboptionCode to a name
+var NTPSuboptionCodeToString = map[NTPSuboptionCode]string{
+	NTPSuboptionSrvAddr: "NTP_SUBOPTION_SRV_ADDR",
+	NTPSuboptionMCAddr:  "NTP_SUBOPTION_MC_ADDR",
+	NTPSuboptionSrvFQDN: "NTP_SUBOPTION_SRV_FQDN",
+}
+
+func (c NTPSuboptionCode) String() string {
+	if s, ok := NTPSuboptionCodeToString[c]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (%d)", uint16(c))
+}
+
+// NTPSuboption is a time source of an OptNTPServer. IP is set for the
+// NTPSuboptionSrvAddr and NTPSuboptionMCAddr suboptions, and FQDN for the
+// NTPSuboptionSrvFQDN suboption
+type NTPSuboption struct {
+	Code NTPSuboptionCode
+	IP   net.IP
+	FQDN string
+}
+
+func (s NTPSuboption) data() []byte {
+	switch s.Code {
+	case NTPSuboptionSrvAddr, NTPSuboptionMCAddr:
+		return s.IP.To16()
+	case NTPSuboptionSrvFQDN:
+		return (&rfc1035label.Labels{Labels: []string{s.FQDN}}).ToBytes()
+	}
+	return nil
+}
+
+func (s NTPSuboption) String() string {
+	if s.Code == NTPSuboptionSrvFQDN {
+		return fmt.Sprintf("%s=%s", s.Code, s.FQDN)
+	}
+	return fmt.Sprintf("%s=%s", s.Code, s.IP)
+}
+
+// OptNTPServer represents an NTP Server option, a list of time sources in
+// order of preference
+type OptNTPServer struct {
+	Suboptions []NTPSuboption
+}
+
+// Code returns the option code
+func (op *OptNTPServer) Code() OptionCode {
+	return OptionNTPServer
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNTPServer) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionNTPServer))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	for _, s := range op.Suboptions {
+		data := s.data()
+		hdr := make([]byte, 4)
+		binary.BigEndian.PutUint16(hdr[0:2], uint16(s.Code))
+		binary.BigEndian.PutUint16(hdr[2:4], uint16(len(data)))
+		buf = append(buf, hdr...)
+		buf = append(buf, data...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptNTPServer) Length() int {
+	length := 0
+	for _, s := range op.Suboptions {
+		length += 4 + len(s.data())
+	}
+	return length
+}
+
+func (op *OptNTPServer) String() string {
+	s := make([]string, 0, len(op.Suboptions))
+	for _, sub := range op.Suboptions {
+		s = append(s, sub.String())
+	}
+	return fmt.Sprintf("OptNTPServer{suboptions=[%s]}", strings.Join(s, ", "))
+}
+
+// Addresses returns the unicast server addresses of the option, in order of
+// preference
+func (op *OptNTPServer) Addresses() []net.IP {
+	return op.ips(NTPSuboptionSrvAddr)
+}
+
+// MulticastAddresses returns the multicast addresses of the option, which
+// clients listen on rather than query, in order of preference
+func (op *OptNTPServer) MulticastAddresses() []net.IP {
+	return op.ips(NTPSuboptionMCAddr)
+}
+
+func (op *OptNTPServer) ips(code NTPSuboptionCode) []net.IP {
+	var ips []net.IP
+	for _, s := range op.Suboptions {
+		if s.Code == code {
+			ips = append(ips, s.IP)
+		}
+	}
+	return ips
+}
+
+// FQDNs returns the server names of the option, in order of preference
+func (op *OptNTPServer) FQDNs() []string {
+	var names []string
+	for _, s := range op.Suboptions {
+		if s.Code == NTPSuboptionSrvFQDN {
+			names = append(names, s.FQDN)
+		}
+	}
+	return names
+}
+
+// ParseOptNTPServer builds an OptNTPServer structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNTPServer(data []byte) (*OptNTPServer, error) {
+	var op OptNTPServer
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("Invalid OptNTPServer data: truncated suboption header")
+		}
+		code := NTPSuboptionCode(binary.BigEndian.Uint16(data[0:2]))
+		length := int(binary.BigEndian.Uint16(data[2:4]))
+		if len(data) < 4+length {
+			return nil, fmt.Errorf("Invalid OptNTPServer data: suboption %s is truncated", code)
+		}
+		subData := data[4 : 4+length]
+		data = data[4+length:]
+
+		s := NTPSuboption{Code: code}
+		switch code {
+		case NTPSuboptionSrvAddr, NTPSuboptionMCAddr:
+			if length != net.IPv6len {
+				return nil, fmt.Errorf("Invalid OptNTPServer data: %s length is %d, expected %d", code, length, net.IPv6len)
+			}
+			s.IP = net.IP(subData)
+		case NTPSuboptionSrvFQDN:
+			labels, err := rfc1035label.FromBytes(subData)
+			if err != nil {
+				return nil, err
+			}
+			if len(labels.Labels) != 1 {
+				return nil, fmt.Errorf("Invalid OptNTPServer data: %s must hold exactly one name", code)
+			}
+			s.FQDN = labels.Labels[0]
+		default:
+			// unknown suboptions are ignored
+			continue
+		}
+		op.Suboptions = append(op.Suboptions, s)
+	}
+	return &op, nil
+}
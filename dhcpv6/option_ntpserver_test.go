@@ -0,0 +1,78 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var sampleOptNTPServerData = []byte{
+	0, 1, 0, 16, // NTP_SUBOPTION_SRV_ADDR
+	0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	0, 2, 0, 16, // NTP_SUBOPTION_MC_ADDR
+	0xff, 0x05, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1,
+	0, 3, 0, 17, // NTP_SUBOPTION_SRV_FQDN
+	3, 'n', 't', 'p', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'o', 'r', 'g', 0,
+}
+
+func TestParseOptNTPServer(t *testing.T) {
+	opt, err := ParseOptNTPServer(sampleOptNTPServerData)
+	require.NoError(t, err)
+	require.Equal(t, OptionNTPServer, opt.Code())
+	require.Equal(t, len(sampleOptNTPServerData), opt.Length())
+	require.Equal(t, []NTPSuboption{
+		{Code: NTPSuboptionSrvAddr, IP: net.ParseIP("2001:db8::1")},
+		{Code: NTPSuboptionMCAddr, IP: net.ParseIP("ff05::101")},
+		{Code: NTPSuboptionSrvFQDN, FQDN: "ntp.example.org"},
+	}, opt.Suboptions)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, opt.Addresses())
+	require.Equal(t, []net.IP{net.ParseIP("ff05::101")}, opt.MulticastAddresses())
+	require.Equal(t, []string{"ntp.example.org"}, opt.FQDNs())
+	require.Equal(t,
+		"OptNTPServer{suboptions=[NTP_SUBOPTION_SRV_ADDR=2001:db8::1, NTP_SUBOPTION_MC_ADDR=ff05::101, NTP_SUBOPTION_SRV_FQDN=ntp.example.org]}",
+		opt.String(),
+	)
+}
+
+func TestOptNTPServerToBytes(t *testing.T) {
+	opt := OptNTPServer{Suboptions: []NTPSuboption{
+		{Code: NTPSuboptionSrvAddr, IP: net.ParseIP("2001:db8::1")},
+		{Code: NTPSuboptionMCAddr, IP: net.ParseIP("ff05::101")},
+		{Code: NTPSuboptionSrvFQDN, FQDN: "ntp.example.org"},
+	}}
+	expected := append([]byte{0, 56, 0, byte(len(sampleOptNTPServerData))}, sampleOptNTPServerData...)
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestParseOptNTPServerUnknownSuboption(t *testing.T) {
+	opt, err := ParseOptNTPServer([]byte{0, 9, 0, 2, 0xaa, 0xbb})
+	require.NoError(t, err)
+	require.Empty(t, opt.Suboptions)
+}
+
+func TestParseOptNTPServerInvalid(t *testing.T) {
+	// truncated suboption header
+	_, err := ParseOptNTPServer([]byte{0, 1, 0})
+	require.Error(t, err)
+	// truncated suboption
+	_, err = ParseOptNTPServer(sampleOptNTPServerData[:10])
+	require.Error(t, err)
+	// bad address length
+	_, err = ParseOptNTPServer([]byte{0, 1, 0, 4, 10, 0, 0, 1})
+	require.Error(t, err)
+	// more than one name
+	_, err = ParseOptNTPServer([]byte{0, 3, 0, 8, 1, 'a', 0, 1, 'b', 0, 1, 'c'})
+	require.Error(t, err)
+}
+
+func TestNTPServerGetters(t *testing.T) {
+	d := &DHCPv6Message{}
+	require.Nil(t, d.NTPServer())
+	require.Nil(t, d.SNTPServers())
+
+	WithNTPServers(net.ParseIP("2001:db8::1"))(d)
+	WithSNTPServers(net.ParseIP("2001:db8::2"))(d)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1")}, d.NTPServer().Addresses())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::2")}, d.SNTPServers())
+}
@@ -0,0 +1,122 @@
+package dhcpv6
+
+// This module defines the OptPDExclude structure.
+// https://www.ietf.org/rfc/rfc6603.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// OptPDExclude represents a Prefix Exclude option. It is carried in an
+// OptIAPrefix and identifies a prefix, within the delegated one, that the
+// requesting router must not assign to any of its downstream links.
+//
+// The excluded prefix is encoded relative to the delegated prefix: SubnetID
+// holds the bits of the excluded prefix that follow the delegated prefix
+// length, left-aligned. Use NewOptPDExclude and Prefix to convert from and to
+// a full prefix.
+type OptPDExclude struct {
+	PrefixLength uint8
+	SubnetID     []byte
+}
+
+// NewOptPDExclude builds an OptPDExclude excluding the prefix excluded from
+// the delegated prefix. The excluded prefix must be longer than, and lie
+// within, the delegated one.
+func NewOptPDExclude(delegated, excluded net.IPNet) (*OptPDExclude, error) {
+	dlen, dbits := delegated.Mask.Size()
+	elen, ebits := excluded.Mask.Size()
+	if dbits != 128 || ebits != 128 || delegated.IP.To16() == nil || excluded.IP.To16() == nil {
+		return nil, fmt.Errorf("delegated and excluded prefixes must be IPv6 prefixes")
+	}
+	if elen <= dlen || !delegated.Contains(excluded.IP) {
+		return nil, fmt.Errorf("excluded prefix %v is not within the delegated prefix %v", excluded.String(), delegated.String())
+	}
+	ip := excluded.IP.To16().Mask(excluded.Mask)
+	subnetID := make([]byte, subnetIDLength(dlen, elen))
+	for i := dlen; i < elen; i++ {
+		if bitsAt(ip, i, 1) == 1 {
+			setBit(subnetID, i-dlen)
+		}
+	}
+	return &OptPDExclude{PrefixLength: uint8(elen), SubnetID: subnetID}, nil
+}
+
+// subnetIDLength returns the size in bytes of the subnet ID of a prefix of
+// length elen excluded from a delegated prefix of length dlen.
+func subnetIDLength(dlen, elen int) int {
+	return (elen-dlen-1)/8 + 1
+}
+
+func setBit(b []byte, i int) {
+	b[i/8] |= 1 << (7 - uint(i%8))
+}
+
+// Prefix returns the excluded prefix, given the delegated prefix of the
+// OptIAPrefix that carries the option. It returns an error if the option is
+// not valid for the delegated prefix.
+func (op *OptPDExclude) Prefix(delegated net.IPNet) (*net.IPNet, error) {
+	dlen, bits := delegated.Mask.Size()
+	elen := int(op.PrefixLength)
+	if bits != 128 || delegated.IP.To16() == nil {
+		return nil, fmt.Errorf("delegated prefix must be an IPv6 prefix")
+	}
+	if elen <= dlen || elen > 128 {
+		return nil, fmt.Errorf("excluded prefix length %d is not longer than the delegated prefix length %d", elen, dlen)
+	}
+	if len(op.SubnetID) != subnetIDLength(dlen, elen) {
+		return nil, fmt.Errorf("subnet ID length is %d, expected %d for a /%d excluded from a /%d",
+			len(op.SubnetID), subnetIDLength(dlen, elen), elen, dlen)
+	}
+	if bitsAt(op.SubnetID, elen-dlen, len(op.SubnetID)*8-(elen-dlen)) != 0 {
+		return nil, fmt.Errorf("subnet ID has bits set beyond the excluded prefix length")
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, delegated.IP.To16().Mask(delegated.Mask))
+	for i := dlen; i < elen; i++ {
+		if bitsAt(op.SubnetID, i-dlen, 1) == 1 {
+			setBit(ip, i)
+		}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(elen, 128)}, nil
+}
+
+// Code returns the option code
+func (op *OptPDExclude) Code() OptionCode {
+	return OptionPDExclude
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptPDExclude) ToBytes() []byte {
+	buf := make([]byte, 5)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionPDExclude))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf[4] = op.PrefixLength
+	return append(buf, op.SubnetID...)
+}
+
+// Length returns the option length
+func (op *OptPDExclude) Length() int {
+	return 1 + len(op.SubnetID)
+}
+
+func (op *OptPDExclude) String() string {
+	return fmt.Sprintf("OptPDExclude{prefixlength=%v, subnetid=%x}", op.PrefixLength, op.SubnetID)
+}
+
+// ParseOptPDExclude builds an OptPDExclude structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+//
+// The subnet ID can only be validated against the delegated prefix, see
+// Prefix.
+func ParseOptPDExclude(data []byte) (*OptPDExclude, error) {
+	if len(data) < 2 || len(data) > 17 {
+		return nil, fmt.Errorf("Invalid OptPDExclude data: length is %d, expected between 2 and 17", len(data))
+	}
+	return &OptPDExclude{
+		PrefixLength: data[0],
+		SubnetID:     append([]byte(nil), data[1:]...),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptPDExclude(t *testing.T) {
+	for _, tt := range []struct {
+		delegated, excluded string
+		subnetID            []byte
+	}{
+		{"2001:db8:1::/48", "2001:db8:1:2::/64", []byte{0x00, 0x02}},
+		{"2001:db8:0:ff00::/56", "2001:db8:0:ff01::/64", []byte{0x01}},
+		{"2001:db8:0:ffe0::/59", "2001:db8:0:fff8::/64", []byte{0xc0}},
+		{"2001:db8::/32", "2001:db8:8000::/33", []byte{0x80}},
+	} {
+		t.Run(tt.excluded, func(t *testing.T) {
+			delegated := mustParseCIDR(t, tt.delegated)
+			excluded := mustParseCIDR(t, tt.excluded)
+			opt, err := NewOptPDExclude(delegated, excluded)
+			require.NoError(t, err)
+			ones, _ := excluded.Mask.Size()
+			require.Equal(t, uint8(ones), opt.PrefixLength)
+			require.Equal(t, tt.subnetID, opt.SubnetID)
+
+			prefix, err := opt.Prefix(delegated)
+			require.NoError(t, err)
+			require.Equal(t, excluded.String(), prefix.String())
+		})
+	}
+}
+
+func TestNewOptPDExcludeInvalid(t *testing.T) {
+	delegated := mustParseCIDR(t, "2001:db8:1::/48")
+	// outside of the delegated prefix
+	_, err := NewOptPDExclude(delegated, mustParseCIDR(t, "2001:db8:2:2::/64"))
+	require.Error(t, err)
+	// not longer than the delegated prefix
+	_, err = NewOptPDExclude(delegated, mustParseCIDR(t, "2001:db8:1::/48"))
+	require.Error(t, err)
+	// not IPv6
+	_, err = NewOptPDExclude(delegated, mustParseCIDR(t, "192.0.2.0/24"))
+	require.Error(t, err)
+}
+
+func TestOptPDExcludePrefixInvalid(t *testing.T) {
+	delegated := mustParseCIDR(t, "2001:db8:1::/48")
+	// prefix length not longer than the delegated one
+	_, err := (&OptPDExclude{PrefixLength: 48, SubnetID: []byte{0}}).Prefix(delegated)
+	require.Error(t, err)
+	// wrong subnet ID length
+	_, err = (&OptPDExclude{PrefixLength: 64, SubnetID: []byte{2}}).Prefix(delegated)
+	require.Error(t, err)
+	// bits set beyond the prefix length
+	_, err = (&OptPDExclude{PrefixLength: 60, SubnetID: []byte{0, 0x21}}).Prefix(delegated)
+	require.Error(t, err)
+}
+
+func TestParseOptPDExclude(t *testing.T) {
+	opt, err := ParseOptPDExclude([]byte{64, 0x00, 0x02})
+	require.NoError(t, err)
+	require.Equal(t, OptionPDExclude, opt.Code())
+	require.Equal(t, 3, opt.Length())
+	require.Equal(t, uint8(64), opt.PrefixLength)
+	require.Equal(t, []byte{0x00, 0x02}, opt.SubnetID)
+	require.Equal(t, "OptPDExclude{prefixlength=64, subnetid=0002}", opt.String())
+}
+
+func TestParseOptPDExcludeInvalidLength(t *testing.T) {
+	_, err := ParseOptPDExclude([]byte{64})
+	require.Error(t, err)
+	_, err = ParseOptPDExclude(make([]byte, 18))
+	require.Error(t, err)
+}
+
+func TestOptPDExcludeToBytes(t *testing.T) {
+	opt := OptPDExclude{PrefixLength: 64, SubnetID: []byte{0x00, 0x02}}
+	expected := []byte{
+		0, 67, // OptionPDExclude
+		0, 3, // length
+		64,         // prefix length
+		0x00, 0x02, // subnet ID
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
+
+func TestOptIAPrefixExcludedPrefix(t *testing.T) {
+	opt := OptIAPrefix{}
+	opt.SetIPv6Prefix(net.ParseIP("2001:db8:1::"))
+	opt.SetPrefixLength(48)
+
+	excluded, err := opt.ExcludedPrefix()
+	require.NoError(t, err)
+	require.Nil(t, excluded)
+
+	require.Error(t, opt.SetExcludedPrefix(mustParseCIDR(t, "2001:db8:2::/64")))
+	require.NoError(t, opt.SetExcludedPrefix(mustParseCIDR(t, "2001:db8:1:3::/64")))
+	require.NoError(t, opt.SetExcludedPrefix(mustParseCIDR(t, "2001:db8:1:2::/64")))
+	require.Len(t, opt.Options, 1)
+
+	// the option survives serialization within the IA Prefix
+	parsed, err := ParseOptIAPrefix(opt.ToBytes()[4:])
+	require.NoError(t, err)
+	excluded, err = parsed.ExcludedPrefix()
+	require.NoError(t, err)
+	require.Equal(t, "2001:db8:1:2::/64", excluded.String())
+}
+
+func TestOptIAPrefixSubPrefixes(t *testing.T) {
+	opt := OptIAPrefix{}
+	opt.SetIPv6Prefix(net.ParseIP("2001:db8:0:ff00::"))
+	opt.SetPrefixLength(62)
+
+	prefixes, err := opt.SubPrefixes(64)
+	require.NoError(t, err)
+	require.Len(t, prefixes, 4)
+	require.Equal(t, "2001:db8:0:ff00::/64", prefixes[0].String())
+	require.Equal(t, "2001:db8:0:ff03::/64", prefixes[3].String())
+
+	// the excluded prefix is not sub-delegated
+	require.NoError(t, opt.SetExcludedPrefix(mustParseCIDR(t, "2001:db8:0:ff01::/64")))
+	prefixes, err = opt.SubPrefixes(64)
+	require.NoError(t, err)
+	var got []string
+	for _, p := range prefixes {
+		got = append(got, p.String())
+	}
+	require.Equal(t, []string{"2001:db8:0:ff00::/64", "2001:db8:0:ff02::/64", "2001:db8:0:ff03::/64"}, got)
+
+	// an excluded prefix longer than the sub-prefixes takes out the whole
+	// sub-prefix that contains it
+	require.NoError(t, opt.SetExcludedPrefix(mustParseCIDR(t, "2001:db8:0:ff02::/80")))
+	prefixes, err = opt.SubPrefixes(63)
+	require.NoError(t, err)
+	require.Len(t, prefixes, 1)
+	require.Equal(t, "2001:db8:0:ff00::/63", prefixes[0].String())
+
+	_, err = opt.SubPrefixes(60)
+	require.Error(t, err)
+	_, err = opt.SubPrefixes(129)
+	require.Error(t, err)
+}
+
+func TestOptIAPrefixSubPrefixesTooMany(t *testing.T) {
+	opt := OptIAPrefix{}
+	opt.SetIPv6Prefix(net.ParseIP("2001:db8::"))
+	opt.SetPrefixLength(32)
+	_, err := opt.SubPrefixes(64)
+	require.Error(t, err)
+}
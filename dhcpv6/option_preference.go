@@ -0,0 +1,51 @@
+package dhcpv6
+
+// This module defines the OptPreference structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MaxPreference is the highest server preference. A client that receives an
+// Advertise with this preference stops waiting for other advertisements.
+const MaxPreference uint8 = 255
+
+// OptPreference represents a Preference option, which a server sends in an
+// Advertise to influence which server the client selects
+type OptPreference struct {
+	Preference uint8
+}
+
+// Code returns the option code
+func (op *OptPreference) Code() OptionCode {
+	return OptionPreference
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptPreference) ToBytes() []byte {
+	buf := make([]byte, 5)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionPreference))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf[4] = op.Preference
+	return buf
+}
+
+// Length returns the option length
+func (op *OptPreference) Length() int {
+	return 1
+}
+
+func (op *OptPreference) String() string {
+	return fmt.Sprintf("OptPreference{preference=%v}", op.Preference)
+}
+
+// ParseOptPreference builds an OptPreference structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptPreference(data []byte) (*OptPreference, error) {
+	if len(data) != 1 {
+		return nil, fmt.Errorf("Invalid OptPreference data: length is %d, expected 1", len(data))
+	}
+	return &OptPreference{Preference: data[0]}, nil
+}
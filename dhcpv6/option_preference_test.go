@@ -0,0 +1,33 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptPreference(t *testing.T) {
+	opt, err := ParseOptPreference([]byte{42})
+	require.NoError(t, err)
+	require.Equal(t, OptionPreference, opt.Code())
+	require.Equal(t, 1, opt.Length())
+	require.Equal(t, uint8(42), opt.Preference)
+	require.Equal(t, "OptPreference{preference=42}", opt.String())
+}
+
+func TestParseOptPreferenceInvalidLength(t *testing.T) {
+	_, err := ParseOptPreference([]byte{})
+	require.Error(t, err)
+	_, err = ParseOptPreference([]byte{1, 2})
+	require.Error(t, err)
+}
+
+func TestOptPreferenceToBytes(t *testing.T) {
+	opt := OptPreference{Preference: MaxPreference}
+	expected := []byte{0, 7, 0, 1, 255}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
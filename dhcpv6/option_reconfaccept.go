@@ -0,0 +1,44 @@
+package dhcpv6
+
+// This module defines the OptReconfAccept structure.
+// https://www.ietf.org/rfc/rfc8415.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptReconfAccept represents a Reconfigure Accept option. A client sends it
+// to tell the server that it accepts Reconfigure messages.
+type OptReconfAccept struct{}
+
+// Code returns the option code
+func (op *OptReconfAccept) Code() OptionCode {
+	return OptionReconfAccept
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptReconfAccept) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionReconfAccept))
+	binary.BigEndian.PutUint16(buf[2:4], 0)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptReconfAccept) Length() int {
+	return 0
+}
+
+func (op *OptReconfAccept) String() string {
+	return "OptReconfAccept{}"
+}
+
+// ParseOptReconfAccept builds an OptReconfAccept structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptReconfAccept(data []byte) (*OptReconfAccept, error) {
+	if len(data) != 0 {
+		return nil, fmt.Errorf("Invalid reconfigure accept data length. Expected 0 bytes, got %v", len(data))
+	}
+	return &OptReconfAccept{}, nil
+}
@@ -0,0 +1,23 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptReconfAccept(t *testing.T) {
+	opt, err := ParseOptReconfAccept([]byte{})
+	require.NoError(t, err)
+	require.Equal(t, OptionReconfAccept, opt.Code())
+	require.Equal(t, 0, opt.Length())
+	require.Equal(t, "OptReconfAccept{}", opt.String())
+
+	_, err = ParseOptReconfAccept([]byte{0})
+	require.Error(t, err, "A non-empty reconfigure accept should return an error")
+}
+
+func TestOptReconfAcceptToBytes(t *testing.T) {
+	opt := OptReconfAccept{}
+	require.Equal(t, []byte{0, 20, 0, 0}, opt.ToBytes())
+}
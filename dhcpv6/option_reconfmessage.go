@@ -0,0 +1,53 @@
+package dhcpv6
+
+// This module defines the OptReconfMessage structure.
+// https://www.ietf.org/rfc/rfc8415.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptReconfMessage represents a Reconfigure Message option. It tells the
+// client which message to send in response to a Reconfigure.
+type OptReconfMessage struct {
+	MessageType MessageType
+}
+
+// Code returns the option code
+func (op *OptReconfMessage) Code() OptionCode {
+	return OptionReconfMessage
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptReconfMessage) ToBytes() []byte {
+	buf := make([]byte, 5)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionReconfMessage))
+	binary.BigEndian.PutUint16(buf[2:4], 1)
+	buf[4] = uint8(op.MessageType)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptReconfMessage) Length() int {
+	return 1
+}
+
+func (op *OptReconfMessage) String() string {
+	return fmt.Sprintf("OptReconfMessage{msgtype=%v}", op.MessageType)
+}
+
+// ParseOptReconfMessage builds an OptReconfMessage structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptReconfMessage(data []byte) (*OptReconfMessage, error) {
+	if len(data) != 1 {
+		return nil, fmt.Errorf("Invalid reconfigure message data length. Expected 1 byte, got %v", len(data))
+	}
+	opt := OptReconfMessage{MessageType: MessageType(data[0])}
+	switch opt.MessageType {
+	case MessageTypeRenew, MessageTypeRebind, MessageTypeInformationRequest:
+	default:
+		return nil, fmt.Errorf("Invalid reconfigure message type %v", opt.MessageType)
+	}
+	return &opt, nil
+}
@@ -0,0 +1,29 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptReconfMessage(t *testing.T) {
+	opt, err := ParseOptReconfMessage([]byte{5})
+	require.NoError(t, err)
+	require.Equal(t, OptionReconfMessage, opt.Code())
+	require.Equal(t, MessageTypeRenew, opt.MessageType)
+	require.Equal(t, 1, opt.Length())
+	require.Equal(t, "OptReconfMessage{msgtype=RENEW}", opt.String())
+}
+
+func TestParseOptReconfMessageInvalid(t *testing.T) {
+	_, err := ParseOptReconfMessage([]byte{})
+	require.Error(t, err, "An empty reconfigure message should return an error")
+
+	_, err = ParseOptReconfMessage([]byte{uint8(MessageTypeSolicit)})
+	require.Error(t, err, "SOLICIT is not a valid reconfigure message type")
+}
+
+func TestOptReconfMessageToBytes(t *testing.T) {
+	opt := OptReconfMessage{MessageType: MessageTypeInformationRequest}
+	require.Equal(t, []byte{0, 19, 0, 1, 11}, opt.ToBytes())
+}
@@ -0,0 +1,51 @@
+package dhcpv6
+
+// This module defines the OptRelayId structure.
+// https://www.ietf.org/rfc/rfc5460.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptRelayId represents a Relay ID option, the DUID of a relay agent
+type OptRelayId struct {
+	Rid Duid
+}
+
+// Code returns the option code
+func (op *OptRelayId) Code() OptionCode {
+	return OptionRelayID
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptRelayId) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionRelayID))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf = append(buf, op.Rid.ToBytes()...)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptRelayId) Length() int {
+	return op.Rid.Length()
+}
+
+func (op *OptRelayId) String() string {
+	return fmt.Sprintf("OptRelayId{rid=%v}", op.Rid.String())
+}
+
+// ParseOptRelayId builds an OptRelayId structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptRelayId(data []byte) (*OptRelayId, error) {
+	if len(data) < 2 {
+		// at least the DUID type is necessary to continue
+		return nil, fmt.Errorf("Invalid OptRelayId data: shorter than 2 bytes")
+	}
+	rid, err := DuidFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OptRelayId{Rid: *rid}, nil
+}
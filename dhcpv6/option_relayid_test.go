@@ -0,0 +1,47 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptRelayId(t *testing.T) {
+	data := []byte{
+		0, 3, // DUID_LL
+		0, 1, // hwtype ethernet
+		0, 1, 2, 3, 4, 5, // hw addr
+	}
+	opt, err := ParseOptRelayId(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionRelayID, opt.Code())
+	require.Equal(t, 10, opt.Length())
+	require.Equal(t, DUID_LL, opt.Rid.Type)
+	require.Equal(t, net.HardwareAddr{0, 1, 2, 3, 4, 5}, opt.Rid.LinkLayerAddr)
+	require.Contains(t, opt.String(), "rid=")
+}
+
+func TestParseOptRelayIdInvalid(t *testing.T) {
+	_, err := ParseOptRelayId([]byte{0})
+	require.Error(t, err, "A short relay ID should return an error")
+}
+
+func TestOptRelayIdToBytes(t *testing.T) {
+	opt := OptRelayId{
+		Rid: Duid{
+			Type:          DUID_LL,
+			HwType:        iana.HWTypeEthernet,
+			LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		},
+	}
+	expected := []byte{
+		0, 53, // OptionRelayID
+		0, 10, // length
+		0, 3, // DUID_LL
+		0, 1, // hwtype ethernet
+		0, 1, 2, 3, 4, 5, // hw addr
+	}
+	require.Equal(t, expected, opt.ToBytes())
+}
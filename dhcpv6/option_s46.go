@@ -0,0 +1,403 @@
+package dhcpv6
+
+// This module defines the options used to configure the softwire
+// IPv4-over-IPv6 transition mechanisms: MAP-E, MAP-T and Lightweight 4over6.
+// https://www.ietf.org/rfc/rfc7598.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// S46RuleFlagFMR is the flag of an OptS46Rule that makes it a Forwarding
+// Mapping Rule
+const S46RuleFlagFMR uint8 = 0x01
+
+// s46PrefixToBytes serializes an IP prefix as a length followed by the
+// significant bytes of the address, as used by the S46 options
+func s46PrefixToBytes(p net.IPNet) []byte {
+	ones, _ := p.Mask.Size()
+	n := (ones + 7) / 8
+	buf := []byte{uint8(ones)}
+	ip := p.IP.To16()
+	if ip == nil {
+		return append(buf, make([]byte, n)...)
+	}
+	return append(buf, ip[:n]...)
+}
+
+// s46PrefixFromBytes parses an IPv6 prefix serialized by s46PrefixToBytes,
+// and returns it along with the number of bytes consumed
+func s46PrefixFromBytes(data []byte) (net.IPNet, int, error) {
+	if len(data) < 1 {
+		return net.IPNet{}, 0, fmt.Errorf("missing prefix length")
+	}
+	ones := int(data[0])
+	if ones > 128 {
+		return net.IPNet{}, 0, fmt.Errorf("invalid prefix length %d", ones)
+	}
+	n := (ones + 7) / 8
+	if len(data) < 1+n {
+		return net.IPNet{}, 0, fmt.Errorf("prefix shorter than %d bits", ones)
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, data[1:1+n])
+	mask := net.CIDRMask(ones, 128)
+	return net.IPNet{IP: ip.Mask(mask), Mask: mask}, 1 + n, nil
+}
+
+func optionsLength(options []Option) int {
+	length := 0
+	for _, opt := range options {
+		length += 4 + opt.Length()
+	}
+	return length
+}
+
+func optionsToBytes(options []Option) []byte {
+	var buf []byte
+	for _, opt := range options {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// OptS46Rule represents an S46 Rule option, a MAP mapping rule
+type OptS46Rule struct {
+	Flags      uint8
+	EALength   uint8
+	IPv4Prefix net.IPNet
+	IPv6Prefix net.IPNet
+	Options    []Option
+}
+
+// Code returns the option code
+func (op *OptS46Rule) Code() OptionCode {
+	return OptionS46Rule
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46Rule) ToBytes() []byte {
+	buf := make([]byte, 6)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionS46Rule))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf[4] = op.Flags
+	buf[5] = op.EALength
+	ones, _ := op.IPv4Prefix.Mask.Size()
+	buf = append(buf, uint8(ones))
+	ip4 := op.IPv4Prefix.IP.To4()
+	if ip4 == nil {
+		ip4 = net.IPv4zero.To4()
+	}
+	buf = append(buf, ip4...)
+	buf = append(buf, s46PrefixToBytes(op.IPv6Prefix)...)
+	return append(buf, optionsToBytes(op.Options)...)
+}
+
+// Length returns the option length
+func (op *OptS46Rule) Length() int {
+	return 7 + len(s46PrefixToBytes(op.IPv6Prefix)) + optionsLength(op.Options)
+}
+
+// IsFMR returns true if the rule is a Forwarding Mapping Rule
+func (op *OptS46Rule) IsFMR() bool {
+	return op.Flags&S46RuleFlagFMR != 0
+}
+
+func (op *OptS46Rule) String() string {
+	return fmt.Sprintf("OptS46Rule{flags=%v, ealength=%v, ipv4prefix=%v, ipv6prefix=%v, options=%v}",
+		op.Flags, op.EALength, op.IPv4Prefix.String(), op.IPv6Prefix.String(), op.Options)
+}
+
+// GetOneOption will get an option of the give type from the Options field, if
+// it is present. It will return `nil` otherwise
+func (op *OptS46Rule) GetOneOption(code OptionCode) Option {
+	return getOption(op.Options, code)
+}
+
+// ParseOptS46Rule builds an OptS46Rule structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptS46Rule(data []byte) (*OptS46Rule, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("Invalid OptS46Rule data: shorter than 8 bytes")
+	}
+	op := OptS46Rule{
+		Flags:    data[0],
+		EALength: data[1],
+	}
+	if data[2] > 32 {
+		return nil, fmt.Errorf("Invalid OptS46Rule data: invalid IPv4 prefix length %d", data[2])
+	}
+	mask := net.CIDRMask(int(data[2]), 32)
+	op.IPv4Prefix = net.IPNet{IP: net.IP(data[3:7]).Mask(mask), Mask: mask}
+	prefix, n, err := s46PrefixFromBytes(data[7:])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OptS46Rule data: %v", err)
+	}
+	op.IPv6Prefix = prefix
+	op.Options, err = OptionsFromBytes(data[7+n:])
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// OptS46BR represents an S46 BR option, the IPv6 address of a Border Relay
+type OptS46BR struct {
+	BRAddress net.IP
+}
+
+// Code returns the option code
+func (op *OptS46BR) Code() OptionCode {
+	return OptionS46BR
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46BR) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionS46BR))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.BRAddress.To16()...)
+}
+
+// Length returns the option length
+func (op *OptS46BR) Length() int {
+	return net.IPv6len
+}
+
+func (op *OptS46BR) String() string {
+	return fmt.Sprintf("OptS46BR{braddress=%v}", op.BRAddress)
+}
+
+// ParseOptS46BR builds an OptS46BR structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptS46BR(data []byte) (*OptS46BR, error) {
+	if len(data) != net.IPv6len {
+		return nil, fmt.Errorf("Invalid OptS46BR data: length is %d, expected %d", len(data), net.IPv6len)
+	}
+	return &OptS46BR{BRAddress: net.IP(data)}, nil
+}
+
+// OptS46DMR represents an S46 DMR option, the Default Mapping Rule prefix
+// of MAP-T
+type OptS46DMR struct {
+	Prefix net.IPNet
+}
+
+// Code returns the option code
+func (op *OptS46DMR) Code() OptionCode {
+	return OptionS46DMR
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46DMR) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionS46DMR))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, s46PrefixToBytes(op.Prefix)...)
+}
+
+// Length returns the option length
+func (op *OptS46DMR) Length() int {
+	return len(s46PrefixToBytes(op.Prefix))
+}
+
+func (op *OptS46DMR) String() string {
+	return fmt.Sprintf("OptS46DMR{prefix=%v}", op.Prefix.String())
+}
+
+// ParseOptS46DMR builds an OptS46DMR structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptS46DMR(data []byte) (*OptS46DMR, error) {
+	prefix, n, err := s46PrefixFromBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OptS46DMR data: %v", err)
+	}
+	if n != len(data) {
+		return nil, fmt.Errorf("Invalid OptS46DMR data: %d trailing bytes", len(data)-n)
+	}
+	return &OptS46DMR{Prefix: prefix}, nil
+}
+
+// OptS46V4V6Bind represents an S46 IPv4/IPv6 Address Binding option, which
+// binds the IPv4 address of a Lightweight 4over6 B4 to its IPv6 prefix
+type OptS46V4V6Bind struct {
+	IPv4Address net.IP
+	BindPrefix  net.IPNet
+	Options     []Option
+}
+
+// Code returns the option code
+func (op *OptS46V4V6Bind) Code() OptionCode {
+	return OptionS46V4V6Bind
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46V4V6Bind) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionS46V4V6Bind))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	ip4 := op.IPv4Address.To4()
+	if ip4 == nil {
+		ip4 = net.IPv4zero.To4()
+	}
+	buf = append(buf, ip4...)
+	buf = append(buf, s46PrefixToBytes(op.BindPrefix)...)
+	return append(buf, optionsToBytes(op.Options)...)
+}
+
+// Length returns the option length
+func (op *OptS46V4V6Bind) Length() int {
+	return net.IPv4len + len(s46PrefixToBytes(op.BindPrefix)) + optionsLength(op.Options)
+}
+
+func (op *OptS46V4V6Bind) String() string {
+	return fmt.Sprintf("OptS46V4V6Bind{ipv4address=%v, bindprefix=%v, options=%v}",
+		op.IPv4Address, op.BindPrefix.String(), op.Options)
+}
+
+// GetOneOption will get an option of the give type from the Options field, if
+// it is present. It will return `nil` otherwise
+func (op *OptS46V4V6Bind) GetOneOption(code OptionCode) Option {
+	return getOption(op.Options, code)
+}
+
+// ParseOptS46V4V6Bind builds an OptS46V4V6Bind structure from a sequence of
+// bytes. The input data does not include option code and length bytes.
+func ParseOptS46V4V6Bind(data []byte) (*OptS46V4V6Bind, error) {
+	if len(data) < 5 {
+		return nil, fmt.Errorf("Invalid OptS46V4V6Bind data: shorter than 5 bytes")
+	}
+	op := OptS46V4V6Bind{IPv4Address: net.IP(data[0:4])}
+	prefix, n, err := s46PrefixFromBytes(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OptS46V4V6Bind data: %v", err)
+	}
+	op.BindPrefix = prefix
+	op.Options, err = OptionsFromBytes(data[4+n:])
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// OptS46PortParams represents an S46 Port Parameters option, which
+// describes the port set of a MAP rule or a Lightweight 4over6 binding
+type OptS46PortParams struct {
+	Offset     uint8
+	PSIDLength uint8
+	PSID       uint16
+}
+
+// Code returns the option code
+func (op *OptS46PortParams) Code() OptionCode {
+	return OptionS46PortParams
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46PortParams) ToBytes() []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionS46PortParams))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	buf[4] = op.Offset
+	buf[5] = op.PSIDLength
+	binary.BigEndian.PutUint16(buf[6:8], op.PSID)
+	return buf
+}
+
+// Length returns the option length
+func (op *OptS46PortParams) Length() int {
+	return 4
+}
+
+func (op *OptS46PortParams) String() string {
+	return fmt.Sprintf("OptS46PortParams{offset=%v, psidlength=%v, psid=0x%04x}",
+		op.Offset, op.PSIDLength, op.PSID)
+}
+
+// ParseOptS46PortParams builds an OptS46PortParams structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptS46PortParams(data []byte) (*OptS46PortParams, error) {
+	if len(data) != 4 {
+		return nil, fmt.Errorf("Invalid OptS46PortParams data: length is %d, expected 4", len(data))
+	}
+	op := OptS46PortParams{
+		Offset:     data[0],
+		PSIDLength: data[1],
+		PSID:       binary.BigEndian.Uint16(data[2:4]),
+	}
+	if int(op.Offset)+int(op.PSIDLength) > 16 {
+		return nil, fmt.Errorf("Invalid OptS46PortParams data: offset and PSID length exceed 16 bits")
+	}
+	return &op, nil
+}
+
+// OptS46Container represents one of the S46 container options, which hold
+// the configuration of MAP-E (OptionS46ContMapE), MAP-T (OptionS46ContMapT)
+// or Lightweight 4over6 (OptionS46ContLW)
+type OptS46Container struct {
+	OptionCode OptionCode
+	Options    []Option
+}
+
+// Code returns the option code
+func (op *OptS46Container) Code() OptionCode {
+	return op.OptionCode
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptS46Container) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(op.OptionCode))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, optionsToBytes(op.Options)...)
+}
+
+// Length returns the option length
+func (op *OptS46Container) Length() int {
+	return optionsLength(op.Options)
+}
+
+func (op *OptS46Container) String() string {
+	return fmt.Sprintf("OptS46Container{code=%v, options=%v}", op.OptionCode, op.Options)
+}
+
+// GetOption will get all the options of the given type from the Options
+// field
+func (op *OptS46Container) GetOption(code OptionCode) []Option {
+	return getOptions(op.Options, code, false)
+}
+
+// GetOneOption will get an option of the give type from the Options field, if
+// it is present. It will return `nil` otherwise
+func (op *OptS46Container) GetOneOption(code OptionCode) Option {
+	return getOption(op.Options, code)
+}
+
+// Rules returns the mapping rules of the container
+func (op *OptS46Container) Rules() []*OptS46Rule {
+	var rules []*OptS46Rule
+	for _, o := range op.GetOption(OptionS46Rule) {
+		if r, ok := o.(*OptS46Rule); ok {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// ParseOptS46Container builds an OptS46Container structure with the given
+// code from a sequence of bytes. The input data does not include option code
+// and length bytes.
+func ParseOptS46Container(code OptionCode, data []byte) (*OptS46Container, error) {
+	switch code {
+	case OptionS46ContMapE, OptionS46ContMapT, OptionS46ContLW:
+	default:
+		return nil, fmt.Errorf("Invalid S46 container option code %v", code)
+	}
+	options, err := OptionsFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OptS46Container{OptionCode: code, Options: options}, nil
+}
@@ -0,0 +1,138 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var sampleOptS46RuleData = []byte{
+	0x01,         // flags: FMR
+	16,           // EA length
+	24,           // IPv4 prefix length
+	192, 0, 2, 0, // IPv4 prefix
+	40,                           // IPv6 prefix length
+	0x20, 0x01, 0x0d, 0xb8, 0x00, // IPv6 prefix
+	0, 93, 0, 4, 6, 0, 0, 0, // OPTION_S46_PORTPARAMS
+}
+
+func mustParseCIDR(t *testing.T, s string) net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	require.NoError(t, err)
+	return *n
+}
+
+func TestParseOptS46Rule(t *testing.T) {
+	opt, err := ParseOptS46Rule(sampleOptS46RuleData)
+	require.NoError(t, err)
+	require.Equal(t, OptionS46Rule, opt.Code())
+	require.True(t, opt.IsFMR())
+	require.Equal(t, uint8(16), opt.EALength)
+	require.Equal(t, "192.0.2.0/24", opt.IPv4Prefix.String())
+	require.Equal(t, "2001:db8::/40", opt.IPv6Prefix.String())
+	require.Equal(t, len(sampleOptS46RuleData), opt.Length())
+	pp, ok := opt.GetOneOption(OptionS46PortParams).(*OptS46PortParams)
+	require.True(t, ok)
+	require.Equal(t, uint8(6), pp.Offset)
+}
+
+func TestOptS46RuleToBytes(t *testing.T) {
+	opt := OptS46Rule{
+		Flags:      S46RuleFlagFMR,
+		EALength:   16,
+		IPv4Prefix: mustParseCIDR(t, "192.0.2.0/24"),
+		IPv6Prefix: mustParseCIDR(t, "2001:db8::/40"),
+		Options:    []Option{&OptS46PortParams{Offset: 6}},
+	}
+	expected := append([]byte{0, 89, 0, byte(len(sampleOptS46RuleData))}, sampleOptS46RuleData...)
+	require.Equal(t, expected, opt.ToBytes())
+}
+
+func TestParseOptS46RuleInvalid(t *testing.T) {
+	// too short
+	_, err := ParseOptS46Rule([]byte{0, 16, 24, 192, 0, 2})
+	require.Error(t, err)
+	// IPv4 prefix length > 32
+	_, err = ParseOptS46Rule([]byte{0, 16, 33, 192, 0, 2, 0, 0})
+	require.Error(t, err)
+	// IPv6 prefix truncated
+	_, err = ParseOptS46Rule([]byte{0, 16, 24, 192, 0, 2, 0, 40, 0x20, 0x01})
+	require.Error(t, err)
+}
+
+func TestOptS46BR(t *testing.T) {
+	data := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	opt, err := ParseOptS46BR(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionS46BR, opt.Code())
+	require.Equal(t, net.ParseIP("2001:db8::1"), opt.BRAddress)
+	require.Equal(t, append([]byte{0, 90, 0, 16}, data...), opt.ToBytes())
+
+	_, err = ParseOptS46BR(data[:15])
+	require.Error(t, err)
+}
+
+func TestOptS46DMR(t *testing.T) {
+	data := []byte{64, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0xff, 0xff}
+	opt, err := ParseOptS46DMR(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionS46DMR, opt.Code())
+	require.Equal(t, "2001:db8:0:ffff::/64", opt.Prefix.String())
+	require.Equal(t, append([]byte{0, 91, 0, 9}, data...), opt.ToBytes())
+
+	_, err = ParseOptS46DMR([]byte{64, 0x20, 0x01})
+	require.Error(t, err)
+}
+
+func TestOptS46V4V6Bind(t *testing.T) {
+	data := []byte{
+		192, 0, 2, 1, // IPv4 address
+		56, 0x20, 0x01, 0x0d, 0xb8, 0, 0x12, 0x34, // bind prefix
+		0, 93, 0, 4, 0, 8, 0x34, 0, // OPTION_S46_PORTPARAMS
+	}
+	opt, err := ParseOptS46V4V6Bind(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionS46V4V6Bind, opt.Code())
+	require.Equal(t, net.IPv4(192, 0, 2, 1).To4(), opt.IPv4Address.To4())
+	require.Equal(t, "2001:db8:12:3400::/56", opt.BindPrefix.String())
+	require.Len(t, opt.Options, 1)
+	require.Equal(t, append([]byte{0, 92, 0, byte(len(data))}, data...), opt.ToBytes())
+
+	_, err = ParseOptS46V4V6Bind(data[:3])
+	require.Error(t, err)
+}
+
+func TestOptS46PortParams(t *testing.T) {
+	opt, err := ParseOptS46PortParams([]byte{6, 8, 0x34, 0})
+	require.NoError(t, err)
+	require.Equal(t, OptionS46PortParams, opt.Code())
+	require.Equal(t, &OptS46PortParams{Offset: 6, PSIDLength: 8, PSID: 0x3400}, opt)
+	require.Equal(t, []byte{0, 93, 0, 4, 6, 8, 0x34, 0}, opt.ToBytes())
+	require.Equal(t, S46PortSet{Offset: 6, PSIDLength: 8, PSID: 0x34}, opt.PortSet())
+
+	_, err = ParseOptS46PortParams([]byte{6, 8, 0x34})
+	require.Error(t, err)
+	_, err = ParseOptS46PortParams([]byte{10, 8, 0, 0})
+	require.Error(t, err)
+}
+
+func TestOptS46Container(t *testing.T) {
+	rule := append([]byte{0, 89, 0, byte(len(sampleOptS46RuleData))}, sampleOptS46RuleData...)
+	br := []byte{0, 90, 0, 16, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	data := append(append([]byte{}, rule...), br...)
+	opt, err := ParseOptS46Container(OptionS46ContMapE, data)
+	require.NoError(t, err)
+	require.Equal(t, OptionS46ContMapE, opt.Code())
+	require.Len(t, opt.Rules(), 1)
+	require.IsType(t, &OptS46BR{}, opt.GetOneOption(OptionS46BR))
+	require.Equal(t, append([]byte{0, 94, 0, byte(len(data))}, data...), opt.ToBytes())
+
+	_, err = ParseOptS46Container(OptionS46Rule, data)
+	require.Error(t, err)
+
+	// through the generic option parser
+	parsed, err := ParseOption(opt.ToBytes())
+	require.NoError(t, err)
+	require.Equal(t, opt, parsed)
+}
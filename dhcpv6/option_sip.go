@@ -0,0 +1,89 @@
+package dhcpv6
+
+// This module defines the OptSIPServerDomainNameList and
+// OptSIPServerAddressList structures.
+// https://www.ietf.org/rfc/rfc3319.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/insomniacslk/dhcp/rfc1035label"
+)
+
+// OptSIPServerDomainNameList represents a SIP Servers Domain Name List option
+type OptSIPServerDomainNameList struct {
+	DomainNameList *rfc1035label.Labels
+}
+
+// Code returns the option code
+func (op *OptSIPServerDomainNameList) Code() OptionCode {
+	return OptionSIPServersDomainNameList
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptSIPServerDomainNameList) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionSIPServersDomainNameList))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.DomainNameList.ToBytes()...)
+}
+
+// Length returns the option length
+func (op *OptSIPServerDomainNameList) Length() int {
+	return len(op.DomainNameList.ToBytes())
+}
+
+func (op *OptSIPServerDomainNameList) String() string {
+	return fmt.Sprintf("OptSIPServerDomainNameList{domainnames=%v}", op.DomainNameList.Labels)
+}
+
+// ParseOptSIPServerDomainNameList builds an OptSIPServerDomainNameList
+// structure from a sequence of bytes. The input data does not include option
+// code and length bytes.
+func ParseOptSIPServerDomainNameList(data []byte) (*OptSIPServerDomainNameList, error) {
+	labels, err := rfc1035label.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &OptSIPServerDomainNameList{DomainNameList: labels}, nil
+}
+
+// OptSIPServerAddressList represents a SIP Servers IPv6 Address List option
+type OptSIPServerAddressList struct {
+	Addresses []net.IP
+}
+
+// Code returns the option code
+func (op *OptSIPServerAddressList) Code() OptionCode {
+	return OptionSIPServersIPv6AddressList
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptSIPServerAddressList) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionSIPServersIPv6AddressList))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, ipv6AddressesToBytes(op.Addresses)...)
+}
+
+// Length returns the option length
+func (op *OptSIPServerAddressList) Length() int {
+	return len(op.Addresses) * net.IPv6len
+}
+
+func (op *OptSIPServerAddressList) String() string {
+	return fmt.Sprintf("OptSIPServerAddressList{addresses=%v}", op.Addresses)
+}
+
+// ParseOptSIPServerAddressList builds an OptSIPServerAddressList structure
+// from a sequence of bytes. The input data does not include option code and
+// length bytes.
+func ParseOptSIPServerAddressList(data []byte) (*OptSIPServerAddressList, error) {
+	addrs, err := parseIPv6Addresses(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OptSIPServerAddressList data: %v", err)
+	}
+	return &OptSIPServerAddressList{Addresses: addrs}, nil
+}
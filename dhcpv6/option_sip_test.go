@@ -0,0 +1,68 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/rfc1035label"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptSIPServerDomainNameList(t *testing.T) {
+	data := []byte{
+		3, 's', 'i', 'p', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		4, 's', 'i', 'p', '2', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'n', 'e', 't', 0,
+	}
+	opt, err := ParseOptSIPServerDomainNameList(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionSIPServersDomainNameList, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, []string{"sip.example.com", "sip2.example.net"}, opt.DomainNameList.Labels)
+}
+
+func TestOptSIPServerDomainNameListToBytes(t *testing.T) {
+	opt := OptSIPServerDomainNameList{
+		DomainNameList: &rfc1035label.Labels{Labels: []string{"sip.example.com"}},
+	}
+	expected := []byte{
+		0, 21, // OptionSIPServersDomainNameList
+		0, 17, // length
+		3, 's', 'i', 'p', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sip.example.com"}, parsed.(*OptSIPServerDomainNameList).DomainNameList.Labels)
+}
+
+func TestParseOptSIPServerAddressList(t *testing.T) {
+	data := []byte{
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2,
+	}
+	opt, err := ParseOptSIPServerAddressList(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionSIPServersIPv6AddressList, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")}, opt.Addresses)
+}
+
+func TestParseOptSIPServerAddressListInvalidLength(t *testing.T) {
+	_, err := ParseOptSIPServerAddressList([]byte{0x20, 0x01, 0x0d, 0xb8})
+	require.Error(t, err)
+}
+
+func TestOptSIPServerAddressListToBytes(t *testing.T) {
+	opt := OptSIPServerAddressList{Addresses: []net.IP{net.ParseIP("2001:db8::1")}}
+	expected := []byte{
+		0, 22, // OptionSIPServersIPv6AddressList
+		0, 16, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
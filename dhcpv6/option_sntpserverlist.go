@@ -0,0 +1,54 @@
+package dhcpv6
+
+// This module defines the OptSNTPServerList structure.
+// https://www.ietf.org/rfc/rfc4075.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// OptSNTPServerList represents a OptionSNTPServerList option
+type OptSNTPServerList struct {
+	Servers []net.IP
+}
+
+// Code returns the option code
+func (op *OptSNTPServerList) Code() OptionCode {
+	return OptionSNTPServerList
+}
+
+// ToBytes returns the option serialized to bytes, including option code and
+// length
+func (op *OptSNTPServerList) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionSNTPServerList))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	for _, s := range op.Servers {
+		buf = append(buf, s.To16()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptSNTPServerList) Length() int {
+	return len(op.Servers) * net.IPv6len
+}
+
+func (op *OptSNTPServerList) String() string {
+	return fmt.Sprintf("OptSNTPServerList{servers=%v}", op.Servers)
+}
+
+// ParseOptSNTPServerList builds an OptSNTPServerList structure from a sequence
+// of bytes. The input data does not include option code and length bytes.
+func ParseOptSNTPServerList(data []byte) (*OptSNTPServerList, error) {
+	if len(data)%net.IPv6len != 0 {
+		return nil, fmt.Errorf("Invalid OptSNTPServerList data: length is not a multiple of %d", net.IPv6len)
+	}
+	var op OptSNTPServerList
+	for i := 0; i < len(data); i += net.IPv6len {
+		op.Servers = append(op.Servers, net.IP(data[i:i+net.IPv6len]))
+	}
+	return &op, nil
+}
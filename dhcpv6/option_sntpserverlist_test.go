@@ -0,0 +1,26 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptSNTPServerList(t *testing.T) {
+	data := []byte{
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x7b,
+	}
+	opt, err := ParseOptSNTPServerList(data)
+	require.NoError(t, err)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::7b")}, opt.Servers)
+	require.Equal(t, OptionSNTPServerList, opt.Code())
+	require.Equal(t, 16, opt.Length())
+	require.Equal(t, "OptSNTPServerList{servers=[2001:db8::7b]}", opt.String())
+	require.Equal(t, append([]byte{0, 31, 0, 16}, data...), opt.ToBytes())
+}
+
+func TestParseOptSNTPServerListInvalid(t *testing.T) {
+	_, err := ParseOptSNTPServerList([]byte{0x20, 0x01, 0x0d, 0xb8})
+	require.Error(t, err, "A truncated address should return an error")
+}
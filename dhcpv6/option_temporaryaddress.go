@@ -0,0 +1,78 @@
+package dhcpv6
+
+// This module defines the OptIATA structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptIATA represents an Identity Association for Temporary Addresses option.
+// Unlike IA_NA, IA_TA carries no T1 and T2 times.
+type OptIATA struct {
+	IaId    [4]byte
+	Options []Option
+}
+
+// Code returns the option code
+func (op *OptIATA) Code() OptionCode {
+	return OptionIATA
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptIATA) ToBytes() []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionIATA))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	copy(buf[4:8], op.IaId[:])
+	for _, opt := range op.Options {
+		buf = append(buf, opt.ToBytes()...)
+	}
+	return buf
+}
+
+// Length returns the option length
+func (op *OptIATA) Length() int {
+	l := 4
+	for _, opt := range op.Options {
+		l += 4 + opt.Length()
+	}
+	return l
+}
+
+func (op *OptIATA) String() string {
+	return fmt.Sprintf("OptIATA{IAID=%v, options=%v}", op.IaId, op.Options)
+}
+
+// AddOption adds an option at the end of the IA_TA options
+func (op *OptIATA) AddOption(opt Option) {
+	op.Options = append(op.Options, opt)
+}
+
+// GetOneOption will get an option of the give type from the Options field, if
+// it is present. It will return `nil` otherwise
+func (op *OptIATA) GetOneOption(code OptionCode) Option {
+	return getOption(op.Options, code)
+}
+
+// DelOption will remove all the options that match a Option code.
+func (op *OptIATA) DelOption(code OptionCode) {
+	op.Options = delOption(op.Options, code)
+}
+
+// ParseOptIATA builds an OptIATA structure from a sequence of bytes. The input
+// data does not include option code and length bytes.
+func ParseOptIATA(data []byte) (*OptIATA, error) {
+	var err error
+	opt := OptIATA{}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("Invalid IA for Temporary Addresses data length. Expected at least 4 bytes, got %v", len(data))
+	}
+	copy(opt.IaId[:], data[:4])
+	opt.Options, err = OptionsFromBytes(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	return &opt, nil
+}
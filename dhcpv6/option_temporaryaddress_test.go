@@ -0,0 +1,59 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptIATAParseOptIATA(t *testing.T) {
+	data := []byte{
+		1, 0, 0, 0, // IAID
+		0, 5, 0, 0x18, 0x24, 1, 0xdb, 0, 0x30, 0x10, 0xc0, 0x8f, 0xfa, 0xce, 0, 0, 0, 0x44, 0, 0, 0, 0, 0xb2, 0x7a, 0, 0, 0xc0, 0x8a, // options
+	}
+	opt, err := ParseOptIATA(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionIATA, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, [4]byte{1, 0, 0, 0}, opt.IaId)
+	require.Len(t, opt.Options, 1)
+	require.Equal(t, OptionIAAddr, opt.Options[0].Code())
+}
+
+func TestOptIATAParseOptIATAInvalidLength(t *testing.T) {
+	_, err := ParseOptIATA([]byte{1, 0, 0})
+	require.Error(t, err)
+}
+
+func TestOptIATAParseOptIATAInvalidOptions(t *testing.T) {
+	_, err := ParseOptIATA([]byte{1, 0, 0, 0, 0, 5, 0, 0x18})
+	require.Error(t, err)
+}
+
+func TestOptIATAToBytes(t *testing.T) {
+	opt := OptIATA{
+		IaId:    [4]byte{1, 2, 3, 4},
+		Options: []Option{&OptElapsedTime{ElapsedTime: 0xaabb}},
+	}
+	expected := []byte{
+		0, 4, // OptionIATA
+		0, 10, // length
+		1, 2, 3, 4, // IAID
+		0, 8, 0, 2, 0xaa, 0xbb, // options
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
+
+func TestOptIATAAddGetDelOption(t *testing.T) {
+	opt := OptIATA{}
+	addr := &OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::1")}
+	opt.AddOption(addr)
+	require.Equal(t, addr, opt.GetOneOption(OptionIAAddr))
+	opt.DelOption(OptionIAAddr)
+	require.Nil(t, opt.GetOneOption(OptionIAAddr))
+}
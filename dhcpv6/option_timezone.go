@@ -0,0 +1,78 @@
+package dhcpv6
+
+// This module defines the OptNewPOSIXTimezone and OptNewTZDBTimezone
+// structures.
+// https://www.ietf.org/rfc/rfc4833.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// OptNewPOSIXTimezone represents a New POSIX Timezone option, a POSIX TZ
+// string like "EST5EDT4,M3.2.0/02:00,M11.1.0/02:00"
+type OptNewPOSIXTimezone struct {
+	Timezone string
+}
+
+// Code returns the option code
+func (op *OptNewPOSIXTimezone) Code() OptionCode {
+	return OptionNewPOSIXTimezone
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNewPOSIXTimezone) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionNewPOSIXTimezone))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.Timezone...)
+}
+
+// Length returns the option length
+func (op *OptNewPOSIXTimezone) Length() int {
+	return len(op.Timezone)
+}
+
+func (op *OptNewPOSIXTimezone) String() string {
+	return fmt.Sprintf("OptNewPOSIXTimezone{timezone=%v}", op.Timezone)
+}
+
+// ParseOptNewPOSIXTimezone builds an OptNewPOSIXTimezone structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNewPOSIXTimezone(data []byte) (*OptNewPOSIXTimezone, error) {
+	return &OptNewPOSIXTimezone{Timezone: string(data)}, nil
+}
+
+// OptNewTZDBTimezone represents a New TZDB Timezone option, the name of a
+// timezone of the TZ database like "Europe/Zurich"
+type OptNewTZDBTimezone struct {
+	Timezone string
+}
+
+// Code returns the option code
+func (op *OptNewTZDBTimezone) Code() OptionCode {
+	return OptionNewTZDBTimezone
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptNewTZDBTimezone) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionNewTZDBTimezone))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.Timezone...)
+}
+
+// Length returns the option length
+func (op *OptNewTZDBTimezone) Length() int {
+	return len(op.Timezone)
+}
+
+func (op *OptNewTZDBTimezone) String() string {
+	return fmt.Sprintf("OptNewTZDBTimezone{timezone=%v}", op.Timezone)
+}
+
+// ParseOptNewTZDBTimezone builds an OptNewTZDBTimezone structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptNewTZDBTimezone(data []byte) (*OptNewTZDBTimezone, error) {
+	return &OptNewTZDBTimezone{Timezone: string(data)}, nil
+}
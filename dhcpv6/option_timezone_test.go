@@ -0,0 +1,45 @@
+package dhcpv6
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptNewPOSIXTimezone(t *testing.T) {
+	data := []byte("EST5EDT4,M3.2.0/02:00,M11.1.0/02:00")
+	opt, err := ParseOptNewPOSIXTimezone(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionNewPOSIXTimezone, opt.Code())
+	require.Equal(t, len(data), opt.Length())
+	require.Equal(t, "EST5EDT4,M3.2.0/02:00,M11.1.0/02:00", opt.Timezone)
+}
+
+func TestOptNewPOSIXTimezoneToBytes(t *testing.T) {
+	opt := OptNewPOSIXTimezone{Timezone: "CET-1CEST"}
+	expected := append([]byte{0, 41, 0, 9}, "CET-1CEST"...)
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
+
+func TestParseOptNewTZDBTimezone(t *testing.T) {
+	opt, err := ParseOptNewTZDBTimezone([]byte("Europe/Zurich"))
+	require.NoError(t, err)
+	require.Equal(t, OptionNewTZDBTimezone, opt.Code())
+	require.Equal(t, 13, opt.Length())
+	require.Equal(t, "Europe/Zurich", opt.Timezone)
+	require.Equal(t, "OptNewTZDBTimezone{timezone=Europe/Zurich}", opt.String())
+}
+
+func TestOptNewTZDBTimezoneToBytes(t *testing.T) {
+	opt := OptNewTZDBTimezone{Timezone: "Europe/Zurich"}
+	expected := append([]byte{0, 42, 0, 13}, "Europe/Zurich"...)
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
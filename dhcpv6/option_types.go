@@ -75,6 +75,16 @@ const (
 	OptionMIPv6HomeNetworkPrefix                  OptionCode = 71
 	OptionMIPv6HomeAgentAddress                   OptionCode = 72
 	OptionMIPv6HomeAgentFQDN                      OptionCode = 73
+	OptionClientLinkLayerAddr                     OptionCode = 79
+	OptionS46Rule                                 OptionCode = 89
+	OptionS46BR                                   OptionCode = 90
+	OptionS46DMR                                  OptionCode = 91
+	OptionS46V4V6Bind                             OptionCode = 92
+	OptionS46PortParams                           OptionCode = 93
+	OptionS46ContMapE                             OptionCode = 94
+	OptionS46ContMapT                             OptionCode = 95
+	OptionS46ContLW                               OptionCode = 96
+	OptionCaptivePortal                           OptionCode = 103
 )
 
 // OptionCodeToString maps DHCPv6 OptionCodes to human-readable strings.
@@ -150,4 +160,14 @@ var OptionCodeToString = map[OptionCode]string{
 	OptionMIPv6HomeNetworkPrefix:                  "MIPv6 Home Network Prefix",
 	OptionMIPv6HomeAgentAddress:                   "MIPv6 Home Agent Address",
 	OptionMIPv6HomeAgentFQDN:                      "MIPv6 Home Agent FQDN",
+	OptionClientLinkLayerAddr:                     "OPTION_CLIENT_LINKLAYER_ADDR",
+	OptionS46Rule:                                 "OPTION_S46_RULE",
+	OptionS46BR:                                   "OPTION_S46_BR",
+	OptionS46DMR:                                  "OPTION_S46_DMR",
+	OptionS46V4V6Bind:                             "OPTION_S46_V4V6BIND",
+	OptionS46PortParams:                           "OPTION_S46_PORTPARAMS",
+	OptionS46ContMapE:                             "OPTION_S46_CONT_MAPE",
+	OptionS46ContMapT:                             "OPTION_S46_CONT_MAPT",
+	OptionS46ContLW:                               "OPTION_S46_CONT_LW",
+	OptionCaptivePortal:                           "OPTION_V6_CAPTIVE_PORTAL",
 }
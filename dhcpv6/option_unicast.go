@@ -0,0 +1,48 @@
+package dhcpv6
+
+// This module defines the OptUnicast structure.
+// https://www.ietf.org/rfc/rfc3315.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// OptUnicast represents a Server Unicast option, the address a client may
+// send its messages to instead of the All_DHCP_Relay_Agents_and_Servers
+// multicast address
+type OptUnicast struct {
+	ServerAddress net.IP
+}
+
+// Code returns the option code
+func (op *OptUnicast) Code() OptionCode {
+	return OptionUnicast
+}
+
+// ToBytes serializes the option and returns it as a sequence of bytes
+func (op *OptUnicast) ToBytes() []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint16(buf[0:2], uint16(OptionUnicast))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(op.Length()))
+	return append(buf, op.ServerAddress.To16()...)
+}
+
+// Length returns the option length
+func (op *OptUnicast) Length() int {
+	return net.IPv6len
+}
+
+func (op *OptUnicast) String() string {
+	return fmt.Sprintf("OptUnicast{serveraddress=%v}", op.ServerAddress)
+}
+
+// ParseOptUnicast builds an OptUnicast structure from a sequence of bytes.
+// The input data does not include option code and length bytes.
+func ParseOptUnicast(data []byte) (*OptUnicast, error) {
+	if len(data) != net.IPv6len {
+		return nil, fmt.Errorf("Invalid OptUnicast data: length is %d, expected %d", len(data), net.IPv6len)
+	}
+	return &OptUnicast{ServerAddress: append(net.IP(nil), data...)}, nil
+}
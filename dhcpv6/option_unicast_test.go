@@ -0,0 +1,36 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptUnicast(t *testing.T) {
+	data := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	opt, err := ParseOptUnicast(data)
+	require.NoError(t, err)
+	require.Equal(t, OptionUnicast, opt.Code())
+	require.Equal(t, net.IPv6len, opt.Length())
+	require.Equal(t, net.ParseIP("2001:db8::1"), opt.ServerAddress)
+}
+
+func TestParseOptUnicastInvalidLength(t *testing.T) {
+	_, err := ParseOptUnicast([]byte{0x20, 0x01, 0x0d, 0xb8})
+	require.Error(t, err)
+}
+
+func TestOptUnicastToBytes(t *testing.T) {
+	opt := OptUnicast{ServerAddress: net.ParseIP("2001:db8::1")}
+	expected := []byte{
+		0, 12, // OptionUnicast
+		0, 16, // length
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1,
+	}
+	require.Equal(t, expected, opt.ToBytes())
+
+	parsed, err := ParseOption(expected)
+	require.NoError(t, err)
+	require.Equal(t, &opt, parsed)
+}
@@ -108,6 +108,74 @@ func ParseOption(dataStart []byte) (Option, error) {
 		opt, err = ParseOptClientArchType(optData)
 	case OptionNII:
 		opt, err = ParseOptNetworkInterfaceId(optData)
+	case OptionLQQuery:
+		opt, err = ParseOptLQQuery(optData)
+	case OptionClientData:
+		opt, err = ParseOptClientData(optData)
+	case OptionCLTTime:
+		opt, err = ParseOptCLTTime(optData)
+	case OptionLQRelayData:
+		opt, err = ParseOptLQRelayData(optData)
+	case OptionLQClientLink:
+		opt, err = ParseOptLQClientLink(optData)
+	case OptionRelayID:
+		opt, err = ParseOptRelayId(optData)
+	case OptionAuth:
+		opt, err = ParseOptAuth(optData)
+	case OptionReconfMessage:
+		opt, err = ParseOptReconfMessage(optData)
+	case OptionReconfAccept:
+		opt, err = ParseOptReconfAccept(optData)
+	case OptionNTPServer:
+		opt, err = ParseOptNTPServer(optData)
+	case OptionSNTPServerList:
+		opt, err = ParseOptSNTPServerList(optData)
+	case OptionAFTRName:
+		opt, err = ParseOptAFTRName(optData)
+	case OptionPDExclude:
+		opt, err = ParseOptPDExclude(optData)
+	case OptionClientLinkLayerAddr:
+		opt, err = ParseOptClientLinkLayerAddress(optData)
+	case OptionS46Rule:
+		opt, err = ParseOptS46Rule(optData)
+	case OptionS46BR:
+		opt, err = ParseOptS46BR(optData)
+	case OptionS46DMR:
+		opt, err = ParseOptS46DMR(optData)
+	case OptionS46V4V6Bind:
+		opt, err = ParseOptS46V4V6Bind(optData)
+	case OptionS46PortParams:
+		opt, err = ParseOptS46PortParams(optData)
+	case OptionS46ContMapE, OptionS46ContMapT, OptionS46ContLW:
+		opt, err = ParseOptS46Container(code, optData)
+	case OptionCaptivePortal:
+		opt, err = ParseOptCaptivePortal(optData)
+	case OptionIATA:
+		opt, err = ParseOptIATA(optData)
+	case OptionPreference:
+		opt, err = ParseOptPreference(optData)
+	case OptionUnicast:
+		opt, err = ParseOptUnicast(optData)
+	case OptionSIPServersDomainNameList:
+		opt, err = ParseOptSIPServerDomainNameList(optData)
+	case OptionSIPServersIPv6AddressList:
+		opt, err = ParseOptSIPServerAddressList(optData)
+	case OptionNISServers:
+		opt, err = ParseOptNISServers(optData)
+	case OptionNISPServers:
+		opt, err = ParseOptNISPServers(optData)
+	case OptionNISDomainName:
+		opt, err = ParseOptNISDomainName(optData)
+	case OptionNISPDomainName:
+		opt, err = ParseOptNISPDomainName(optData)
+	case OptionBCMCSControllerDomainNameList:
+		opt, err = ParseOptBCMCSControllerDomainNameList(optData)
+	case OptionBCMCSControllerIPv6AddressList:
+		opt, err = ParseOptBCMCSControllerAddressList(optData)
+	case OptionNewPOSIXTimezone:
+		opt, err = ParseOptNewPOSIXTimezone(optData)
+	case OptionNewTZDBTimezone:
+		opt, err = ParseOptNewTZDBTimezone(optData)
 	default:
 		opt = &OptionGeneric{OptionCode: code, OptionData: optData}
 	}
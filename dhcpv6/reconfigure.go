@@ -0,0 +1,243 @@
+package dhcpv6
+
+// This module implements Reconfigure messages and the Reconfigure Key
+// authentication protocol.
+// https://www.ietf.org/rfc/rfc8415.txt
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ReconfigureKeyLen is the length of a reconfigure key, and of the HMAC-MD5
+// digest computed with it.
+const ReconfigureKeyLen = 16
+
+// Types of the authentication information carried by an OptAuth using the
+// Reconfigure Key protocol, as defined by RFC 8415, Section 20.4.1.
+const (
+	ReconfigureKeyValue   uint8 = 1
+	ReconfigureKeyHMACMD5 uint8 = 2
+)
+
+// GenerateReconfigureKey returns a new random reconfigure key
+func GenerateReconfigureKey() ([]byte, error) {
+	key := make([]byte, ReconfigureKeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// WithReconfigureAccept adds a Reconfigure Accept option to a DHCPv6 packet,
+// telling the server that the client accepts Reconfigure messages
+func WithReconfigureAccept(d DHCPv6) DHCPv6 {
+	d.UpdateOption(&OptReconfAccept{})
+	return d
+}
+
+// WithReconfigureKey adds the reconfigure key to a DHCPv6 packet. A server
+// sends it in the Reply to a client that included a Reconfigure Accept
+// option, and then signs its Reconfigure messages with it. replay must be
+// strictly increasing across all the messages sent to the client.
+func WithReconfigureKey(key []byte, replay uint64) Modifier {
+	return func(d DHCPv6) DHCPv6 {
+		d.UpdateOption(&OptAuth{
+			Protocol:        AuthProtocolReconfigureKey,
+			Algorithm:       AuthAlgorithmHMACMD5,
+			RDM:             RDMMonotonicCounter,
+			ReplayDetection: replay,
+			AuthInfo:        append([]byte{ReconfigureKeyValue}, key...),
+		})
+		return d
+	}
+}
+
+// GetReconfigureKey returns the reconfigure key and replay detection value
+// sent by a server in a Reply.
+func GetReconfigureKey(reply DHCPv6) ([]byte, uint64, error) {
+	auth, err := reconfigureAuth(reply, ReconfigureKeyValue)
+	if err != nil {
+		return nil, 0, err
+	}
+	return append([]byte(nil), auth.AuthInfo[1:]...), auth.ReplayDetection, nil
+}
+
+// NewReconfigure creates a new RECONFIGURE message, asking the client to send
+// a message of type msgType. msgType must be one of MessageTypeRenew,
+// MessageTypeRebind or MessageTypeInformationRequest. The message must be
+// signed with SignReconfigure before it is sent.
+func NewReconfigure(clientID, serverID Duid, msgType MessageType, modifiers ...Modifier) (DHCPv6, error) {
+	switch msgType {
+	case MessageTypeRenew, MessageTypeRebind, MessageTypeInformationRequest:
+	default:
+		return nil, fmt.Errorf("Cannot reconfigure with message type %v", msgType)
+	}
+	rec := DHCPv6Message{}
+	rec.SetMessage(MessageTypeReconfigure)
+	// The transaction ID of a Reconfigure is always zero.
+	rec.SetTransactionID(0)
+	rec.AddOption(&OptServerId{Sid: serverID})
+	rec.AddOption(&OptClientId{Cid: clientID})
+	rec.AddOption(&OptReconfMessage{MessageType: msgType})
+
+	// apply modifiers
+	d := DHCPv6(&rec)
+	for _, mod := range modifiers {
+		d = mod(d)
+	}
+	return d, nil
+}
+
+// SignReconfigure adds an HMAC-MD5 authentication option to the RECONFIGURE
+// message d, computed with the reconfigure key, as described by RFC 8415,
+// Section 20.4. replay must be greater than any replay detection value
+// previously sent to the client. No option may be added to d afterwards.
+func SignReconfigure(d DHCPv6, key []byte, replay uint64) error {
+	if d.Type() != MessageTypeReconfigure {
+		return errors.New("The passed message must have RECONFIGURE type set")
+	}
+	auth := OptAuth{
+		Protocol:        AuthProtocolReconfigureKey,
+		Algorithm:       AuthAlgorithmHMACMD5,
+		RDM:             RDMMonotonicCounter,
+		ReplayDetection: replay,
+		AuthInfo:        make([]byte, 1+md5.Size),
+	}
+	auth.AuthInfo[0] = ReconfigureKeyHMACMD5
+	d.UpdateOption(&auth)
+	mac, err := reconfigureMAC(d.ToBytes(), key)
+	if err != nil {
+		return err
+	}
+	copy(auth.AuthInfo[1:], mac)
+	return nil
+}
+
+// VerifyReconfigure checks that the RECONFIGURE message packet, as received
+// from the network, is authenticated with the reconfigure key, and that its
+// replay detection value is greater than lastReplay, the last value received
+// from the server. It returns the type of message the server asks the client
+// to send.
+func VerifyReconfigure(packet []byte, key []byte, lastReplay uint64) (MessageType, error) {
+	if len(packet) < MessageHeaderSize {
+		return MessageTypeNone, fmt.Errorf("Invalid RECONFIGURE: shorter than %v bytes", MessageHeaderSize)
+	}
+	d, err := FromBytes(packet)
+	if err != nil {
+		return MessageTypeNone, err
+	}
+	if d.Type() != MessageTypeReconfigure {
+		return MessageTypeNone, errors.New("The passed message must have RECONFIGURE type set")
+	}
+	opt := d.GetOneOption(OptionReconfMessage)
+	if opt == nil {
+		return MessageTypeNone, errors.New("Reconfigure message option cannot be nil in RECONFIGURE")
+	}
+	if d.GetOneOption(OptionServerID) == nil {
+		return MessageTypeNone, errors.New("Server ID cannot be nil in RECONFIGURE")
+	}
+	auth, err := reconfigureAuth(d, ReconfigureKeyHMACMD5)
+	if err != nil {
+		return MessageTypeNone, err
+	}
+	if auth.ReplayDetection <= lastReplay {
+		return MessageTypeNone, fmt.Errorf("Replayed RECONFIGURE: replay detection %d is not greater than %d",
+			auth.ReplayDetection, lastReplay)
+	}
+	// The HMAC covers the message as sent by the server, which may differ
+	// from what ToBytes would encode from d.
+	mac, err := reconfigureMAC(packet, key)
+	if err != nil {
+		return MessageTypeNone, err
+	}
+	if !hmac.Equal(auth.AuthInfo[1:], mac) {
+		return MessageTypeNone, errors.New("Invalid RECONFIGURE authentication")
+	}
+	return opt.(*OptReconfMessage).MessageType, nil
+}
+
+// reconfigureAuth returns the Reconfigure Key authentication option of d,
+// checking that it carries information of type infoType.
+func reconfigureAuth(d DHCPv6, infoType uint8) (*OptAuth, error) {
+	opt := d.GetOneOption(OptionAuth)
+	if opt == nil {
+		return nil, errors.New("No authentication option found")
+	}
+	auth := opt.(*OptAuth)
+	if auth.Protocol != AuthProtocolReconfigureKey {
+		return nil, fmt.Errorf("Unexpected authentication protocol %v", auth.Protocol)
+	}
+	if auth.Algorithm != AuthAlgorithmHMACMD5 || auth.RDM != RDMMonotonicCounter {
+		return nil, fmt.Errorf("Unsupported reconfigure key algorithm %d or RDM %d", auth.Algorithm, auth.RDM)
+	}
+	if len(auth.AuthInfo) != 1+ReconfigureKeyLen || auth.AuthInfo[0] != infoType {
+		return nil, errors.New("Invalid reconfigure key authentication information")
+	}
+	return auth, nil
+}
+
+// reconfigureMAC computes the HMAC-MD5 of the whole message packet, with the
+// digest of its authentication option set to zero.
+func reconfigureMAC(packet []byte, key []byte) ([]byte, error) {
+	offset, err := reconfigureMACOffset(packet)
+	if err != nil {
+		return nil, err
+	}
+	m := append([]byte(nil), packet...)
+	for i := offset; i < offset+md5.Size; i++ {
+		m[i] = 0
+	}
+	mac := hmac.New(md5.New, key)
+	mac.Write(m)
+	return mac.Sum(nil), nil
+}
+
+// reconfigureMACOffset returns the offset in packet of the HMAC-MD5 digest
+// carried by its authentication option.
+func reconfigureMACOffset(packet []byte) (int, error) {
+	for i := MessageHeaderSize; i+4 <= len(packet); {
+		code := OptionCode(binary.BigEndian.Uint16(packet[i : i+2]))
+		length := int(binary.BigEndian.Uint16(packet[i+2 : i+4]))
+		i += 4
+		if i+length > len(packet) {
+			break
+		}
+		if code == OptionAuth {
+			if length != 11+1+md5.Size {
+				return 0, errors.New("Invalid reconfigure key authentication information")
+			}
+			// protocol, algorithm, RDM, replay detection and type
+			return i + 11 + 1, nil
+		}
+		i += length
+	}
+	return 0, errors.New("No authentication option found")
+}
+
+// checkReconfigureIDs checks that a RECONFIGURE is meant for the client that
+// received the given reply, and comes from the server that sent it.
+func checkReconfigureIDs(reconf, reply DHCPv6) error {
+	rcid := reconf.GetOneOption(OptionClientID)
+	cid := reply.GetOneOption(OptionClientID)
+	if rcid == nil || cid == nil {
+		return errors.New("Client ID cannot be nil in RECONFIGURE or REPLY")
+	}
+	if !bytes.Equal(rcid.ToBytes(), cid.ToBytes()) {
+		return errors.New("RECONFIGURE is for a different client")
+	}
+	rsid := reconf.GetOneOption(OptionServerID)
+	sid := reply.GetOneOption(OptionServerID)
+	if rsid == nil || sid == nil {
+		return errors.New("Server ID cannot be nil in RECONFIGURE or REPLY")
+	}
+	if !bytes.Equal(rsid.ToBytes(), sid.ToBytes()) {
+		return errors.New("RECONFIGURE is from a different server")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package dhcpv6
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testClientID = Duid{
+		Type:          DUID_LL,
+		HwType:        iana.HWTypeEthernet,
+		LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+	}
+	testServerID = Duid{
+		Type:          DUID_LL,
+		HwType:        iana.HWTypeEthernet,
+		LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 6},
+	}
+)
+
+func TestGenerateReconfigureKey(t *testing.T) {
+	key, err := GenerateReconfigureKey()
+	require.NoError(t, err)
+	require.Len(t, key, ReconfigureKeyLen)
+}
+
+func TestReconfigureKeyInReply(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	reply := DHCPv6Message{}
+	reply.SetMessage(MessageTypeReply)
+	d := WithReconfigureKey(key, 3)(&reply)
+
+	parsed, err := FromBytes(d.ToBytes())
+	require.NoError(t, err)
+	gotKey, replay, err := GetReconfigureKey(parsed)
+	require.NoError(t, err)
+	require.Equal(t, key, gotKey)
+	require.Equal(t, uint64(3), replay)
+
+	_, _, err = GetReconfigureKey(&DHCPv6Message{})
+	require.Error(t, err)
+}
+
+func TestSignVerifyReconfigure(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	rec, err := NewReconfigure(testClientID, testServerID, MessageTypeRenew)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), rec.(*DHCPv6Message).TransactionID())
+	require.NoError(t, SignReconfigure(rec, key, 5))
+
+	packet := rec.ToBytes()
+	msgType, err := VerifyReconfigure(packet, key, 4)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeRenew, msgType)
+
+	// Replayed.
+	_, err = VerifyReconfigure(packet, key, 5)
+	require.Error(t, err)
+
+	// Wrong key.
+	_, err = VerifyReconfigure(packet, []byte("fedcba9876543210"), 4)
+	require.Error(t, err)
+
+	// Tampered.
+	rec.UpdateOption(&OptReconfMessage{MessageType: MessageTypeRebind})
+	_, err = VerifyReconfigure(rec.ToBytes(), key, 4)
+	require.Error(t, err)
+
+	// Unsigned.
+	rec, err = NewReconfigure(testClientID, testServerID, MessageTypeRenew)
+	require.NoError(t, err)
+	_, err = VerifyReconfigure(rec.ToBytes(), key, 0)
+	require.Error(t, err)
+
+	// Truncated.
+	_, err = VerifyReconfigure(nil, key, 0)
+	require.Error(t, err)
+}
+
+func TestVerifyReconfigureWireBytes(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	// The HMAC is computed over the message as sent, with the digest set to
+	// zero, here with the authentication option before the others.
+	packet := []byte{
+		byte(MessageTypeReconfigure), 0, 0, 0,
+		// authentication: reconfigure key, HMAC-MD5, monotonic counter
+		0, 11, 0, 28, 3, 1, 0, 0, 0, 0, 0, 0, 0, 0, 7, 2,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		// reconfigure message: rebind
+		0, 19, 0, 1, byte(MessageTypeRebind),
+		// server ID: DUID-LL
+		0, 2, 0, 10, 0, 3, 0, 1, 0, 1, 2, 3, 4, 5,
+	}
+	mac := hmac.New(md5.New, key)
+	mac.Write(packet)
+	copy(packet[20:36], mac.Sum(nil))
+
+	msgType, err := VerifyReconfigure(packet, key, 6)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeRebind, msgType)
+}
+
+func TestCheckReconfigureIDs(t *testing.T) {
+	rec, err := NewReconfigure(testClientID, testServerID, MessageTypeInformationRequest)
+	require.NoError(t, err)
+	reply := DHCPv6Message{}
+	reply.SetMessage(MessageTypeReply)
+	reply.AddOption(&OptClientId{Cid: testClientID})
+	reply.AddOption(&OptServerId{Sid: testServerID})
+	require.NoError(t, checkReconfigureIDs(rec, &reply))
+
+	other := Duid{Type: DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{5, 4, 3, 2, 1, 0}}
+	rec, err = NewReconfigure(testClientID, other, MessageTypeInformationRequest)
+	require.NoError(t, err)
+	require.Error(t, checkReconfigureIDs(rec, &reply))
+
+	rec, err = NewReconfigure(other, testServerID, MessageTypeInformationRequest)
+	require.NoError(t, err)
+	require.Error(t, checkReconfigureIDs(rec, &reply))
+}
+
+func TestNewReconfigureInvalid(t *testing.T) {
+	_, err := NewReconfigure(testClientID, testServerID, MessageTypeSolicit)
+	require.Error(t, err)
+
+	sol, err := NewMessage()
+	require.NoError(t, err)
+	require.Error(t, SignReconfigure(sol, []byte("0123456789abcdef"), 1))
+}
+
+func TestNewMessagesFromReply(t *testing.T) {
+	reply := DHCPv6Message{}
+	reply.SetMessage(MessageTypeReply)
+	reply.AddOption(&OptClientId{Cid: testClientID})
+	reply.AddOption(&OptServerId{Sid: testServerID})
+	reply.AddOption(&OptIANA{IaId: [4]byte{1, 2, 3, 4}})
+
+	renew, err := NewRenewFromReply(&reply)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeRenew, renew.Type())
+	require.NotNil(t, renew.GetOneOption(OptionServerID))
+	require.NotNil(t, renew.GetOneOption(OptionIANA))
+
+	rebind, err := NewRebindFromReply(&reply)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeRebind, rebind.Type())
+	require.Nil(t, rebind.GetOneOption(OptionServerID))
+	require.NotNil(t, rebind.GetOneOption(OptionIANA))
+
+	inforeq, err := NewInformationRequestFromReply(&reply)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeInformationRequest, inforeq.Type())
+	require.Nil(t, inforeq.GetOneOption(OptionIANA))
+	require.Equal(t, &OptClientId{Cid: testClientID}, inforeq.GetOneOption(OptionClientID))
+
+	_, err = NewRenewFromReply(renew)
+	require.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package dhcpv6
+
+// This module computes the IPv4 address and port set of a MAP CE from a
+// mapping rule.
+// https://www.ietf.org/rfc/rfc7597.txt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// DefaultS46PSIDOffset is the PSID offset of mapping rules without port
+// parameters, as defined in RFC 7597, Section 5.1. It excludes the system
+// ports 0-1023 from every port set.
+const DefaultS46PSIDOffset = 6
+
+// S46PortSet is the set of ports of an IPv4 address shared between CEs,
+// identified by a Port Set ID (PSID), as described in RFC 7597, Section 5.1
+type S46PortSet struct {
+	Offset     uint8
+	PSIDLength uint8
+	// PSID is the numeric value of the Port Set ID, that is its lowest
+	// PSIDLength bits
+	PSID uint16
+}
+
+// S46PortRange is a range of contiguous ports, bounds included
+type S46PortRange struct {
+	Min, Max uint16
+}
+
+// PortSet returns the port set described by the option. Unlike the option,
+// where the PSID is left-aligned, the PSID of the port set is its numeric
+// value.
+func (op *OptS46PortParams) PortSet() S46PortSet {
+	ps := S46PortSet{Offset: op.Offset, PSIDLength: op.PSIDLength}
+	if op.PSIDLength > 0 {
+		ps.PSID = op.PSID >> (16 - op.PSIDLength)
+	}
+	return ps
+}
+
+// Ranges returns the ranges of contiguous ports that make up the port set, in
+// increasing order
+func (p S46PortSet) Ranges() []S46PortRange {
+	a, k := uint(p.Offset), uint(p.PSIDLength)
+	if a+k > 16 {
+		return nil
+	}
+	m := 16 - a - k
+	first := 0
+	if a > 0 {
+		// the ports whose a first bits are zero are excluded
+		first = 1
+	}
+	var ranges []S46PortRange
+	for i := first; i < 1<<a; i++ {
+		min := uint16(i<<(16-a)) | uint16(uint(p.PSID)<<m)
+		ranges = append(ranges, S46PortRange{Min: min, Max: min + uint16(1<<m-1)})
+	}
+	return ranges
+}
+
+// Contains returns true if port is in the port set
+func (p S46PortSet) Contains(port uint16) bool {
+	a, k := uint(p.Offset), uint(p.PSIDLength)
+	if a+k > 16 {
+		return false
+	}
+	if a > 0 && port>>(16-a) == 0 {
+		return false
+	}
+	m := 16 - a - k
+	psid := (port >> m) & uint16(1<<k-1)
+	return psid == p.PSID
+}
+
+// S46Mapping is the IPv4 configuration that a mapping rule gives a CE
+type S46Mapping struct {
+	// IPv4Prefix is the IPv4 address of the CE, as a /32, or its IPv4
+	// prefix if the rule hands out more than an address
+	IPv4Prefix net.IPNet
+	PortSet    S46PortSet
+	// IPv6Address is the MAP IPv6 address of the CE, described in RFC 7597,
+	// Section 6
+	IPv6Address net.IP
+}
+
+// bitsAt returns n bits of ip, starting at bit start, with n <= 64
+func bitsAt(ip net.IP, start, n int) uint64 {
+	var v uint64
+	for i := start; i < start+n; i++ {
+		v = v<<1 | uint64(ip[i/8]>>(7-uint(i%8))&1)
+	}
+	return v
+}
+
+// Mapping computes the IPv4 address, or prefix, and port set that the rule
+// gives a CE with the delegated End-user IPv6 prefix, as described in RFC
+// 7597, Section 5. The PSID offset is taken from the OptS46PortParams of the
+// rule if present, and is DefaultS46PSIDOffset otherwise.
+func (op *OptS46Rule) Mapping(delegated net.IPNet) (*S46Mapping, error) {
+	r6, _ := op.IPv6Prefix.Mask.Size()
+	d6, bits := delegated.Mask.Size()
+	ip6 := delegated.IP.To16()
+	if bits != 128 || ip6 == nil || d6 < r6 || !op.IPv6Prefix.Contains(ip6) {
+		return nil, fmt.Errorf("delegated prefix %v is not within the rule IPv6 prefix %v", delegated.String(), op.IPv6Prefix.String())
+	}
+	ea := int(op.EALength)
+	if r6+ea > d6 {
+		return nil, fmt.Errorf("delegated prefix %v is shorter than the rule IPv6 prefix and EA bits (%d)", delegated.String(), r6+ea)
+	}
+	if d6 > 64 {
+		return nil, fmt.Errorf("delegated prefix %v is longer than 64 bits", delegated.String())
+	}
+	r4, _ := op.IPv4Prefix.Mask.Size()
+	ip4 := op.IPv4Prefix.IP.To4()
+	if ip4 == nil {
+		return nil, fmt.Errorf("invalid rule IPv4 prefix %v", op.IPv4Prefix.String())
+	}
+	p := 32 - r4
+	eaBits := bitsAt(ip6, r6, ea)
+
+	m := S46Mapping{PortSet: S46PortSet{Offset: DefaultS46PSIDOffset}}
+	pp, _ := op.GetOneOption(OptionS46PortParams).(*OptS46PortParams)
+	if pp != nil {
+		m.PortSet.Offset = pp.Offset
+	}
+	addr := binary.BigEndian.Uint32(ip4)
+	if ea >= p {
+		q := ea - p
+		if p > 0 {
+			addr |= uint32(eaBits >> uint(q))
+		}
+		m.IPv4Prefix.Mask = net.CIDRMask(32, 32)
+		m.PortSet.PSIDLength = uint8(q)
+		m.PortSet.PSID = uint16(eaBits & (1<<uint(q) - 1))
+		if q == 0 && pp != nil {
+			// the PSID is not embedded, but given explicitly
+			m.PortSet = pp.PortSet()
+		}
+	} else {
+		addr |= uint32(eaBits << uint(p-ea))
+		m.IPv4Prefix.Mask = net.CIDRMask(r4+ea, 32)
+		m.PortSet = S46PortSet{}
+	}
+	if int(m.PortSet.Offset)+int(m.PortSet.PSIDLength) > 16 {
+		return nil, fmt.Errorf("PSID offset %d and length %d exceed 16 bits", m.PortSet.Offset, m.PortSet.PSIDLength)
+	}
+	m.IPv4Prefix.IP = make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(m.IPv4Prefix.IP, addr)
+
+	// End-user IPv6 prefix, subnet ID 0, and the interface ID made of 16
+	// zero bits, the IPv4 address and the PSID
+	m.IPv6Address = make(net.IP, net.IPv6len)
+	copy(m.IPv6Address, ip6.Mask(delegated.Mask))
+	copy(m.IPv6Address[10:14], m.IPv4Prefix.IP)
+	binary.BigEndian.PutUint16(m.IPv6Address[14:16], m.PortSet.PSID)
+	return &m, nil
+}
@@ -0,0 +1,98 @@
+package dhcpv6
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sampleS46Rule is the Basic Mapping Rule of RFC 7597, Appendix A, Example 1
+func sampleS46Rule(t *testing.T) *OptS46Rule {
+	return &OptS46Rule{
+		EALength:   16,
+		IPv4Prefix: mustParseCIDR(t, "192.0.2.0/24"),
+		IPv6Prefix: mustParseCIDR(t, "2001:db8::/40"),
+		Options:    []Option{&OptS46PortParams{Offset: 6}},
+	}
+}
+
+func TestS46RuleMapping(t *testing.T) {
+	m, err := sampleS46Rule(t).Mapping(mustParseCIDR(t, "2001:db8:12:3400::/56"))
+	require.NoError(t, err)
+	require.Equal(t, "192.0.2.18/32", m.IPv4Prefix.String())
+	require.Equal(t, S46PortSet{Offset: 6, PSIDLength: 8, PSID: 0x34}, m.PortSet)
+	require.Equal(t, net.ParseIP("2001:db8:12:3400:0:c000:212:34"), m.IPv6Address)
+
+	ranges := m.PortSet.Ranges()
+	require.Len(t, ranges, 63)
+	require.Equal(t, S46PortRange{Min: 1232, Max: 1235}, ranges[0])
+	require.Equal(t, S46PortRange{Min: 2256, Max: 2259}, ranges[1])
+	require.Equal(t, S46PortRange{Min: 64720, Max: 64723}, ranges[62])
+	require.True(t, m.PortSet.Contains(1233))
+	require.True(t, m.PortSet.Contains(64723))
+	require.False(t, m.PortSet.Contains(1236))
+	require.False(t, m.PortSet.Contains(208))
+}
+
+func TestS46RuleMappingDefaultOffset(t *testing.T) {
+	rule := sampleS46Rule(t)
+	rule.Options = nil
+	m, err := rule.Mapping(mustParseCIDR(t, "2001:db8:12:3400::/56"))
+	require.NoError(t, err)
+	require.Equal(t, uint8(DefaultS46PSIDOffset), m.PortSet.Offset)
+}
+
+func TestS46RuleMappingFullAddress(t *testing.T) {
+	// 1:1 rule, every CE gets a full IPv4 address
+	rule := &OptS46Rule{
+		EALength:   8,
+		IPv4Prefix: mustParseCIDR(t, "192.0.2.0/24"),
+		IPv6Prefix: mustParseCIDR(t, "2001:db8::/40"),
+	}
+	m, err := rule.Mapping(mustParseCIDR(t, "2001:db8:12::/48"))
+	require.NoError(t, err)
+	require.Equal(t, "192.0.2.18/32", m.IPv4Prefix.String())
+	require.Equal(t, uint8(0), m.PortSet.PSIDLength)
+	require.True(t, m.PortSet.Contains(1024))
+	require.False(t, m.PortSet.Contains(1023))
+}
+
+func TestS46RuleMappingPrefix(t *testing.T) {
+	rule := &OptS46Rule{
+		EALength:   4,
+		IPv4Prefix: mustParseCIDR(t, "192.0.2.0/24"),
+		IPv6Prefix: mustParseCIDR(t, "2001:db8::/40"),
+	}
+	m, err := rule.Mapping(mustParseCIDR(t, "2001:db8:30::/44"))
+	require.NoError(t, err)
+	require.Equal(t, "192.0.2.48/28", m.IPv4Prefix.String())
+}
+
+func TestS46RuleMappingExplicitPSID(t *testing.T) {
+	// Lightweight 4over6-like rule, without EA bits and with an explicit PSID
+	rule := &OptS46Rule{
+		IPv4Prefix: mustParseCIDR(t, "192.0.2.1/32"),
+		IPv6Prefix: mustParseCIDR(t, "2001:db8::/40"),
+		Options:    []Option{&OptS46PortParams{Offset: 4, PSIDLength: 4, PSID: 0x3000}},
+	}
+	m, err := rule.Mapping(mustParseCIDR(t, "2001:db8:12:3400::/56"))
+	require.NoError(t, err)
+	require.Equal(t, "192.0.2.1/32", m.IPv4Prefix.String())
+	require.Equal(t, S46PortSet{Offset: 4, PSIDLength: 4, PSID: 3}, m.PortSet)
+	require.Equal(t, S46PortRange{Min: 0x1300, Max: 0x13ff}, m.PortSet.Ranges()[0])
+}
+
+func TestS46RuleMappingInvalid(t *testing.T) {
+	rule := sampleS46Rule(t)
+	// outside of the rule prefix
+	_, err := rule.Mapping(mustParseCIDR(t, "2001:db9:12:3400::/56"))
+	require.Error(t, err)
+	// too short to hold the EA bits
+	_, err = rule.Mapping(mustParseCIDR(t, "2001:db8:12::/48"))
+	require.Error(t, err)
+	// PSID does not fit in a port
+	rule.Options = []Option{&OptS46PortParams{Offset: 10}}
+	_, err = rule.Mapping(mustParseCIDR(t, "2001:db8:12:3400::/56"))
+	require.Error(t, err)
+}
@@ -151,6 +151,24 @@ func (s *Server) Close() error {
 	return nil
 }
 
+// SendReconfigure sends a signed RECONFIGURE message to a client from the
+// server's listening socket. See NewReconfigure and SignReconfigure.
+func (s *Server) SendReconfigure(client net.Addr, reconf DHCPv6) error {
+	if reconf.Type() != MessageTypeReconfigure {
+		return fmt.Errorf("SendReconfigure: not a RECONFIGURE message: %v", reconf.Type())
+	}
+	if reconf.GetOneOption(OptionAuth) == nil {
+		return fmt.Errorf("SendReconfigure: RECONFIGURE must be signed")
+	}
+	s.connMutex.Lock()
+	defer s.connMutex.Unlock()
+	if s.conn == nil {
+		return fmt.Errorf("SendReconfigure: server is not listening")
+	}
+	_, err := s.conn.WriteTo(reconf.ToBytes(), client)
+	return err
+}
+
 // NewServer initializes and returns a new Server object
 func NewServer(addr net.UDPAddr, handler Handler) *Server {
 	return &Server{
@@ -75,3 +75,95 @@ func TestServerActivateAndServe(t *testing.T) {
 	_, _, err = c.Solicit(ifaces[0].Name)
 	require.NoError(t, err)
 }
+
+func TestServerLeaseQuery(t *testing.T) {
+	handler := func(conn net.PacketConn, peer net.Addr, m DHCPv6) {
+		rep, err := NewLeaseQueryReplyFromLeaseQuery(m)
+		if err != nil {
+			log.Printf("NewLeaseQueryReplyFromLeaseQuery failed: %v", err)
+			return
+		}
+		if _, err := conn.WriteTo(rep.ToBytes(), peer); err != nil {
+			log.Printf("Cannot reply to client: %v", err)
+		}
+	}
+	c, s := setUpClientAndServer(handler)
+	defer s.Close()
+
+	ifaces, err := interfaces.GetLoopbackInterfaces()
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(ifaces))
+
+	query := &OptLQQuery{QueryType: QueryByAddress, LinkAddress: net.IPv6zero}
+	query.AddOption(&OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::10")})
+	lq, reply, err := c.LeaseQuery(ifaces[0].Name, query)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeLeaseQuery, lq.Type())
+	require.Equal(t, MessageTypeLeaseQueryReply, reply.Type())
+}
+
+func TestServerReconfigure(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	handler := func(conn net.PacketConn, peer net.Addr, m DHCPv6) {
+		rep, err := NewReplyFromDHCPv6Message(m, WithServerID(testServerID))
+		if err != nil {
+			log.Printf("NewReplyFromDHCPv6Message failed: %v", err)
+			return
+		}
+		if _, err := conn.WriteTo(rep.ToBytes(), peer); err != nil {
+			log.Printf("Cannot reply to client: %v", err)
+		}
+	}
+	c, s := setUpClientAndServer(handler)
+	defer s.Close()
+
+	// The server sends a signed RECONFIGURE to the client.
+	clientConn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.ParseIP("::1")})
+	require.NoError(t, err)
+	defer clientConn.Close()
+	rec, err := NewReconfigure(testClientID, testServerID, MessageTypeRenew)
+	require.NoError(t, err)
+	require.NoError(t, SignReconfigure(rec, key, 2))
+	require.NoError(t, s.SendReconfigure(clientConn.LocalAddr(), rec))
+
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, MaxUDPReceivedPacketSize)
+	n, _, err := clientConn.ReadFrom(buf)
+	require.NoError(t, err)
+	received := buf[:n]
+
+	// The client renews with the server.
+	lease := DHCPv6Message{}
+	lease.SetMessage(MessageTypeReply)
+	lease.AddOption(&OptClientId{Cid: testClientID})
+	lease.AddOption(&OptServerId{Sid: testServerID})
+	WithReconfigureKey(key, 1)(&lease)
+
+	ifaces, err := interfaces.GetLoopbackInterfaces()
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(ifaces))
+
+	renew, reply, err := c.Reconfigure(ifaces[0].Name, received, &lease, 1)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeRenew, renew.Type())
+	require.Equal(t, MessageTypeReply, reply.Type())
+
+	// The same RECONFIGURE is rejected once its replay value was seen.
+	_, _, err = c.Reconfigure(ifaces[0].Name, received, &lease, 2)
+	require.Error(t, err)
+
+	// An INFORMATION-REQUEST names the server that sent the RECONFIGURE.
+	rec, err = NewReconfigure(testClientID, testServerID, MessageTypeInformationRequest)
+	require.NoError(t, err)
+	require.NoError(t, SignReconfigure(rec, key, 3))
+	inforeq, reply, err := c.Reconfigure(ifaces[0].Name, rec.ToBytes(), &lease, 2)
+	require.NoError(t, err)
+	require.Equal(t, MessageTypeInformationRequest, inforeq.Type())
+	require.Equal(t, &OptServerId{Sid: testServerID}, inforeq.GetOneOption(OptionServerID))
+	require.Equal(t, MessageTypeReply, reply.Type())
+
+	// Unsigned RECONFIGUREs are not sent.
+	rec, err = NewReconfigure(testClientID, testServerID, MessageTypeRenew)
+	require.NoError(t, err)
+	require.Error(t, s.SendReconfigure(clientConn.LocalAddr(), rec))
+}
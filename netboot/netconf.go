@@ -27,6 +27,10 @@ type NetConf struct {
 	DNSServers    []net.IP
 	DNSSearchList []string
 	Routers       []net.IP
+	NTPServers    []net.IP
+	// CaptivePortal is the URI of the captive portal API of the network, as
+	// described in RFC 8910, or an empty string if there is none
+	CaptivePortal string
 }
 
 // GetNetConfFromPacketv6 extracts network configuration information from a DHCPv6
@@ -72,6 +76,16 @@ func GetNetConfFromPacketv6(d *dhcpv6.DHCPv6Message) (*NetConf, error) {
 		netconf.DNSSearchList = odomains.DomainSearchList.Labels
 	}
 
+	// get NTP configuration, preferring the NTP Server option to the older
+	// SNTP Server List option. Multicast addresses of the NTP Server option
+	// are not servers to query, and are left out.
+	if ontp := d.NTPServer(); ontp != nil {
+		netconf.NTPServers = append(netconf.NTPServers, ontp.Addresses()...)
+	}
+	netconf.NTPServers = append(netconf.NTPServers, d.SNTPServers()...)
+
+	netconf.CaptivePortal = d.CaptivePortal()
+
 	return &netconf, nil
 }
 
@@ -132,6 +146,11 @@ func GetNetConfFromPacketv4(d *dhcpv4.DHCPv4) (*NetConf, error) {
 		return nil, errors.New("no routers specified in the corresponding option")
 	}
 	netconf.Routers = routersList
+
+	// get NTP servers
+	netconf.NTPServers = d.NTPServers()
+
+	netconf.CaptivePortal = d.CaptivePortal()
 	return &netconf, nil
 }
 
@@ -104,6 +104,37 @@ func TestGetNetConfFromPacketv6(t *testing.T) {
 	require.Equal(t, "slackware.it", netconf.DNSSearchList[0])
 	// check routers
 	require.Equal(t, 0, len(netconf.Routers))
+	// check NTP servers
+	require.Equal(t, 0, len(netconf.NTPServers))
+}
+
+func TestGetNetConfFromPacketv6NTPServers(t *testing.T) {
+	adv := getAdv(
+		dhcpv6.WithIANA(dhcpv6.OptIAAddress{IPv6Addr: net.ParseIP("::1")}),
+		dhcpv6.WithDNS(net.ParseIP("fe80::1")),
+		func(d dhcpv6.DHCPv6) dhcpv6.DHCPv6 {
+			d.AddOption(&dhcpv6.OptNTPServer{Suboptions: []dhcpv6.NTPSuboption{
+				{Code: dhcpv6.NTPSuboptionMCAddr, IP: net.ParseIP("ff05::101")},
+				{Code: dhcpv6.NTPSuboptionSrvAddr, IP: net.ParseIP("2001:db8::1")},
+			}})
+			return d
+		},
+		dhcpv6.WithSNTPServers(net.ParseIP("2001:db8::2")),
+	)
+	netconf, err := GetNetConfFromPacketv6(adv)
+	require.NoError(t, err)
+	require.Equal(t, []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")}, netconf.NTPServers)
+}
+
+func TestGetNetConfFromPacketv6CaptivePortal(t *testing.T) {
+	adv := getAdv(
+		dhcpv6.WithIANA(dhcpv6.OptIAAddress{IPv6Addr: net.ParseIP("::1")}),
+		dhcpv6.WithDNS(net.ParseIP("fe80::1")),
+		dhcpv6.WithCaptivePortal("https://example.org/captive"),
+	)
+	netconf, err := GetNetConfFromPacketv6(adv)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.org/captive", netconf.CaptivePortal)
 }
 
 func TestGetNetConfFromPacketv4AddrZero(t *testing.T) {
@@ -213,6 +244,8 @@ func TestGetNetConfFromPacketv4(t *testing.T) {
 		dhcpv4.WithDomainSearchList("slackware.it", "dhcp.slackware.it"),
 		dhcpv4.WithRouter(net.ParseIP("10.0.0.254")),
 		dhcpv4.WithYourIP(net.ParseIP("10.0.0.1")),
+		dhcpv4.WithOption(dhcpv4.OptNTPServers(net.ParseIP("10.0.0.123"))),
+		dhcpv4.WithCaptivePortal("https://example.org/captive"),
 	)
 
 	netconf, err := GetNetConfFromPacketv4(d)
@@ -233,4 +266,8 @@ func TestGetNetConfFromPacketv4(t *testing.T) {
 	// check routers
 	require.Equal(t, 1, len(netconf.Routers))
 	require.Equal(t, net.ParseIP("10.0.0.254").To4(), netconf.Routers[0])
+	// check NTP servers
+	require.Equal(t, []net.IP{net.ParseIP("10.0.0.123").To4()}, netconf.NTPServers)
+	// check captive portal
+	require.Equal(t, "https://example.org/captive", netconf.CaptivePortal)
 }